@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/internal/tmux"
 	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/d-kuro/gwq/pkg/models"
@@ -266,7 +267,7 @@ func (ca *ClaudeAgent) checkExecutionStatus(sessionID string, task *Task, startT
 	// Check if Claude Code process is still running
 	claudeRunning, err := ca.checkClaudeProcessRunning(sessionID)
 	if err != nil {
-		fmt.Printf("Warning: failed to check Claude process: %v\n", err)
+		logging.Warnf("failed to check Claude process: %v", err)
 		return nil, true // Continue monitoring
 	}
 
@@ -279,7 +280,7 @@ func (ca *ClaudeAgent) checkExecutionStatus(sessionID string, task *Task, startT
 	// Check for completion patterns in session output
 	completed, exitCode, err := ca.checkSessionCompletion(sessionID)
 	if err != nil {
-		fmt.Printf("Warning: failed to check session completion: %v\n", err)
+		logging.Warnf("failed to check session completion: %v", err)
 		return nil, true // Continue monitoring
 	}
 
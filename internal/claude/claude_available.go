@@ -0,0 +1,38 @@
+package claude
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/d-kuro/gwq/pkg/system"
+)
+
+// resolvedExecutables caches executables found on PATH for the life of the
+// process. Misses are not cached, so installing Claude fixes later retries
+// of a long-running worker without a restart.
+var resolvedExecutables sync.Map // executable -> resolved path
+
+// checkClaudeAvailable reports a configuration error when the Claude CLI
+// can't be found, before any pipe or tmux session is set up
+func checkClaudeAvailable(sys system.SystemInterface, executable string) error {
+	if _, ok := resolvedExecutables.Load(executable); ok {
+		return nil
+	}
+
+	path, err := sys.LookPath(executable)
+	if err != nil {
+		return fmt.Errorf("claude executable '%s' not found; set claude.executable in config: %w", executable, err)
+	}
+	resolvedExecutables.Store(executable, path)
+	return nil
+}
+
+// CheckClaudeAvailable verifies that the configured Claude executable exists
+func (cce *ClaudeCodeExecutor) CheckClaudeAvailable() error {
+	return checkClaudeAvailable(cce.system, cce.config.Executable)
+}
+
+// CheckClaudeAvailable verifies that the configured Claude executable exists
+func (em *ExecutionManager) CheckClaudeAvailable() error {
+	return checkClaudeAvailable(em.system, em.config.Executable)
+}
@@ -0,0 +1,85 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// lookPathSystem is a SystemInterface that resolves executables from a fixed
+// set and counts lookups
+type lookPathSystem struct {
+	failingPipeSystem
+	found   map[string]bool
+	lookups int
+}
+
+func (s *lookPathSystem) LookPath(file string) (string, error) {
+	s.lookups++
+	if !s.found[file] {
+		return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+	}
+	return "/usr/local/bin/" + file, nil
+}
+
+func TestClaudeCodeExecutorMissingExecutable(t *testing.T) {
+	config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: "claude-missing-executor"}
+	executor := NewClaudeCodeExecutorWithSystem(config, &lookPathSystem{})
+
+	execution := &UnifiedExecution{ExecutionID: "task-missing", ExecutionType: ExecutionTypeTask, Prompt: "test"}
+	result, err := executor.Execute(context.Background(), execution, "")
+	if err == nil {
+		t.Fatal("Execute() should fail when the executable is missing")
+	}
+	if want := "claude executable 'claude-missing-executor' not found; set claude.executable in config"; !strings.Contains(err.Error(), want) {
+		t.Errorf("Execute() error = %q, want it to contain %q", err, want)
+	}
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Execute() error = %v, want it to wrap exec.ErrNotFound", err)
+	}
+	if result == nil || result.ErrorKind != ErrorKindSetup {
+		t.Errorf("Execute() result = %+v, want a setup error", result)
+	}
+}
+
+func TestExecutionManagerMissingExecutable(t *testing.T) {
+	sessions := &mockSessionManager{}
+	em, err := NewExecutionManagerWithSessions(&models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: "claude-missing-manager"}, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+	em.system = &lookPathSystem{}
+
+	_, err = em.Execute(context.Background(), &ExecutionMetadata{ExecutionID: "exec-missing", Prompt: "test"})
+	if err == nil || !strings.Contains(err.Error(), "set claude.executable in config") {
+		t.Fatalf("Execute() error = %v, want the missing executable error", err)
+	}
+	if len(sessions.alive) != 0 {
+		t.Errorf("Execute() created tmux sessions %v before failing", sessions.alive)
+	}
+}
+
+func TestCheckClaudeAvailableCachesHits(t *testing.T) {
+	sys := &lookPathSystem{}
+	executor := NewClaudeCodeExecutorWithSystem(&models.ClaudeConfig{Executable: "claude-installed-later"}, sys)
+
+	// Misses are retried, so installing the CLI takes effect without a restart
+	for i := 0; i < 2; i++ {
+		if err := executor.CheckClaudeAvailable(); err == nil {
+			t.Fatal("CheckClaudeAvailable() should fail before the executable exists")
+		}
+	}
+	sys.found = map[string]bool{"claude-installed-later": true}
+	for i := 0; i < 2; i++ {
+		if err := executor.CheckClaudeAvailable(); err != nil {
+			t.Fatalf("CheckClaudeAvailable() failed: %v", err)
+		}
+	}
+	if sys.lookups != 3 {
+		t.Errorf("LookPath called %d times, want 3", sys.lookups)
+	}
+}
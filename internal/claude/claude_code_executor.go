@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -13,9 +14,11 @@ import (
 
 	"github.com/d-kuro/gwq/internal/config"
 	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/d-kuro/gwq/pkg/system"
+	"github.com/d-kuro/gwq/pkg/utils"
 )
 
 // ClaudeCodeExecutor handles the actual execution of Claude Code commands
@@ -40,24 +43,57 @@ func NewClaudeCodeExecutorWithSystem(config *models.ClaudeConfig, sys system.Sys
 	}
 }
 
-// Execute runs Claude Code and captures the output
+// Execute runs Claude Code and captures the output. With execution.DryRun
+// set it only records what would run in execution.Plan and returns an empty
+// successful result; Claude doesn't need to be installed for that.
 func (cce *ClaudeCodeExecutor) Execute(ctx context.Context, execution *UnifiedExecution, logFile string) (*ExecutionResult, error) {
+	if execution.DryRun {
+		execution.Plan = cce.planExecution(execution, logFile)
+		return &ExecutionResult{Success: true, ErrorKind: ErrorKindNone}, nil
+	}
+
+	if execution.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, execution.Timeout)
+		defer cancel()
+	}
+
+	// Log capture cancels the command when the streamed cost goes over budget
+	ctx, cancelBudget := context.WithCancelCause(ctx)
+	defer cancelBudget(nil)
+	budget := newCostBudget(effectiveMaxCost(execution.MaxCostUSD, cce.config.MaxCostUSD), cancelBudget)
+
+	if err := cce.CheckClaudeAvailable(); err != nil {
+		return &ExecutionResult{
+			Success:   false,
+			ExitCode:  1,
+			Error:     err.Error(),
+			ErrorKind: ErrorKindSetup,
+		}, err
+	}
+
 	// Create named pipe for output capture
 	pipePath, cleanup, err := cce.createNamedPipe(execution.ExecutionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create named pipe: %w", err)
+		return &ExecutionResult{
+			Success:   false,
+			ExitCode:  1,
+			Error:     fmt.Sprintf("failed to create named pipe: %v", err),
+			ErrorKind: ErrorKindSetup,
+		}, fmt.Errorf("failed to create named pipe: %w", err)
 	}
 	defer cleanup()
 
 	// Start log capture in background
-	logCaptureDone := cce.startLogCapture(pipePath, logFile, execution)
+	logCaptureDone := cce.startLogCapture(pipePath, logFile, execution, budget)
 
 	// Setup and validate execution environment
 	if err := cce.ensureWorktreeExists(execution); err != nil {
 		return &ExecutionResult{
-			Success:  false,
-			ExitCode: 1,
-			Error:    fmt.Sprintf("failed to ensure worktree exists: %v", err),
+			Success:   false,
+			ExitCode:  1,
+			Error:     fmt.Sprintf("failed to ensure worktree exists: %v", err),
+			ErrorKind: ErrorKindSetup,
 		}, err
 	}
 
@@ -65,9 +101,10 @@ func (cce *ClaudeCodeExecutor) Execute(ctx context.Context, execution *UnifiedEx
 	cmd, err := cce.setupCommandExecution(ctx, execution, pipePath)
 	if err != nil {
 		return &ExecutionResult{
-			Success:  false,
-			ExitCode: 1,
-			Error:    fmt.Sprintf("failed to setup command: %v", err),
+			Success:   false,
+			ExitCode:  1,
+			Error:     fmt.Sprintf("failed to setup command: %v", err),
+			ErrorKind: ErrorKindSetup,
 		}, err
 	}
 
@@ -77,7 +114,7 @@ func (cce *ClaudeCodeExecutor) Execute(ctx context.Context, execution *UnifiedEx
 	cce.handlePostExecution(ctx, execution)
 
 	// Collect and return results
-	return cce.collectExecutionResult(exitCode, cmdErr, logCaptureDone, execution)
+	return cce.collectExecutionResult(ctx, exitCode, cmdErr, logCaptureDone, execution)
 }
 
 // buildClaudeCommand builds the appropriate Claude command
@@ -85,16 +122,21 @@ func (cce *ClaudeCodeExecutor) buildClaudeCommand(execution *UnifiedExecution) s
 	args := []string{cce.config.Executable}
 
 	// Add standard arguments for task execution
-	args = append(args, "--dangerously-skip-permissions", "--output-format", "stream-json")
+	args = append(args, "--dangerously-skip-permissions", "--output-format", outputFormat(cce.config))
 
-	// Add the prompt
-	args = append(args, "-p", fmt.Sprintf(`"%s"`, escapeForShell(execution.Prompt)))
+	if execution.Model != "" {
+		args = append(args, "--model", utils.ShellQuote(execution.Model))
+	}
+
+	// The prompt is passed on stdin, avoiding argument length limits
+	args = append(args, "-p")
 
 	return strings.Join(args, " ")
 }
 
-// captureLogOutput captures the JSON output from Claude
-func (cce *ClaudeCodeExecutor) captureLogOutput(pipePath, logFile string, execution *UnifiedExecution) error {
+// captureLogOutput captures the JSON output from Claude, reporting the cost
+// of each line to budget
+func (cce *ClaudeCodeExecutor) captureLogOutput(pipePath, logFile string, execution *UnifiedExecution, budget *costBudget) error {
 	// Open pipe for reading
 	pipe, err := os.OpenFile(pipePath, os.O_RDONLY, 0)
 	if err != nil {
@@ -102,42 +144,49 @@ func (cce *ClaudeCodeExecutor) captureLogOutput(pipePath, logFile string, execut
 	}
 	defer func() {
 		if err := pipe.Close(); err != nil {
-			fmt.Printf("Warning: failed to close pipe: %v\n", err)
+			logging.Warnf("failed to close pipe: %v", err)
 		}
 	}()
 
-	// Create log file
-	log, err := os.Create(logFile)
+	// Compiled after opening the pipe so a bad pattern can't leave the
+	// writer blocked on it
+	redactor, err := NewLogRedactor(cce.config.RedactPatterns)
+	if err != nil {
+		return err
+	}
+
+	// Create log file, rotating into part files once it grows past the
+	// configured size
+	log, err := openRotatingLog(logFile, logPartSize(cce.config), false)
 	if err != nil {
 		return fmt.Errorf("failed to create log file: %w", err)
 	}
 	defer func() {
 		if err := log.Close(); err != nil {
-			fmt.Printf("Warning: failed to close log file: %v\n", err)
+			logging.Warnf("failed to close log file: %v", err)
 		}
 	}()
 
-	// Read and process JSON stream
-	scanner := bufio.NewScanner(pipe)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024) // 1MB buffer
-
-	for scanner.Scan() {
-		line := scanner.Text()
+	// Read and process JSON stream, or the single object of the json format
+	err = forEachOutputRecord(pipe, outputFormat(cce.config), func(line string) {
 		if line == "" {
-			continue
+			return
 		}
 
 		// Add timestamp and execution context to each JSON line
 		var jsonData map[string]interface{}
 		if err := json.Unmarshal([]byte(line), &jsonData); err == nil {
+			redactor.RedactJSON(jsonData)
+			budget.observe(jsonData)
+
 			// Enhance with execution context
-			jsonData["timestamp"] = time.Now().Format(time.RFC3339)
+			stampLogEntry(jsonData, time.Now())
 			jsonData["execution_id"] = execution.ExecutionID
 			jsonData["execution_type"] = execution.ExecutionType
 
 			// Extract cost and model info if available
 			if jsonData["type"] == "result" {
-				if cost, ok := jsonData["cost_usd"].(float64); ok {
+				if cost, ok := resultCostUSD(jsonData); ok {
 					execution.CostUSD = cost
 				}
 				if duration, ok := jsonData["duration_ms"].(float64); ok {
@@ -153,26 +202,37 @@ func (cce *ClaudeCodeExecutor) captureLogOutput(pipePath, logFile string, execut
 
 			// Write enhanced JSON line
 			enhancedLine, _ := json.Marshal(jsonData)
-			if _, err := fmt.Fprintf(log, "%s\n", enhancedLine); err != nil {
-				fmt.Printf("Warning: failed to write enhanced log line: %v\n", err)
+			if err := log.WriteLine(string(enhancedLine)); err != nil {
+				logging.Warnf("failed to write enhanced log line: %v", err)
 			}
 		} else {
 			// If not valid JSON, write as-is with execution context
 			contextLine := fmt.Sprintf(`{"type":"raw","content":"%s","execution_id":"%s","timestamp":"%s"}`,
-				escapeJSONString(line), execution.ExecutionID, time.Now().Format(time.RFC3339))
-			if _, err := fmt.Fprintln(log, contextLine); err != nil {
-				fmt.Printf("Warning: failed to write log line: %v\n", err)
+				escapeJSONString(redactor.RedactString(line)), execution.ExecutionID, time.Now().Format(time.RFC3339))
+			if err := log.WriteLine(contextLine); err != nil {
+				logging.Warnf("failed to write log line: %v", err)
 			}
 		}
-	}
-
-	if err := scanner.Err(); err != nil {
+	})
+	if err != nil {
 		return fmt.Errorf("error reading from pipe: %w", err)
 	}
 
 	return nil
 }
 
+// stampLogEntry records when a log line was captured. A timestamp emitted by
+// Claude is kept as the event time and the capture time goes to captured_at,
+// so buffering between Claude and gwq does not skew the timeline.
+func stampLogEntry(jsonData map[string]interface{}, now time.Time) {
+	captured := now.Format(time.RFC3339)
+	if ts, ok := jsonData["timestamp"].(string); ok && ts != "" {
+		jsonData["captured_at"] = captured
+		return
+	}
+	jsonData["timestamp"] = captured
+}
+
 // detectChangedFiles detects files that were changed during execution
 func (cce *ClaudeCodeExecutor) detectChangedFiles(execution *UnifiedExecution) []string {
 	workingDir := execution.WorkingDir
@@ -221,14 +281,14 @@ func (cce *ClaudeCodeExecutor) detectChangedFiles(execution *UnifiedExecution) [
 
 // createNamedPipe creates a named pipe for output capture
 func (cce *ClaudeCodeExecutor) createNamedPipe(executionID string) (string, func(), error) {
-	pipePath := fmt.Sprintf("/tmp/gwq-claude-%s.pipe", executionID)
+	pipePath := namedPipePath(executionID)
 	if err := cce.system.CreateNamedPipe(pipePath, 0600); err != nil {
 		return "", nil, err
 	}
 
 	cleanup := func() {
 		if err := cce.system.RemoveFile(pipePath); err != nil {
-			fmt.Printf("Warning: failed to remove pipe: %v\n", err)
+			logging.Warnf("failed to remove pipe: %v", err)
 		}
 	}
 
@@ -236,33 +296,50 @@ func (cce *ClaudeCodeExecutor) createNamedPipe(executionID string) (string, func
 }
 
 // startLogCapture starts log capture in a background goroutine
-func (cce *ClaudeCodeExecutor) startLogCapture(pipePath, logFile string, execution *UnifiedExecution) <-chan error {
+func (cce *ClaudeCodeExecutor) startLogCapture(pipePath, logFile string, execution *UnifiedExecution, budget *costBudget) <-chan error {
 	logCaptureDone := make(chan error, 1)
 	go func() {
-		logCaptureDone <- cce.captureLogOutput(pipePath, logFile, execution)
+		logCaptureDone <- cce.captureLogOutput(pipePath, logFile, execution, budget)
 	}()
 	return logCaptureDone
 }
 
 // setupCommandExecution creates and configures the command for execution
 func (cce *ClaudeCodeExecutor) setupCommandExecution(ctx context.Context, execution *UnifiedExecution, pipePath string) (*exec.Cmd, error) {
-	// Build the Claude command
-	claudeCmd := cce.buildClaudeCommand(execution)
-	fullCmd := fmt.Sprintf("%s | tee %s", claudeCmd, pipePath)
-
 	// Create command with context
-	cmd := exec.CommandContext(ctx, "bash", "-c", fullCmd)
+	cmd := exec.CommandContext(ctx, "bash", "-c", cce.commandLine(execution, pipePath))
 	cmd.Dir = execution.WorkingDir
+	cmd.Stdin = strings.NewReader(execution.Prompt)
+	killProcessGroupOnCancel(cmd)
 
-	// Set environment variables
-	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("CLAUDE_EXECUTION_ID=%s", execution.ExecutionID),
-		fmt.Sprintf("CLAUDE_SESSION_ID=%s", execution.SessionID),
-	)
+	// Set environment variables; task values override inherited ones, but
+	// the built-in identifiers always win
+	cmd.Env = append(mergeEnv(os.Environ(), execution.Env), executionIDEnv(execution)...)
 
 	return cmd, nil
 }
 
+// commandLine returns the shell command running Claude with its output
+// copied to pipePath
+func (cce *ClaudeCodeExecutor) commandLine(execution *UnifiedExecution, pipePath string) string {
+	// pipefail makes Claude's exit code, not tee's, the exit code of the pipeline
+	return fmt.Sprintf("set -o pipefail; %s | tee %s", cce.buildClaudeCommand(execution), utils.ShellQuote(pipePath))
+}
+
+// executionIDEnv returns the environment identifying an execution to Claude
+func executionIDEnv(execution *UnifiedExecution) []string {
+	return executionIDVars(execution.ExecutionID, execution.SessionID)
+}
+
+// executionIDVars returns the CLAUDE_EXECUTION_ID and CLAUDE_SESSION_ID
+// variables for the given IDs
+func executionIDVars(executionID, sessionID string) []string {
+	return []string{
+		fmt.Sprintf("CLAUDE_EXECUTION_ID=%s", executionID),
+		fmt.Sprintf("CLAUDE_SESSION_ID=%s", sessionID),
+	}
+}
+
 // executeCommand starts the command and waits for completion
 func (cce *ClaudeCodeExecutor) executeCommand(cmd *exec.Cmd) (int, error) {
 	// Start the command
@@ -292,8 +369,11 @@ func (cce *ClaudeCodeExecutor) handlePostExecution(ctx context.Context, executio
 	}
 }
 
-// collectExecutionResult collects execution results and builds the final result
-func (cce *ClaudeCodeExecutor) collectExecutionResult(exitCode int, cmdErr error, logCaptureDone <-chan error, execution *UnifiedExecution) (*ExecutionResult, error) {
+// collectExecutionResult collects execution results and builds the final result.
+// Command failures, timeouts and exceeded cost budgets are returned as errors;
+// a log capture failure alone is only recorded in the result since Claude
+// itself succeeded.
+func (cce *ClaudeCodeExecutor) collectExecutionResult(ctx context.Context, exitCode int, cmdErr error, logCaptureDone <-chan error, execution *UnifiedExecution) (*ExecutionResult, error) {
 	// Wait for log capture to complete
 	logErr := <-logCaptureDone
 
@@ -305,11 +385,28 @@ func (cce *ClaudeCodeExecutor) collectExecutionResult(exitCode int, cmdErr error
 		Success:      exitCode == 0 && cmdErr == nil,
 		ExitCode:     exitCode,
 		FilesChanged: changedFiles,
+		ErrorKind:    ErrorKindNone,
 	}
 
 	// Handle command execution errors
 	if cmdErr != nil {
 		result.Error = cmdErr.Error()
+		result.ErrorKind = ErrorKindCommand
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.ErrorKind = ErrorKindTimeout
+		}
+	}
+
+	// An exceeded budget fails the execution even if Claude exited before
+	// the cancellation reached it
+	budgetErr := context.Cause(ctx)
+	if !errors.Is(budgetErr, ErrCostBudgetExceeded) {
+		budgetErr = nil
+	}
+	if budgetErr != nil {
+		result.Success = false
+		result.Error = budgetErr.Error()
+		result.ErrorKind = ErrorKindBudget
 	}
 
 	// Handle log capture errors
@@ -318,9 +415,19 @@ func (cce *ClaudeCodeExecutor) collectExecutionResult(exitCode int, cmdErr error
 			result.Error += fmt.Sprintf("; log capture error: %v", logErr)
 		} else {
 			result.Error = fmt.Sprintf("log capture error: %v", logErr)
+			result.ErrorKind = ErrorKindLogCapture
 		}
 	}
 
+	switch result.ErrorKind {
+	case ErrorKindBudget:
+		return result, fmt.Errorf("execution aborted: %w", budgetErr)
+	case ErrorKindTimeout:
+		return result, fmt.Errorf("execution timed out: %w", cmdErr)
+	case ErrorKindCommand:
+		return result, fmt.Errorf("claude command failed: %w", cmdErr)
+	}
+
 	return result, nil
 }
 
@@ -333,7 +440,7 @@ func (cce *ClaudeCodeExecutor) WatchOutput(ctx context.Context, execution *Unifi
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			fmt.Printf("Warning: failed to close log file: %v\n", err)
+			logging.Warnf("failed to close log file: %v", err)
 		}
 	}()
 
@@ -498,6 +605,13 @@ func (cce *ClaudeCodeExecutor) waitForTmuxSessionTermination(ctx context.Context
 	for {
 		select {
 		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(context.Cause(ctx), ErrCostBudgetExceeded) {
+				// The execution timed out or went over budget, so stop the
+				// session instead of leaving it running
+				if err := exec.Command("tmux", "kill-session", "-t", sessionName).Run(); err != nil {
+					logging.Warnf("failed to kill session %s: %v", sessionName, err)
+				}
+			}
 			return
 		case <-ticker.C:
 			// Check if tmux session still exists
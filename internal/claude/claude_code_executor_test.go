@@ -0,0 +1,487 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestCreateNamedPipe(t *testing.T) {
+	executor := NewClaudeCodeExecutor(&models.ClaudeConfig{})
+
+	pipePath, cleanup, err := executor.createNamedPipe("task-pipetest")
+	if err != nil {
+		t.Fatalf("createNamedPipe() failed: %v", err)
+	}
+
+	if filepath.Dir(pipePath) != filepath.Clean(os.TempDir()) {
+		t.Errorf("Expected pipe in %s, got %s", os.TempDir(), pipePath)
+	}
+
+	if _, err := os.Stat(pipePath); err != nil {
+		t.Fatalf("Pipe was not created: %v", err)
+	}
+
+	cleanup()
+
+	if _, err := os.Stat(pipePath); !os.IsNotExist(err) {
+		t.Errorf("Pipe %s still exists after cleanup", pipePath)
+	}
+}
+
+func TestClaudeCodeExecutorBuildClaudeCommandModel(t *testing.T) {
+	executor := NewClaudeCodeExecutor(&models.ClaudeConfig{Executable: "claude"})
+
+	tests := []struct {
+		name      string
+		model     string
+		wantModel bool
+	}{
+		{name: "with model", model: "sonnet", wantModel: true},
+		{name: "shell metacharacters", model: "opus; rm -rf ~", wantModel: true},
+		{name: "without model", model: "", wantModel: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := executor.buildClaudeCommand(&UnifiedExecution{Prompt: "do it", Model: tt.model})
+
+			hasFlag := strings.Contains(cmd, "--model")
+			if hasFlag != tt.wantModel {
+				t.Errorf("buildClaudeCommand() = %q, want --model present: %v", cmd, tt.wantModel)
+			}
+			if tt.wantModel {
+				assertModelArg(t, cmd, tt.model)
+			}
+		})
+	}
+}
+
+func TestClaudeCodeExecutorBuildClaudeCommandOutputFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "", want: "--output-format stream-json"},
+		{format: OutputFormatStreamJSON, want: "--output-format stream-json"},
+		{format: OutputFormatJSON, want: "--output-format json"},
+		{format: OutputFormatText, want: "--output-format text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			executor := NewClaudeCodeExecutor(&models.ClaudeConfig{Executable: "claude", OutputFormat: tt.format})
+			cmd := executor.buildClaudeCommand(&UnifiedExecution{Prompt: "do it"})
+			if !strings.Contains(cmd, tt.want) {
+				t.Errorf("buildClaudeCommand() = %q, want %q", cmd, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{"", OutputFormatText, OutputFormatJSON, OutputFormatStreamJSON} {
+		if err := ValidateOutputFormat(format); err != nil {
+			t.Errorf("ValidateOutputFormat(%q) = %v, want nil", format, err)
+		}
+	}
+	if err := ValidateOutputFormat("yaml"); err == nil {
+		t.Error("ValidateOutputFormat(\"yaml\") = nil, want error")
+	}
+}
+
+func TestClaudeCodeExecutorCaptureSingleObject(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.json")
+	response := `{
+  "type": "result",
+  "subtype": "success",
+  "result": "done",
+  "cost_usd": 0.25,
+  "duration_ms": 1500
+}
+`
+	if err := os.WriteFile(output, []byte(response), 0644); err != nil {
+		t.Fatalf("failed to write output: %v", err)
+	}
+
+	executor := NewClaudeCodeExecutor(&models.ClaudeConfig{OutputFormat: OutputFormatJSON})
+	execution := &UnifiedExecution{ExecutionID: "task-json", ExecutionType: ExecutionTypeTask}
+	logFile := filepath.Join(t.TempDir(), "exec.jsonl")
+	if err := executor.captureLogOutput(output, logFile, execution, nil); err != nil {
+		t.Fatalf("captureLogOutput() failed: %v", err)
+	}
+
+	if execution.CostUSD != 0.25 || execution.DurationMS != 1500 {
+		t.Errorf("cost = %v, duration = %d, want 0.25 and 1500", execution.CostUSD, execution.DurationMS)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("log has %d lines, want the object on one line: %q", len(lines), data)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v", err)
+	}
+	if entry["type"] != "result" || entry["execution_id"] != "task-json" {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+}
+
+func TestClaudeCodeExecutorCaptureJSONLWithJSONFormat(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.jsonl")
+	stream := `{"type":"system","subtype":"init","model":"sonnet"}` + "\n" + `{"type":"result","cost_usd":0.5}` + "\n"
+	if err := os.WriteFile(output, []byte(stream), 0644); err != nil {
+		t.Fatalf("failed to write output: %v", err)
+	}
+
+	executor := NewClaudeCodeExecutor(&models.ClaudeConfig{OutputFormat: OutputFormatJSON})
+	execution := &UnifiedExecution{ExecutionID: "task-jsonl", ExecutionType: ExecutionTypeTask}
+	logFile := filepath.Join(t.TempDir(), "exec.jsonl")
+	if err := executor.captureLogOutput(output, logFile, execution, nil); err != nil {
+		t.Fatalf("captureLogOutput() failed: %v", err)
+	}
+
+	if execution.Model != "sonnet" || execution.CostUSD != 0.5 {
+		t.Errorf("model = %q, cost = %v, want sonnet and 0.5", execution.Model, execution.CostUSD)
+	}
+}
+
+func TestClaudeCodeExecutorCapturePreservesTimestamps(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.jsonl")
+	stream := `{"type":"system","subtype":"init","timestamp":"2025-01-02T10:00:00.250Z"}` + "\n" + `{"type":"assistant"}` + "\n"
+	if err := os.WriteFile(output, []byte(stream), 0644); err != nil {
+		t.Fatalf("failed to write output: %v", err)
+	}
+
+	executor := NewClaudeCodeExecutor(&models.ClaudeConfig{})
+	execution := &UnifiedExecution{ExecutionID: "task-ts", ExecutionType: ExecutionTypeTask}
+	logFile := filepath.Join(t.TempDir(), "exec.jsonl")
+	if err := executor.captureLogOutput(output, logFile, execution, nil); err != nil {
+		t.Fatalf("captureLogOutput() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log has %d lines, want 2", len(lines))
+	}
+
+	stamped, ok := parseJSONLogLine(lines[0])
+	if !ok {
+		t.Fatalf("failed to parse %q", lines[0])
+	}
+	if stamped.Timestamp != "2025-01-02T10:00:00.250Z" {
+		t.Errorf("timestamp = %q, want the upstream timestamp", stamped.Timestamp)
+	}
+	if _, err := time.Parse(time.RFC3339, stamped.CapturedAt); err != nil {
+		t.Errorf("captured_at = %q, want an RFC3339 time: %v", stamped.CapturedAt, err)
+	}
+
+	unstamped, ok := parseJSONLogLine(lines[1])
+	if !ok {
+		t.Fatalf("failed to parse %q", lines[1])
+	}
+	if _, err := time.Parse(time.RFC3339, unstamped.Timestamp); err != nil {
+		t.Errorf("timestamp = %q, want the capture time: %v", unstamped.Timestamp, err)
+	}
+	if unstamped.CapturedAt != "" {
+		t.Errorf("captured_at = %q, want it unset without an upstream timestamp", unstamped.CapturedAt)
+	}
+}
+
+func TestValidateModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   string
+		allowed []string
+		wantErr bool
+	}{
+		{name: "empty model", model: "", allowed: []string{"sonnet"}, wantErr: false},
+		{name: "empty allowlist", model: "opus", allowed: nil, wantErr: false},
+		{name: "allowed model", model: "sonnet", allowed: []string{"opus", "sonnet"}, wantErr: false},
+		{name: "disallowed model", model: "haiku", allowed: []string{"opus", "sonnet"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateModel(tt.model, tt.allowed)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// failingPipeSystem is a SystemInterface whose named pipe creation fails
+type failingPipeSystem struct{}
+
+func (failingPipeSystem) CreateNamedPipe(path string, mode uint32) error {
+	return errors.New("mkfifo not permitted")
+}
+
+func (failingPipeSystem) RemoveFile(path string) error { return nil }
+
+func (failingPipeSystem) NotifySignal(c chan<- os.Signal, signals ...os.Signal) {}
+
+func (failingPipeSystem) LookPath(file string) (string, error) { return file, nil }
+
+// writeFakeClaude writes a shell script that stands in for the Claude CLI
+func writeFakeClaude(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake claude: %v", err)
+	}
+	return path
+}
+
+func TestClaudeCodeExecutorErrorKinds(t *testing.T) {
+	tests := []struct {
+		name      string
+		script    string
+		badLogDir bool
+		timeout   time.Duration
+		system    bool // use failingPipeSystem
+		worktree  string
+		wantKind  ErrorKind
+		wantErr   bool
+	}{
+		{
+			name:     "success",
+			script:   `echo '{"type":"result","result":"ok"}'`,
+			wantKind: ErrorKindNone,
+		},
+		{
+			name:     "command exits non-zero",
+			script:   `echo '{"type":"assistant"}'; exit 3`,
+			wantKind: ErrorKindCommand,
+			wantErr:  true,
+		},
+		{
+			name:      "log capture failure",
+			script:    `exit 0`,
+			badLogDir: true,
+			wantKind:  ErrorKindLogCapture,
+		},
+		{
+			name:     "timeout",
+			script:   `sleep 1`,
+			timeout:  100 * time.Millisecond,
+			wantKind: ErrorKindTimeout,
+			wantErr:  true,
+		},
+		{
+			name:     "pipe setup failure",
+			system:   true,
+			wantKind: ErrorKindSetup,
+			wantErr:  true,
+		},
+		{
+			name:     "missing worktree",
+			worktree: "does-not-exist",
+			wantKind: ErrorKindSetup,
+			wantErr:  true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+
+			config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: writeFakeClaude(t, tt.script)}
+			executor := NewClaudeCodeExecutor(config)
+			if tt.system {
+				executor = NewClaudeCodeExecutorWithSystem(config, failingPipeSystem{})
+			}
+
+			execution := &UnifiedExecution{
+				ExecutionID:   fmt.Sprintf("task-errkind-%d-%d", os.Getpid(), i),
+				ExecutionType: ExecutionTypeTask,
+				WorkingDir:    t.TempDir(),
+				Repository:    t.TempDir(),
+				Prompt:        "test",
+			}
+			if tt.worktree != "" {
+				execution.TaskInfo = &TaskExecutionInfo{Worktree: tt.worktree}
+			}
+
+			logFile := filepath.Join(t.TempDir(), "exec.jsonl")
+			if tt.badLogDir {
+				logFile = filepath.Join(t.TempDir(), "missing", "exec.jsonl")
+			}
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			result, err := executor.Execute(ctx, execution, logFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result == nil {
+				t.Fatal("Execute() returned nil result")
+			}
+			if result.ErrorKind != tt.wantKind {
+				t.Errorf("ErrorKind = %q, want %q (error: %s)", result.ErrorKind, tt.wantKind, result.Error)
+			}
+			if (tt.wantKind == ErrorKindNone) != (result.Error == "") {
+				t.Errorf("Error = %q inconsistent with kind %q", result.Error, result.ErrorKind)
+			}
+		})
+	}
+}
+
+func TestClaudeCodeExecutorTimeout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: writeFakeClaude(t, `sleep 30`)}
+	executor := NewClaudeCodeExecutor(config)
+	execution := &UnifiedExecution{
+		ExecutionID:   fmt.Sprintf("task-timeout-%d", os.Getpid()),
+		ExecutionType: ExecutionTypeTask,
+		WorkingDir:    t.TempDir(),
+		Repository:    t.TempDir(),
+		Prompt:        "test",
+		Timeout:       200 * time.Millisecond,
+	}
+
+	start := time.Now()
+	result, err := executor.Execute(context.Background(), execution, filepath.Join(t.TempDir(), "exec.jsonl"))
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Execute() took %s, want the command killed at the timeout", elapsed)
+	}
+	if err == nil {
+		t.Fatal("Execute() succeeded, want timeout error")
+	}
+	if result == nil || result.ErrorKind != ErrorKindTimeout {
+		t.Errorf("result = %+v, want error kind %s", result, ErrorKindTimeout)
+	}
+}
+
+func TestClaudeCodeExecutorCostBudget(t *testing.T) {
+	// Costs are streamed before the result; the last increment crosses the
+	// budget while Claude keeps running
+	script := `echo '{"type":"assistant","cost_usd":0.2}'
+echo '{"type":"assistant","cost_usd":0.2}'
+echo '{"type":"assistant","cost_usd":0.2}'
+sleep 30
+echo '{"type":"result","result":"ok"}'`
+
+	tests := []struct {
+		name          string
+		configMaxCost float64
+		taskMaxCost   float64
+		wantBudget    bool
+		wantSpent     string
+	}{
+		{name: "config budget", configMaxCost: 0.5, wantBudget: true, wantSpent: "spent $0.6000 of $0.5000"},
+		{name: "task budget overrides config", configMaxCost: 10, taskMaxCost: 0.3, wantBudget: true, wantSpent: "spent $0.4000 of $0.3000"},
+		{name: "within budget", configMaxCost: 1},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+
+			config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: writeFakeClaude(t, script), MaxCostUSD: tt.configMaxCost}
+			execution := &UnifiedExecution{
+				ExecutionID:   fmt.Sprintf("task-budget-%d-%d", os.Getpid(), i),
+				ExecutionType: ExecutionTypeTask,
+				WorkingDir:    t.TempDir(),
+				Repository:    t.TempDir(),
+				Prompt:        "test",
+				MaxCostUSD:    tt.taskMaxCost,
+			}
+			timeout := 10 * time.Second
+			if !tt.wantBudget {
+				timeout = 500 * time.Millisecond // Only the timeout stops it
+			}
+			execution.Timeout = timeout
+
+			start := time.Now()
+			result, err := NewClaudeCodeExecutor(config).Execute(context.Background(), execution, filepath.Join(t.TempDir(), "exec.jsonl"))
+			if !tt.wantBudget {
+				if errors.Is(err, ErrCostBudgetExceeded) || result.ErrorKind != ErrorKindTimeout {
+					t.Errorf("Execute() = %+v, %v, want only a timeout", result, err)
+				}
+				return
+			}
+
+			if elapsed := time.Since(start); elapsed >= timeout {
+				t.Errorf("Execute() took %s, want the command cancelled when the budget was exceeded", elapsed)
+			}
+			if !errors.Is(err, ErrCostBudgetExceeded) {
+				t.Errorf("Execute() error = %v, want %v", err, ErrCostBudgetExceeded)
+			}
+			if result == nil || result.ErrorKind != ErrorKindBudget || result.Success {
+				t.Fatalf("result = %+v, want a failure of kind %s", result, ErrorKindBudget)
+			}
+			if !strings.Contains(result.Error, tt.wantSpent) {
+				t.Errorf("result.Error = %q, want %q", result.Error, tt.wantSpent)
+			}
+		})
+	}
+}
+
+func TestClaudeCodeExecutorPassesPromptVerbatim(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	promptFile := filepath.Join(t.TempDir(), "prompt.txt")
+	script := `cat > '` + promptFile + `'`
+	config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: writeFakeClaude(t, script)}
+
+	prompt := "Fix Bob's \"flaky\" test\nthen run `make test` and echo $HOME\n\\done"
+	execution := &UnifiedExecution{
+		ExecutionID:   fmt.Sprintf("task-prompt-%d", os.Getpid()),
+		ExecutionType: ExecutionTypeTask,
+		WorkingDir:    t.TempDir(),
+		Repository:    t.TempDir(),
+		Prompt:        prompt,
+	}
+
+	if _, err := NewClaudeCodeExecutor(config).Execute(context.Background(), execution, filepath.Join(t.TempDir(), "exec.jsonl")); err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+
+	got, err := os.ReadFile(promptFile)
+	if err != nil {
+		t.Fatalf("failed to read prompt: %v", err)
+	}
+	if string(got) != prompt {
+		t.Errorf("prompt received by claude = %q, want %q", got, prompt)
+	}
+}
+
+func TestErrorKindRetryable(t *testing.T) {
+	for kind, want := range map[ErrorKind]bool{
+		ErrorKindSetup:      false,
+		ErrorKindCommand:    true,
+		ErrorKindLogCapture: true,
+		ErrorKindTimeout:    true,
+		ErrorKindBudget:     false,
+		"":                  true,
+	} {
+		if got := kind.Retryable(); got != want {
+			t.Errorf("ErrorKind(%q).Retryable() = %v, want %v", kind, got, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCostBudgetExceeded is the cancellation cause of an execution whose
+// streamed cost went over its budget
+var ErrCostBudgetExceeded = errors.New("cost budget exceeded")
+
+// costBudget tracks the running cost of an execution from its output stream
+// and cancels the execution once the cost goes over the limit
+type costBudget struct {
+	limit  float64
+	total  float64
+	cancel context.CancelCauseFunc
+
+	exceeded bool
+}
+
+// newCostBudget returns a budget of limit USD calling cancel when exceeded,
+// or nil when limit is not positive
+func newCostBudget(limit float64, cancel context.CancelCauseFunc) *costBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &costBudget{limit: limit, cancel: cancel}
+}
+
+// effectiveMaxCost returns the budget of an execution: its own limit when
+// set, otherwise the configured default
+func effectiveMaxCost(taskLimit, configLimit float64) float64 {
+	if taskLimit > 0 {
+		return taskLimit
+	}
+	return configLimit
+}
+
+// observe adds the cost reported by a stream entry to the running total.
+// cost_usd values accumulate, while total_cost_usd covers the whole session
+// and replaces the total. The execution is cancelled the first time the total
+// goes over the limit. A nil budget ignores every entry.
+func (b *costBudget) observe(entry map[string]interface{}) {
+	if b == nil || b.exceeded {
+		return
+	}
+
+	if total, ok := entry["total_cost_usd"].(float64); ok && total > 0 {
+		b.total = total
+	} else if cost, ok := entry["cost_usd"].(float64); ok && cost > 0 {
+		b.total += cost
+	} else {
+		return
+	}
+
+	if b.total > b.limit {
+		b.exceeded = true
+		b.cancel(fmt.Errorf("%w: spent $%.4f of $%.4f", ErrCostBudgetExceeded, b.total, b.limit))
+	}
+}
@@ -0,0 +1,59 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCostBudgetObserve(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	cancels := 0
+	budget := newCostBudget(1.0, func(cause error) {
+		cancels++
+		cancel(cause)
+	})
+
+	steps := []struct {
+		entry     map[string]interface{}
+		wantTotal float64
+		cancelled bool
+	}{
+		{entry: map[string]interface{}{"type": "system"}, wantTotal: 0},
+		{entry: map[string]interface{}{"type": "assistant", "cost_usd": 0.4}, wantTotal: 0.4},
+		{entry: map[string]interface{}{"type": "assistant", "cost_usd": 0.5}, wantTotal: 0.9},
+		{entry: map[string]interface{}{"type": "assistant", "total_cost_usd": 0.95}, wantTotal: 0.95},
+		{entry: map[string]interface{}{"type": "assistant", "cost_usd": 0.1}, wantTotal: 1.05, cancelled: true},
+		{entry: map[string]interface{}{"type": "result", "cost_usd": 2.0}, wantTotal: 1.05, cancelled: true},
+	}
+	for i, step := range steps {
+		budget.observe(step.entry)
+		if diff := budget.total - step.wantTotal; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("step %d: total = %v, want %v", i, budget.total, step.wantTotal)
+		}
+		if got := ctx.Err() != nil; got != step.cancelled {
+			t.Errorf("step %d: cancelled = %v, want %v", i, got, step.cancelled)
+		}
+	}
+
+	if cancels != 1 {
+		t.Errorf("cancel called %d times, want once", cancels)
+	}
+	if cause := context.Cause(ctx); !errors.Is(cause, ErrCostBudgetExceeded) {
+		t.Errorf("cancellation cause = %v, want %v", cause, ErrCostBudgetExceeded)
+	}
+
+	// No limit means no budget, which ignores everything
+	none := newCostBudget(0, nil)
+	none.observe(map[string]interface{}{"cost_usd": 100.0})
+}
+
+func TestEffectiveMaxCost(t *testing.T) {
+	if got := effectiveMaxCost(0, 5); got != 5 {
+		t.Errorf("effectiveMaxCost(0, 5) = %v, want the config limit", got)
+	}
+	if got := effectiveMaxCost(2, 5); got != 2 {
+		t.Errorf("effectiveMaxCost(2, 5) = %v, want the task limit", got)
+	}
+}
@@ -2,13 +2,28 @@ package claude
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
+	"sync"
+	"time"
 )
 
-// DependencyGraph manages task dependencies and execution order.
+// DependencyGraph manages task dependencies and execution order. It is safe
+// for concurrent use; the task worker updates it from each running task.
 type DependencyGraph struct {
+	mu    sync.Mutex
 	tasks map[string]*Task
 	edges map[string][]string // task_id -> dependencies
+	now   func() time.Time    // Clock used for retry backoff and aging, replaceable in tests
+	aging PriorityAging
+}
+
+// PriorityAging raises the effective priority of tasks that wait in the
+// queue, so low priority tasks are not starved by newer high priority ones.
+type PriorityAging struct {
+	Threshold time.Duration // Time in queue before aging starts
+	Rate      float64       // Priority points gained per hour beyond Threshold (0 disables aging)
 }
 
 // NewDependencyGraph creates a new dependency graph.
@@ -16,11 +31,59 @@ func NewDependencyGraph() *DependencyGraph {
 	return &DependencyGraph{
 		tasks: make(map[string]*Task),
 		edges: make(map[string][]string),
+		now:   time.Now,
+	}
+}
+
+// SetPriorityAging configures how waiting tasks gain priority over time.
+func (dg *DependencyGraph) SetPriorityAging(aging PriorityAging) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	dg.aging = aging
+}
+
+// EffectivePriority returns the task priority including any aging bonus.
+func (dg *DependencyGraph) EffectivePriority(task *Task) float64 {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.effectivePriority(task, dg.now())
+}
+
+// effectivePriority computes the aged priority of a task at the given time.
+func (dg *DependencyGraph) effectivePriority(task *Task, now time.Time) float64 {
+	priority := float64(task.Priority)
+	if dg.aging.Rate <= 0 || task.CreatedAt.IsZero() {
+		return priority
+	}
+
+	aged := now.Sub(task.CreatedAt) - dg.aging.Threshold
+	if aged <= 0 {
+		return priority
 	}
+	return priority + dg.aging.Rate*aged.Hours()
+}
+
+// sortByEffectivePriority orders tasks by effective priority (highest first),
+// then by creation time (oldest first) and ID so the order is deterministic.
+func (dg *DependencyGraph) sortByEffectivePriority(tasks []*Task) {
+	now := dg.now()
+	sort.Slice(tasks, func(i, j int) bool {
+		pi, pj := dg.effectivePriority(tasks[i], now), dg.effectivePriority(tasks[j], now)
+		if pi != pj {
+			return pi > pj
+		}
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
 }
 
 // AddTask adds a task to the dependency graph.
 func (dg *DependencyGraph) AddTask(task *Task) error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
 	if task.ID == "" {
 		return fmt.Errorf("task ID cannot be empty")
 	}
@@ -45,6 +108,13 @@ func (dg *DependencyGraph) AddTask(task *Task) error {
 
 // ValidateDependencies checks for circular dependencies and missing dependencies.
 func (dg *DependencyGraph) ValidateDependencies() error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.validateDependencies()
+}
+
+// validateDependencies implements ValidateDependencies; dg.mu must be held.
+func (dg *DependencyGraph) validateDependencies() error {
 	// Check for missing dependencies
 	for taskID, deps := range dg.edges {
 		for _, depID := range deps {
@@ -91,33 +161,42 @@ func (dg *DependencyGraph) hasCycle(taskID string, visited, recStack map[string]
 
 // GetExecutableTask returns the highest priority task that is ready to run.
 func (dg *DependencyGraph) GetExecutableTask() (*Task, error) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
 	readyTasks := dg.getReadyTasks()
 
 	if len(readyTasks) == 0 {
 		return nil, fmt.Errorf("no executable tasks available")
 	}
 
-	// Sort by priority (highest first), then by creation time (oldest first)
-	sort.Slice(readyTasks, func(i, j int) bool {
-		if readyTasks[i].Priority == readyTasks[j].Priority {
-			return readyTasks[i].CreatedAt.Before(readyTasks[j].CreatedAt)
-		}
-		return readyTasks[i].Priority > readyTasks[j].Priority
-	})
+	dg.sortByEffectivePriority(readyTasks)
 
 	return readyTasks[0], nil
 }
 
-// GetReadyTasks returns all tasks that are ready to execute.
+// GetReadyTasks returns all tasks that are ready to execute, highest
+// effective priority first.
 func (dg *DependencyGraph) GetReadyTasks() []*Task {
-	return dg.getReadyTasks()
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	readyTasks := dg.getReadyTasks()
+	dg.sortByEffectivePriority(readyTasks)
+	return readyTasks
 }
 
 // getReadyTasks finds tasks that have no pending dependencies.
 func (dg *DependencyGraph) getReadyTasks() []*Task {
 	var readyTasks []*Task
+	now := dg.now()
 
 	for taskID, task := range dg.tasks {
+		// Tasks waiting out a retry backoff are not ready yet
+		if task.NextRetryAt != nil && now.Before(*task.NextRetryAt) {
+			continue
+		}
+
 		if task.Status == StatusPending && dg.areDependenciesCompleted(taskID) {
 			readyTasks = append(readyTasks, task)
 		}
@@ -152,6 +231,8 @@ func (dg *DependencyGraph) areDependenciesCompleted(taskID string) bool {
 				return false
 			case DependencyPolicyWait:
 				return false // Keep waiting
+			case DependencyPolicyContinue:
+				continue // Run anyway; StartTask records the failure
 			}
 		default:
 			return false // Still pending/running
@@ -161,9 +242,187 @@ func (dg *DependencyGraph) areDependenciesCompleted(taskID string) bool {
 	return true
 }
 
+// DetectDeadlock returns the IDs of the pending tasks when none of them can
+// ever run: no task is running and every pending task is transitively blocked
+// by a failed dependency under DependencyPolicyWait, or by a skipped, cancelled,
+// aborted or missing one. It returns nil whenever some task can still make
+// progress. Cycles are not reported here; ValidateDependencies catches them.
+func (dg *DependencyGraph) DetectDeadlock() []string {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	blocked := make(map[string]bool)
+	visiting := make(map[string]bool)
+
+	var deadlocked []string
+	for taskID, task := range dg.tasks {
+		switch task.Status {
+		case StatusRunning:
+			return nil // Its completion may unblock other tasks
+		case StatusPending, StatusWaiting:
+			if !dg.isBlockedForever(taskID, blocked, visiting) {
+				return nil
+			}
+			deadlocked = append(deadlocked, taskID)
+		}
+	}
+
+	sort.Strings(deadlocked)
+	return deadlocked
+}
+
+// isBlockedForever reports whether a pending task can never become ready.
+// Results are memoized in blocked; tasks on a cycle are treated as unblocked.
+func (dg *DependencyGraph) isBlockedForever(taskID string, blocked, visiting map[string]bool) bool {
+	if result, ok := blocked[taskID]; ok {
+		return result
+	}
+	if visiting[taskID] {
+		return false
+	}
+	visiting[taskID] = true
+	defer delete(visiting, taskID)
+
+	result := dg.hasBlockingDependency(dg.tasks[taskID], blocked, visiting)
+	blocked[taskID] = result
+	return result
+}
+
+// hasBlockingDependency mirrors areDependenciesCompleted without changing any
+// task status, reporting whether a dependency will never be satisfied.
+func (dg *DependencyGraph) hasBlockingDependency(task *Task, blocked, visiting map[string]bool) bool {
+	for _, depID := range task.DependsOn {
+		depTask, exists := dg.tasks[depID]
+		if !exists {
+			return true
+		}
+
+		switch depTask.Status {
+		case StatusCompleted, StatusRunning:
+			continue
+		case StatusFailed:
+			switch task.DependencyPolicy {
+			case DependencyPolicyWait:
+				return true
+			case DependencyPolicyFail, DependencyPolicySkip:
+				return false // The task is resolved on the next readiness check
+			}
+		case StatusPending, StatusWaiting:
+			if dg.isBlockedForever(depID, blocked, visiting) {
+				return true
+			}
+		default:
+			return true // Skipped, cancelled and aborted tasks never complete
+		}
+	}
+
+	return false
+}
+
+// maxRetryBackoff caps the retry backoff as it doubles with each retry.
+const maxRetryBackoff = time.Hour
+
+// ScheduleRetry resets a failed task to pending if it has retries left.
+// The task becomes ready again once its backoff has elapsed; the backoff
+// doubles with each retry, up to maxRetryBackoff. It returns false when
+// retries are exhausted.
+func (dg *DependencyGraph) ScheduleRetry(taskID string) bool {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	task, exists := dg.tasks[taskID]
+	if !exists {
+		return false
+	}
+	return dg.scheduleRetry(task)
+}
+
+// scheduleRetry implements ScheduleRetry for a task that may not be in the
+// graph yet.
+func (dg *DependencyGraph) scheduleRetry(task *Task) bool {
+	if task.Result == nil {
+		task.Result = &TaskResult{}
+	}
+	if task.Result.RetryCount >= task.MaxRetries {
+		return false
+	}
+
+	// Double by steps rather than shifting, which overflows after enough retries
+	backoff := task.RetryBackoff
+	for range task.Result.RetryCount {
+		if backoff >= maxRetryBackoff {
+			break
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+	task.Result.RetryCount++
+
+	nextRetry := dg.now().Add(backoff)
+	task.NextRetryAt = &nextRetry
+	task.Status = StatusPending
+	task.CompletedAt = nil
+
+	return true
+}
+
+// StartTask marks a task as running and returns a copy of it for the caller
+// to execute, so running the task never touches the graph's own copy. The
+// copy's result lists the failed dependencies a DependencyPolicyContinue task
+// runs despite. The result is handed back with FinishTask.
+func (dg *DependencyGraph) StartTask(taskID string) (*Task, bool) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	task, exists := dg.tasks[taskID]
+	if !exists {
+		return nil, false
+	}
+	task.Status = StatusRunning
+
+	started := cloneTask(task)
+	if task.DependencyPolicy == DependencyPolicyContinue {
+		if started.Result == nil {
+			started.Result = &TaskResult{}
+		}
+		started.Result.DependencyFailures = dg.failedDependencies(task)
+	}
+	return started, true
+}
+
+// failedDependencies returns the IDs of the failed dependencies of a task.
+func (dg *DependencyGraph) failedDependencies(task *Task) []string {
+	var failed []string
+	for _, depID := range task.DependsOn {
+		if depTask, exists := dg.tasks[depID]; exists && depTask.Status == StatusFailed {
+			failed = appendUnique(failed, depID)
+		}
+	}
+	return failed
+}
+
+// FinishTask stores a task returned by StartTask once it has run. A failed
+// task is put back to pending in the same step when retryable is set and it
+// has retries left, so other tasks never see it as failed in between. It
+// reports whether a retry was scheduled.
+func (dg *DependencyGraph) FinishTask(task *Task, retryable bool) bool {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	retried := task.Status == StatusFailed && retryable && dg.scheduleRetry(task)
+	dg.tasks[task.ID] = task
+	return retried
+}
+
 // GetTopologicalOrder returns tasks in topological order (dependencies first).
 func (dg *DependencyGraph) GetTopologicalOrder() ([]*Task, error) {
-	if err := dg.ValidateDependencies(); err != nil {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.topologicalOrder()
+}
+
+// topologicalOrder implements GetTopologicalOrder; dg.mu must be held.
+func (dg *DependencyGraph) topologicalOrder() ([]*Task, error) {
+	if err := dg.validateDependencies(); err != nil {
 		return nil, err
 	}
 
@@ -227,8 +486,49 @@ func (dg *DependencyGraph) GetTopologicalOrder() ([]*Task, error) {
 	return result, nil
 }
 
+// TopologicalLevels groups tasks into dependency levels: level 0 holds tasks
+// without dependencies and level k the tasks whose dependencies all sit in
+// earlier levels. Tasks in one level are independent of each other and can
+// be dispatched together. Within a level tasks keep their topological
+// (priority) order.
+func (dg *DependencyGraph) TopologicalLevels() ([][]*Task, error) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	order, err := dg.topologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	level := make(map[string]int, len(order))
+	var levels [][]*Task
+	for _, task := range order {
+		l := 0
+		for _, depID := range dg.edges[task.ID] {
+			if level[depID]+1 > l {
+				l = level[depID] + 1
+			}
+		}
+		level[task.ID] = l
+
+		if l == len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[l] = append(levels[l], task)
+	}
+
+	return levels, nil
+}
+
 // GetDependents returns tasks that depend on the given task.
 func (dg *DependencyGraph) GetDependents(taskID string) []*Task {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.getDependents(taskID)
+}
+
+// getDependents implements GetDependents; dg.mu must be held.
+func (dg *DependencyGraph) getDependents(taskID string) []*Task {
 	var dependents []*Task
 
 	for id, task := range dg.tasks {
@@ -245,6 +545,13 @@ func (dg *DependencyGraph) GetDependents(taskID string) []*Task {
 
 // GetDependencies returns tasks that the given task depends on.
 func (dg *DependencyGraph) GetDependencies(taskID string) []*Task {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.getDependencies(taskID)
+}
+
+// getDependencies implements GetDependencies; dg.mu must be held.
+func (dg *DependencyGraph) getDependencies(taskID string) []*Task {
 	task, exists := dg.tasks[taskID]
 	if !exists {
 		return nil
@@ -260,8 +567,20 @@ func (dg *DependencyGraph) GetDependencies(taskID string) []*Task {
 	return dependencies
 }
 
+// GetTask returns the task with the given ID from the dependency graph.
+func (dg *DependencyGraph) GetTask(taskID string) (*Task, bool) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	task, ok := dg.tasks[taskID]
+	return task, ok
+}
+
 // UpdateTask updates a task in the dependency graph.
 func (dg *DependencyGraph) UpdateTask(task *Task) error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
 	if task.ID == "" {
 		return fmt.Errorf("task ID cannot be empty")
 	}
@@ -275,6 +594,9 @@ func (dg *DependencyGraph) UpdateTask(task *Task) error {
 
 // RemoveTask removes a task from the dependency graph.
 func (dg *DependencyGraph) RemoveTask(taskID string) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
 	delete(dg.tasks, taskID)
 	delete(dg.edges, taskID)
 
@@ -291,8 +613,109 @@ func (dg *DependencyGraph) RemoveTask(taskID string) {
 	}
 }
 
+// Subgraph returns a new graph containing rootID and all of its transitive
+// dependencies. Tasks are deep-copied, so the subgraph can be modified without
+// affecting this graph.
+func (dg *DependencyGraph) Subgraph(rootID string) (*DependencyGraph, error) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	if _, exists := dg.tasks[rootID]; !exists {
+		return nil, fmt.Errorf("task %s not found", rootID)
+	}
+
+	included := make(map[string]bool)
+	stack := []string{rootID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if included[id] {
+			continue
+		}
+		included[id] = true
+		for _, dep := range dg.getDependencies(id) {
+			stack = append(stack, dep.ID)
+		}
+	}
+
+	sub := NewDependencyGraph()
+	sub.now = dg.now
+	sub.aging = dg.aging
+	for id := range included {
+		task := cloneTask(dg.tasks[id])
+		// Only keep blocked tasks that are part of the subgraph
+		task.Blocks = slices.DeleteFunc(task.Blocks, func(blocked string) bool {
+			return !included[blocked]
+		})
+		sub.tasks[id] = task
+		sub.edges[id] = slices.Clone(dg.edges[id])
+	}
+
+	if err := sub.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// Clone returns a deep copy of the graph. Tasks in the copy can be mutated,
+// for example to simulate scheduling, without affecting this graph.
+func (dg *DependencyGraph) Clone() *DependencyGraph {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	clone := NewDependencyGraph()
+	clone.now = dg.now
+	clone.aging = dg.aging
+	for id, task := range dg.tasks {
+		clone.tasks[id] = cloneTask(task)
+	}
+	for id, deps := range dg.edges {
+		clone.edges[id] = slices.Clone(deps)
+	}
+	return clone
+}
+
+// SimulateSchedule predicts the order in which the pending tasks run,
+// assuming every task succeeds. Each wave holds the IDs of the tasks that
+// become ready together, highest effective priority first. Running tasks are
+// assumed to finish before the first wave and retry backoffs to have elapsed.
+// Tasks that can never become ready, because of a deadlock or a cycle, are
+// left out. The graph itself is not modified.
+func (dg *DependencyGraph) SimulateSchedule() [][]string {
+	sim := dg.Clone()
+	for _, task := range sim.tasks {
+		task.NextRetryAt = nil
+		if task.Status == StatusRunning {
+			task.Status = StatusCompleted
+		}
+	}
+
+	var waves [][]string
+	for {
+		ready := sim.GetReadyTasks()
+		if len(ready) == 0 {
+			return waves
+		}
+
+		wave := make([]string, 0, len(ready))
+		for _, task := range ready {
+			wave = append(wave, task.ID)
+		}
+		// Complete the wave only once it is recorded, so tasks that depend
+		// on it land in a later wave
+		for _, task := range ready {
+			task.Status = StatusCompleted
+		}
+		waves = append(waves, wave)
+	}
+}
+
 // GetDependencyDepth returns the maximum dependency depth for the graph.
 func (dg *DependencyGraph) GetDependencyDepth() int {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
 	maxDepth := 0
 
 	for taskID := range dg.tasks {
@@ -305,6 +728,56 @@ func (dg *DependencyGraph) GetDependencyDepth() int {
 	return maxDepth
 }
 
+// GetCriticalPath returns the longest dependency chain, dependencies first.
+// Each task is weighted by its recorded Result.Duration when available and
+// by a single unit otherwise, so a graph without history yields the chain
+// with the most tasks.
+func (dg *DependencyGraph) GetCriticalPath() ([]*Task, error) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	order, err := dg.topologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	// Longest weighted distance ending at each task, and its predecessor on that path
+	dist := make(map[string]time.Duration, len(order))
+	prev := make(map[string]string, len(order))
+	var end string
+
+	for _, task := range order {
+		var best time.Duration
+		for _, depID := range dg.edges[task.ID] {
+			if prev[task.ID] == "" || dist[depID] > best {
+				best = dist[depID]
+				prev[task.ID] = depID
+			}
+		}
+		dist[task.ID] = best + criticalPathWeight(task)
+
+		if end == "" || dist[task.ID] > dist[end] {
+			end = task.ID
+		}
+	}
+
+	var path []*Task
+	for id := end; id != ""; id = prev[id] {
+		path = append(path, dg.tasks[id])
+	}
+	slices.Reverse(path)
+
+	return path, nil
+}
+
+// criticalPathWeight returns the weight of a task on the critical path.
+func criticalPathWeight(task *Task) time.Duration {
+	if task.Result != nil && task.Result.Duration > 0 {
+		return task.Result.Duration
+	}
+	return 1
+}
+
 // calculateDepth calculates the dependency depth for a specific task.
 func (dg *DependencyGraph) calculateDepth(taskID string, visited map[string]bool) int {
 	if visited[taskID] {
@@ -327,6 +800,37 @@ func (dg *DependencyGraph) calculateDepth(taskID string, visited map[string]bool
 
 // Helper functions
 
+// cloneTask returns a deep copy of task.
+func cloneTask(task *Task) *Task {
+	clone := *task
+	clone.Tags = slices.Clone(task.Tags)
+	clone.Env = maps.Clone(task.Env)
+	clone.SecretEnv = slices.Clone(task.SecretEnv)
+	clone.DependsOn = slices.Clone(task.DependsOn)
+	clone.Blocks = slices.Clone(task.Blocks)
+	clone.FilesToFocus = slices.Clone(task.FilesToFocus)
+	clone.VerificationCommands = slices.Clone(task.VerificationCommands)
+	clone.StartedAt = cloneTime(task.StartedAt)
+	clone.CompletedAt = cloneTime(task.CompletedAt)
+	clone.NextRetryAt = cloneTime(task.NextRetryAt)
+	if task.Result != nil {
+		result := *task.Result
+		result.FilesChanged = slices.Clone(task.Result.FilesChanged)
+		result.DependencyFailures = slices.Clone(task.Result.DependencyFailures)
+		clone.Result = &result
+	}
+	return &clone
+}
+
+// cloneTime copies an optional timestamp.
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
 // appendUnique adds an item to a slice if it's not already present.
 func appendUnique(slice []string, item string) []string {
 	for _, s := range slice {
@@ -0,0 +1,147 @@
+package claude
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// graphStatusColors maps task statuses to node fill colors for graph exports.
+var graphStatusColors = map[Status]string{
+	StatusPending:   "#e0e0e0",
+	StatusWaiting:   "#fff3b0",
+	StatusRunning:   "#9ecbff",
+	StatusCompleted: "#b5e8b0",
+	StatusFailed:    "#f4a6a6",
+	StatusSkipped:   "#d7c4f0",
+	StatusCancelled: "#c8c8c8",
+	StatusAborted:   "#f7c59f",
+}
+
+// graphShortIDLength is how many characters of a task ID appear in node labels.
+const graphShortIDLength = 8
+
+// WriteDOT writes the graph in Graphviz DOT format, with edges pointing from
+// each dependency to its dependents.
+func (dg *DependencyGraph) WriteDOT(w io.Writer) error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("digraph tasks {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\"];\n")
+
+	ids := dg.sortedTaskIDs()
+	for _, id := range ids {
+		task := dg.tasks[id]
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%q];\n", dotQuote(id), dotQuote(graphNodeLabel(task, "\n")), graphStatusColor(task.Status))
+	}
+	for _, id := range ids {
+		for _, dependent := range dg.sortedDependents(id) {
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(id), dotQuote(dependent.ID))
+		}
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart, with edges pointing
+// from each dependency to its dependents.
+func (dg *DependencyGraph) WriteMermaid(w io.Writer) error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	// Task IDs may contain characters Mermaid treats as syntax, so nodes use positional IDs
+	ids := dg.sortedTaskIDs()
+	nodeIDs := make(map[string]string, len(ids))
+	for i, id := range ids {
+		nodeIDs[id] = fmt.Sprintf("t%d", i)
+	}
+
+	usedStatuses := make(map[Status]bool)
+	for _, id := range ids {
+		task := dg.tasks[id]
+		label := strings.ReplaceAll(graphNodeLabel(task, "<br/>"), `"`, "#quot;")
+		fmt.Fprintf(&b, "  %s[\"%s\"]:::%s\n", nodeIDs[id], label, mermaidClass(task.Status))
+		usedStatuses[task.Status] = true
+	}
+	for _, id := range ids {
+		for _, dependent := range dg.sortedDependents(id) {
+			fmt.Fprintf(&b, "  %s --> %s\n", nodeIDs[id], nodeIDs[dependent.ID])
+		}
+	}
+
+	statuses := make([]string, 0, len(usedStatuses))
+	for status := range usedStatuses {
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "  classDef %s fill:%s\n", mermaidClass(Status(status)), graphStatusColor(Status(status)))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sortedTaskIDs returns the IDs of all tasks in the graph in lexical order.
+func (dg *DependencyGraph) sortedTaskIDs() []string {
+	ids := make([]string, 0, len(dg.tasks))
+	for id := range dg.tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sortedDependents returns the dependents of a task ordered by ID.
+func (dg *DependencyGraph) sortedDependents(taskID string) []*Task {
+	dependents := dg.getDependents(taskID)
+	sort.Slice(dependents, func(i, j int) bool {
+		return dependents[i].ID < dependents[j].ID
+	})
+	return dependents
+}
+
+// graphNodeLabel returns the task name and short ID separated by sep.
+func graphNodeLabel(task *Task, sep string) string {
+	shortID := task.ID
+	if len(shortID) > graphShortIDLength {
+		shortID = shortID[:graphShortIDLength]
+	}
+	if task.Name == "" {
+		return shortID
+	}
+	return task.Name + sep + "(" + shortID + ")"
+}
+
+// graphStatusColor returns the fill color for a task status.
+func graphStatusColor(status Status) string {
+	if color, ok := graphStatusColors[status]; ok {
+		return color
+	}
+	return "#ffffff"
+}
+
+// mermaidClass returns the Mermaid class name used for a task status.
+func mermaidClass(status Status) string {
+	if status == "" {
+		return "unknown"
+	}
+	return string(status)
+}
+
+// dotQuote quotes a string as a DOT identifier.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
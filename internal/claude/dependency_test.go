@@ -1,6 +1,10 @@
 package claude
 
 import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -290,6 +294,54 @@ func TestGetExecutableTask(t *testing.T) {
 	}
 }
 
+func TestGetExecutableTaskPriorityAging(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	aging := PriorityAging{Threshold: time.Hour, Rate: 5}
+
+	tests := []struct {
+		name  string
+		aging PriorityAging
+		age   time.Duration
+		want  string
+	}{
+		{name: "within threshold", aging: aging, age: 30 * time.Minute, want: "fresh-normal"},
+		{name: "aging not enough yet", aging: aging, age: 2 * time.Hour, want: "fresh-normal"},
+		{name: "equal effective priority falls back to created at", aging: aging, age: 6 * time.Hour, want: "old-low"},
+		{name: "old task overtakes", aging: aging, age: 12 * time.Hour, want: "old-low"},
+		{name: "aging disabled", aging: PriorityAging{}, age: 48 * time.Hour, want: "fresh-normal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dg := NewDependencyGraph()
+			dg.now = func() time.Time { return now }
+			dg.SetPriorityAging(tt.aging)
+
+			for _, task := range []*Task{
+				{ID: "fresh-normal", Status: StatusPending, Priority: PriorityNormal, CreatedAt: now},
+				{ID: "old-low", Status: StatusPending, Priority: PriorityLow, CreatedAt: now.Add(-tt.age)},
+			} {
+				if err := dg.AddTask(task); err != nil {
+					t.Fatalf("AddTask(%s) failed: %v", task.ID, err)
+				}
+			}
+
+			got, err := dg.GetExecutableTask()
+			if err != nil {
+				t.Fatalf("GetExecutableTask() failed: %v", err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("GetExecutableTask() = %s, want %s", got.ID, tt.want)
+			}
+
+			ready := dg.GetReadyTasks()
+			if len(ready) != 2 || ready[0].ID != tt.want {
+				t.Errorf("GetReadyTasks()[0] = %v, want %s first", ready, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetExecutableTaskNone(t *testing.T) {
 	dg := NewDependencyGraph()
 
@@ -369,6 +421,56 @@ func TestGetTopologicalOrder(t *testing.T) {
 	}
 }
 
+func TestTopologicalLevels(t *testing.T) {
+	// Diamond: setup -> (api, db) -> release, plus an independent lint task
+	dg := NewDependencyGraph()
+	tasks := []*Task{
+		{ID: "setup", Priority: 50},
+		{ID: "lint", Priority: 10},
+		{ID: "api", Priority: 40, DependsOn: []string{"setup"}},
+		{ID: "db", Priority: 30, DependsOn: []string{"setup"}},
+		{ID: "release", Priority: 90, DependsOn: []string{"api", "db"}},
+	}
+	for _, task := range tasks {
+		if err := dg.AddTask(task); err != nil {
+			t.Fatalf("AddTask(%s) failed: %v", task.ID, err)
+		}
+	}
+
+	levels, err := dg.TopologicalLevels()
+	if err != nil {
+		t.Fatalf("TopologicalLevels() failed: %v", err)
+	}
+
+	want := [][]string{{"setup", "lint"}, {"api", "db"}, {"release"}}
+	if len(levels) != len(want) {
+		t.Fatalf("TopologicalLevels() returned %d levels, want %d", len(levels), len(want))
+	}
+	for i, level := range levels {
+		var got []string
+		for _, task := range level {
+			got = append(got, task.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(want[i], ",") {
+			t.Errorf("level %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestTopologicalLevelsCycle(t *testing.T) {
+	dg := NewDependencyGraph()
+	if err := dg.AddTask(&Task{ID: "a", DependsOn: []string{"b"}}); err != nil {
+		t.Fatalf("AddTask(a) failed: %v", err)
+	}
+	if err := dg.AddTask(&Task{ID: "b", DependsOn: []string{"a"}}); err != nil {
+		t.Fatalf("AddTask(b) failed: %v", err)
+	}
+
+	if _, err := dg.TopologicalLevels(); err == nil {
+		t.Error("TopologicalLevels() succeeded on a cycle, want error")
+	}
+}
+
 func TestGetDependencies(t *testing.T) {
 	dg := NewDependencyGraph()
 
@@ -553,6 +655,269 @@ func TestGetDependencyDepth(t *testing.T) {
 	}
 }
 
+func TestGetCriticalPath(t *testing.T) {
+	// setup -> api -> docs, setup -> db -> migrate -> release, api -> release
+	tests := []struct {
+		name      string
+		durations map[string]time.Duration
+		want      []string
+	}{
+		{
+			name: "unit weight picks the longest chain",
+			want: []string{"setup", "db", "migrate", "release"},
+		},
+		{
+			name: "recorded durations change the chain",
+			durations: map[string]time.Duration{
+				"setup":   time.Minute,
+				"api":     time.Hour,
+				"docs":    time.Hour,
+				"db":      time.Minute,
+				"migrate": time.Minute,
+				"release": time.Minute,
+			},
+			want: []string{"setup", "api", "docs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dg := NewDependencyGraph()
+			tasks := []*Task{
+				{ID: "setup", Priority: 50},
+				{ID: "api", Priority: 40, DependsOn: []string{"setup"}},
+				{ID: "docs", Priority: 30, DependsOn: []string{"api"}},
+				{ID: "db", Priority: 20, DependsOn: []string{"setup"}},
+				{ID: "migrate", Priority: 10, DependsOn: []string{"db"}},
+				{ID: "release", Priority: 5, DependsOn: []string{"migrate", "api"}},
+			}
+			for _, task := range tasks {
+				if d, ok := tt.durations[task.ID]; ok {
+					task.Result = &TaskResult{Duration: d}
+				}
+				if err := dg.AddTask(task); err != nil {
+					t.Fatalf("AddTask(%s) failed: %v", task.ID, err)
+				}
+			}
+
+			path, err := dg.GetCriticalPath()
+			if err != nil {
+				t.Fatalf("GetCriticalPath() failed: %v", err)
+			}
+
+			var got []string
+			for _, task := range path {
+				got = append(got, task.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("GetCriticalPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCriticalPathCycle(t *testing.T) {
+	dg := NewDependencyGraph()
+	if err := dg.AddTask(&Task{ID: "a", DependsOn: []string{"b"}}); err != nil {
+		t.Fatalf("AddTask(a) failed: %v", err)
+	}
+	if err := dg.AddTask(&Task{ID: "b", DependsOn: []string{"a"}}); err != nil {
+		t.Fatalf("AddTask(b) failed: %v", err)
+	}
+
+	if _, err := dg.GetCriticalPath(); err == nil {
+		t.Error("GetCriticalPath() succeeded on a cycle, want error")
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	// Diamond: base -> left, base -> right, left + right -> top; docs also depends on base
+	newDiamond := func(t *testing.T) *DependencyGraph {
+		t.Helper()
+		dg := NewDependencyGraph()
+		tasks := []*Task{
+			{ID: "base", Status: StatusCompleted, Result: &TaskResult{FilesChanged: []string{"go.mod"}}},
+			{ID: "left", Status: StatusFailed, DependsOn: []string{"base"}},
+			{ID: "right", Status: StatusCompleted, DependsOn: []string{"base"}},
+			{ID: "top", Status: StatusPending, DependsOn: []string{"left", "right"}},
+			{ID: "docs", Status: StatusPending, DependsOn: []string{"base"}},
+		}
+		for _, task := range tasks {
+			if err := dg.AddTask(task); err != nil {
+				t.Fatalf("AddTask(%s) failed: %v", task.ID, err)
+			}
+		}
+		return dg
+	}
+
+	tests := []struct {
+		root      string
+		wantTasks []string
+		wantBase  []string // Expected Blocks of base in the subgraph
+	}{
+		{root: "top", wantTasks: []string{"base", "left", "right", "top"}, wantBase: []string{"left", "right"}},
+		{root: "left", wantTasks: []string{"base", "left"}, wantBase: []string{"left"}},
+		{root: "base", wantTasks: []string{"base"}, wantBase: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.root, func(t *testing.T) {
+			dg := newDiamond(t)
+			sub, err := dg.Subgraph(tt.root)
+			if err != nil {
+				t.Fatalf("Subgraph(%s) failed: %v", tt.root, err)
+			}
+
+			order, err := sub.GetTopologicalOrder()
+			if err != nil {
+				t.Fatalf("GetTopologicalOrder() failed: %v", err)
+			}
+			var got []string
+			for _, task := range order {
+				got = append(got, task.ID)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.wantTasks, ",") {
+				t.Errorf("Subgraph(%s) tasks = %v, want %v", tt.root, got, tt.wantTasks)
+			}
+
+			blocks := sub.tasks["base"].Blocks
+			sort.Strings(blocks)
+			if strings.Join(blocks, ",") != strings.Join(tt.wantBase, ",") {
+				t.Errorf("base.Blocks = %v, want %v", blocks, tt.wantBase)
+			}
+
+			// Mutating the subgraph must not affect the original graph
+			sub.tasks["base"].Status = StatusPending
+			sub.tasks["base"].Result.FilesChanged[0] = "changed"
+			original := dg.tasks["base"]
+			if original.Status != StatusCompleted || original.Result.FilesChanged[0] != "go.mod" {
+				t.Errorf("original base task was modified: %+v", original)
+			}
+			if len(dg.tasks["base"].Blocks) != 3 {
+				t.Errorf("original base.Blocks = %v, want 3 entries", dg.tasks["base"].Blocks)
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	dg := NewDependencyGraph()
+	tasks := []*Task{
+		{ID: "base", Status: StatusCompleted, Result: &TaskResult{FilesChanged: []string{"go.mod"}}},
+		{ID: "app", Status: StatusPending, DependsOn: []string{"base"}, Env: map[string]string{"TOKEN": "a"}, SecretEnv: []string{"TOKEN"}},
+	}
+	for _, task := range tasks {
+		if err := dg.AddTask(task); err != nil {
+			t.Fatalf("AddTask(%s) failed: %v", task.ID, err)
+		}
+	}
+
+	clone := dg.Clone()
+	clone.tasks["app"].Env["TOKEN"] = "b"
+	clone.tasks["app"].SecretEnv[0] = "OTHER"
+	clone.tasks["base"].Status = StatusFailed
+	clone.tasks["base"].Result.FilesChanged[0] = "changed"
+	clone.tasks["base"].Blocks[0] = "other"
+	clone.tasks["app"].Status = StatusCompleted
+	clone.edges["app"][0] = "other"
+	clone.RemoveTask("base")
+
+	base, ok := dg.GetTask("base")
+	if !ok || base.Status != StatusCompleted || base.Result.FilesChanged[0] != "go.mod" || base.Blocks[0] != "app" {
+		t.Errorf("original base task was modified: %+v", base)
+	}
+	if app := dg.tasks["app"]; app.Status != StatusPending || app.DependsOn[0] != "base" || dg.edges["app"][0] != "base" ||
+		app.Env["TOKEN"] != "a" || app.SecretEnv[0] != "TOKEN" {
+		t.Errorf("original app task was modified: %+v, edges %v", app, dg.edges["app"])
+	}
+}
+
+func TestSimulateSchedule(t *testing.T) {
+	newGraph := func(t *testing.T, tasks []*Task) *DependencyGraph {
+		t.Helper()
+		dg := NewDependencyGraph()
+		for _, task := range tasks {
+			if err := dg.AddTask(task); err != nil {
+				t.Fatalf("AddTask(%s) failed: %v", task.ID, err)
+			}
+		}
+		return dg
+	}
+
+	t.Run("MatchesTopologicalLevels", func(t *testing.T) {
+		dg := newGraph(t, []*Task{
+			{ID: "setup", Priority: 50, Status: StatusPending},
+			{ID: "lint", Priority: 10, Status: StatusPending},
+			{ID: "api", Priority: 40, Status: StatusPending, DependsOn: []string{"setup"}},
+			{ID: "db", Priority: 30, Status: StatusPending, DependsOn: []string{"setup"}},
+			{ID: "release", Priority: 90, Status: StatusPending, DependsOn: []string{"api", "db"}},
+		})
+
+		levels, err := dg.TopologicalLevels()
+		if err != nil {
+			t.Fatalf("TopologicalLevels() failed: %v", err)
+		}
+		var want [][]string
+		for _, level := range levels {
+			var ids []string
+			for _, task := range level {
+				ids = append(ids, task.ID)
+			}
+			want = append(want, ids)
+		}
+
+		got := dg.SimulateSchedule()
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("SimulateSchedule() = %v, want %v", got, want)
+		}
+		for _, task := range dg.tasks {
+			if task.Status != StatusPending {
+				t.Errorf("task %s status = %s after simulation, want pending", task.ID, task.Status)
+			}
+		}
+	})
+
+	t.Run("PartialProgressAndDeadlock", func(t *testing.T) {
+		retryAt := time.Now().Add(time.Hour)
+		dg := newGraph(t, []*Task{
+			{ID: "done", Status: StatusCompleted},
+			{ID: "running", Status: StatusRunning},
+			{ID: "broken", Status: StatusFailed},
+			{ID: "retry", Status: StatusPending, NextRetryAt: &retryAt},
+			{ID: "next", Status: StatusPending, DependsOn: []string{"done", "running"}},
+			{ID: "last", Status: StatusPending, DependsOn: []string{"next", "retry"}},
+			{ID: "stuck", Status: StatusPending, DependsOn: []string{"broken"}, DependencyPolicy: DependencyPolicyWait},
+		})
+
+		got := dg.SimulateSchedule()
+		want := [][]string{{"next", "retry"}, {"last"}}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("SimulateSchedule() = %v, want %v", got, want)
+		}
+		if dg.tasks["running"].Status != StatusRunning || dg.tasks["retry"].NextRetryAt == nil {
+			t.Error("simulation modified the original graph")
+		}
+	})
+}
+
+func TestSubgraphErrors(t *testing.T) {
+	dg := NewDependencyGraph()
+	if err := dg.AddTask(&Task{ID: "a", DependsOn: []string{"b"}}); err != nil {
+		t.Fatalf("AddTask(a) failed: %v", err)
+	}
+	if err := dg.AddTask(&Task{ID: "b", DependsOn: []string{"a"}}); err != nil {
+		t.Fatalf("AddTask(b) failed: %v", err)
+	}
+
+	if _, err := dg.Subgraph("a"); err == nil {
+		t.Error("Subgraph() succeeded on a cycle, want error")
+	}
+	if _, err := dg.Subgraph("missing"); err == nil {
+		t.Error("Subgraph() succeeded for an unknown task, want error")
+	}
+}
+
 func TestDependencyPolicyHandling(t *testing.T) {
 	dg := NewDependencyGraph()
 
@@ -619,3 +984,313 @@ func TestDependencyPolicyHandling(t *testing.T) {
 		t.Error("Fail task should be marked as failed")
 	}
 }
+
+func TestScheduleRetryBackoff(t *testing.T) {
+	dg := NewDependencyGraph()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	dg.now = func() time.Time { return now }
+
+	task := &Task{
+		ID:           "flaky-task",
+		Status:       StatusFailed,
+		MaxRetries:   2,
+		RetryBackoff: time.Minute,
+	}
+	if err := dg.AddTask(task); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	// First retry: ready again after 1 minute
+	if !dg.ScheduleRetry("flaky-task") {
+		t.Fatal("ScheduleRetry() should schedule the first retry")
+	}
+	if task.Status != StatusPending {
+		t.Errorf("Status = %s, want %s", task.Status, StatusPending)
+	}
+	if task.Result.RetryCount != 1 {
+		t.Errorf("RetryCount = %d, want 1", task.Result.RetryCount)
+	}
+	if len(dg.GetReadyTasks()) != 0 {
+		t.Error("Task should not be ready before the backoff elapses")
+	}
+
+	now = now.Add(59 * time.Second)
+	if len(dg.GetReadyTasks()) != 0 {
+		t.Error("Task should not be ready one second before the backoff elapses")
+	}
+
+	now = now.Add(time.Second)
+	if ready := dg.GetReadyTasks(); len(ready) != 1 || ready[0].ID != "flaky-task" {
+		t.Errorf("Task should be ready once the backoff elapses, got %v", ready)
+	}
+
+	// Second retry: backoff doubles
+	task.Status = StatusFailed
+	if !dg.ScheduleRetry("flaky-task") {
+		t.Fatal("ScheduleRetry() should schedule the second retry")
+	}
+	if want := now.Add(2 * time.Minute); !task.NextRetryAt.Equal(want) {
+		t.Errorf("NextRetryAt = %v, want %v", task.NextRetryAt, want)
+	}
+
+	// Retries exhausted: stays failed
+	task.Status = StatusFailed
+	if dg.ScheduleRetry("flaky-task") {
+		t.Error("ScheduleRetry() should give up after MaxRetries")
+	}
+	if task.Status != StatusFailed {
+		t.Errorf("Status = %s, want %s", task.Status, StatusFailed)
+	}
+	if task.Result.RetryCount != 2 {
+		t.Errorf("RetryCount = %d, want 2", task.Result.RetryCount)
+	}
+}
+
+func TestScheduleRetryWithoutRetries(t *testing.T) {
+	dg := NewDependencyGraph()
+
+	task := &Task{ID: "once", Status: StatusFailed}
+	if err := dg.AddTask(task); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	if dg.ScheduleRetry("once") {
+		t.Error("ScheduleRetry() should not retry a task with MaxRetries = 0")
+	}
+	if dg.ScheduleRetry("missing") {
+		t.Error("ScheduleRetry() should not retry an unknown task")
+	}
+}
+
+func TestScheduleRetryBackoffCapped(t *testing.T) {
+	dg := NewDependencyGraph()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	dg.now = func() time.Time { return now }
+
+	// Shifting a 30s backoff by 70 overflows into a zero or negative delay
+	task := &Task{
+		ID:           "flaky-task",
+		Status:       StatusFailed,
+		MaxRetries:   100,
+		RetryBackoff: 30 * time.Second,
+		Result:       &TaskResult{RetryCount: 70},
+	}
+	if err := dg.AddTask(task); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	if !dg.ScheduleRetry("flaky-task") {
+		t.Fatal("ScheduleRetry() should schedule a retry")
+	}
+	if want := now.Add(maxRetryBackoff); !task.NextRetryAt.Equal(want) {
+		t.Errorf("NextRetryAt = %v, want %v", task.NextRetryAt, want)
+	}
+}
+
+func TestFinishTaskRetriesBeforeDependentsSeeFailure(t *testing.T) {
+	dg := NewDependencyGraph()
+	if err := dg.AddTask(&Task{ID: "base", Status: StatusPending, MaxRetries: 1, RetryBackoff: time.Minute}); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+	if err := dg.AddTask(&Task{ID: "dependent", Status: StatusPending, DependsOn: []string{"base"}, DependencyPolicy: DependencyPolicyFail}); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	task, ok := dg.StartTask("base")
+	if !ok {
+		t.Fatal("StartTask() should find the task")
+	}
+	if graphTask, _ := dg.GetTask("base"); graphTask == task || graphTask.Status != StatusRunning {
+		t.Errorf("StartTask() should mark the graph's task running and return a copy")
+	}
+
+	task.Status = StatusFailed
+	if !dg.FinishTask(task, true) {
+		t.Fatal("FinishTask() should schedule a retry")
+	}
+	dg.GetReadyTasks()
+	if dependent, _ := dg.GetTask("dependent"); dependent.Status != StatusPending {
+		t.Errorf("dependent Status = %s, want %s while its dependency is retried", dependent.Status, StatusPending)
+	}
+
+	// Out of retries the failure reaches the dependent
+	task, _ = dg.StartTask("base")
+	task.Status = StatusFailed
+	if dg.FinishTask(task, true) {
+		t.Error("FinishTask() should not retry once retries are exhausted")
+	}
+	dg.GetReadyTasks()
+	if dependent, _ := dg.GetTask("dependent"); dependent.Status != StatusFailed {
+		t.Errorf("dependent Status = %s, want %s", dependent.Status, StatusFailed)
+	}
+}
+
+func TestDependencyPolicyContinue(t *testing.T) {
+	dg := NewDependencyGraph()
+
+	lintTask := &Task{
+		ID:     "lint-task",
+		Status: StatusFailed,
+	}
+	buildTask := &Task{
+		ID:     "build-task",
+		Status: StatusCompleted,
+	}
+	deployTask := &Task{
+		ID:               "deploy-task",
+		Status:           StatusPending,
+		DependsOn:        []string{"lint-task", "build-task"},
+		DependencyPolicy: DependencyPolicyContinue,
+	}
+	waitTask := &Task{
+		ID:               "wait-task",
+		Status:           StatusPending,
+		DependsOn:        []string{"lint-task"},
+		DependencyPolicy: DependencyPolicyWait,
+	}
+
+	for _, task := range []*Task{lintTask, buildTask, deployTask, waitTask} {
+		if err := dg.AddTask(task); err != nil {
+			t.Fatalf("AddTask(%s) failed: %v", task.ID, err)
+		}
+	}
+
+	if !dg.areDependenciesCompleted("deploy-task") {
+		t.Error("Continue task should be ready despite a failed dependency")
+	}
+	if deployTask.Status != StatusPending {
+		t.Errorf("Continue task status = %s, want %s", deployTask.Status, StatusPending)
+	}
+	// The readiness check leaves the task alone
+	if deployTask.Result != nil {
+		t.Errorf("areDependenciesCompleted() set Result = %+v", deployTask.Result)
+	}
+
+	// Other policies are unaffected
+	if dg.areDependenciesCompleted("wait-task") {
+		t.Error("Wait task should not be ready with a failed dependency")
+	}
+
+	ready := dg.GetReadyTasks()
+	if len(ready) != 1 || ready[0].ID != "deploy-task" {
+		t.Errorf("GetReadyTasks() = %v, want only deploy-task", ready)
+	}
+	if deployTask.Result != nil {
+		t.Errorf("GetReadyTasks() set Result = %+v", deployTask.Result)
+	}
+
+	// Starting the task records the failed dependency it runs despite
+	started, ok := dg.StartTask("deploy-task")
+	if !ok {
+		t.Fatal("StartTask() should find deploy-task")
+	}
+	if started.Result == nil || !slices.Equal(started.Result.DependencyFailures, []string{"lint-task"}) {
+		t.Errorf("DependencyFailures should record lint-task, got %+v", started.Result)
+	}
+}
+
+func TestDependencyPolicyContinueWaitsForRunningDeps(t *testing.T) {
+	dg := NewDependencyGraph()
+
+	if err := dg.AddTask(&Task{ID: "lint-task", Status: StatusRunning}); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+	if err := dg.AddTask(&Task{
+		ID:               "deploy-task",
+		Status:           StatusPending,
+		DependsOn:        []string{"lint-task"},
+		DependencyPolicy: DependencyPolicyContinue,
+	}); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	if dg.areDependenciesCompleted("deploy-task") {
+		t.Error("Continue task should still wait for dependencies that have not finished")
+	}
+}
+
+func TestDetectDeadlock(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []*Task
+		want  []string
+	}{
+		{
+			name: "waiting behind failed dependency",
+			tasks: []*Task{
+				{ID: "dep", Status: StatusFailed},
+				{ID: "wait", Status: StatusPending, DependsOn: []string{"dep"}, DependencyPolicy: DependencyPolicyWait},
+				{ID: "downstream", Status: StatusPending, DependsOn: []string{"wait"}, DependencyPolicy: DependencyPolicySkip},
+				{ID: "done", Status: StatusCompleted},
+			},
+			want: []string{"downstream", "wait"},
+		},
+		{
+			name: "behind skipped dependency",
+			tasks: []*Task{
+				{ID: "dep", Status: StatusSkipped},
+				{ID: "next", Status: StatusPending, DependsOn: []string{"dep"}, DependencyPolicy: DependencyPolicyContinue},
+			},
+			want: []string{"next"},
+		},
+		{
+			name: "another task is ready",
+			tasks: []*Task{
+				{ID: "dep", Status: StatusFailed},
+				{ID: "wait", Status: StatusPending, DependsOn: []string{"dep"}, DependencyPolicy: DependencyPolicyWait},
+				{ID: "ready", Status: StatusPending},
+			},
+		},
+		{
+			name: "another task is running",
+			tasks: []*Task{
+				{ID: "dep", Status: StatusFailed},
+				{ID: "wait", Status: StatusPending, DependsOn: []string{"dep"}, DependencyPolicy: DependencyPolicyWait},
+				{ID: "busy", Status: StatusRunning},
+			},
+		},
+		{
+			name: "skip policy is resolved instead of blocked",
+			tasks: []*Task{
+				{ID: "dep", Status: StatusFailed},
+				{ID: "skip", Status: StatusPending, DependsOn: []string{"dep"}, DependencyPolicy: DependencyPolicySkip},
+			},
+		},
+		{
+			name: "cycle is not a deadlock",
+			tasks: []*Task{
+				{ID: "a", Status: StatusPending, DependsOn: []string{"b"}},
+				{ID: "b", Status: StatusPending, DependsOn: []string{"a"}},
+			},
+		},
+		{
+			name: "nothing pending",
+			tasks: []*Task{
+				{ID: "dep", Status: StatusFailed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dg := NewDependencyGraph()
+			for _, task := range tt.tasks {
+				if err := dg.AddTask(task); err != nil {
+					t.Fatalf("AddTask(%s) failed: %v", task.ID, err)
+				}
+			}
+
+			got := dg.DetectDeadlock()
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("DetectDeadlock() = %v, want %v", got, tt.want)
+			}
+
+			// Detection must not resolve tasks the way readiness checks do
+			for _, task := range tt.tasks {
+				if task.ID == "skip" && task.Status != StatusPending {
+					t.Errorf("DetectDeadlock() changed status of %s to %s", task.ID, task.Status)
+				}
+			}
+		})
+	}
+}
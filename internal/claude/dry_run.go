@@ -0,0 +1,39 @@
+package claude
+
+import (
+	"github.com/d-kuro/gwq/internal/tmux"
+)
+
+// ExecutionPlan describes what an execution runs. Execute fills it in
+// instead of running anything when DryRun is set.
+type ExecutionPlan struct {
+	Command    string               `json:"command"`           // Shell command line, including output capture
+	WorkingDir string               `json:"working_dir"`       // Directory the command runs in
+	Env        []string             `json:"env,omitempty"`     // KEY=VALUE pairs added to the inherited environment
+	LogFile    string               `json:"log_file"`          // Where the output is recorded
+	Session    *tmux.SessionOptions `json:"session,omitempty"` // tmux session the command runs in, if any
+}
+
+// planExecution returns what Execute runs for metadata, without side effects
+func (em *ExecutionManager) planExecution(metadata *ExecutionMetadata) *ExecutionPlan {
+	opts := em.sessionOptions(metadata, namedPipePath(metadata.ExecutionID))
+	return &ExecutionPlan{
+		Command:    opts.Command,
+		WorkingDir: opts.WorkingDir,
+		Env:        em.sessionEnv(metadata),
+		LogFile:    em.executionLogFile(metadata),
+		Session:    &opts,
+	}
+}
+
+// planExecution returns what Execute runs for execution, without side
+// effects. Secret environment values are masked.
+func (cce *ClaudeCodeExecutor) planExecution(execution *UnifiedExecution, logFile string) *ExecutionPlan {
+	env := RedactEnv(execution.Env, execution.SecretEnv)
+	return &ExecutionPlan{
+		Command:    cce.commandLine(execution, namedPipePath(execution.ExecutionID)),
+		WorkingDir: execution.WorkingDir,
+		Env:        append(mergeEnv(nil, env), executionIDEnv(execution)...),
+		LogFile:    logFile,
+	}
+}
@@ -0,0 +1,144 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/d-kuro/gwq/pkg/utils"
+)
+
+// assertNoFiles fails when dir contains any file
+func assertNoFiles(t *testing.T, dir string) {
+	t.Helper()
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			t.Errorf("dry run created %s", path)
+		}
+		return nil
+	})
+}
+
+func TestExecutionManagerDryRun(t *testing.T) {
+	sessions := &mockSessionManager{}
+	config := &models.ClaudeConfig{
+		ConfigDir:  t.TempDir(),
+		Executable: "claude-not-installed",
+		Execution:  models.ClaudeExecutionConfig{AutoCleanup: true},
+	}
+	em, err := NewExecutionManagerWithSessions(config, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+
+	startTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	workDir := t.TempDir()
+	metadata := &ExecutionMetadata{
+		ExecutionID:      "exec-dryrun",
+		SessionID:        "session-1",
+		Prompt:           "Fix Bob's test",
+		Model:            "sonnet",
+		Repository:       "/src/gwq",
+		WorkingDirectory: workDir,
+		StartTime:        startTime,
+		DryRun:           true,
+	}
+
+	session, err := em.Execute(context.Background(), metadata)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	pipePath := namedPipePath("exec-dryrun")
+	wantCommand := "bash -c " + utils.ShellQuote("export 'CLAUDE_EXECUTION_ID=exec-dryrun' 'CLAUDE_SESSION_ID=session-1'; set -o pipefail; claude-not-installed --verbose --dangerously-skip-permissions --output-format stream-json --model 'sonnet' -p "+
+		utils.ShellQuote("Fix Bob's test")+" | tee "+utils.ShellQuote(pipePath)+"; echo $? > "+utils.ShellQuote(exitStatusPath("exec-dryrun")))
+	plan := metadata.Plan
+	if plan == nil {
+		t.Fatal("Execute() left Plan nil")
+	}
+	if plan.Command != wantCommand {
+		t.Errorf("Plan.Command = %q, want %q", plan.Command, wantCommand)
+	}
+	if plan.WorkingDir != workDir {
+		t.Errorf("Plan.WorkingDir = %q, want %q", plan.WorkingDir, workDir)
+	}
+	if got, want := strings.Join(plan.Env, " "), "CLAUDE_EXECUTION_ID=exec-dryrun CLAUDE_SESSION_ID=session-1"; got != want {
+		t.Errorf("Plan.Env = %q, want %q", got, want)
+	}
+	if want := filepath.Join(em.GetLogDir(), "executions", "20250102-030405-exec-dryrun.jsonl"); plan.LogFile != want {
+		t.Errorf("Plan.LogFile = %q, want %q", plan.LogFile, want)
+	}
+	if plan.Session == nil || plan.Session.Context != "claude-exec" || plan.Session.Identifier != "exec-dryrun" || plan.Session.Metadata["execution_id"] != "exec-dryrun" {
+		t.Errorf("Plan.Session = %+v, want the claude-exec session for exec-dryrun", plan.Session)
+	}
+	if session == nil || session.Command != wantCommand || session.SessionName != "" {
+		t.Errorf("Execute() session = %+v, want an unstarted session running the command", session)
+	}
+
+	if len(sessions.alive) != 0 {
+		t.Errorf("dry run created tmux sessions %v", sessions.alive)
+	}
+	if _, err := os.Stat(pipePath); !os.IsNotExist(err) {
+		t.Errorf("dry run created the pipe %s", pipePath)
+	}
+	assertNoFiles(t, em.GetLogDir())
+	if metadata.TmuxSession != "" {
+		t.Errorf("TmuxSession = %q, want it unset", metadata.TmuxSession)
+	}
+}
+
+func TestClaudeCodeExecutorDryRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: "claude-not-installed", OutputFormat: "json"}
+	workDir := t.TempDir()
+	execution := &UnifiedExecution{
+		ExecutionID:   "task-dryrun",
+		SessionID:     "session-2",
+		ExecutionType: ExecutionTypeTask,
+		WorkingDir:    workDir,
+		Repository:    t.TempDir(),
+		Prompt:        "do it",
+		Env:           map[string]string{"B_VAR": "2", "A_VAR": "1", "API_TOKEN": "s3cret"},
+		SecretEnv:     []string{"API_TOKEN"},
+		TaskInfo:      &TaskExecutionInfo{Worktree: "does-not-exist"},
+		DryRun:        true,
+	}
+	logDir := t.TempDir()
+	logFile := filepath.Join(logDir, "exec.jsonl")
+
+	result, err := NewClaudeCodeExecutor(config).Execute(context.Background(), execution, logFile)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result == nil || !result.Success {
+		t.Errorf("Execute() result = %+v, want success", result)
+	}
+
+	pipePath := namedPipePath("task-dryrun")
+	plan := execution.Plan
+	if plan == nil {
+		t.Fatal("Execute() left Plan nil")
+	}
+	wantCommand := "set -o pipefail; claude-not-installed --dangerously-skip-permissions --output-format json -p | tee " + utils.ShellQuote(pipePath)
+	if plan.Command != wantCommand {
+		t.Errorf("Plan.Command = %q, want %q", plan.Command, wantCommand)
+	}
+	if plan.WorkingDir != workDir || plan.LogFile != logFile {
+		t.Errorf("Plan = %+v, want working dir %s and log file %s", plan, workDir, logFile)
+	}
+	wantEnv := "API_TOKEN=" + redactedEnvValue + " A_VAR=1 B_VAR=2 CLAUDE_EXECUTION_ID=task-dryrun CLAUDE_SESSION_ID=session-2"
+	if got := strings.Join(plan.Env, " "); got != wantEnv {
+		t.Errorf("Plan.Env = %q, want %q", got, wantEnv)
+	}
+
+	if _, err := os.Stat(pipePath); !os.IsNotExist(err) {
+		t.Errorf("dry run created the pipe %s", pipePath)
+	}
+	assertNoFiles(t, logDir)
+	assertNoFiles(t, config.ConfigDir)
+}
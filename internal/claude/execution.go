@@ -4,17 +4,25 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/pkg/filesystem"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/d-kuro/gwq/pkg/system"
+	"github.com/d-kuro/gwq/pkg/utils"
 )
 
 // ExecutionStatus represents the status of a Claude execution
@@ -29,37 +37,73 @@ const (
 
 // ExecutionMetadata holds metadata about a Claude execution
 type ExecutionMetadata struct {
-	ExecutionID      string          `json:"execution_id"`
-	SessionID        string          `json:"session_id"`
-	Prompt           string          `json:"prompt"`
-	StartTime        time.Time       `json:"start_time"`
-	EndTime          *time.Time      `json:"end_time,omitempty"`
-	Status           ExecutionStatus `json:"status"`
-	ExitCode         int             `json:"exit_code"`
-	Repository       string          `json:"repository"`
-	WorkingDirectory string          `json:"working_directory"`
-	TmuxSession      string          `json:"tmux_session"`
-	CostUSD          float64         `json:"cost_usd"`
-	DurationMS       int64           `json:"duration_ms"`
-	Model            string          `json:"model"`
-	Tags             []string        `json:"tags,omitempty"`
-	Priority         string          `json:"priority"`
-	Timeout          time.Duration   `json:"timeout"`
+	ExecutionID      string           `json:"execution_id"`
+	SessionID        string           `json:"session_id"`
+	Prompt           string           `json:"prompt"`
+	StartTime        time.Time        `json:"start_time"`
+	EndTime          *time.Time       `json:"end_time,omitempty"`
+	Status           ExecutionStatus  `json:"status"`
+	ExitCode         int              `json:"exit_code"`
+	Repository       string           `json:"repository"`
+	WorkingDirectory string           `json:"working_directory"`
+	TmuxSession      string           `json:"tmux_session"`
+	CostUSD          float64          `json:"cost_usd"`
+	DurationMS       int64            `json:"duration_ms"`
+	Model            string           `json:"model"`
+	Tags             []string         `json:"tags,omitempty"`
+	Priority         string           `json:"priority"`
+	Timeout          time.Duration    `json:"timeout"`
+	MaxCostUSD       float64          `json:"max_cost_usd,omitempty"` // Budget overriding claude.max_cost_usd (0 = use the config)
+	Result           *ExecutionResult `json:"result,omitempty"`       // Set for executions recorded by the execution engine
+	LogParts         []LogPart        `json:"log_parts,omitempty"`    // Set once the log has been rotated into part files
+
+	// DryRun makes Execute only fill in Plan, without creating a pipe,
+	// session or log files
+	DryRun bool           `json:"-"`
+	Plan   *ExecutionPlan `json:"-"`
+}
+
+// SessionManager is the subset of tmux.SessionManager used by ExecutionManager,
+// so executions can be driven by a test double instead of a real tmux
+type SessionManager interface {
+	CreateSession(ctx context.Context, opts tmux.SessionOptions) (*tmux.Session, error)
+	ListSessions() ([]*tmux.Session, error)
+	HasSession(sessionName string) bool
+	PipePane(sessionName, shellCommand string) error
+	KillSessionDirect(session *tmux.Session) error
 }
 
 // ExecutionManager manages Claude executions
 type ExecutionManager struct {
 	config     *models.ClaudeConfig
-	sessionMgr *tmux.SessionManager
+	sessionMgr SessionManager
 	logDir     string
 	system     system.SystemInterface
 	mu         sync.RWMutex
+
+	// monitorInterval is how often monitorExecution checks the tmux session
+	monitorInterval time.Duration
+
+	// logPartSize is the size in bytes at which captureLogOutput starts a
+	// new log part (0 = no rotation)
+	logPartSize int64
 }
 
 // NewExecutionManager creates a new execution manager
 func NewExecutionManager(config *models.ClaudeConfig) (*ExecutionManager, error) {
+	sessionMgr := tmux.NewSessionManager(&tmux.SessionConfig{
+		Enabled:      true,
+		TmuxCommand:  "tmux",
+		HistoryLimit: 50000,
+	}, config.ConfigDir)
+
+	return NewExecutionManagerWithSessions(config, sessionMgr)
+}
+
+// NewExecutionManagerWithSessions creates a new execution manager with custom session manager
+func NewExecutionManagerWithSessions(config *models.ClaudeConfig, sessionMgr SessionManager) (*ExecutionManager, error) {
 	// Create log directory structure
-	logDir := filepath.Join(config.ConfigDir, "logs")
+	logDir := LogDir(config)
 	dirs := []string{
 		filepath.Join(logDir, "executions"),
 		filepath.Join(logDir, "metadata"),
@@ -71,45 +115,67 @@ func NewExecutionManager(config *models.ClaudeConfig) (*ExecutionManager, error)
 		}
 	}
 
-	// Create session manager
-	sessionMgr := tmux.NewSessionManager(&tmux.SessionConfig{
-		Enabled:      true,
-		TmuxCommand:  "tmux",
-		HistoryLimit: 50000,
-	}, config.ConfigDir)
-
 	return &ExecutionManager{
-		config:     config,
-		sessionMgr: sessionMgr,
-		logDir:     logDir,
-		system:     system.NewStandardSystem(),
+		config:          config,
+		sessionMgr:      sessionMgr,
+		logDir:          logDir,
+		system:          system.NewStandardSystem(),
+		monitorInterval: 5 * time.Second,
+		logPartSize:     logPartSize(config),
 	}, nil
 }
 
-// Execute starts a Claude execution
+// logPartSize returns the configured log part size in bytes (0 = no rotation)
+func logPartSize(config *models.ClaudeConfig) int64 {
+	return int64(config.Execution.LogPartSizeMB) * 1024 * 1024
+}
+
+// Execute starts a Claude execution. With metadata.DryRun set it only
+// records what would run in metadata.Plan and returns the session that would
+// be created, unstarted; Claude doesn't need to be installed for that.
 func (em *ExecutionManager) Execute(ctx context.Context, metadata *ExecutionMetadata) (*tmux.Session, error) {
+	if err := ValidateModel(metadata.Model, em.config.AllowedModels); err != nil {
+		return nil, err
+	}
+	if err := ValidateOutputFormat(em.config.OutputFormat); err != nil {
+		return nil, err
+	}
+	if _, err := NewLogRedactor(em.config.RedactPatterns); err != nil {
+		return nil, err
+	}
+	if metadata.Timeout == 0 {
+		metadata.Timeout = em.config.Execution.Timeout
+	}
+	if metadata.DryRun {
+		metadata.Plan = em.planExecution(metadata)
+		opts := metadata.Plan.Session
+		return &tmux.Session{
+			Context:    opts.Context,
+			Identifier: opts.Identifier,
+			WorkingDir: opts.WorkingDir,
+			Command:    opts.Command,
+			Metadata:   opts.Metadata,
+		}, nil
+	}
+	if err := em.CheckClaudeAvailable(); err != nil {
+		return nil, err
+	}
+
 	// Auto cleanup old logs if enabled
 	if em.config.Execution.AutoCleanup {
 		go func() {
 			if err := em.autoCleanupLogs(); err != nil {
-				fmt.Printf("Warning: auto cleanup failed: %v\n", err)
+				logging.Warnf("auto cleanup failed: %v", err)
 			}
 		}()
 	}
 
-	// Build Claude command
-	cmd := em.buildClaudeCommand(metadata.Prompt)
-
 	// Create log file paths (no date subdirectory)
-	execLogDir := filepath.Join(em.logDir, "executions")
-	if err := os.MkdirAll(execLogDir, 0755); err != nil {
+	logFile := em.executionLogFile(metadata)
+	if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create execution log directory: %w", err)
 	}
-
-	// Generate timestamp-prefixed filenames for better sorting
-	logFileName := GenerateLogFileName(metadata.StartTime, metadata.ExecutionID)
 	metadataFileName := GenerateMetadataFileName(metadata.StartTime, metadata.ExecutionID)
-	logFile := filepath.Join(execLogDir, logFileName)
 	metadataFile := filepath.Join(em.logDir, "metadata", metadataFileName)
 
 	// Save initial metadata
@@ -118,43 +184,33 @@ func (em *ExecutionManager) Execute(ctx context.Context, metadata *ExecutionMeta
 	}
 
 	// Create named pipe for capturing output
-	pipePath := filepath.Join(os.TempDir(), fmt.Sprintf("gwq-claude-%s.pipe", metadata.ExecutionID))
+	pipePath := namedPipePath(metadata.ExecutionID)
 	if err := em.system.CreateNamedPipe(pipePath, 0600); err != nil {
 		return nil, fmt.Errorf("failed to create named pipe: %w", err)
 	}
-	defer func() {
-		if err := em.system.RemoveFile(pipePath); err != nil {
-			fmt.Printf("Warning: failed to remove pipe: %v\n", err)
-		}
-	}()
 
-	// Start log capture goroutine
+	ctx, budget, cancelBudget := em.withCostBudget(ctx, metadata)
+
+	// Start log capture goroutine; the pipe stays until the session has
+	// opened it and finished writing
 	logCaptureDone := make(chan error, 1)
 	go func() {
-		logCaptureDone <- em.captureLogOutput(pipePath, logFile, metadata)
+		err := em.captureLogOutput(pipePath, logFile, metadata, false, budget)
+		if removeErr := em.system.RemoveFile(pipePath); removeErr != nil {
+			logging.Warnf("failed to remove pipe: %v", removeErr)
+		}
+		logCaptureDone <- err
 	}()
 
-	// Build command with output redirection
-	fullCmd := fmt.Sprintf("%s | tee %s", cmd, pipePath)
-
 	// Create tmux session
-	sessionOpts := tmux.SessionOptions{
-		Context:    "claude-exec",
-		Identifier: metadata.ExecutionID,
-		WorkingDir: metadata.WorkingDirectory,
-		Command:    fullCmd,
-		Metadata: map[string]string{
-			"execution_id": metadata.ExecutionID,
-			"session_id":   metadata.SessionID,
-			"prompt":       truncateString(metadata.Prompt, 100),
-			"repository":   metadata.Repository,
-			"priority":     metadata.Priority,
-			"type":         "task",
-		},
-	}
-
-	session, err := em.sessionMgr.CreateSession(ctx, sessionOpts)
+	session, err := em.sessionMgr.CreateSession(ctx, em.sessionOptions(metadata, pipePath))
 	if err != nil {
+		// Unblock the capture goroutine waiting for a writer
+		if pipe, openErr := os.OpenFile(pipePath, os.O_WRONLY, 0); openErr == nil {
+			_ = pipe.Close()
+		}
+		<-logCaptureDone
+		cancelBudget(nil)
 		return nil, fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
@@ -163,36 +219,103 @@ func (em *ExecutionManager) Execute(ctx context.Context, metadata *ExecutionMeta
 	// Update metadata with session info
 	if err := em.saveMetadata(metadata, metadataFile); err != nil {
 		// Log error but don't fail
-		fmt.Printf("Warning: failed to update metadata: %v\n", err)
+		logging.Warnf("failed to update metadata: %v", err)
 	}
 
 	// Start monitoring goroutine
-	go em.monitorExecution(ctx, metadata, session, logCaptureDone)
+	go func() {
+		defer cancelBudget(nil)
+		em.monitorExecution(ctx, metadata, session, logCaptureDone)
+	}()
 
 	return session, nil
 }
 
-// buildClaudeCommand builds the Claude command for execution
-func (em *ExecutionManager) buildClaudeCommand(prompt string) string {
-	// Escape the prompt for shell
-	escapedPrompt := strings.ReplaceAll(prompt, `"`, `\"`)
-	escapedPrompt = strings.ReplaceAll(escapedPrompt, `$`, `\$`)
-	escapedPrompt = strings.ReplaceAll(escapedPrompt, "`", "\\`")
+// withCostBudget returns ctx and the budget of metadata, whose log capture
+// cancels ctx with ErrCostBudgetExceeded once the streamed cost goes over it
+func (em *ExecutionManager) withCostBudget(ctx context.Context, metadata *ExecutionMetadata) (context.Context, *costBudget, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return ctx, newCostBudget(effectiveMaxCost(metadata.MaxCostUSD, em.config.MaxCostUSD), cancel), cancel
+}
+
+// unblockPipeReader releases a capture goroutine still waiting for a writer
+// to open pipePath, so it sees EOF and removes the pipe. When no reader is
+// waiting the non-blocking open fails and nothing happens.
+func unblockPipeReader(pipePath string) {
+	if pipe, err := os.OpenFile(pipePath, os.O_WRONLY|syscall.O_NONBLOCK, 0); err == nil {
+		_ = pipe.Close()
+	}
+}
+
+// executionLogFile returns the path of an execution's log, with a
+// timestamp-prefixed name for better sorting
+func (em *ExecutionManager) executionLogFile(metadata *ExecutionMetadata) string {
+	return filepath.Join(em.logDir, "executions", GenerateLogFileName(metadata.StartTime, metadata.ExecutionID))
+}
+
+// sessionOptions returns the tmux session running an execution, with its
+// output copied to pipePath
+func (em *ExecutionManager) sessionOptions(metadata *ExecutionMetadata, pipePath string) tmux.SessionOptions {
+	cmd := em.buildClaudeCommand(metadata.Prompt, metadata.Model)
+
+	// The session exports the same identifiers as task executions
+	exports := make([]string, 0, 2)
+	for _, entry := range em.sessionEnv(metadata) {
+		exports = append(exports, utils.ShellQuote(entry))
+	}
+
+	// bash runs the pipeline whatever the user's shell is; pipefail makes
+	// Claude's exit status, not tee's, the one recorded for finishExecution
+	script := fmt.Sprintf("export %s; set -o pipefail; %s | tee %s; echo $? > %s",
+		strings.Join(exports, " "), cmd, utils.ShellQuote(pipePath), utils.ShellQuote(exitStatusPath(metadata.ExecutionID)))
+
+	return tmux.SessionOptions{
+		Context: "claude-exec",
+		Identifier: RenderSessionName(em.config.SessionNameTemplate, SessionNameVars{
+			Repo: sessionRepoName(metadata.Repository),
+			ID:   metadata.ExecutionID,
+		}),
+		WorkingDir: metadata.WorkingDirectory,
+		Command:    "bash -c " + utils.ShellQuote(script),
+		Metadata: map[string]string{
+			"execution_id": metadata.ExecutionID,
+			"session_id":   metadata.SessionID,
+			"prompt":       truncateString(metadata.Prompt, 100),
+			"repository":   metadata.Repository,
+			"priority":     metadata.Priority,
+			"type":         "task",
+		},
+	}
+}
 
+// sessionEnv returns the KEY=VALUE pairs the session of an execution exports
+func (em *ExecutionManager) sessionEnv(metadata *ExecutionMetadata) []string {
+	return executionIDVars(metadata.ExecutionID, metadata.SessionID)
+}
+
+// buildClaudeCommand builds the Claude command for execution
+func (em *ExecutionManager) buildClaudeCommand(prompt, model string) string {
 	// Build command with required flags for execution
 	args := []string{
 		em.config.Executable,
 		"--verbose",
 		"--dangerously-skip-permissions",
-		"--output-format", "stream-json",
-		"-p", fmt.Sprintf(`"%s"`, escapedPrompt),
+		"--output-format", outputFormat(em.config),
 	}
 
+	if model != "" {
+		args = append(args, "--model", utils.ShellQuote(model))
+	}
+
+	args = append(args, "-p", utils.ShellQuote(prompt))
+
 	return strings.Join(args, " ")
 }
 
-// captureLogOutput captures the JSON output from Claude
-func (em *ExecutionManager) captureLogOutput(pipePath, logFile string, metadata *ExecutionMetadata) error {
+// captureLogOutput captures the JSON output from Claude, reporting the cost
+// of each line to budget.
+// When appendLog is true the existing log file is extended instead of truncated.
+func (em *ExecutionManager) captureLogOutput(pipePath, logFile string, metadata *ExecutionMetadata, appendLog bool, budget *costBudget) error {
 	// Open pipe for reading
 	pipe, err := os.OpenFile(pipePath, os.O_RDONLY, 0)
 	if err != nil {
@@ -200,39 +323,53 @@ func (em *ExecutionManager) captureLogOutput(pipePath, logFile string, metadata
 	}
 	defer func() {
 		if err := pipe.Close(); err != nil {
-			fmt.Printf("Warning: failed to close pipe: %v\n", err)
+			logging.Warnf("failed to close pipe: %v", err)
 		}
 	}()
 
-	// Create log file
-	log, err := os.Create(logFile)
+	// Compiled after opening the pipe so a bad pattern can't leave the
+	// writer blocked on it
+	redactor, err := NewLogRedactor(em.config.RedactPatterns)
+	if err != nil {
+		return err
+	}
+
+	// Create log file, rotating into part files once it grows past the
+	// configured size
+	log, err := openRotatingLog(logFile, em.logPartSize, appendLog)
 	if err != nil {
 		return fmt.Errorf("failed to create log file: %w", err)
 	}
 	defer func() {
 		if err := log.Close(); err != nil {
-			fmt.Printf("Warning: failed to close log file: %v\n", err)
+			logging.Warnf("failed to close log file: %v", err)
 		}
 	}()
+	log.onRotate = func(part string) {
+		now := time.Now()
+		if len(metadata.LogParts) == 0 {
+			metadata.LogParts = append(metadata.LogParts, LogPart{File: filepath.Base(logFile), StartedAt: metadata.StartTime})
+		}
+		metadata.LogParts = append(metadata.LogParts, LogPart{File: filepath.Base(part), StartedAt: now})
+	}
 
-	// Read and process JSON stream
-	scanner := bufio.NewScanner(pipe)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024) // 1MB buffer
-
-	for scanner.Scan() {
-		line := scanner.Text()
+	// Read and process JSON stream; pane output piped through tmux carries
+	// terminal line endings, which forEachLine strips
+	err = forEachOutputRecord(pipe, outputFormat(em.config), func(line string) {
 		if line == "" {
-			continue
+			return
 		}
 
 		// Add timestamp to each JSON line
 		var jsonData map[string]interface{}
 		if err := json.Unmarshal([]byte(line), &jsonData); err == nil {
-			jsonData["timestamp"] = time.Now().Format(time.RFC3339)
+			redactor.RedactJSON(jsonData)
+			stampLogEntry(jsonData, time.Now())
+			budget.observe(jsonData)
 
 			// Extract cost and model info if available
 			if jsonData["type"] == "result" {
-				if cost, ok := jsonData["cost_usd"].(float64); ok {
+				if cost, ok := resultCostUSD(jsonData); ok {
 					metadata.CostUSD = cost
 				}
 				if duration, ok := jsonData["duration_ms"].(float64); ok {
@@ -248,18 +385,17 @@ func (em *ExecutionManager) captureLogOutput(pipePath, logFile string, metadata
 
 			// Write enhanced JSON line
 			enhancedLine, _ := json.Marshal(jsonData)
-			if _, err := fmt.Fprintf(log, "%s\n", enhancedLine); err != nil {
-				fmt.Printf("Warning: failed to write enhanced log line: %v\n", err)
+			if err := log.WriteLine(string(enhancedLine)); err != nil {
+				logging.Warnf("failed to write enhanced log line: %v", err)
 			}
 		} else {
 			// If not valid JSON, write as-is
-			if _, err := fmt.Fprintln(log, line); err != nil {
-				fmt.Printf("Warning: failed to write log line: %v\n", err)
+			if err := log.WriteLine(redactor.RedactString(line)); err != nil {
+				logging.Warnf("failed to write log line: %v", err)
 			}
 		}
-	}
-
-	if err := scanner.Err(); err != nil {
+	})
+	if err != nil {
 		return fmt.Errorf("error reading from pipe: %w", err)
 	}
 
@@ -268,28 +404,53 @@ func (em *ExecutionManager) captureLogOutput(pipePath, logFile string, metadata
 
 // monitorExecution monitors the execution and updates metadata
 func (em *ExecutionManager) monitorExecution(ctx context.Context, metadata *ExecutionMetadata, session *tmux.Session, logCaptureDone <-chan error) {
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(em.monitorInterval)
 	defer ticker.Stop()
 
 	metadataFileName := GenerateMetadataFileName(metadata.StartTime, metadata.ExecutionID)
 	metadataFile := filepath.Join(em.logDir, "metadata", metadataFileName)
 
+	// The deadline is measured from the start time so resumed executions keep their original budget
+	var deadline <-chan time.Time
+	if metadata.Timeout > 0 {
+		timer := time.NewTimer(time.Until(metadata.StartTime.Add(metadata.Timeout)))
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
 	for {
 		select {
+		case <-deadline:
+			em.stopExecution(session, logCaptureDone)
+			em.failExecution(metadata, metadataFile, ErrorKindTimeout, fmt.Sprintf("execution timed out after %s", metadata.Timeout))
+			return
+
 		case <-ctx.Done():
+			if cause := overBudget(ctx); cause != nil {
+				em.stopExecution(session, logCaptureDone)
+				em.failExecution(metadata, metadataFile, ErrorKindBudget, cause.Error())
+				return
+			}
 			metadata.Status = ExecutionStatusAborted
 			endTime := time.Now()
 			metadata.EndTime = &endTime
 			metadata.DurationMS = int64(endTime.Sub(metadata.StartTime).Milliseconds())
 			if err := em.saveMetadata(metadata, metadataFile); err != nil {
-				fmt.Printf("Warning: failed to save metadata on abort: %v\n", err)
+				logging.Warnf("failed to save metadata on abort: %v", err)
 			}
 			return
 
 		case err := <-logCaptureDone:
 			// Log capture completed
 			if err != nil {
-				fmt.Printf("Warning: log capture error: %v\n", err)
+				logging.Warnf("log capture error: %v", err)
+			}
+
+			// The final result may have put the execution over budget
+			if cause := overBudget(ctx); cause != nil {
+				em.stopExecution(session, nil)
+				em.failExecution(metadata, metadataFile, ErrorKindBudget, cause.Error())
+				return
 			}
 
 			// Check final status
@@ -298,95 +459,363 @@ func (em *ExecutionManager) monitorExecution(ctx context.Context, metadata *Exec
 				time.Sleep(2 * time.Second)
 			}
 
-			// Determine final status
-			metadata.Status = ExecutionStatusCompleted
-			if err != nil {
-				metadata.Status = ExecutionStatusFailed
-			}
-
-			endTime := time.Now()
-			metadata.EndTime = &endTime
-			metadata.DurationMS = int64(endTime.Sub(metadata.StartTime).Milliseconds())
-			if err := em.saveMetadata(metadata, metadataFile); err != nil {
-				fmt.Printf("Warning: failed to save metadata on completion: %v\n", err)
-			}
+			em.finishExecution(metadata, metadataFile, err)
 			return
 
 		case <-ticker.C:
 			// Check if session still exists
 			if !em.sessionMgr.HasSession(session.SessionName) {
 				// Session ended, wait for log capture to complete
+				var captureErr error
 				select {
-				case <-logCaptureDone:
-					// Already handled above
-				case <-time.After(10 * time.Second):
-					// Timeout waiting for log capture
-					metadata.Status = ExecutionStatusCompleted
-					endTime := time.Now()
-					metadata.EndTime = &endTime
-					metadata.DurationMS = int64(endTime.Sub(metadata.StartTime).Milliseconds())
-					if err := em.saveMetadata(metadata, metadataFile); err != nil {
-						fmt.Printf("Warning: failed to save metadata on timeout: %v\n", err)
+				case captureErr = <-logCaptureDone:
+					if captureErr != nil {
+						logging.Warnf("log capture error: %v", captureErr)
 					}
+				case <-time.After(10 * time.Second):
+					// The session may have ended without opening the pipe
+					unblockPipeReader(namedPipePath(metadata.ExecutionID))
 				}
+				if cause := overBudget(ctx); cause != nil {
+					em.failExecution(metadata, metadataFile, ErrorKindBudget, cause.Error())
+					return
+				}
+				em.finishExecution(metadata, metadataFile, captureErr)
 				return
 			}
 		}
 	}
 }
 
-// WatchExecution watches the execution output in real-time
-func (em *ExecutionManager) WatchExecution(ctx context.Context, executionID string) error {
-	// Find the log file
+// finishExecution records the final status of an execution whose session
+// ended, unless it was cancelled in the meantime
+func (em *ExecutionManager) finishExecution(metadata *ExecutionMetadata, metadataFile string, captureErr error) {
+	if em.wasCancelled(metadata.ExecutionID) {
+		return
+	}
+
+	metadata.Status = ExecutionStatusCompleted
+	if exitCode, ok := readExitStatus(metadata.ExecutionID); ok {
+		metadata.ExitCode = exitCode
+		if exitCode != 0 {
+			metadata.Status = ExecutionStatusFailed
+			metadata.Result = &ExecutionResult{
+				ExitCode:  exitCode,
+				Error:     fmt.Sprintf("claude exited with status %d", exitCode),
+				ErrorKind: ErrorKindCommand,
+			}
+		}
+	}
+	if captureErr != nil {
+		metadata.Status = ExecutionStatusFailed
+	}
+
+	endTime := time.Now()
+	metadata.EndTime = &endTime
+	metadata.DurationMS = int64(endTime.Sub(metadata.StartTime).Milliseconds())
+	if err := em.saveMetadata(metadata, metadataFile); err != nil {
+		logging.Warnf("failed to save metadata on completion: %v", err)
+	}
+}
+
+// overBudget returns the cancellation cause of ctx when log capture
+// cancelled it for going over the cost budget, or nil
+func overBudget(ctx context.Context) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrCostBudgetExceeded) {
+		return cause
+	}
+	return nil
+}
+
+// stopExecution kills the session of an execution cut short and waits for
+// log capture to finish, unless logCaptureDone is nil
+func (em *ExecutionManager) stopExecution(session *tmux.Session, logCaptureDone <-chan error) {
+	if em.sessionMgr.HasSession(session.SessionName) {
+		if err := em.sessionMgr.KillSessionDirect(session); err != nil {
+			logging.Warnf("failed to kill session %s: %v", session.SessionName, err)
+		}
+	}
+	if logCaptureDone == nil {
+		return
+	}
+
+	// Killing the session closes the pipe, so log capture finishes shortly after
+	select {
+	case <-logCaptureDone:
+	case <-time.After(10 * time.Second):
+	}
+}
+
+// failExecution records an execution as failed with an error of kind
+func (em *ExecutionManager) failExecution(metadata *ExecutionMetadata, metadataFile string, kind ErrorKind, message string) {
+	metadata.Status = ExecutionStatusFailed
+	metadata.ExitCode = 1
+	metadata.Result = &ExecutionResult{
+		ExitCode:  1,
+		Error:     message,
+		ErrorKind: kind,
+	}
+	endTime := time.Now()
+	metadata.EndTime = &endTime
+	metadata.DurationMS = int64(endTime.Sub(metadata.StartTime).Milliseconds())
+	if err := em.saveMetadata(metadata, metadataFile); err != nil {
+		logging.Warnf("failed to save metadata of failed execution: %v", err)
+	}
+}
+
+// CancelExecution kills the tmux session of a running execution and records
+// it as aborted. An execution engine running it sees the aborted status and
+// stops its own Claude process.
+func (em *ExecutionManager) CancelExecution(executionID string) (*ExecutionMetadata, error) {
+	metadata, err := em.LoadMetadata(executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+	if metadata.Status != ExecutionStatusRunning {
+		return nil, fmt.Errorf("execution %s is not running (status: %s)", executionID, metadata.Status)
+	}
+
+	if metadata.TmuxSession == "" || !em.sessionMgr.HasSession(metadata.TmuxSession) {
+		return nil, fmt.Errorf("cannot cancel execution %s: tmux session %q not found", executionID, metadata.TmuxSession)
+	}
+	if err := em.sessionMgr.KillSessionDirect(&tmux.Session{SessionName: metadata.TmuxSession}); err != nil {
+		return nil, fmt.Errorf("failed to cancel execution %s: %w", executionID, err)
+	}
+
+	metadata.Status = ExecutionStatusAborted
+	endTime := time.Now()
+	metadata.EndTime = &endTime
+	metadata.DurationMS = int64(endTime.Sub(metadata.StartTime).Milliseconds())
+	metadataFile := filepath.Join(em.logDir, "metadata", GenerateMetadataFileName(metadata.StartTime, executionID))
+	if err := em.saveMetadata(metadata, metadataFile); err != nil {
+		return nil, fmt.Errorf("failed to save metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// wasCancelled reports whether the execution was aborted by CancelExecution,
+// so the monitor does not overwrite that status when the session ends
+func (em *ExecutionManager) wasCancelled(executionID string) bool {
+	metadata, err := em.LoadMetadata(executionID)
+	if err != nil {
+		return false
+	}
+	return metadata.Status == ExecutionStatusAborted
+}
+
+// ResumeExecution reconnects log capture to the tmux session of an interrupted execution.
+// It fails if the original tmux session no longer exists. Capture continues
+// with the output the pane prints after resuming, appended to the existing
+// log; output written while nothing was capturing is not recovered.
+func (em *ExecutionManager) ResumeExecution(ctx context.Context, executionID string) error {
 	metadata, err := em.LoadMetadata(executionID)
 	if err != nil {
 		return fmt.Errorf("failed to load metadata: %w", err)
 	}
 
+	if metadata.TmuxSession == "" || !em.sessionMgr.HasSession(metadata.TmuxSession) {
+		return fmt.Errorf("cannot resume execution %s: tmux session %q no longer exists", executionID, metadata.TmuxSession)
+	}
+
 	logFile := FindLogFileByExecutionID(em.logDir, metadata.StartTime, executionID)
+	metadataFile := filepath.Join(em.logDir, "metadata", GenerateMetadataFileName(metadata.StartTime, executionID))
+
+	// Create a fresh named pipe and stream the pane output into it
+	pipePath := namedPipePath(executionID)
+	_ = em.system.RemoveFile(pipePath) // Remove any pipe left behind by the interrupted run
+	if err := em.system.CreateNamedPipe(pipePath, 0600); err != nil {
+		return fmt.Errorf("failed to create named pipe: %w", err)
+	}
+
+	ctx, budget, cancelBudget := em.withCostBudget(ctx, metadata)
+
+	logCaptureDone := make(chan error, 1)
+	go func() {
+		err := em.captureLogOutput(pipePath, logFile, metadata, true, budget)
+		if removeErr := em.system.RemoveFile(pipePath); removeErr != nil {
+			logging.Warnf("failed to remove pipe: %v", removeErr)
+		}
+		logCaptureDone <- err
+	}()
+
+	if err := em.sessionMgr.PipePane(metadata.TmuxSession, fmt.Sprintf("cat > '%s'", pipePath)); err != nil {
+		// Unblock the capture goroutine waiting for a writer
+		if pipe, openErr := os.OpenFile(pipePath, os.O_WRONLY, 0); openErr == nil {
+			_ = pipe.Close()
+		}
+		<-logCaptureDone
+		cancelBudget(nil)
+		return fmt.Errorf("failed to attach to tmux session output: %w", err)
+	}
+
+	metadata.Status = ExecutionStatusRunning
+	metadata.EndTime = nil
+	if err := em.saveMetadata(metadata, metadataFile); err != nil {
+		logging.Warnf("failed to update metadata: %v", err)
+	}
+
+	session := &tmux.Session{
+		SessionName: metadata.TmuxSession,
+		WorkingDir:  metadata.WorkingDirectory,
+	}
+	go func() {
+		defer cancelBudget(nil)
+		em.monitorExecution(ctx, metadata, session, logCaptureDone)
+	}()
+
+	return nil
+}
+
+// watchPollInterval is how often WatchExecution checks for new output
+const watchPollInterval = 100 * time.Millisecond
+
+// WatchOptions controls how WatchExecutionWithOptions follows a log
+type WatchOptions struct {
+	Raw          bool          // Print JSONL lines unparsed
+	Output       io.Writer     // Destination for output (default os.Stdout)
+	PollInterval time.Duration // Delay between checks for new output (default watchPollInterval)
+}
+
+// WatchExecution watches the execution output in real-time
+func (em *ExecutionManager) WatchExecution(ctx context.Context, executionID string) error {
+	return em.WatchExecutionWithOptions(ctx, executionID, WatchOptions{})
+}
+
+// WatchExecutionWithOptions follows an execution's log until it is no longer
+// running, waiting for the log file to appear if necessary
+func (em *ExecutionManager) WatchExecutionWithOptions(ctx context.Context, executionID string, opts WatchOptions) error {
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = watchPollInterval
+	}
 
-	// Open log file
-	file, err := os.Open(logFile)
+	return em.followExecutionLog(ctx, executionID, interval, func(line string) {
+		em.writeWatchedLine(out, line, opts.Raw)
+	})
+}
+
+// followExecutionLog calls fn with each complete line of an execution's log
+// until the execution is no longer running, waiting for the log file to appear
+func (em *ExecutionManager) followExecutionLog(ctx context.Context, executionID string, interval time.Duration, fn func(line string)) error {
+	metadata, err := em.LoadMetadata(executionID)
 	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	// Wait for the log file while the execution is starting up
+	var file *os.File
+	var logFile string
+	for {
+		logFile = FindLogFileByExecutionID(em.logDir, metadata.StartTime, executionID)
+		file, err = os.Open(logFile)
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		if !em.isExecutionRunning(executionID) {
+			return fmt.Errorf("log file not found for execution %s", executionID)
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			fmt.Printf("Warning: failed to close log file: %v\n", err)
+			logging.Warnf("failed to close log file: %v", err)
 		}
 	}()
 
-	// Follow the file
+	// Follow the file, keeping partial lines until they are complete
 	reader := bufio.NewReader(file)
+	part := 1
+	var pending string
+	finished := false
 	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			line, err := reader.ReadString('\n')
-			if err != nil {
-				if err == io.EOF {
-					// Check if execution is still running
-					if metadata.Status == ExecutionStatusRunning {
-						time.Sleep(100 * time.Millisecond)
-						continue
-					}
-					return nil
-				}
-				return err
+		chunk, err := reader.ReadString('\n')
+		pending += chunk
+		if err == nil {
+			fn(pending)
+			pending = ""
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		// A part is complete once the next one exists, so move on to it
+		if next, err := os.Open(logPartFile(logFile, part+1)); err == nil {
+			if pending != "" {
+				fn(pending + "\n")
+				pending = ""
+			}
+			if err := file.Close(); err != nil {
+				logging.Warnf("failed to close log file: %v", err)
 			}
+			file = next
+			reader.Reset(file)
+			part++
+			continue
+		}
 
-			// Parse and format JSON for display
-			em.displayLogLine(line)
+		// Drain once more after the execution stops so trailing output isn't lost
+		if finished {
+			if pending != "" {
+				fn(pending + "\n")
+			}
+			return nil
+		}
+		if !em.isExecutionRunning(executionID) {
+			finished = true
+			continue
 		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// writeWatchedLine writes a followed log line either raw or formatted
+func (em *ExecutionManager) writeWatchedLine(w io.Writer, line string, raw bool) {
+	if raw {
+		_, _ = fmt.Fprint(w, line)
+		return
+	}
+	em.displayLogLine(w, line)
+}
+
+// isExecutionRunning reports whether the execution's metadata is still running
+func (em *ExecutionManager) isExecutionRunning(executionID string) bool {
+	metadata, err := em.LoadMetadata(executionID)
+	if err != nil {
+		return false
+	}
+	return metadata.Status == ExecutionStatusRunning
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
 
-// displayLogLine formats and displays a log line
-func (em *ExecutionManager) displayLogLine(line string) {
+// displayLogLine formats and writes a log line to w
+func (em *ExecutionManager) displayLogLine(w io.Writer, line string) {
 	var data map[string]interface{}
 	if err := json.Unmarshal([]byte(line), &data); err != nil {
-		fmt.Print(line)
+		_, _ = fmt.Fprint(w, line)
 		return
 	}
 
@@ -399,7 +828,7 @@ func (em *ExecutionManager) displayLogLine(line string) {
 					if contentItem, ok := item.(map[string]interface{}); ok {
 						if contentItem["type"] == "text" {
 							if text, ok := contentItem["text"].(string); ok {
-								fmt.Printf("🤖 %s\n", text)
+								_, _ = fmt.Fprintf(w, "🤖 %s\n", text)
 							}
 						}
 					}
@@ -414,7 +843,7 @@ func (em *ExecutionManager) displayLogLine(line string) {
 					if contentItem, ok := item.(map[string]interface{}); ok {
 						if contentItem["type"] == "tool_result" {
 							if result, ok := contentItem["content"].(string); ok {
-								fmt.Printf("📊 Tool Result:\n%s\n", result)
+								_, _ = fmt.Fprintf(w, "📊 Tool Result:\n%s\n", result)
 							}
 						}
 					}
@@ -423,10 +852,10 @@ func (em *ExecutionManager) displayLogLine(line string) {
 		}
 	case "result":
 		if result, ok := data["result"].(string); ok {
-			fmt.Printf("\n✅ Result: %s\n", result)
+			_, _ = fmt.Fprintf(w, "\n✅ Result: %s\n", result)
 		}
 		if cost, ok := data["cost_usd"].(float64); ok {
-			fmt.Printf("💰 Cost: $%.4f\n", cost)
+			_, _ = fmt.Fprintf(w, "💰 Cost: $%.4f\n", cost)
 		}
 	}
 }
@@ -441,11 +870,63 @@ func (em *ExecutionManager) saveMetadata(metadata *ExecutionMetadata, path strin
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	if err := writeFileAtomic(filesystem.NewStandardFileSystem(), path, data, 0644); err != nil {
+		return err
+	}
+
+	// A stale index is rebuilt on the next listing, so don't fail the save
+	if err := em.appendMetadataIndex(metadata, path); err != nil {
+		logging.Warnf("failed to update metadata index: %v", err)
+	}
+	return nil
 }
 
+// atomicWriteSeq keeps temporary file names unique within the process
+var atomicWriteSeq atomic.Uint64
+
+// writeFileAtomic writes data to a temporary file next to path on fs and
+// renames it into place, so concurrent readers in other processes see either
+// the old or the new content but never a partial write
+func writeFileAtomic(fs filesystem.FileSystemInterface, path string, data []byte, perm os.FileMode) error {
+	tmp := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s-%d-%d.tmp", filepath.Base(path), os.Getpid(), atomicWriteSeq.Add(1)))
+	if err := fs.WriteFile(tmp, data, perm); err != nil {
+		_ = fs.Remove(tmp)
+		return err
+	}
+	if err := fs.Rename(tmp, path); err != nil {
+		_ = fs.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// metadataLoadAttempts and metadataLoadRetryDelay let LoadMetadata ride out
+// a metadata file that is briefly missing while another process replaces it
+const (
+	metadataLoadAttempts   = 3
+	metadataLoadRetryDelay = 20 * time.Millisecond
+)
+
+// errMetadataNotFound is returned by loadMetadataOnce when no readable
+// metadata file exists for the execution
+var errMetadataNotFound = errors.New("metadata not found")
+
 // LoadMetadata loads execution metadata by searching for files containing the executionID
 func (em *ExecutionManager) LoadMetadata(executionID string) (*ExecutionMetadata, error) {
+	for attempt := 1; ; attempt++ {
+		metadata, err := em.loadMetadataOnce(executionID)
+		if !errors.Is(err, errMetadataNotFound) {
+			return metadata, err
+		}
+		if attempt == metadataLoadAttempts {
+			return nil, fmt.Errorf("metadata not found for execution ID: %s", executionID)
+		}
+		time.Sleep(metadataLoadRetryDelay)
+	}
+}
+
+// loadMetadataOnce makes a single attempt at loading execution metadata
+func (em *ExecutionManager) loadMetadataOnce(executionID string) (*ExecutionMetadata, error) {
 	em.mu.RLock()
 	defer em.mu.RUnlock()
 
@@ -484,7 +965,7 @@ func (em *ExecutionManager) LoadMetadata(executionID string) (*ExecutionMetadata
 		}
 	}
 
-	return nil, fmt.Errorf("metadata not found for execution ID: %s", executionID)
+	return nil, errMetadataNotFound
 }
 
 // DetermineExecutionState determines the state of an execution
@@ -513,16 +994,141 @@ func (em *ExecutionManager) DetermineExecutionState(executionID string) Executio
 	return ExecutionStatusAborted
 }
 
+// reconcileGracePeriod skips executions that may still be creating their tmux session
+const reconcileGracePeriod = time.Minute
+
+// ReconcileExecutions repairs executions left "running" after a crash. Any
+// running execution whose tmux session no longer exists is marked completed
+// if its log has a final result entry, or aborted otherwise. It returns the
+// number of executions updated.
+func (em *ExecutionManager) ReconcileExecutions() (int, error) {
+	metadataDir := filepath.Join(em.logDir, "metadata")
+	files, err := os.ReadDir(metadataDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read metadata directory: %w", err)
+	}
+
+	repaired := 0
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		metadataFile := filepath.Join(metadataDir, file.Name())
+		data, err := os.ReadFile(metadataFile)
+		if err != nil {
+			continue
+		}
+
+		var metadata ExecutionMetadata
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			continue
+		}
+
+		if metadata.Status != ExecutionStatusRunning {
+			continue
+		}
+		if metadata.TmuxSession == "" && time.Since(metadata.StartTime) < reconcileGracePeriod {
+			continue // Execute saves metadata before the session is created
+		}
+		if metadata.TmuxSession != "" && em.sessionMgr.HasSession(metadata.TmuxSession) {
+			continue
+		}
+
+		em.finalizeOrphanedExecution(&metadata)
+		if err := em.saveMetadata(&metadata, metadataFile); err != nil {
+			logging.Warnf("failed to update metadata for %s: %v", metadata.ExecutionID, err)
+			continue
+		}
+		repaired++
+	}
+
+	return repaired, nil
+}
+
+// finalizeOrphanedExecution sets the final status of an execution whose
+// session is gone, using the log's result entry when present
+func (em *ExecutionManager) finalizeOrphanedExecution(metadata *ExecutionMetadata) {
+	metadata.Status = ExecutionStatusAborted
+	endTime := time.Now()
+
+	logFile := FindLogFileByExecutionID(em.logDir, metadata.StartTime, metadata.ExecutionID)
+	if info, err := os.Stat(logFile); err == nil {
+		endTime = info.ModTime()
+	}
+
+	if result, ok := readFinalResult(logFile); ok {
+		metadata.Status = ExecutionStatusCompleted
+		if cost, ok := resultCostUSD(result); ok {
+			metadata.CostUSD = cost
+		}
+		if duration, ok := result["duration_ms"].(float64); ok && metadata.DurationMS == 0 {
+			metadata.DurationMS = int64(duration)
+		}
+	}
+
+	if metadata.EndTime == nil {
+		metadata.EndTime = &endTime
+	}
+	if metadata.DurationMS == 0 {
+		metadata.DurationMS = metadata.EndTime.Sub(metadata.StartTime).Milliseconds()
+	}
+}
+
+// resultCostUSD returns the cost reported by a result entry. total_cost_usd
+// covers the whole session and supersedes cost_usd.
+func resultCostUSD(result map[string]interface{}) (float64, bool) {
+	if total, ok := result["total_cost_usd"].(float64); ok && total > 0 {
+		return total, true
+	}
+	cost, ok := result["cost_usd"].(float64)
+	return cost, ok
+}
+
+// readFinalResult returns the last "result" entry in a JSONL log
+func readFinalResult(logFile string) (map[string]interface{}, bool) {
+	file, err := openLogParts(logFile)
+	if err != nil {
+		return nil, false
+	}
+	defer func() { _ = file.Close() }()
+
+	var result map[string]interface{}
+	_ = forEachLine(file, func(line string) {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return
+		}
+		if entry["type"] == "result" {
+			result = entry
+		}
+	})
+
+	return result, result != nil
+}
+
 // GetLogDir returns the log directory path
 func (em *ExecutionManager) GetLogDir() string {
 	return em.logDir
 }
 
+// defaultRetentionDays is the log retention used when none is configured
+const defaultRetentionDays = 30
+
+// retentionDays returns the effective log retention in days
+func (em *ExecutionManager) retentionDays() int {
+	if em.config.Execution.RetentionDays > 0 {
+		return em.config.Execution.RetentionDays
+	}
+	return defaultRetentionDays
+}
+
 // autoCleanupLogs automatically cleans up old log files based on retention policy
 func (em *ExecutionManager) autoCleanupLogs() error {
-	// Use default retention of 30 days
-	const defaultRetentionDays = 30
-	cutoff := time.Now().AddDate(0, 0, -defaultRetentionDays)
+	cutoff := time.Now().AddDate(0, 0, -em.retentionDays())
 
 	// Clean up execution logs
 	executionsDir := filepath.Join(em.logDir, "executions")
@@ -577,7 +1183,7 @@ func (em *ExecutionManager) cleanupExecutionLogs(executionsDir string, cutoff ti
 		if fileTime.Before(cutoff) {
 			filePath := filepath.Join(executionsDir, entry.Name())
 			if err := os.Remove(filePath); err != nil {
-				fmt.Printf("Warning: failed to remove old log file %s: %v\n", entry.Name(), err)
+				logging.Warnf("failed to remove old log file %s: %v", entry.Name(), err)
 			} else {
 				deletedCount++
 			}
@@ -585,7 +1191,7 @@ func (em *ExecutionManager) cleanupExecutionLogs(executionsDir string, cutoff ti
 	}
 
 	if deletedCount > 0 {
-		fmt.Printf("Auto cleanup: removed %d old execution log files\n", deletedCount)
+		fmt.Printf("Auto cleanup: removed %d old execution log files (retention: %d days)\n", deletedCount, em.retentionDays())
 	}
 
 	return nil
@@ -625,7 +1231,7 @@ func (em *ExecutionManager) cleanupMetadataFiles(metadataDir string, cutoff time
 			// Check if execution is still running before deleting
 			if !em.isExecutionRunningFromMetadataFile(filePath) {
 				if err := os.Remove(filePath); err != nil {
-					fmt.Printf("Warning: failed to remove old metadata file %s: %v\n", entry.Name(), err)
+					logging.Warnf("failed to remove old metadata file %s: %v", entry.Name(), err)
 				} else {
 					deletedCount++
 				}
@@ -634,7 +1240,7 @@ func (em *ExecutionManager) cleanupMetadataFiles(metadataDir string, cutoff time
 	}
 
 	if deletedCount > 0 {
-		fmt.Printf("Auto cleanup: removed %d old metadata files\n", deletedCount)
+		fmt.Printf("Auto cleanup: removed %d old metadata files (retention: %d days)\n", deletedCount, em.retentionDays())
 	}
 
 	return nil
@@ -667,7 +1273,7 @@ func (em *ExecutionManager) cleanupObsoleteIndexFile() {
 		if err := os.Remove(indexFile); err == nil {
 			fmt.Printf("Auto cleanup: removed obsolete index.json file\n")
 		} else {
-			fmt.Printf("Warning: failed to remove obsolete index.json file: %v\n", err)
+			logging.Warnf("failed to remove obsolete index.json file: %v", err)
 		}
 	}
 }
@@ -695,9 +1301,37 @@ func ParseFileNameTimestamp(filename string) (time.Time, error) {
 	return time.Parse("20060102-150405", parts[0]+"-"+parts[1])
 }
 
+// SortExecutionsNewestFirst sorts executions by start time, newest first.
+// Executions that started at the same time, common when tasks are enqueued
+// in a batch, are ordered by ID so listings are stable.
+func SortExecutionsNewestFirst(executions []ExecutionMetadata) {
+	sort.Slice(executions, func(i, j int) bool {
+		return startedAfter(executions[i].StartTime, executions[i].ExecutionID, executions[j].StartTime, executions[j].ExecutionID)
+	})
+}
+
+// startedAfter orders executions newest first, breaking ties by ID
+func startedAfter(startA time.Time, idA string, startB time.Time, idB string) bool {
+	if !startA.Equal(startB) {
+		return startA.After(startB)
+	}
+	return idA < idB
+}
+
+// LogDir returns the directory execution logs are stored in: the configured
+// LogDir, or the logs directory under ConfigDir
+func LogDir(config *models.ClaudeConfig) string {
+	if config.LogDir != "" {
+		return config.LogDir
+	}
+	return filepath.Join(config.ConfigDir, "logs")
+}
+
 // FindLogFileByExecutionID finds a log file by execution ID following the design specification:
 // Primary: Flat structure with timestamp-first naming (YYYYMMDD-HHMMSS-{type}-{id}.jsonl)
 // Fallback: Legacy formats in flat structure
+// The first file of a rotated log is returned; readers pick up its
+// "-partN" siblings through LogFileParts.
 func FindLogFileByExecutionID(logDir string, startTime time.Time, executionID string) string {
 	execLogDir := filepath.Join(logDir, "executions")
 
@@ -711,10 +1345,11 @@ func FindLogFileByExecutionID(logDir string, startTime time.Time, executionID st
 		return filePath
 	}
 
-	// 2. Try to find any file in flat structure containing the execution ID
+	// 2. Try to find any file in flat structure containing the execution ID,
+	// skipping the later parts of rotated logs
 	if entries, err := os.ReadDir(execLogDir); err == nil {
 		for _, entry := range entries {
-			if !entry.IsDir() && strings.Contains(entry.Name(), executionID) && strings.HasSuffix(entry.Name(), ".jsonl") {
+			if !entry.IsDir() && strings.Contains(entry.Name(), executionID) && strings.HasSuffix(entry.Name(), ".jsonl") && !isLogPartFile(entry.Name()) {
 				filePath := filepath.Join(execLogDir, entry.Name())
 				// Verify the file actually exists before returning
 				if _, err := os.Stat(filePath); err == nil {
@@ -736,6 +1371,35 @@ func FindLogFileByExecutionID(logDir string, startTime time.Time, executionID st
 	return filepath.Join(execLogDir, fmt.Sprintf("%s-%s.jsonl", timestamp, executionID))
 }
 
+// namedPipePath returns the temporary named pipe path used to capture an execution's output
+func namedPipePath(executionID string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gwq-claude-%s.pipe", executionID))
+}
+
+// exitStatusPath returns the file the session of an execution writes Claude's
+// exit status to
+func exitStatusPath(executionID string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gwq-claude-%s.exit", executionID))
+}
+
+// readExitStatus reads and removes the exit status written by the session of
+// an execution. It reports false when the session ended without writing one,
+// e.g. because it was killed.
+func readExitStatus(executionID string) (int, bool) {
+	path := exitStatusPath(executionID)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	_ = os.Remove(path)
+
+	exitCode, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, false
+	}
+	return exitCode, true
+}
+
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -2,9 +2,12 @@ package claude
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/d-kuro/gwq/pkg/utils"
 )
@@ -47,6 +50,17 @@ type UnifiedExecution struct {
 	CostUSD    float64       `json:"cost_usd"`
 	DurationMS int64         `json:"duration_ms"`
 	Timeout    time.Duration `json:"timeout"`
+	MaxCostUSD float64       `json:"max_cost_usd,omitempty"` // Budget overriding claude.max_cost_usd (0 = use the config)
+
+	// Extra environment for the Claude process; values of SecretEnv keys are
+	// redacted when the execution is saved
+	Env       map[string]string `json:"env,omitempty"`
+	SecretEnv []string          `json:"secret_env,omitempty"`
+
+	// DryRun makes ClaudeCodeExecutor.Execute only fill in Plan, without
+	// creating a pipe, worktree or log file or running Claude
+	DryRun bool           `json:"-"`
+	Plan   *ExecutionPlan `json:"-"`
 }
 
 // TaskExecutionInfo contains task-specific execution information
@@ -62,12 +76,37 @@ type TaskExecutionInfo struct {
 	Prompt             string   `json:"prompt,omitempty"`
 }
 
+// ErrorKind classifies why an execution failed
+type ErrorKind string
+
+const (
+	ErrorKindNone       ErrorKind = "none"        // Execution succeeded
+	ErrorKindSetup      ErrorKind = "setup"       // Pipe, worktree, or command could not be prepared
+	ErrorKindCommand    ErrorKind = "command"     // Claude exited with an error
+	ErrorKindLogCapture ErrorKind = "log_capture" // Output could not be recorded
+	ErrorKindTimeout    ErrorKind = "timeout"     // Execution exceeded its deadline
+	ErrorKindBudget     ErrorKind = "budget"      // Execution went over its cost budget
+	ErrorKindCancelled  ErrorKind = "cancelled"   // Execution was cancelled with gwq task cancel
+)
+
+// ErrExecutionCancelled is returned by ExecutionEngine.Execute for an
+// execution cancelled while it was running
+var ErrExecutionCancelled = errors.New("execution cancelled")
+
+// Retryable reports whether a failure of this kind may succeed when retried.
+// Retrying an execution that went over budget would only spend more, and
+// retrying a cancelled one would undo the cancellation.
+func (k ErrorKind) Retryable() bool {
+	return k != ErrorKindSetup && k != ErrorKindBudget && k != ErrorKindCancelled
+}
+
 // ExecutionResult contains detailed execution results
 type ExecutionResult struct {
-	Success      bool     `json:"success"`
-	ExitCode     int      `json:"exit_code"`
-	Error        string   `json:"error,omitempty"`
-	FilesChanged []string `json:"files_changed,omitempty"`
+	Success      bool      `json:"success"`
+	ExitCode     int       `json:"exit_code"`
+	Error        string    `json:"error,omitempty"`      // Human-readable error for display
+	ErrorKind    ErrorKind `json:"error_kind,omitempty"` // Machine-readable classification of Error
+	FilesChanged []string  `json:"files_changed,omitempty"`
 
 	// Detailed analysis
 	TokensUsed int      `json:"tokens_used,omitempty"`
@@ -84,7 +123,11 @@ type ExecutionRequest struct {
 	TaskInfo   *TaskExecutionInfo // For task executions
 	Tags       []string
 	Priority   string
+	Model      string
 	Timeout    time.Duration
+	MaxCostUSD float64
+	Env        map[string]string
+	SecretEnv  []string
 }
 
 // ExecutionEngine provides unified execution of Claude Code for all execution types
@@ -93,6 +136,10 @@ type ExecutionEngine struct {
 	sessionManager *UnifiedSessionManager
 	logManager     *UnifiedLogManager
 	claudeExecutor *ClaudeCodeExecutor
+
+	// cancelPollInterval is how often Execute checks whether the execution
+	// was cancelled
+	cancelPollInterval time.Duration
 }
 
 // NewExecutionEngine creates a new unified execution engine
@@ -117,6 +164,8 @@ func NewExecutionEngine(config *models.ClaudeConfig) (*ExecutionEngine, error) {
 		sessionManager: sessionManager,
 		logManager:     logManager,
 		claudeExecutor: claudeExecutor,
+
+		cancelPollInterval: time.Second,
 	}, nil
 }
 
@@ -139,24 +188,59 @@ func (ee *ExecutionEngine) Execute(ctx context.Context, req *ExecutionRequest) (
 		TaskInfo:      req.TaskInfo,
 		Tags:          req.Tags,
 		Priority:      req.Priority,
+		Model:         req.Model,
 		Timeout:       req.Timeout,
+		MaxCostUSD:    req.MaxCostUSD,
+		Env:           req.Env,
+		SecretEnv:     req.SecretEnv,
+	}
+
+	// Invalid settings and a missing executable are setup errors; no
+	// session is started for them
+	if err := ValidateModel(req.Model, ee.config.AllowedModels); err != nil {
+		return setupFailure(execution, err)
+	}
+	if err := ValidateOutputFormat(ee.config.OutputFormat); err != nil {
+		return setupFailure(execution, err)
+	}
+	if _, err := NewLogRedactor(ee.config.RedactPatterns); err != nil {
+		return setupFailure(execution, err)
+	}
+	if err := ee.claudeExecutor.CheckClaudeAvailable(); err != nil {
+		return setupFailure(execution, err)
 	}
 
 	// Create tmux session with unified naming
 	session, err := ee.sessionManager.CreateSession(ctx, execution)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create session: %w", err)
+		return setupFailure(execution, fmt.Errorf("failed to create session: %w", err))
 	}
 	execution.TmuxSession = session.SessionName
 
 	// Start unified logging
 	logFile, err := ee.logManager.StartLogging(execution)
 	if err != nil {
-		return nil, fmt.Errorf("failed to start logging: %w", err)
+		return setupFailure(execution, fmt.Errorf("failed to start logging: %w", err))
 	}
 
+	// Cancelling only records the execution as aborted and kills its session;
+	// stop the Claude process run by the executor as well
+	ctx, cancelExecution := context.WithCancelCause(ctx)
+	defer cancelExecution(nil)
+	stopWatching := ee.watchCancellation(ctx, executionID, cancelExecution)
+
 	// Execute Claude Code with unified monitoring
 	result, err := ee.claudeExecutor.Execute(ctx, execution, logFile)
+	stopWatching()
+	cancelled := errors.Is(context.Cause(ctx), ErrExecutionCancelled) || ee.wasCancelled(executionID)
+
+	// The session runs Claude too; stop it along with an execution that was
+	// interrupted, timed out or went over budget
+	if (ctx.Err() != nil && !cancelled) || (result != nil && (result.ErrorKind == ErrorKindTimeout || result.ErrorKind == ErrorKindBudget)) {
+		if killErr := ee.sessionManager.KillSessionDirect(session); killErr != nil {
+			logging.Warnf("failed to kill session %s: %v", session.SessionName, killErr)
+		}
+	}
 
 	// Update execution record
 	execution.Result = result
@@ -164,7 +248,17 @@ func (ee *ExecutionEngine) Execute(ctx context.Context, req *ExecutionRequest) (
 	execution.EndTime = &endTime
 	execution.DurationMS = int64(endTime.Sub(execution.StartTime).Milliseconds())
 
-	if err != nil {
+	if cancelled {
+		// Keep the aborted status recorded by the cancellation
+		err = ErrExecutionCancelled
+		execution.Status = ExecutionStatusAborted
+		if execution.Result == nil {
+			execution.Result = &ExecutionResult{}
+		}
+		execution.Result.Success = false
+		execution.Result.Error = err.Error()
+		execution.Result.ErrorKind = ErrorKindCancelled
+	} else if err != nil {
 		execution.Status = ExecutionStatusFailed
 		if execution.Result == nil {
 			execution.Result = &ExecutionResult{}
@@ -182,6 +276,59 @@ func (ee *ExecutionEngine) Execute(ctx context.Context, req *ExecutionRequest) (
 	return execution, err
 }
 
+// setupFailure records err as a setup failure of execution, which is not
+// retried
+func setupFailure(execution *UnifiedExecution, err error) (*UnifiedExecution, error) {
+	execution.Status = ExecutionStatusFailed
+	execution.Result = &ExecutionResult{
+		ExitCode:  1,
+		Error:     err.Error(),
+		ErrorKind: ErrorKindSetup,
+	}
+	return execution, err
+}
+
+// watchCancellation cancels ctx with ErrExecutionCancelled once the saved
+// execution is marked aborted. The returned function stops watching.
+func (ee *ExecutionEngine) watchCancellation(ctx context.Context, executionID string, cancel context.CancelCauseFunc) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ee.cancelPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if ee.wasCancelled(executionID) {
+					cancel(ErrExecutionCancelled)
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// wasCancelled reports whether the saved execution was aborted by
+// ExecutionManager.CancelExecution
+func (ee *ExecutionEngine) wasCancelled(executionID string) bool {
+	execution, err := ee.logManager.LoadExecution(executionID)
+	if err != nil {
+		return false
+	}
+	return execution.Status == ExecutionStatusAborted
+}
+
 // ExecuteTask is a convenience method for executing tasks through the unified engine
 func (ee *ExecutionEngine) ExecuteTask(ctx context.Context, task *Task) (*UnifiedExecution, error) {
 	// Convert task to execution request
@@ -190,7 +337,12 @@ func (ee *ExecutionEngine) ExecuteTask(ctx context.Context, task *Task) (*Unifie
 		Repository: task.RepositoryRoot,
 		WorkingDir: task.WorktreePath,
 		Priority:   fmt.Sprintf("%d", task.Priority),
-		Timeout:    2 * time.Hour, // Default timeout for tasks
+		Model:      task.Model,
+		Tags:       task.Tags,
+		Env:        task.Env,
+		SecretEnv:  task.SecretEnv,
+		Timeout:    ee.config.Execution.Timeout,
+		MaxCostUSD: task.MaxCostUSD,
 		TaskInfo: &TaskExecutionInfo{
 			TaskID:             task.ID,
 			TaskName:           task.Name,
@@ -220,6 +372,7 @@ func (ee *ExecutionEngine) ExecuteTask(ctx context.Context, task *Task) (*Unifie
 			Duration:     time.Duration(execution.DurationMS) * time.Millisecond,
 			FilesChanged: execution.Result.FilesChanged,
 			Error:        execution.Result.Error,
+			ErrorKind:    execution.Result.ErrorKind,
 		}
 	}
 
@@ -253,6 +406,52 @@ func FilterByStatus(status ExecutionStatus) ExecutionFilter {
 	}
 }
 
+// ValidateModel checks a model name against the configured allowlist.
+// An empty model or an empty allowlist always passes.
+func ValidateModel(model string, allowed []string) error {
+	if model == "" || len(allowed) == 0 {
+		return nil
+	}
+
+	for _, m := range allowed {
+		if m == model {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("model %q is not allowed (allowed: %s)", model, strings.Join(allowed, ", "))
+}
+
+// Output formats accepted by Claude Code's --output-format flag
+const (
+	OutputFormatText       = "text"
+	OutputFormatJSON       = "json"
+	OutputFormatStreamJSON = "stream-json"
+)
+
+// DefaultOutputFormat is used when no output format is configured
+const DefaultOutputFormat = OutputFormatStreamJSON
+
+// ValidateOutputFormat checks a configured output format against the formats
+// Claude Code supports. An empty format passes and means DefaultOutputFormat.
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case "", OutputFormatText, OutputFormatJSON, OutputFormatStreamJSON:
+		return nil
+	}
+
+	return fmt.Errorf("output format %q is not supported (supported: %s, %s, %s)",
+		format, OutputFormatText, OutputFormatJSON, OutputFormatStreamJSON)
+}
+
+// outputFormat returns the configured output format or the default
+func outputFormat(config *models.ClaudeConfig) string {
+	if config == nil || config.OutputFormat == "" {
+		return DefaultOutputFormat
+	}
+	return config.OutputFormat
+}
+
 // generateExecutionID generates a unique execution ID with type prefix
 func (ee *ExecutionEngine) generateExecutionID(execType ExecutionType) string {
 	return fmt.Sprintf("%s-%s", execType, utils.GenerateShortID())
@@ -0,0 +1,133 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestExecutionEngineChecksExecutableBeforeSession(t *testing.T) {
+	config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: "claude-not-installed"}
+	engine, err := NewExecutionEngine(config)
+	if err != nil {
+		t.Fatalf("NewExecutionEngine() failed: %v", err)
+	}
+
+	execution, err := engine.Execute(context.Background(), &ExecutionRequest{
+		Type:       ExecutionTypeTask,
+		WorkingDir: t.TempDir(),
+		Prompt:     "do it",
+	})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want the missing executable reported")
+	}
+	if execution == nil || execution.Result == nil || execution.Result.ErrorKind != ErrorKindSetup {
+		t.Errorf("Execute() execution = %+v, want a setup failure", execution)
+	}
+
+	// Metadata is written once the session is started; nothing should have
+	// got that far
+	if files, _ := filepath.Glob(filepath.Join(LogDir(config), "metadata", "*")); len(files) != 0 {
+		t.Errorf("Execute() wrote session metadata %v before checking the executable", files)
+	}
+}
+
+func TestExecutionEngineRejectsDisallowedModel(t *testing.T) {
+	config := &models.ClaudeConfig{
+		ConfigDir:     t.TempDir(),
+		Executable:    writeFakeClaude(t, "exit 0"),
+		AllowedModels: []string{"sonnet"},
+	}
+	engine, err := NewExecutionEngine(config)
+	if err != nil {
+		t.Fatalf("NewExecutionEngine() failed: %v", err)
+	}
+
+	execution, err := engine.Execute(context.Background(), &ExecutionRequest{
+		Type:       ExecutionTypeTask,
+		WorkingDir: t.TempDir(),
+		Prompt:     "do it",
+		Model:      "opus",
+	})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want the disallowed model reported")
+	}
+	if execution == nil || execution.Status != ExecutionStatusFailed || execution.Result == nil || execution.Result.ErrorKind != ErrorKindSetup {
+		t.Fatalf("Execute() execution = %+v, want a failed setup", execution)
+	}
+	if execution.Result.ErrorKind.Retryable() {
+		t.Error("a disallowed model should not be retried")
+	}
+	if files, _ := filepath.Glob(filepath.Join(LogDir(config), "metadata", "*")); len(files) != 0 {
+		t.Errorf("Execute() wrote session metadata %v for a disallowed model", files)
+	}
+}
+
+func TestExecutionEngineStopsCancelledExecution(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	// A private tmux server keeps the test away from the user's sessions
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+
+	config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: writeFakeClaude(t, "sleep 30")}
+	engine, err := NewExecutionEngine(config)
+	if err != nil {
+		t.Fatalf("NewExecutionEngine() failed: %v", err)
+	}
+	engine.cancelPollInterval = 10 * time.Millisecond
+	em, err := NewExecutionManager(config)
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+
+	// Cancel the execution the way gwq task cancel does once it is running
+	cancelErr := make(chan error, 1)
+	go func() {
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			executions, _ := engine.ListExecutions()
+			if len(executions) == 1 && executions[0].TmuxSession != "" {
+				_, err := em.CancelExecution(executions[0].ExecutionID)
+				cancelErr <- err
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancelErr <- errors.New("execution never started")
+	}()
+
+	start := time.Now()
+	execution, err := engine.Execute(context.Background(), &ExecutionRequest{
+		Type:       ExecutionTypeTask,
+		WorkingDir: t.TempDir(),
+		Prompt:     "do it",
+	})
+	if err := <-cancelErr; err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	if !errors.Is(err, ErrExecutionCancelled) {
+		t.Errorf("Execute() error = %v, want %v", err, ErrExecutionCancelled)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Execute() took %s, want Claude stopped when the execution was cancelled", elapsed)
+	}
+	if execution == nil || execution.Status != ExecutionStatusAborted || execution.Result == nil || execution.Result.ErrorKind != ErrorKindCancelled {
+		t.Fatalf("Execute() execution = %+v, want it aborted as cancelled", execution)
+	}
+
+	saved, err := engine.GetExecution(execution.ExecutionID)
+	if err != nil {
+		t.Fatalf("GetExecution() failed: %v", err)
+	}
+	if saved.Status != ExecutionStatusAborted {
+		t.Errorf("saved Status = %s, want %s", saved.Status, ExecutionStatusAborted)
+	}
+}
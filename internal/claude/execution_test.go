@@ -0,0 +1,1096 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/d-kuro/gwq/pkg/system"
+)
+
+func TestAutoCleanupLogsRetention(t *testing.T) {
+	tempDir := t.TempDir()
+	config := &models.ClaudeConfig{
+		ConfigDir: tempDir,
+		Execution: models.ClaudeExecutionConfig{
+			RetentionDays: 1,
+		},
+	}
+
+	em, err := NewExecutionManager(config)
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+
+	executionsDir := filepath.Join(em.GetLogDir(), "executions")
+	oldLog := filepath.Join(executionsDir, GenerateLogFileName(time.Now().AddDate(0, 0, -3), "task-old"))
+	newLog := filepath.Join(executionsDir, GenerateLogFileName(time.Now(), "task-new"))
+
+	for _, path := range []string{oldLog, newLog} {
+		if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+			t.Fatalf("Failed to create log file: %v", err)
+		}
+	}
+
+	if err := em.autoCleanupLogs(); err != nil {
+		t.Fatalf("autoCleanupLogs() failed: %v", err)
+	}
+
+	if _, err := os.Stat(oldLog); !os.IsNotExist(err) {
+		t.Errorf("Expected old log %s to be removed", oldLog)
+	}
+	if _, err := os.Stat(newLog); err != nil {
+		t.Errorf("Expected new log %s to survive: %v", newLog, err)
+	}
+}
+
+func TestRetentionDaysDefault(t *testing.T) {
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+
+	if got := em.retentionDays(); got != defaultRetentionDays {
+		t.Errorf("retentionDays() = %d, want %d", got, defaultRetentionDays)
+	}
+}
+
+func TestResumeExecutionMissingSession(t *testing.T) {
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-resume",
+		StartTime:   time.Now(),
+		Status:      ExecutionStatusAborted,
+		TmuxSession: "gwq-claude-exec-exec-resume-20000101000000",
+	}
+	metadataFile := filepath.Join(em.GetLogDir(), "metadata", GenerateMetadataFileName(metadata.StartTime, metadata.ExecutionID))
+	if err := em.saveMetadata(metadata, metadataFile); err != nil {
+		t.Fatalf("saveMetadata() failed: %v", err)
+	}
+
+	if err := em.ResumeExecution(context.Background(), metadata.ExecutionID); err == nil {
+		t.Error("Expected error when resuming an execution whose tmux session is gone")
+	}
+
+	loaded, err := em.LoadMetadata(metadata.ExecutionID)
+	if err != nil {
+		t.Fatalf("LoadMetadata() failed: %v", err)
+	}
+	if loaded.Status != ExecutionStatusAborted {
+		t.Errorf("Status = %s, want %s", loaded.Status, ExecutionStatusAborted)
+	}
+}
+
+func TestResumeExecutionAppendsToExistingLog(t *testing.T) {
+	sessions := &mockSessionManager{alive: map[string]bool{"gwq-resume": true}}
+	em, err := NewExecutionManagerWithSessions(&models.ClaudeConfig{ConfigDir: t.TempDir()}, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+	em.monitorInterval = 10 * time.Millisecond
+
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-resume-log",
+		StartTime:   time.Now(),
+		Status:      ExecutionStatusAborted,
+		TmuxSession: "gwq-resume",
+	}
+	writeWatchTestMetadata(t, em, metadata)
+	logFile := em.executionLogFile(metadata)
+	before := `{"type":"assistant","message":{"content":[{"type":"text","text":"before"}]}}` + "\n"
+	if err := os.WriteFile(logFile, []byte(before), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pane prints the rest of the output once attached, then the session ends
+	after := `{"type":"result","result":"after","cost_usd":0.5}` + "\n"
+	sessions.onPipePane = func(sessionName, shellCommand string) error {
+		go func() {
+			pipe, err := os.OpenFile(namedPipePath("exec-resume-log"), os.O_WRONLY, 0)
+			if err != nil {
+				return
+			}
+			_, _ = pipe.WriteString(after)
+			sessions.mu.Lock()
+			delete(sessions.alive, sessionName)
+			sessions.mu.Unlock()
+			_ = pipe.Close()
+		}()
+		return nil
+	}
+
+	if err := em.ResumeExecution(context.Background(), "exec-resume-log"); err != nil {
+		t.Fatalf("ResumeExecution() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		saved, err := em.LoadMetadata("exec-resume-log")
+		if err != nil {
+			t.Fatalf("LoadMetadata() failed: %v", err)
+		}
+		if saved.Status == ExecutionStatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Status = %s, want the resumed execution to complete", saved.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	entries, err := NewLogProcessor().loadJSONLog(logFile)
+	if err != nil {
+		t.Fatalf("loadJSONLog() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Type != "assistant" || entries[1].Type != "result" {
+		t.Errorf("log entries = %+v, want the earlier output followed by the resumed output", entries)
+	}
+}
+
+func TestExecutionManagerBuildClaudeCommandModel(t *testing.T) {
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: "claude"})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+
+	for _, model := range []string{"opus", "opus; rm -rf ~", "claude opus"} {
+		assertModelArg(t, em.buildClaudeCommand("do it", model), model)
+	}
+
+	cmd := em.buildClaudeCommand("do it", "")
+	if strings.Contains(cmd, "--model") {
+		t.Errorf("buildClaudeCommand() = %q, want no --model flag", cmd)
+	}
+}
+
+// assertModelArg fails unless the shell splits command into arguments where
+// --model is followed by model as a single argument
+func assertModelArg(t *testing.T, command, model string) {
+	t.Helper()
+
+	out, err := exec.Command("bash", "-c", "set -- "+command+`; printf '%s\n' "$@"`).Output()
+	if err != nil {
+		t.Fatalf("failed to split %q: %v", command, err)
+	}
+	args := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	for i, arg := range args {
+		if arg == "--model" && i+1 < len(args) {
+			if args[i+1] != model {
+				t.Errorf("buildClaudeCommand() = %q passes --model %q, want %q", command, args[i+1], model)
+			}
+			return
+		}
+	}
+	t.Errorf("buildClaudeCommand() = %q has no --model argument", command)
+}
+
+// writeWatchTestMetadata persists metadata for a watched execution
+func writeWatchTestMetadata(t *testing.T, em *ExecutionManager, metadata *ExecutionMetadata) {
+	t.Helper()
+	path := filepath.Join(em.logDir, "metadata", GenerateMetadataFileName(metadata.StartTime, metadata.ExecutionID))
+	if err := em.saveMetadata(metadata, path); err != nil {
+		t.Fatalf("saveMetadata() failed: %v", err)
+	}
+}
+
+func TestWatchExecutionFollowsIncrementalLog(t *testing.T) {
+	lines := []string{
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"hello"}]}}` + "\n",
+		`{"type":"result","result":"done","cost_usd":0.5}` + "\n",
+	}
+
+	tests := []struct {
+		name string
+		raw  bool
+		want []string
+	}{
+		{
+			name: "formatted",
+			want: []string{"🤖 hello\n", "\n✅ Result: done\n", "💰 Cost: $0.5000\n"},
+		},
+		{
+			name: "raw",
+			raw:  true,
+			want: lines,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+			if err != nil {
+				t.Fatalf("NewExecutionManager() failed: %v", err)
+			}
+
+			metadata := &ExecutionMetadata{
+				ExecutionID: "exec-watch",
+				StartTime:   time.Now(),
+				Status:      ExecutionStatusRunning,
+			}
+			writeWatchTestMetadata(t, em, metadata)
+
+			var out bytes.Buffer
+			done := make(chan error, 1)
+			go func() {
+				done <- em.WatchExecutionWithOptions(context.Background(), metadata.ExecutionID, WatchOptions{
+					Raw:          tt.raw,
+					Output:       &out,
+					PollInterval: 5 * time.Millisecond,
+				})
+			}()
+
+			// The log file appears after the watch has started
+			time.Sleep(20 * time.Millisecond)
+			logFile := filepath.Join(em.logDir, "executions", GenerateLogFileName(metadata.StartTime, metadata.ExecutionID))
+			f, err := os.Create(logFile)
+			if err != nil {
+				t.Fatalf("failed to create log file: %v", err)
+			}
+
+			// Write the first line in two pieces to exercise partial reads
+			half := len(lines[0]) / 2
+			for _, chunk := range []string{lines[0][:half], lines[0][half:], lines[1]} {
+				if _, err := f.WriteString(chunk); err != nil {
+					t.Fatalf("failed to write log: %v", err)
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			_ = f.Close()
+
+			metadata.Status = ExecutionStatusCompleted
+			writeWatchTestMetadata(t, em, metadata)
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("WatchExecutionWithOptions() error = %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("WatchExecutionWithOptions() did not return after execution completed")
+			}
+
+			if got, want := out.String(), strings.Join(tt.want, ""); got != want {
+				t.Errorf("output = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestStreamExecutionEmitsTypedEvents(t *testing.T) {
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+
+	if _, err := em.StreamExecution(context.Background(), "exec-missing"); err == nil {
+		t.Error("StreamExecution() should fail for an unknown execution")
+	}
+
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-stream",
+		StartTime:   time.Now(),
+		Status:      ExecutionStatusRunning,
+	}
+	writeWatchTestMetadata(t, em, metadata)
+
+	events, err := em.StreamExecution(context.Background(), metadata.ExecutionID)
+	if err != nil {
+		t.Fatalf("StreamExecution() error = %v", err)
+	}
+
+	logFile := filepath.Join(em.logDir, "executions", GenerateLogFileName(metadata.StartTime, metadata.ExecutionID))
+	f, err := os.Create(logFile)
+	if err != nil {
+		t.Fatalf("failed to create log file: %v", err)
+	}
+	lines := []string{
+		`{"type":"system","subtype":"init"}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"reading"},{"type":"tool_use","id":"tu1","name":"Read","input":{"file_path":"a.go"}}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"tu1","content":"package a"}]}}`,
+		`{"type":"result","subtype":"success","result":"done","total_cost_usd":0.25,"duration_ms":1200}`,
+	}
+	for _, line := range lines {
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatalf("failed to write log: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	_ = f.Close()
+
+	metadata.Status = ExecutionStatusCompleted
+	writeWatchTestMetadata(t, em, metadata)
+
+	var got []LogEvent
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				done = true
+				break
+			}
+			got = append(got, event)
+		case <-timeout:
+			t.Fatal("StreamExecution() channel was not closed after execution completed")
+		}
+	}
+
+	want := []LogEvent{
+		{Type: LogEventText, Text: "reading"},
+		{Type: LogEventToolUse, ToolName: "Read", ToolUseID: "tu1", ToolInput: `{"file_path":"a.go"}`},
+		{Type: LogEventToolResult, ToolUseID: "tu1", Text: "package a"},
+		{Type: LogEventResult, Text: "done", CostUSD: 0.25, DurationMS: 1200},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %+v, want %+v", got, want)
+	}
+}
+
+func TestWatchExecutionMissingLog(t *testing.T) {
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+
+	finished := &ExecutionMetadata{ExecutionID: "exec-done", StartTime: time.Now(), Status: ExecutionStatusCompleted}
+	writeWatchTestMetadata(t, em, finished)
+	if err := em.WatchExecution(context.Background(), finished.ExecutionID); err == nil {
+		t.Error("WatchExecution() should fail when a finished execution has no log")
+	}
+
+	running := &ExecutionMetadata{ExecutionID: "exec-pending", StartTime: time.Now(), Status: ExecutionStatusRunning}
+	writeWatchTestMetadata(t, em, running)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = em.WatchExecutionWithOptions(ctx, running.ExecutionID, WatchOptions{PollInterval: 5 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WatchExecutionWithOptions() error = %v, want context deadline exceeded", err)
+	}
+}
+
+// mockSessionManager is an in-memory SessionManager that tracks which tmux
+// sessions are alive
+type mockSessionManager struct {
+	mu     sync.Mutex
+	alive  map[string]bool
+	onKill func(sessionName string)
+	// onHasSession is called with the result of every HasSession check
+	onHasSession func(sessionName string, alive bool)
+	// onPipePane is called in place of attaching a command to the pane output
+	onPipePane func(sessionName, shellCommand string) error
+}
+
+func (m *mockSessionManager) CreateSession(ctx context.Context, opts tmux.SessionOptions) (*tmux.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name := fmt.Sprintf("gwq-%s-%s", opts.Context, opts.Identifier)
+	if m.alive == nil {
+		m.alive = make(map[string]bool)
+	}
+	m.alive[name] = true
+	return &tmux.Session{SessionName: name, Context: opts.Context, Identifier: opts.Identifier}, nil
+}
+
+func (m *mockSessionManager) ListSessions() ([]*tmux.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sessions []*tmux.Session
+	for name := range m.alive {
+		sessions = append(sessions, &tmux.Session{SessionName: name})
+	}
+	return sessions, nil
+}
+
+func (m *mockSessionManager) HasSession(sessionName string) bool {
+	m.mu.Lock()
+	alive := m.alive[sessionName]
+	m.mu.Unlock()
+	if m.onHasSession != nil {
+		m.onHasSession(sessionName, alive)
+	}
+	return alive
+}
+
+func (m *mockSessionManager) PipePane(sessionName, shellCommand string) error {
+	if m.onPipePane != nil {
+		return m.onPipePane(sessionName, shellCommand)
+	}
+	return nil
+}
+
+func (m *mockSessionManager) KillSessionDirect(session *tmux.Session) error {
+	m.mu.Lock()
+	delete(m.alive, session.SessionName)
+	m.mu.Unlock()
+	if m.onKill != nil {
+		m.onKill(session.SessionName)
+	}
+	return nil
+}
+
+func TestCancelExecution(t *testing.T) {
+	var killed []string
+	sessions := &mockSessionManager{
+		alive:  map[string]bool{"gwq-running": true},
+		onKill: func(name string) { killed = append(killed, name) },
+	}
+	em, err := NewExecutionManagerWithSessions(&models.ClaudeConfig{ConfigDir: t.TempDir()}, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+
+	running := &ExecutionMetadata{
+		ExecutionID: "exec-running",
+		StartTime:   time.Now().Add(-time.Minute),
+		Status:      ExecutionStatusRunning,
+		TmuxSession: "gwq-running",
+	}
+	finished := &ExecutionMetadata{
+		ExecutionID: "exec-finished",
+		StartTime:   time.Now().Add(-time.Hour),
+		Status:      ExecutionStatusCompleted,
+		TmuxSession: "gwq-finished",
+	}
+	writeWatchTestMetadata(t, em, running)
+	writeWatchTestMetadata(t, em, finished)
+
+	metadata, err := em.CancelExecution(running.ExecutionID)
+	if err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	if len(killed) != 1 || killed[0] != "gwq-running" {
+		t.Errorf("killed sessions = %v, want [gwq-running]", killed)
+	}
+
+	saved, err := em.LoadMetadata(running.ExecutionID)
+	if err != nil {
+		t.Fatalf("LoadMetadata() failed: %v", err)
+	}
+	if saved.Status != ExecutionStatusAborted {
+		t.Errorf("Status = %s, want %s", saved.Status, ExecutionStatusAborted)
+	}
+	if saved.EndTime == nil {
+		t.Error("EndTime should be set")
+	}
+	if saved.DurationMS < time.Minute.Milliseconds() || saved.DurationMS != metadata.DurationMS {
+		t.Errorf("DurationMS = %d, want at least one minute and equal to returned %d", saved.DurationMS, metadata.DurationMS)
+	}
+	if !em.wasCancelled(running.ExecutionID) {
+		t.Error("wasCancelled() = false after CancelExecution()")
+	}
+
+	if _, err := em.CancelExecution(finished.ExecutionID); err == nil {
+		t.Error("CancelExecution() should fail for an execution that is not running")
+	}
+	if len(killed) != 1 {
+		t.Errorf("finished execution's session should not be killed, killed = %v", killed)
+	}
+
+	orphaned := &ExecutionMetadata{
+		ExecutionID: "exec-orphaned",
+		StartTime:   time.Now().Add(-time.Minute),
+		Status:      ExecutionStatusRunning,
+		TmuxSession: "gwq-gone",
+	}
+	writeWatchTestMetadata(t, em, orphaned)
+	if _, err := em.CancelExecution(orphaned.ExecutionID); err == nil {
+		t.Error("CancelExecution() should fail when the tmux session is not found")
+	}
+	if saved, err := em.LoadMetadata(orphaned.ExecutionID); err != nil || saved.Status != ExecutionStatusRunning {
+		t.Errorf("execution without a session should be left alone, got %+v, %v", saved, err)
+	}
+}
+
+func TestMonitorExecutionTimeout(t *testing.T) {
+	logCaptureDone := make(chan error, 1)
+	var killed []string
+	sessions := &mockSessionManager{
+		alive: map[string]bool{"gwq-slow": true},
+		onKill: func(sessionName string) {
+			killed = append(killed, sessionName)
+			logCaptureDone <- nil
+		},
+	}
+	em, err := NewExecutionManagerWithSessions(&models.ClaudeConfig{ConfigDir: t.TempDir()}, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-slow",
+		StartTime:   time.Now(),
+		Status:      ExecutionStatusRunning,
+		TmuxSession: "gwq-slow",
+		Timeout:     100 * time.Millisecond,
+	}
+	session := &tmux.Session{SessionName: "gwq-slow"}
+
+	done := make(chan struct{})
+	go func() {
+		em.monitorExecution(context.Background(), metadata, session, logCaptureDone)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitorExecution() did not stop after the timeout")
+	}
+
+	if len(killed) != 1 || killed[0] != "gwq-slow" {
+		t.Errorf("killed sessions = %v, want [gwq-slow]", killed)
+	}
+
+	saved, err := em.LoadMetadata("exec-slow")
+	if err != nil {
+		t.Fatalf("LoadMetadata() failed: %v", err)
+	}
+	if saved.Status != ExecutionStatusFailed {
+		t.Errorf("Status = %s, want %s", saved.Status, ExecutionStatusFailed)
+	}
+	if saved.Result == nil || saved.Result.ErrorKind != ErrorKindTimeout {
+		t.Errorf("Result = %+v, want error kind %s", saved.Result, ErrorKindTimeout)
+	}
+	if saved.EndTime == nil {
+		t.Error("EndTime not set")
+	}
+}
+
+func TestExecuteAppliesConfiguredTimeout(t *testing.T) {
+	sessions := &mockSessionManager{}
+	// The fake session never writes, so close the pipe when it is killed
+	sessions.onKill = func(string) {
+		if pipe, err := os.OpenFile(namedPipePath("exec-config-timeout"), os.O_WRONLY, 0); err == nil {
+			_ = pipe.Close()
+		}
+	}
+	config := &models.ClaudeConfig{
+		ConfigDir:  t.TempDir(),
+		Executable: writeFakeClaude(t, "exit 0"),
+		Execution:  models.ClaudeExecutionConfig{Timeout: 100 * time.Millisecond},
+	}
+	em, err := NewExecutionManagerWithSessions(config, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+
+	metadata := &ExecutionMetadata{
+		ExecutionID:      "exec-config-timeout",
+		Prompt:           "never finishes",
+		WorkingDirectory: t.TempDir(),
+		StartTime:        time.Now(),
+		Status:           ExecutionStatusRunning,
+	}
+	if _, err := em.Execute(context.Background(), metadata); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		saved, err := em.LoadMetadata("exec-config-timeout")
+		if err == nil && saved.Status == ExecutionStatusFailed {
+			if saved.Timeout != 100*time.Millisecond {
+				t.Errorf("Timeout = %s, want the configured 100ms", saved.Timeout)
+			}
+			if saved.Result == nil || saved.Result.ErrorKind != ErrorKindTimeout {
+				t.Errorf("Result = %+v, want error kind %s", saved.Result, ErrorKindTimeout)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("execution was not timed out, metadata = %+v, err = %v", saved, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	if len(sessions.alive) != 0 {
+		t.Errorf("sessions = %v, want the timed out session killed", sessions.alive)
+	}
+}
+
+func TestExecuteStopsSessionOverBudget(t *testing.T) {
+	sessions := &mockSessionManager{}
+	config := &models.ClaudeConfig{
+		ConfigDir:  t.TempDir(),
+		Executable: writeFakeClaude(t, "exit 0"),
+		MaxCostUSD: 100,
+	}
+	em, err := NewExecutionManagerWithSessions(config, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+
+	metadata := &ExecutionMetadata{
+		ExecutionID:      "exec-budget",
+		Prompt:           "expensive",
+		WorkingDirectory: t.TempDir(),
+		StartTime:        time.Now(),
+		Status:           ExecutionStatusRunning,
+		MaxCostUSD:       1,
+	}
+	if _, err := em.Execute(context.Background(), metadata); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// Stand in for the session: stream costs crossing the budget, and stop
+	// writing only once the session is killed
+	pipe, err := os.OpenFile(namedPipePath("exec-budget"), os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("failed to open pipe: %v", err)
+	}
+	var closeOnce sync.Once
+	closePipe := func() { closeOnce.Do(func() { _ = pipe.Close() }) }
+	sessions.onKill = func(string) { closePipe() }
+	defer closePipe()
+	for range 3 {
+		if _, err := pipe.WriteString(`{"type":"assistant","cost_usd":0.6}` + "\n"); err != nil {
+			t.Fatalf("failed to write to pipe: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		saved, err := em.LoadMetadata("exec-budget")
+		if err == nil && saved.Status == ExecutionStatusFailed {
+			if saved.Result == nil || saved.Result.ErrorKind != ErrorKindBudget || !strings.Contains(saved.Result.Error, "$1.0000") {
+				t.Errorf("Result = %+v, want a budget error against the $1 limit", saved.Result)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("execution was not stopped, metadata = %+v, err = %v", saved, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	if len(sessions.alive) != 0 {
+		t.Errorf("sessions = %v, want the session over budget killed", sessions.alive)
+	}
+}
+
+func TestMonitorExecutionSessionEnds(t *testing.T) {
+	captureErr := errors.New("broken pipe")
+
+	tests := []struct {
+		name string
+		// viaTicker delivers the log capture result only after the monitor
+		// has seen the session disappear
+		viaTicker  bool
+		captureErr error
+		wantStatus ExecutionStatus
+	}{
+		{name: "log capture finishes", captureErr: nil, wantStatus: ExecutionStatusCompleted},
+		{name: "log capture fails", captureErr: captureErr, wantStatus: ExecutionStatusFailed},
+		{name: "session ends", viaTicker: true, captureErr: nil, wantStatus: ExecutionStatusCompleted},
+		{name: "session ends with capture error", viaTicker: true, captureErr: captureErr, wantStatus: ExecutionStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logCaptureDone := make(chan error, 1)
+			sessions := &mockSessionManager{}
+			if tt.viaTicker {
+				var once sync.Once
+				sessions.onHasSession = func(sessionName string, alive bool) {
+					if !alive {
+						once.Do(func() { logCaptureDone <- tt.captureErr })
+					}
+				}
+			} else {
+				logCaptureDone <- tt.captureErr
+			}
+
+			em, err := NewExecutionManagerWithSessions(&models.ClaudeConfig{ConfigDir: t.TempDir()}, sessions)
+			if err != nil {
+				t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+			}
+			em.monitorInterval = 10 * time.Millisecond
+
+			metadata := &ExecutionMetadata{
+				ExecutionID: "exec-done",
+				StartTime:   time.Now(),
+				Status:      ExecutionStatusRunning,
+				TmuxSession: "gwq-done",
+			}
+			writeWatchTestMetadata(t, em, metadata)
+
+			done := make(chan struct{})
+			go func() {
+				em.monitorExecution(context.Background(), metadata, &tmux.Session{SessionName: "gwq-done"}, logCaptureDone)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("monitorExecution() did not stop after the session ended")
+			}
+
+			saved, err := em.LoadMetadata("exec-done")
+			if err != nil {
+				t.Fatalf("LoadMetadata() failed: %v", err)
+			}
+			if saved.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", saved.Status, tt.wantStatus)
+			}
+			if saved.EndTime == nil {
+				t.Error("EndTime not set")
+			}
+		})
+	}
+}
+
+func TestMonitorExecutionRecordsExitStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string // Written by the session; empty writes nothing
+		wantStatus ExecutionStatus
+		wantExit   int
+	}{
+		{name: "success", status: "0\n", wantStatus: ExecutionStatusCompleted},
+		{name: "claude failed", status: "3\n", wantStatus: ExecutionStatusFailed, wantExit: 3},
+		{name: "no status", wantStatus: ExecutionStatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			em, err := NewExecutionManagerWithSessions(&models.ClaudeConfig{ConfigDir: t.TempDir()}, &mockSessionManager{})
+			if err != nil {
+				t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+			}
+
+			executionID := "exec-status-" + strings.ReplaceAll(tt.name, " ", "-")
+			metadata := &ExecutionMetadata{
+				ExecutionID: executionID,
+				StartTime:   time.Now(),
+				Status:      ExecutionStatusRunning,
+				TmuxSession: "gwq-status",
+			}
+			writeWatchTestMetadata(t, em, metadata)
+			if tt.status != "" {
+				if err := os.WriteFile(exitStatusPath(executionID), []byte(tt.status), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			logCaptureDone := make(chan error, 1)
+			logCaptureDone <- nil
+			em.monitorExecution(context.Background(), metadata, &tmux.Session{SessionName: "gwq-status"}, logCaptureDone)
+
+			saved, err := em.LoadMetadata(executionID)
+			if err != nil {
+				t.Fatalf("LoadMetadata() failed: %v", err)
+			}
+			if saved.Status != tt.wantStatus || saved.ExitCode != tt.wantExit {
+				t.Errorf("Status, ExitCode = %s, %d, want %s, %d", saved.Status, saved.ExitCode, tt.wantStatus, tt.wantExit)
+			}
+			if tt.wantExit != 0 && (saved.Result == nil || saved.Result.ExitCode != tt.wantExit || saved.Result.ErrorKind != ErrorKindCommand) {
+				t.Errorf("Result = %+v, want a command error with exit code %d", saved.Result, tt.wantExit)
+			}
+			if _, err := os.Stat(exitStatusPath(executionID)); !os.IsNotExist(err) {
+				t.Error("the exit status file should be removed once recorded")
+			}
+		})
+	}
+}
+
+func TestMonitorExecutionKeepsCancelledStatus(t *testing.T) {
+	sessions := &mockSessionManager{alive: map[string]bool{"gwq-cancel": true}}
+	em, err := NewExecutionManagerWithSessions(&models.ClaudeConfig{ConfigDir: t.TempDir()}, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+	em.monitorInterval = 10 * time.Millisecond
+
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-cancel",
+		StartTime:   time.Now(),
+		Status:      ExecutionStatusRunning,
+		TmuxSession: "gwq-cancel",
+	}
+	writeWatchTestMetadata(t, em, metadata)
+
+	if _, err := em.CancelExecution("exec-cancel"); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+
+	logCaptureDone := make(chan error, 1)
+	logCaptureDone <- nil
+	em.monitorExecution(context.Background(), metadata, &tmux.Session{SessionName: "gwq-cancel"}, logCaptureDone)
+
+	saved, err := em.LoadMetadata("exec-cancel")
+	if err != nil {
+		t.Fatalf("LoadMetadata() failed: %v", err)
+	}
+	if saved.Status != ExecutionStatusAborted {
+		t.Errorf("Status = %s, want %s", saved.Status, ExecutionStatusAborted)
+	}
+}
+
+func TestReconcileExecutions(t *testing.T) {
+	sessions := &mockSessionManager{alive: map[string]bool{"gwq-alive": true}}
+	em, err := NewExecutionManagerWithSessions(&models.ClaudeConfig{ConfigDir: t.TempDir()}, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	resultLog := `{"type":"assistant","message":{"content":[]}}` + "\n" + `{"type":"result","result":"done","cost_usd":0.25}` + "\n"
+	totalCostLog := `{"type":"result","result":"done","cost_usd":0.25,"total_cost_usd":0.75,"duration_ms":1200}` + "\n"
+
+	tests := []struct {
+		name       string
+		metadata   ExecutionMetadata
+		log        *string
+		wantStatus ExecutionStatus
+	}{
+		{
+			name:       "session alive",
+			metadata:   ExecutionMetadata{ExecutionID: "exec-alive", StartTime: old, Status: ExecutionStatusRunning, TmuxSession: "gwq-alive"},
+			wantStatus: ExecutionStatusRunning,
+		},
+		{
+			name:       "session gone with result",
+			metadata:   ExecutionMetadata{ExecutionID: "exec-result", StartTime: old, Status: ExecutionStatusRunning, TmuxSession: "gwq-gone-1"},
+			log:        &resultLog,
+			wantStatus: ExecutionStatusCompleted,
+		},
+		{
+			name:       "session gone with total cost",
+			metadata:   ExecutionMetadata{ExecutionID: "exec-total", StartTime: old, Status: ExecutionStatusRunning, TmuxSession: "gwq-gone-5"},
+			log:        &totalCostLog,
+			wantStatus: ExecutionStatusCompleted,
+		},
+		{
+			name:       "session gone without result",
+			metadata:   ExecutionMetadata{ExecutionID: "exec-partial", StartTime: old, Status: ExecutionStatusRunning, TmuxSession: "gwq-gone-2"},
+			log:        func() *string { s := `{"type":"assistant"}` + "\n"; return &s }(),
+			wantStatus: ExecutionStatusAborted,
+		},
+		{
+			name:       "session gone without log",
+			metadata:   ExecutionMetadata{ExecutionID: "exec-nolog", StartTime: old, Status: ExecutionStatusRunning, TmuxSession: "gwq-gone-3"},
+			wantStatus: ExecutionStatusAborted,
+		},
+		{
+			name:       "starting without session",
+			metadata:   ExecutionMetadata{ExecutionID: "exec-starting", StartTime: time.Now(), Status: ExecutionStatusRunning},
+			wantStatus: ExecutionStatusRunning,
+		},
+		{
+			name:       "stale without session",
+			metadata:   ExecutionMetadata{ExecutionID: "exec-stale", StartTime: old, Status: ExecutionStatusRunning},
+			wantStatus: ExecutionStatusAborted,
+		},
+		{
+			name:       "already finished",
+			metadata:   ExecutionMetadata{ExecutionID: "exec-failed", StartTime: old, Status: ExecutionStatusFailed, TmuxSession: "gwq-gone-4"},
+			wantStatus: ExecutionStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		metadata := tt.metadata
+		writeWatchTestMetadata(t, em, &metadata)
+		if tt.log != nil {
+			logFile := filepath.Join(em.logDir, "executions", GenerateLogFileName(metadata.StartTime, metadata.ExecutionID))
+			if err := os.WriteFile(logFile, []byte(*tt.log), 0644); err != nil {
+				t.Fatalf("failed to write log: %v", err)
+			}
+		}
+	}
+
+	repaired, err := em.ReconcileExecutions()
+	if err != nil {
+		t.Fatalf("ReconcileExecutions() error = %v", err)
+	}
+	if repaired != 5 {
+		t.Errorf("ReconcileExecutions() repaired = %d, want 5", repaired)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := em.LoadMetadata(tt.metadata.ExecutionID)
+			if err != nil {
+				t.Fatalf("LoadMetadata() failed: %v", err)
+			}
+			if loaded.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", loaded.Status, tt.wantStatus)
+			}
+			if tt.wantStatus != tt.metadata.Status && loaded.EndTime == nil {
+				t.Error("EndTime should be set on repaired executions")
+			}
+		})
+	}
+
+	completed, _ := em.LoadMetadata("exec-result")
+	if completed.CostUSD != 0.25 {
+		t.Errorf("CostUSD = %v, want cost from result entry", completed.CostUSD)
+	}
+	total, _ := em.LoadMetadata("exec-total")
+	if total.CostUSD != 0.75 || total.DurationMS != 1200 {
+		t.Errorf("CostUSD, DurationMS = %v, %d; want the session total 0.75 and 1200 from the result entry", total.CostUSD, total.DurationMS)
+	}
+
+	// A second pass finds nothing left to repair
+	if repaired, err := em.ReconcileExecutions(); err != nil || repaired != 0 {
+		t.Errorf("second ReconcileExecutions() = %d, %v; want 0, nil", repaired, err)
+	}
+}
+
+func TestSaveMetadataConcurrentReadersSeeWholeFiles(t *testing.T) {
+	configDir := t.TempDir()
+	startTime := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	const executionID = "exec-concurrent"
+
+	// Separate managers share no mutex, like a worker and a gwq task logs process
+	newManager := func() *ExecutionManager {
+		em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: configDir})
+		if err != nil {
+			t.Fatalf("NewExecutionManager() failed: %v", err)
+		}
+		return em
+	}
+	path := filepath.Join(newManager().GetLogDir(), "metadata", GenerateMetadataFileName(startTime, executionID))
+
+	// A large prompt makes a non-atomic write observable mid-way
+	prompt := strings.Repeat("x", 1<<20)
+	if err := newManager().saveMetadata(&ExecutionMetadata{ExecutionID: executionID, StartTime: startTime, Prompt: prompt}, path); err != nil {
+		t.Fatalf("saveMetadata() failed: %v", err)
+	}
+
+	const writers, readers, iterations = 4, 4, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*iterations+readers*iterations)
+
+	for w := 0; w < writers; w++ {
+		em := newManager()
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				metadata := &ExecutionMetadata{ExecutionID: executionID, StartTime: startTime, Prompt: prompt, ExitCode: w*iterations + i}
+				if err := em.saveMetadata(metadata, path); err != nil {
+					errs <- fmt.Errorf("saveMetadata() failed: %w", err)
+				}
+			}
+		}(w)
+	}
+
+	for r := 0; r < readers; r++ {
+		em := newManager()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					errs <- fmt.Errorf("failed to read metadata: %w", err)
+				} else if !json.Valid(data) {
+					errs <- fmt.Errorf("read partial JSON of %d bytes", len(data))
+				}
+
+				metadata, err := em.LoadMetadata(executionID)
+				if err != nil {
+					errs <- fmt.Errorf("LoadMetadata() failed: %w", err)
+					continue
+				}
+				if len(metadata.Prompt) != len(prompt) {
+					errs <- fmt.Errorf("LoadMetadata() returned a prompt of %d bytes, want %d", len(metadata.Prompt), len(prompt))
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("failed to read metadata directory: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("temporary file %s was left behind", entry.Name())
+		}
+	}
+}
+
+func TestSortExecutionsNewestFirst(t *testing.T) {
+	batch := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	executions := []ExecutionMetadata{
+		{ExecutionID: "exec-c", StartTime: batch},
+		{ExecutionID: "exec-old", StartTime: batch.Add(-time.Hour)},
+		{ExecutionID: "exec-a", StartTime: batch},
+		{ExecutionID: "exec-new", StartTime: batch.Add(time.Hour)},
+		{ExecutionID: "exec-b", StartTime: batch},
+	}
+
+	SortExecutionsNewestFirst(executions)
+
+	want := []string{"exec-new", "exec-a", "exec-b", "exec-c", "exec-old"}
+	var got []string
+	for _, exec := range executions {
+		got = append(got, exec.ExecutionID)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortExecutionsNewestFirst() order = %v, want %v", got, want)
+	}
+}
+
+func TestUnblockPipeReader(t *testing.T) {
+	pipePath := filepath.Join(t.TempDir(), "capture.pipe")
+	if err := system.NewStandardSystem().CreateNamedPipe(pipePath, 0600); err != nil {
+		t.Fatalf("CreateNamedPipe() failed: %v", err)
+	}
+
+	// Without a reader there is nothing to release
+	unblockPipeReader(pipePath)
+
+	opened := make(chan error, 1)
+	go func() {
+		pipe, err := os.OpenFile(pipePath, os.O_RDONLY, 0)
+		if err == nil {
+			_ = pipe.Close()
+		}
+		opened <- err
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		unblockPipeReader(pipePath)
+		select {
+		case err := <-opened:
+			if err != nil {
+				t.Fatalf("reader open failed: %v", err)
+			}
+			return
+		case <-deadline:
+			t.Fatal("unblockPipeReader() did not release the waiting reader")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// forEachLine calls fn for every line in r. Unlike bufio.Scanner there is no
+// maximum line length, so large tool results are never dropped. Trailing
+// "\r\n" is removed and a final line without a newline is still delivered.
+func forEachLine(r io.Reader, fn func(line string)) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			fn(strings.TrimRight(line, "\r\n"))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// forEachOutputRecord calls fn for every record of Claude output in r. With
+// the json output format Claude prints a single object, possibly spread over
+// several lines, which is delivered compacted as one record; any other
+// content, and every other format, is delivered line by line.
+func forEachOutputRecord(r io.Reader, format string, fn func(line string)) error {
+	if format != OutputFormatJSON {
+		return forEachLine(r, fn)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, trimmed); err == nil {
+			fn(compacted.String())
+			return nil
+		}
+	}
+
+	return forEachLine(bytes.NewReader(data), fn)
+}
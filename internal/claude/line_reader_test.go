@@ -0,0 +1,134 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestForEachLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "newline terminated", input: "a\nb\n", want: []string{"a", "b"}},
+		{name: "partial final line", input: "a\nb", want: []string{"a", "b"}},
+		{name: "carriage returns", input: "a\r\nb\r\n", want: []string{"a", "b"}},
+		{name: "empty lines kept", input: "a\n\nb\n", want: []string{"a", "", "b"}},
+		{name: "empty input", input: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			if err := forEachLine(strings.NewReader(tt.input), func(line string) {
+				got = append(got, line)
+			}); err != nil {
+				t.Fatalf("forEachLine() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("forEachLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// largeToolResultStream returns a stream with a >1MB JSON line followed by a
+// result line that has no trailing newline
+func largeToolResultStream(t *testing.T) (string, string) {
+	t.Helper()
+	content := strings.Repeat("x", 2*1024*1024)
+	line, err := json.Marshal(map[string]interface{}{
+		"type": "user",
+		"message": map[string]interface{}{
+			"content": []interface{}{map[string]interface{}{"type": "tool_result", "content": content}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal line: %v", err)
+	}
+	return string(line) + "\n" + `{"type":"result","result":"done","cost_usd":0.5}`, content
+}
+
+// assertLargeLogIntact checks that the captured log kept the large tool result and the final line
+func assertLargeLogIntact(t *testing.T, logFile, content string) {
+	t.Helper()
+	var lines []map[string]interface{}
+	file, err := os.Open(logFile)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	if err := forEachLine(file, func(line string) {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Errorf("invalid log line: %v", err)
+			return
+		}
+		lines = append(lines, entry)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("log has %d lines, want 2", len(lines))
+	}
+	msg := lines[0]["message"].(map[string]interface{})
+	item := msg["content"].([]interface{})[0].(map[string]interface{})
+	if item["content"] != content {
+		t.Errorf("large tool result was not recorded intact (len %d)", len(item["content"].(string)))
+	}
+	if lines[1]["type"] != "result" {
+		t.Errorf("final line type = %v, want result", lines[1]["type"])
+	}
+}
+
+func TestCaptureLogOutputLargeLine(t *testing.T) {
+	stream, content := largeToolResultStream(t)
+
+	t.Run("ExecutionManager", func(t *testing.T) {
+		em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("NewExecutionManager() failed: %v", err)
+		}
+		input := filepath.Join(t.TempDir(), "stream")
+		if err := os.WriteFile(input, []byte(stream), 0644); err != nil {
+			t.Fatal(err)
+		}
+		logFile := filepath.Join(t.TempDir(), "exec.jsonl")
+		metadata := &ExecutionMetadata{ExecutionID: "exec-large", StartTime: time.Now()}
+
+		if err := em.captureLogOutput(input, logFile, metadata, false, nil); err != nil {
+			t.Fatalf("captureLogOutput() error = %v", err)
+		}
+		assertLargeLogIntact(t, logFile, content)
+		if metadata.CostUSD != 0.5 {
+			t.Errorf("CostUSD = %v, want 0.5 from final line", metadata.CostUSD)
+		}
+	})
+
+	t.Run("ClaudeCodeExecutor", func(t *testing.T) {
+		cce := NewClaudeCodeExecutor(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+		input := filepath.Join(t.TempDir(), "stream")
+		if err := os.WriteFile(input, []byte(stream), 0644); err != nil {
+			t.Fatal(err)
+		}
+		logFile := filepath.Join(t.TempDir(), "exec.jsonl")
+		execution := &UnifiedExecution{ExecutionID: "task-large", ExecutionType: ExecutionTypeTask}
+
+		if err := cce.captureLogOutput(input, logFile, execution, nil); err != nil {
+			t.Fatalf("captureLogOutput() error = %v", err)
+		}
+		assertLargeLogIntact(t, logFile, content)
+		if execution.CostUSD != 0.5 {
+			t.Errorf("CostUSD = %v, want 0.5 from final line", execution.CostUSD)
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrLoadAverageUnsupported is returned by LoadAverage on platforms where the
+// system load cannot be read
+var ErrLoadAverageUnsupported = errors.New("load average is not supported on this platform")
+
+// parseLoadAverage returns the 1-minute load average from /proc/loadavg
+// ("0.52 0.58 0.59 1/389 12345") or sysctl vm.loadavg ("{ 0.52 0.58 0.59 }")
+func parseLoadAverage(s string) (float64, error) {
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(s), "{}"))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty load average")
+	}
+
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid load average %q: %w", fields[0], err)
+	}
+	return load, nil
+}
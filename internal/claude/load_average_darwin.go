@@ -0,0 +1,14 @@
+//go:build darwin
+
+package claude
+
+import "os/exec"
+
+// LoadAverage returns the system's 1-minute load average
+func LoadAverage() (float64, error) {
+	out, err := exec.Command("sysctl", "-n", "vm.loadavg").Output()
+	if err != nil {
+		return 0, err
+	}
+	return parseLoadAverage(string(out))
+}
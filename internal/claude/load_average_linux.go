@@ -0,0 +1,14 @@
+//go:build linux
+
+package claude
+
+import "os"
+
+// LoadAverage returns the system's 1-minute load average
+func LoadAverage() (float64, error) {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, err
+	}
+	return parseLoadAverage(string(data))
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package claude
+
+// LoadAverage returns ErrLoadAverageUnsupported on this platform
+func LoadAverage() (float64, error) {
+	return 0, ErrLoadAverageUnsupported
+}
@@ -0,0 +1,27 @@
+package claude
+
+import "testing"
+
+func TestParseLoadAverage(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{input: "0.52 0.58 0.59 1/389 12345\n", want: 0.52},
+		{input: "{ 1.75 1.60 1.52 }\n", want: 1.75},
+		{input: "", wantErr: true},
+		{input: "busy", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseLoadAverage(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLoadAverage(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseLoadAverage(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
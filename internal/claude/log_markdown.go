@@ -0,0 +1,178 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatMarkdown formats the execution as Markdown with headings, fenced code
+// blocks for tool input and a cost table
+func (lp *LogProcessor) FormatMarkdown(metadata *ExecutionMetadata, conversations []Conversation, toolUses []ToolUse, results *Result, operationFlow []OperationStep) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("# Execution %s\n\n", metadata.ExecutionID))
+	output.WriteString("| Field | Value |\n")
+	output.WriteString("| --- | --- |\n")
+	output.WriteString(fmt.Sprintf("| Status | %s |\n", metadata.Status))
+	if metadata.Status == ExecutionStatusFailed || metadata.ExitCode != 0 {
+		output.WriteString(fmt.Sprintf("| Exit Code | %d |\n", metadata.ExitCode))
+	}
+	output.WriteString(fmt.Sprintf("| Started | %s |\n", metadata.StartTime.Format("2006-01-02 15:04:05")))
+	if metadata.Repository != "" {
+		output.WriteString(fmt.Sprintf("| Repository | %s |\n", markdownTableCell(metadata.Repository)))
+	}
+	if metadata.Model != "" {
+		output.WriteString(fmt.Sprintf("| Model | %s |\n", markdownTableCell(metadata.Model)))
+	}
+
+	// Prompt
+	output.WriteString("\n## Prompt\n\n")
+	output.WriteString(strings.TrimSpace(lp.extractActualPrompt(metadata.Prompt)))
+	output.WriteString("\n")
+
+	// Claude's Response
+	var responses []string
+	for _, conv := range conversations {
+		if conv.Type == "text" {
+			responses = append(responses, strings.TrimSpace(conv.Content))
+		}
+	}
+	if len(responses) > 0 {
+		output.WriteString("\n## Response\n\n")
+		output.WriteString(strings.Join(responses, "\n\n"))
+		output.WriteString("\n")
+	}
+
+	// Operation Flow
+	if len(operationFlow) > 0 {
+		output.WriteString("\n## Operation Flow\n")
+
+		for _, step := range operationFlow {
+			timestamp := ""
+			if step.Timestamp != "" {
+				if t, err := lp.parseTimestamp(step.Timestamp); err == nil {
+					timestamp = fmt.Sprintf(" `%s`", t.Format("15:04:05"))
+				}
+			}
+
+			if step.Type == "assistant_message" {
+				output.WriteString(fmt.Sprintf("\n%d. **Assistant**%s\n\n", step.StepNumber, timestamp))
+				for _, line := range strings.Split(strings.TrimSpace(step.Details), "\n") {
+					output.WriteString(strings.TrimRight("   "+line, " ") + "\n")
+				}
+				continue
+			}
+
+			output.WriteString(fmt.Sprintf("\n%d. **%s**%s\n", step.StepNumber, step.Content, timestamp))
+
+			if step.Details == "" {
+				continue
+			}
+
+			switch step.Type {
+			case "tool_use":
+				if cmd := lp.extractCommandFromDetails(step.Details); cmd != "" {
+					output.WriteString(markdownCodeBlock(cmd, "bash", "   "))
+				} else {
+					output.WriteString(markdownCodeBlock(step.Details, "json", "   "))
+				}
+			case "tool_result":
+				if !step.Success {
+					output.WriteString(markdownBlockquote("**Error:** "+step.Details, "   "))
+				} else {
+					output.WriteString(markdownCodeBlock(step.Details, "", "   "))
+				}
+			case "result":
+				if !step.Success {
+					output.WriteString(markdownBlockquote("**Error:** "+step.Details, "   "))
+				}
+			}
+		}
+	}
+
+	// Tool usage summary
+	if len(toolUses) > 0 {
+		counts := make(map[string]int)
+		var names []string
+		for _, tool := range toolUses {
+			if _, seen := counts[tool.Name]; !seen {
+				names = append(names, tool.Name)
+			}
+			counts[tool.Name]++
+		}
+
+		output.WriteString("\n## Tools\n\n")
+		output.WriteString("| Tool | Uses |\n")
+		output.WriteString("| --- | --- |\n")
+		for _, name := range names {
+			output.WriteString(fmt.Sprintf("| %s | %d |\n", markdownTableCell(name), counts[name]))
+		}
+	}
+
+	// Cost table
+	totalCost := metadata.CostUSD
+	duration := metadata.DurationMS
+	if results != nil {
+		if results.CostUSD > 0 {
+			totalCost = results.CostUSD
+		}
+		if results.Duration > 0 {
+			duration = results.Duration
+		}
+	}
+
+	output.WriteString("\n## Cost\n\n")
+	output.WriteString("| Metric | Value |\n")
+	output.WriteString("| --- | --- |\n")
+	output.WriteString(fmt.Sprintf("| Total cost | $%.4f |\n", totalCost))
+	output.WriteString(fmt.Sprintf("| Duration | %d ms |\n", duration))
+
+	// Final Result/Summary - only show if different from response
+	if results != nil && results.Message != "" {
+		if len(conversations) == 0 || results.Message != conversations[len(conversations)-1].Content {
+			if results.Success {
+				output.WriteString("\n## Summary\n\n")
+				output.WriteString(strings.TrimSpace(results.Message))
+				output.WriteString("\n")
+			} else {
+				output.WriteString("\n## Error\n\n")
+				output.WriteString(markdownBlockquote(results.Message, ""))
+			}
+		}
+	}
+
+	return output.String()
+}
+
+// markdownCodeBlock wraps content in a fenced code block, using a fence longer
+// than any backtick run inside the content
+func markdownCodeBlock(content, lang, indent string) string {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + indent + fence + lang + "\n")
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		b.WriteString(indent + line + "\n")
+	}
+	b.WriteString(indent + fence + "\n")
+	return b.String()
+}
+
+// markdownBlockquote renders content as a blockquote
+func markdownBlockquote(content, indent string) string {
+	var b strings.Builder
+	b.WriteString("\n")
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		b.WriteString(strings.TrimRight(indent+"> "+line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// markdownTableCell escapes characters that would break a Markdown table cell
+func markdownTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,98 @@
+package claude
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func TestFormatMarkdownGolden(t *testing.T) {
+	lp := NewLogProcessor()
+
+	entries, err := lp.loadJSONLog(filepath.Join("testdata", "execution.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-abc123",
+		Status:      ExecutionStatusCompleted,
+		StartTime:   time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+		Repository:  "github.com/example/repo",
+		Prompt:      "Fix the failing tests",
+		Model:       "claude-sonnet-4",
+	}
+
+	got := lp.FormatMarkdown(
+		metadata,
+		lp.extractConversations(entries),
+		lp.extractToolUses(entries),
+		lp.extractResults(entries),
+		lp.extractOperationFlow(entries),
+	)
+
+	golden := filepath.Join("testdata", "execution.md")
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	if got != string(want) {
+		t.Errorf("FormatMarkdown() mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestMarkdownCodeBlockFence(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain", "echo hi", "\n```bash\necho hi\n```\n"},
+		{"nested fence", "a ```b``` c", "\n````bash\na ```b``` c\n````\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownCodeBlock(tt.content, "bash", ""); got != tt.want {
+				t.Errorf("markdownCodeBlock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LogFormat
+		wantErr bool
+	}{
+		{"", LogFormatText, false},
+		{"text", LogFormatText, false},
+		{"md", LogFormatMarkdown, false},
+		{"markdown", LogFormatMarkdown, false},
+		{"json", LogFormatJSON, false},
+		{"html", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLogFormat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLogFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLogFormat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package claude
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/logging"
+)
+
+// LogPart records where one part of a rotated execution log begins
+type LogPart struct {
+	File      string    `json:"file"`       // File name within the executions log directory
+	StartedAt time.Time `json:"started_at"` // When the first line of the part was written
+}
+
+// logPartPattern matches the "-partN" suffix of rotated log parts
+var logPartPattern = regexp.MustCompile(`-part(\d+)\.jsonl$`)
+
+// logPartFile returns the path of part n of logFile. Part 1 is logFile itself.
+func logPartFile(logFile string, n int) string {
+	if n <= 1 {
+		return logFile
+	}
+	return fmt.Sprintf("%s-part%d.jsonl", strings.TrimSuffix(logFile, ".jsonl"), n)
+}
+
+// RemoveLogFile removes logFile together with its rotated parts. The error
+// is that of removing logFile itself.
+func RemoveLogFile(logFile string) error {
+	parts := LogFileParts(logFile)
+	for _, part := range parts[1:] {
+		if err := os.Remove(part); err != nil && !os.IsNotExist(err) {
+			logging.Warnf("failed to remove log part %s: %v", part, err)
+		}
+	}
+	return os.Remove(logFile)
+}
+
+// isLogPartFile reports whether name is a rotated part following the first
+// file of a log
+func isLogPartFile(name string) bool {
+	return logPartPattern.MatchString(name)
+}
+
+// LogFileParts returns logFile followed by the rotated parts that exist for
+// it, in the order they were written
+func LogFileParts(logFile string) []string {
+	parts := []string{logFile}
+
+	matches, err := filepath.Glob(strings.TrimSuffix(logFile, ".jsonl") + "-part*.jsonl")
+	if err != nil {
+		return parts
+	}
+	numbers := make(map[string]int, len(matches))
+	var rotated []string
+	for _, match := range matches {
+		m := logPartPattern.FindStringSubmatch(match)
+		if m == nil || logPartFile(logFile, atoiOrZero(m[1])) != match {
+			continue // Another log sharing the prefix
+		}
+		numbers[match] = atoiOrZero(m[1])
+		rotated = append(rotated, match)
+	}
+	sort.Slice(rotated, func(i, j int) bool {
+		return numbers[rotated[i]] < numbers[rotated[j]]
+	})
+	return append(parts, rotated...)
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// multiFileReader reads several files as one stream
+type multiFileReader struct {
+	io.Reader
+	files []*os.File
+}
+
+func (r *multiFileReader) Close() error {
+	var firstErr error
+	for _, file := range r.files {
+		if err := file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// openLogParts opens logFile and its rotated parts as a single log. Parts are
+// separated by a newline so a part ending without one can't merge two lines.
+// It fails like os.Open when logFile itself can't be opened; parts that
+// disappear meanwhile are skipped.
+func openLogParts(logFile string) (io.ReadCloser, error) {
+	parts := LogFileParts(logFile)
+	r := &multiFileReader{}
+	var readers []io.Reader
+	for i, part := range parts {
+		file, err := os.Open(part)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			continue
+		}
+		if len(r.files) > 0 {
+			readers = append(readers, strings.NewReader("\n"))
+		}
+		r.files = append(r.files, file)
+		readers = append(readers, file)
+	}
+	r.Reader = io.MultiReader(readers...)
+	return r, nil
+}
+
+// rotatingLogWriter writes whole lines to a log, starting a new part file
+// before a line would grow the current part beyond maxBytes
+type rotatingLogWriter struct {
+	logFile  string
+	maxBytes int64 // Zero disables rotation
+	file     *os.File
+	part     int
+	size     int64
+
+	// onRotate is called with the name of each new part once it is open
+	onRotate func(part string)
+}
+
+// openRotatingLog opens logFile for writing. When appendLog is set the last
+// existing part is extended; otherwise logFile is truncated and parts left by
+// an earlier capture are removed.
+func openRotatingLog(logFile string, maxBytes int64, appendLog bool) (*rotatingLogWriter, error) {
+	w := &rotatingLogWriter{logFile: logFile, maxBytes: maxBytes}
+
+	parts := LogFileParts(logFile)
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendLog {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		w.part = len(parts)
+	} else {
+		w.part = 1
+		for _, stale := range parts[1:] {
+			if err := os.Remove(stale); err != nil {
+				logging.Warnf("failed to remove old log part %s: %v", stale, err)
+			}
+		}
+	}
+
+	file, err := os.OpenFile(parts[w.part-1], flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := file.Stat(); err == nil {
+		w.size = info.Size()
+	}
+	w.file = file
+	return w, nil
+}
+
+// WriteLine writes line and a newline, rotating first if needed
+func (w *rotatingLogWriter) WriteLine(line string) error {
+	n := int64(len(line) + 1)
+	if w.maxBytes > 0 && w.size > 0 && w.size+n > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := io.WriteString(w.file, line+"\n")
+	w.size += int64(written)
+	return err
+}
+
+// rotate closes the current part and starts the next one
+func (w *rotatingLogWriter) rotate() error {
+	next := logPartFile(w.logFile, w.part+1)
+	file, err := os.OpenFile(next, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create log part: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		logging.Warnf("failed to close log part: %v", err)
+	}
+
+	w.file = file
+	w.part++
+	w.size = 0
+	if w.onRotate != nil {
+		w.onRotate(next)
+	}
+	return nil
+}
+
+// Close closes the current part
+func (w *rotatingLogWriter) Close() error {
+	return w.file.Close()
+}
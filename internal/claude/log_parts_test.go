@@ -0,0 +1,214 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// rotationStream returns a stream of n assistant messages followed by a result
+func rotationStream(n int) string {
+	var b strings.Builder
+	b.WriteString(`{"type":"system","subtype":"init","model":"claude-sonnet"}` + "\n")
+	for i := range n {
+		fmt.Fprintf(&b, `{"type":"assistant","message":{"content":[{"type":"text","text":"message %03d %s"}]}}`+"\n", i, strings.Repeat("x", 100))
+	}
+	b.WriteString(`{"type":"result","subtype":"success","cost_usd":0.25,"duration_ms":1000,"result":"done"}` + "\n")
+	return b.String()
+}
+
+func TestCaptureLogOutputRotation(t *testing.T) {
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+	em.logPartSize = 2048
+
+	input := filepath.Join(t.TempDir(), "stream")
+	if err := os.WriteFile(input, []byte(rotationStream(50)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	startTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	logFile := filepath.Join(em.GetLogDir(), "executions", GenerateLogFileName(startTime, "exec-rotate"))
+	metadata := &ExecutionMetadata{ExecutionID: "exec-rotate", StartTime: startTime}
+
+	if err := em.captureLogOutput(input, logFile, metadata, false, nil); err != nil {
+		t.Fatalf("captureLogOutput() error = %v", err)
+	}
+
+	parts := LogFileParts(logFile)
+	if len(parts) < 3 {
+		t.Fatalf("LogFileParts() = %v, want the log rotated into at least 3 parts", parts)
+	}
+	for i, part := range parts {
+		if part != logPartFile(logFile, i+1) {
+			t.Errorf("part %d = %s, want %s", i+1, part, logPartFile(logFile, i+1))
+		}
+		info, err := os.Stat(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > em.logPartSize {
+			t.Errorf("part %d is %d bytes, want at most %d", i+1, info.Size(), em.logPartSize)
+		}
+	}
+
+	if len(metadata.LogParts) != len(parts) {
+		t.Fatalf("metadata.LogParts = %v, want %d parts", metadata.LogParts, len(parts))
+	}
+	for i, part := range metadata.LogParts {
+		if part.File != filepath.Base(parts[i]) {
+			t.Errorf("LogParts[%d].File = %s, want %s", i, part.File, filepath.Base(parts[i]))
+		}
+	}
+	if !metadata.LogParts[0].StartedAt.Equal(startTime) {
+		t.Errorf("LogParts[0].StartedAt = %v, want the start time %v", metadata.LogParts[0].StartedAt, startTime)
+	}
+
+	// The parts read back as one log, located from the first file
+	if found := FindLogFileByExecutionID(em.GetLogDir(), startTime.Add(time.Second), "exec-rotate"); found != logFile {
+		t.Errorf("FindLogFileByExecutionID() = %s, want the first part %s", found, logFile)
+	}
+	entries, err := NewLogProcessor().loadJSONLog(logFile)
+	if err != nil {
+		t.Fatalf("loadJSONLog() error = %v", err)
+	}
+	if len(entries) != 52 {
+		t.Fatalf("loadJSONLog() returned %d entries, want 52", len(entries))
+	}
+	if entries[0].Type != "system" || entries[len(entries)-1].Type != "result" {
+		t.Errorf("entries run from %q to %q, want system to result", entries[0].Type, entries[len(entries)-1].Type)
+	}
+	if result, ok := readFinalResult(logFile); !ok || result["result"] != "done" {
+		t.Errorf("readFinalResult() = %v, %v, want the result from the last part", result, ok)
+	}
+	if metadata.CostUSD != 0.25 || metadata.Model != "claude-sonnet" {
+		t.Errorf("metadata cost/model = %v/%q, want 0.25/claude-sonnet", metadata.CostUSD, metadata.Model)
+	}
+}
+
+func TestCaptureLogOutputRotationAppendAndRestart(t *testing.T) {
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+	em.logPartSize = 2048
+
+	input := filepath.Join(t.TempDir(), "stream")
+	if err := os.WriteFile(input, []byte(rotationStream(30)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(t.TempDir(), "exec.jsonl")
+	metadata := &ExecutionMetadata{ExecutionID: "exec-append", StartTime: time.Now()}
+
+	if err := em.captureLogOutput(input, logFile, metadata, false, nil); err != nil {
+		t.Fatalf("captureLogOutput() error = %v", err)
+	}
+	before := len(LogFileParts(logFile))
+
+	// Resuming continues after the last part
+	if err := em.captureLogOutput(input, logFile, metadata, true, nil); err != nil {
+		t.Fatalf("captureLogOutput(append) error = %v", err)
+	}
+	after := LogFileParts(logFile)
+	if len(after) <= before {
+		t.Errorf("appending left %d parts, want more than %d", len(after), before)
+	}
+	entries, err := NewLogProcessor().loadJSONLog(logFile)
+	if err != nil {
+		t.Fatalf("loadJSONLog() error = %v", err)
+	}
+	if len(entries) != 64 {
+		t.Errorf("loadJSONLog() returned %d entries after append, want 64", len(entries))
+	}
+
+	// A fresh capture removes the parts left by the earlier one
+	if err := os.WriteFile(input, []byte(rotationStream(1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := em.captureLogOutput(input, logFile, &ExecutionMetadata{}, false, nil); err != nil {
+		t.Fatalf("captureLogOutput() error = %v", err)
+	}
+	if parts := LogFileParts(logFile); len(parts) != 1 {
+		t.Errorf("LogFileParts() = %v after a fresh capture, want only the log file", parts)
+	}
+}
+
+func TestClaudeCodeExecutorCaptureLogOutputRotation(t *testing.T) {
+	config := &models.ClaudeConfig{ConfigDir: t.TempDir()}
+	config.Execution.LogPartSizeMB = 1
+	executor := NewClaudeCodeExecutor(config)
+
+	// Each message is about 1 KB, so 2000 of them need at least two parts
+	var b strings.Builder
+	for i := range 2000 {
+		fmt.Fprintf(&b, `{"type":"assistant","message":{"content":[{"type":"text","text":"message %04d %s"}]}}`+"\n", i, strings.Repeat("x", 1000))
+	}
+	input := filepath.Join(t.TempDir(), "stream")
+	if err := os.WriteFile(input, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(t.TempDir(), "task.jsonl")
+
+	if err := executor.captureLogOutput(input, logFile, &UnifiedExecution{ExecutionID: "task-rotate"}, nil); err != nil {
+		t.Fatalf("captureLogOutput() error = %v", err)
+	}
+	parts := LogFileParts(logFile)
+	if len(parts) < 2 {
+		t.Fatalf("LogFileParts() = %v, want the log rotated", parts)
+	}
+	for _, part := range parts {
+		info, err := os.Stat(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 1024*1024 {
+			t.Errorf("%s is %d bytes, want at most 1 MB", part, info.Size())
+		}
+	}
+
+	if err := RemoveLogFile(logFile); err != nil {
+		t.Fatalf("RemoveLogFile() error = %v", err)
+	}
+	for _, part := range parts {
+		if _, err := os.Stat(part); !os.IsNotExist(err) {
+			t.Errorf("RemoveLogFile() left %s", part)
+		}
+	}
+}
+
+func TestLogFileParts(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "20250102-030405-exec-1.jsonl")
+	for _, name := range []string{
+		"20250102-030405-exec-1.jsonl",
+		"20250102-030405-exec-1-part2.jsonl",
+		"20250102-030405-exec-1-part10.jsonl",
+		"20250102-030405-exec-1-part3.jsonl",
+		"20250102-030405-exec-12.jsonl",
+		"20250102-030405-exec-12-part2.jsonl",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, part := range LogFileParts(logFile) {
+		got = append(got, filepath.Base(part))
+	}
+	want := []string{
+		"20250102-030405-exec-1.jsonl",
+		"20250102-030405-exec-1-part2.jsonl",
+		"20250102-030405-exec-1-part3.jsonl",
+		"20250102-030405-exec-1-part10.jsonl",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("LogFileParts() = %v, want %v", got, want)
+	}
+}
@@ -1,12 +1,15 @@
 package claude
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/d-kuro/gwq/internal/logging"
 )
 
 // Constants for log processing
@@ -20,15 +23,79 @@ const (
 )
 
 // LogProcessor processes Claude execution logs for human-readable display
-type LogProcessor struct{}
+type LogProcessor struct {
+	opts LogProcessorOptions
+}
 
-// NewLogProcessor creates a new log processor
+// LogProcessorOptions controls how plain-text output is decorated
+type LogProcessorOptions struct {
+	// NoIcons replaces emoji and status symbols with plain text.
+	NoIcons bool
+	// Width wraps prose and truncates operation flow lines to this many
+	// characters. Zero disables wrapping.
+	Width int
+}
+
+// DefaultLogProcessorOptions returns options for plain-text output,
+// disabling icons when the NO_COLOR environment variable is set
+func DefaultLogProcessorOptions() LogProcessorOptions {
+	return LogProcessorOptions{
+		NoIcons: os.Getenv("NO_COLOR") != "",
+	}
+}
+
+// NewLogProcessor creates a new log processor with icons and no width limit
 func NewLogProcessor() *LogProcessor {
 	return &LogProcessor{}
 }
 
+// NewLogProcessorWithOptions creates a new log processor using the given options
+func NewLogProcessorWithOptions(opts LogProcessorOptions) *LogProcessor {
+	return &LogProcessor{opts: opts}
+}
+
+// LogFormat represents an output format for processed execution logs
+type LogFormat string
+
+const (
+	// LogFormatText renders logs as decorated plain text (default).
+	LogFormatText LogFormat = "text"
+	// LogFormatMarkdown renders logs as Markdown suitable for PRs and wikis.
+	LogFormatMarkdown LogFormat = "md"
+	// LogFormatJSON renders the parsed log structure as JSON.
+	LogFormatJSON LogFormat = "json"
+)
+
+// ParseLogFormat validates a format name and returns the corresponding LogFormat
+func ParseLogFormat(format string) (LogFormat, error) {
+	switch LogFormat(format) {
+	case "", LogFormatText:
+		return LogFormatText, nil
+	case LogFormatMarkdown, "markdown":
+		return LogFormatMarkdown, nil
+	case LogFormatJSON:
+		return LogFormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported log format: %s (expected text, md or json)", format)
+	}
+}
+
+// ExecutionReport is the JSON representation of a processed execution log
+type ExecutionReport struct {
+	Metadata      *ExecutionMetadata `json:"metadata"`
+	Conversations []Conversation     `json:"conversations"`
+	ToolUses      []ToolUse          `json:"tool_uses"`
+	Result        *Result            `json:"result,omitempty"`
+	OperationFlow []OperationStep    `json:"operation_flow"`
+}
+
 // ProcessExecution processes an execution's logs and returns formatted output
 func (lp *LogProcessor) ProcessExecution(metadata *ExecutionMetadata, execMgr *ExecutionManager) (string, error) {
+	return lp.ProcessExecutionAs(metadata, execMgr, LogFormatText)
+}
+
+// ProcessExecutionAs processes an execution's logs and renders them in the given format
+func (lp *LogProcessor) ProcessExecutionAs(metadata *ExecutionMetadata, execMgr *ExecutionManager, format LogFormat) (string, error) {
 	// Load raw log using the unified file finding logic
 	logFile := FindLogFileByExecutionID(execMgr.GetLogDir(), metadata.StartTime, metadata.ExecutionID)
 
@@ -44,21 +111,39 @@ func (lp *LogProcessor) ProcessExecution(metadata *ExecutionMetadata, execMgr *E
 	operationFlow := lp.extractOperationFlow(logEntries)
 
 	// Format output
-	formatted := lp.formatExecution(metadata, conversations, toolUses, results, operationFlow)
-	return formatted, nil
+	switch format {
+	case LogFormatMarkdown:
+		return lp.FormatMarkdown(metadata, conversations, toolUses, results, operationFlow), nil
+	case LogFormatJSON:
+		report := ExecutionReport{
+			Metadata:      metadata,
+			Conversations: conversations,
+			ToolUses:      toolUses,
+			Result:        results,
+			OperationFlow: operationFlow,
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal execution report: %w", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return lp.formatExecution(metadata, conversations, toolUses, results, operationFlow), nil
+	}
 }
 
 // JSONLogEntry represents a single log entry
 type JSONLogEntry struct {
-	Type      string                 `json:"type"`
-	Subtype   string                 `json:"subtype,omitempty"`
-	Message   map[string]interface{} `json:"message,omitempty"`
-	Result    string                 `json:"result,omitempty"`
-	CostUSD   float64                `json:"cost_usd,omitempty"`
-	Duration  int64                  `json:"duration_ms,omitempty"`
-	Model     string                 `json:"model,omitempty"`
-	Timestamp string                 `json:"timestamp,omitempty"`
-	Raw       map[string]interface{} `json:"-"` // Store raw data
+	Type       string                 `json:"type"`
+	Subtype    string                 `json:"subtype,omitempty"`
+	Message    map[string]interface{} `json:"message,omitempty"`
+	Result     string                 `json:"result,omitempty"`
+	CostUSD    float64                `json:"cost_usd,omitempty"`
+	Duration   int64                  `json:"duration_ms,omitempty"`
+	Model      string                 `json:"model,omitempty"`
+	Timestamp  string                 `json:"timestamp,omitempty"`   // When Claude emitted the entry, or when it was captured
+	CapturedAt string                 `json:"captured_at,omitempty"` // When gwq captured an entry Claude had timestamped
+	Raw        map[string]interface{} `json:"-"`                     // Store raw data
 }
 
 // Conversation represents a parsed conversation
@@ -70,10 +155,13 @@ type Conversation struct {
 
 // ToolUse represents a tool usage
 type ToolUse struct {
-	Name    string `json:"name"`
-	Input   string `json:"input"`
-	Output  string `json:"output"`
-	Success bool   `json:"success"`
+	Name       string `json:"name"`
+	Input      string `json:"input"`
+	Output     string `json:"output"`
+	Success    bool   `json:"success"`
+	DurationMS int64  `json:"duration_ms,omitempty"` // Time until the result arrived, when both were timestamped
+
+	completed bool // Whether a tool_result was seen
 }
 
 // Result represents the final result
@@ -95,50 +183,146 @@ type OperationStep struct {
 	Timestamp  string `json:"timestamp,omitempty"`
 }
 
-// loadJSONLog loads and parses the JSON log file
+// loadJSONLog loads and parses the JSON log file and its rotated parts
 func (lp *LogProcessor) loadJSONLog(logFile string) ([]JSONLogEntry, error) {
-	file, err := os.Open(logFile)
+	file, err := openLogParts(logFile)
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			fmt.Printf("Warning: failed to close log file: %v\n", err)
+			logging.Warnf("failed to close log file: %v", err)
 		}
 	}()
 
 	var entries []JSONLogEntry
-	scanner := bufio.NewScanner(file)
+	err = forEachLine(file, func(line string) {
+		if entry, ok := parseJSONLogLine(line); ok {
+			entries = append(entries, entry)
+		}
+	})
+
+	return entries, err
+}
+
+// parseJSONLogLine parses a single JSONL line, skipping blank or invalid lines
+func parseJSONLogLine(line string) (JSONLogEntry, bool) {
+	var entry JSONLogEntry
+	if strings.TrimSpace(line) == "" {
+		return entry, false
+	}
+
+	// Parse into raw map first
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return entry, false // Skip invalid JSON
+	}
+
+	// Parse into structured entry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return entry, false
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "" {
+	entry.Raw = raw
+	return entry, true
+}
+
+// SearchLogContent scans assistant text and tool results in a JSONL log for text
+// (case-insensitive) and returns a snippet around the first match
+func (lp *LogProcessor) SearchLogContent(logFile, text string) (string, bool, error) {
+	entries, err := lp.loadJSONLog(logFile)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to load log: %w", err)
+	}
+
+	// Match on the original body: lowercasing can change byte offsets
+	pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta(text))
+	for _, entry := range entries {
+		if entry.Message == nil || (entry.Type != "assistant" && entry.Type != "user") {
+			continue
+		}
+
+		content, ok := entry.Message["content"].([]interface{})
+		if !ok {
 			continue
 		}
 
-		var entry JSONLogEntry
-		var raw map[string]interface{}
+		for _, item := range content {
+			contentItem, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
 
-		// Parse into raw map first
-		if err := json.Unmarshal([]byte(line), &raw); err != nil {
-			continue // Skip invalid JSON
+			var body string
+			switch contentItem["type"] {
+			case "text":
+				if entry.Type == "assistant" {
+					body, _ = contentItem["text"].(string)
+				}
+			case "tool_result":
+				body = lp.toolResultText(contentItem["content"])
+			}
+
+			if loc := pattern.FindStringIndex(body); loc != nil {
+				return lp.matchSnippet(body, loc[0], loc[1]-loc[0]), true, nil
+			}
 		}
+	}
 
-		// Parse into structured entry
-		if err := json.Unmarshal([]byte(line), &entry); err != nil {
-			continue
+	return "", false, nil
+}
+
+// toolResultText flattens tool_result content, which is either a string or a list of text blocks
+func (lp *LogProcessor) toolResultText(content interface{}) string {
+	switch c := content.(type) {
+	case string:
+		return c
+	case []interface{}:
+		var parts []string
+		for _, item := range c {
+			if block, ok := item.(map[string]interface{}); ok {
+				if text, ok := block["text"].(string); ok {
+					parts = append(parts, text)
+				}
+			}
 		}
+		return strings.Join(parts, "\n")
+	default:
+		return ""
+	}
+}
 
-		entry.Raw = raw
-		entries = append(entries, entry)
+// matchSnippet returns a single-line excerpt of s around the match at idx
+func (lp *LogProcessor) matchSnippet(s string, idx, matchLen int) string {
+	start := idx - maxSummaryLength/2
+	prefix := "..."
+	if start <= 0 {
+		start = 0
+		prefix = ""
+	}
+	end := idx + matchLen + maxSummaryLength/2
+	suffix := "..."
+	if end >= len(s) {
+		end = len(s)
+		suffix = ""
 	}
 
-	return entries, scanner.Err()
+	// Avoid splitting multi-byte characters
+	for start > 0 && !utf8.RuneStart(s[start]) {
+		start--
+	}
+	for end < len(s) && !utf8.RuneStart(s[end]) {
+		end++
+	}
+
+	snippet := strings.Join(strings.Fields(s[start:end]), " ")
+	return prefix + snippet + suffix
 }
 
 // extractConversations extracts conversation messages
 func (lp *LogProcessor) extractConversations(entries []JSONLogEntry) []Conversation {
 	var conversations []Conversation
+	streamed := lp.streamedToolInputs(entries)
 
 	for _, entry := range entries {
 		if entry.Type == "assistant" && entry.Message != nil {
@@ -157,7 +341,7 @@ func (lp *LogProcessor) extractConversations(entries []JSONLogEntry) []Conversat
 						case "tool_use":
 							if name, ok := contentItem["name"].(string); ok {
 								input := "No input"
-								if inputData, ok := contentItem["input"].(map[string]interface{}); ok {
+								if inputData, ok := toolUseInput(contentItem, streamed); ok {
 									if inputStr, err := json.MarshalIndent(inputData, "", "  "); err == nil {
 										input = string(inputStr)
 									}
@@ -178,10 +362,13 @@ func (lp *LogProcessor) extractConversations(entries []JSONLogEntry) []Conversat
 	return conversations
 }
 
-// extractToolUses extracts tool usage information
+// extractToolUses extracts tool usage information. When both the tool_use and
+// its tool_result carry timestamps, the time between them is recorded.
 func (lp *LogProcessor) extractToolUses(entries []JSONLogEntry) []ToolUse {
 	var toolUses []ToolUse
-	toolMap := make(map[string]*ToolUse) // Map tool_use_id to ToolUse
+	toolIndex := make(map[string]int)        // Map tool_use_id to its index in toolUses
+	startedAt := make(map[string]*time.Time) // Map tool_use_id to when it was requested
+	streamed := lp.streamedToolInputs(entries)
 
 	for _, entry := range entries {
 		if entry.Type == "assistant" && entry.Message != nil {
@@ -189,7 +376,7 @@ func (lp *LogProcessor) extractToolUses(entries []JSONLogEntry) []ToolUse {
 				for _, item := range content {
 					if contentItem, ok := item.(map[string]interface{}); ok {
 						if contentItem["type"] == "tool_use" {
-							toolUse := &ToolUse{
+							toolUse := ToolUse{
 								Success: false,
 							}
 
@@ -197,15 +384,18 @@ func (lp *LogProcessor) extractToolUses(entries []JSONLogEntry) []ToolUse {
 								toolUse.Name = name
 							}
 
-							if input, ok := contentItem["input"].(map[string]interface{}); ok {
+							if input, ok := toolUseInput(contentItem, streamed); ok {
 								if inputStr, err := json.MarshalIndent(input, "", "  "); err == nil {
 									toolUse.Input = string(inputStr)
 								}
 							}
 
 							if id, ok := contentItem["id"].(string); ok {
-								toolMap[id] = toolUse
-								toolUses = append(toolUses, *toolUse)
+								toolIndex[id] = len(toolUses)
+								if ts, err := lp.parseTimestamp(entry.Timestamp); err == nil {
+									startedAt[id] = ts
+								}
+								toolUses = append(toolUses, toolUse)
 							}
 						}
 					}
@@ -217,13 +407,18 @@ func (lp *LogProcessor) extractToolUses(entries []JSONLogEntry) []ToolUse {
 					if contentItem, ok := item.(map[string]interface{}); ok {
 						if contentItem["type"] == "tool_result" {
 							if toolUseID, ok := contentItem["tool_use_id"].(string); ok {
-								if toolUse, exists := toolMap[toolUseID]; exists {
-									if result, ok := contentItem["content"].(string); ok {
-										toolUse.Output = result
-										if isError, ok := contentItem["is_error"].(bool); ok {
-											toolUse.Success = !isError
-										} else {
-											toolUse.Success = true // Default to success if is_error is not present
+								if i, exists := toolIndex[toolUseID]; exists {
+									toolUse := &toolUses[i]
+									toolUse.Output = lp.toolResultText(contentItem["content"])
+									toolUse.completed = true
+									if isError, ok := contentItem["is_error"].(bool); ok {
+										toolUse.Success = !isError
+									} else {
+										toolUse.Success = true // Default to success if is_error is not present
+									}
+									if start, ok := startedAt[toolUseID]; ok {
+										if end, err := lp.parseTimestamp(entry.Timestamp); err == nil && end.After(*start) {
+											toolUse.DurationMS = end.Sub(*start).Milliseconds()
 										}
 									}
 								}
@@ -266,6 +461,7 @@ func (lp *LogProcessor) extractOperationFlow(entries []JSONLogEntry) []Operation
 	var steps []OperationStep
 	stepNumber := 1
 	toolMap := make(map[string]string) // tool_use_id to tool name mapping
+	streamed := lp.streamedToolInputs(entries)
 
 	for _, entry := range entries {
 		switch entry.Type {
@@ -309,7 +505,7 @@ func (lp *LogProcessor) extractOperationFlow(entries []JSONLogEntry) []Operation
 								}
 
 								toolInput := ""
-								if input, ok := contentItem["input"].(map[string]interface{}); ok {
+								if input, ok := toolUseInput(contentItem, streamed); ok {
 									if inputStr, err := json.MarshalIndent(input, "", "  "); err == nil {
 										toolInput = string(inputStr)
 									}
@@ -359,16 +555,11 @@ func (lp *LogProcessor) extractOperationFlow(entries []JSONLogEntry) []Operation
 									resultContent = lp.truncateString(result, maxDisplayLength)
 								}
 
-								statusIcon := "✓"
-								if isError {
-									statusIcon = "✗"
-								}
-
 								steps = append(steps, OperationStep{
 									StepNumber: stepNumber,
 									Type:       "tool_result",
 									Actor:      "user",
-									Content:    fmt.Sprintf("%s %s result", statusIcon, toolName),
+									Content:    fmt.Sprintf("%s%s result", lp.statusIcon(!isError), toolName),
 									Details:    resultContent,
 									Success:    !isError,
 									Timestamp:  entry.Timestamp,
@@ -381,10 +572,10 @@ func (lp *LogProcessor) extractOperationFlow(entries []JSONLogEntry) []Operation
 			}
 
 		case "result":
-			statusIcon := "✓"
+			success := true
 			resultType := "Completed"
 			if _, ok := entry.Raw["error"].(string); ok {
-				statusIcon = "✗"
+				success = false
 				resultType = "Failed"
 			}
 
@@ -392,9 +583,9 @@ func (lp *LogProcessor) extractOperationFlow(entries []JSONLogEntry) []Operation
 				StepNumber: stepNumber,
 				Type:       "result",
 				Actor:      "system",
-				Content:    fmt.Sprintf("%s Execution %s", statusIcon, resultType),
+				Content:    fmt.Sprintf("%sExecution %s", lp.statusIcon(success), resultType),
 				Details:    entry.Result,
-				Success:    statusIcon == "✓",
+				Success:    success,
 				Timestamp:  entry.Timestamp,
 			})
 			stepNumber++
@@ -410,22 +601,24 @@ func (lp *LogProcessor) formatExecution(metadata *ExecutionMetadata, conversatio
 
 	// 1. Prompt - simplified to just show the content without header
 	actualPrompt := lp.extractActualPrompt(metadata.Prompt)
-	output.WriteString(fmt.Sprintf("💬 Prompt:\n%s", actualPrompt))
+	output.WriteString(fmt.Sprintf("%sPrompt:\n%s", lp.icon("💬"), lp.wrapText(actualPrompt)))
 
 	// 2. Claude's Response
 	if len(conversations) > 0 {
-		output.WriteString("\n\n🤖 Claude's Response:\n")
+		output.WriteString(fmt.Sprintf("\n\n%sClaude's Response:\n", lp.icon("🤖")))
+		var response strings.Builder
 		for _, conv := range conversations {
 			if conv.Type == "text" {
-				output.WriteString(conv.Content)
+				response.WriteString(conv.Content)
 			}
 		}
+		output.WriteString(lp.wrapText(response.String()))
 		output.WriteString("\n")
 	}
 
 	// 3. Operation Flow - enhanced with more detailed information
 	if len(operationFlow) > 0 {
-		output.WriteString("\n\n⚡ Operation Flow:\n")
+		output.WriteString(fmt.Sprintf("\n\n%sOperation Flow:\n", lp.icon("⚡")))
 
 		// Group operations by type for better visualization
 		systemSteps := 0
@@ -433,62 +626,14 @@ func (lp *LogProcessor) formatExecution(metadata *ExecutionMetadata, conversatio
 		toolSteps := 0
 
 		for _, step := range operationFlow {
-			icon := lp.getStepIcon(step.Type)
-			timestamp := ""
-			if step.Timestamp != "" {
-				// Parse and format timestamp for better readability
-				if t, err := lp.parseTimestamp(step.Timestamp); err == nil {
-					timestamp = fmt.Sprintf(" [%s]", t.Format("15:04:05"))
-				}
-			}
-
-			// Enhanced step display with more context
-			output.WriteString(fmt.Sprintf("%d. %s %s%s", step.StepNumber, icon, step.Content, timestamp))
+			output.WriteString(lp.FormatOperationStep(step))
 
-			// Add success indicator for non-system steps
-			if step.Type != "system" {
-				if step.Success {
-					output.WriteString(" ✅")
-				} else {
-					output.WriteString(" ❌")
-				}
-			}
-			output.WriteString("\n")
-
-			// Show enhanced details
 			if step.Details != "" {
 				switch step.Type {
 				case "tool_use":
 					toolSteps++
-					// Show tool input details
-					if cmd := lp.extractCommandFromDetails(step.Details); cmd != "" {
-						output.WriteString(fmt.Sprintf("   ➤ Command: %s\n", cmd))
-					} else {
-						// Show formatted input for non-bash tools
-						formattedInput := lp.formatToolInput(step.Details)
-						if formattedInput != "" {
-							output.WriteString(fmt.Sprintf("   ➤ Input: %s\n", formattedInput))
-						}
-					}
-				case "tool_result":
-					// Show result summary
-					if !step.Success {
-						output.WriteString(fmt.Sprintf("   ⚠️  Error: %s\n", lp.truncateString(step.Details, maxDisplayLength)))
-					} else {
-						// Show successful result summary
-						summary := lp.summarizeToolResult(step.Details)
-						if summary != "" {
-							output.WriteString(fmt.Sprintf("   ✓ Result: %s\n", summary))
-						}
-					}
 				case "assistant_message":
 					assistantSteps++
-					// Show message type and length
-					messageLen := len(step.Details)
-					if messageLen > maxDisplayLength {
-						output.WriteString(fmt.Sprintf("   📝 Message (%d chars): %s...\n",
-							messageLen, lp.truncateString(step.Details, maxDisplayLength)))
-					}
 				case "system":
 					systemSteps++
 				}
@@ -496,8 +641,9 @@ func (lp *LogProcessor) formatExecution(metadata *ExecutionMetadata, conversatio
 		}
 
 		// Add operation summary
-		output.WriteString(fmt.Sprintf("\n📊 Flow Summary: %d system, %d assistant, %d tools used\n",
-			systemSteps, assistantSteps, toolSteps))
+		output.WriteString("\n")
+		lp.writeFlowLine(&output, fmt.Sprintf("%sFlow Summary: %d system, %d assistant, %d tools used",
+			lp.icon("📊"), systemSteps, assistantSteps, toolSteps))
 	}
 
 	// Total Cost Information - as a separate section
@@ -506,16 +652,16 @@ func (lp *LogProcessor) formatExecution(metadata *ExecutionMetadata, conversatio
 		totalCost = results.CostUSD
 	}
 
-	output.WriteString(fmt.Sprintf("\n\n💰 Total Cost:\n$%.4f", totalCost))
+	output.WriteString(fmt.Sprintf("\n\n%sTotal Cost:\n$%.4f", lp.icon("💰"), totalCost))
 
 	// Final Result/Summary - only show if different from response
 	if results != nil && results.Message != "" {
 		// Only show summary if it's different from the Claude response
 		if len(conversations) == 0 || (len(conversations) > 0 && results.Message != conversations[len(conversations)-1].Content) {
 			if results.Success {
-				output.WriteString(fmt.Sprintf("\n\n📊 Summary:\n%s", results.Message))
+				output.WriteString(fmt.Sprintf("\n\n%sSummary:\n%s", lp.icon("📊"), lp.wrapText(results.Message)))
 			} else {
-				output.WriteString(fmt.Sprintf("\n\n❌ Error:\n%s", results.Message))
+				output.WriteString(fmt.Sprintf("\n\n%sError:\n%s", lp.icon("❌"), lp.wrapText(results.Message)))
 			}
 		}
 	}
@@ -523,6 +669,171 @@ func (lp *LogProcessor) formatExecution(metadata *ExecutionMetadata, conversatio
 	return output.String()
 }
 
+// icon returns the icon followed by a space, or nothing when icons are disabled
+func (lp *LogProcessor) icon(icon string) string {
+	if lp.opts.NoIcons {
+		return ""
+	}
+	return icon + " "
+}
+
+// statusIcon returns a check or cross prefix for step content, or nothing when icons are disabled
+func (lp *LogProcessor) statusIcon(success bool) string {
+	if success {
+		return lp.icon("✓")
+	}
+	return lp.icon("✗")
+}
+
+// successIndicator returns the suffix marking a step as succeeded or failed
+func (lp *LogProcessor) successIndicator(success bool) string {
+	switch {
+	case lp.opts.NoIcons && success:
+		return " [ok]"
+	case lp.opts.NoIcons:
+		return " [failed]"
+	case success:
+		return " ✅"
+	default:
+		return " ❌"
+	}
+}
+
+// FormatOperationStep renders a single operation flow step, with the details
+// shown under it, as it appears in the text log output
+func (lp *LogProcessor) FormatOperationStep(step OperationStep) string {
+	var output strings.Builder
+
+	timestamp := ""
+	if step.Timestamp != "" {
+		// Parse and format timestamp for better readability
+		if t, err := lp.parseTimestamp(step.Timestamp); err == nil {
+			timestamp = fmt.Sprintf(" [%s]", t.Format("15:04:05"))
+		}
+	}
+
+	// Enhanced step display with more context
+	line := fmt.Sprintf("%d. %s%s%s", step.StepNumber, lp.icon(lp.getStepIcon(step.Type)), step.Content, timestamp)
+
+	// Add success indicator for non-system steps
+	if step.Type != "system" {
+		line += lp.successIndicator(step.Success)
+	}
+	lp.writeFlowLine(&output, line)
+
+	if step.Details == "" {
+		return output.String()
+	}
+
+	switch step.Type {
+	case "tool_use":
+		// Show tool input details
+		if cmd := lp.extractCommandFromDetails(step.Details); cmd != "" {
+			lp.writeFlowLine(&output, fmt.Sprintf("   %sCommand: %s", lp.icon("➤"), cmd))
+		} else {
+			// Show formatted input for non-bash tools
+			formattedInput := lp.formatToolInput(step.Details)
+			if formattedInput != "" {
+				lp.writeFlowLine(&output, fmt.Sprintf("   %sInput: %s", lp.icon("➤"), formattedInput))
+			}
+		}
+	case "tool_result":
+		// Show result summary
+		if !step.Success {
+			lp.writeFlowLine(&output, fmt.Sprintf("   %sError: %s", lp.icon("⚠️ "), lp.truncateString(step.Details, maxDisplayLength)))
+		} else {
+			// Show successful result summary
+			summary := lp.summarizeToolResult(step.Details)
+			if summary != "" {
+				lp.writeFlowLine(&output, fmt.Sprintf("   %sResult: %s", lp.icon("✓"), summary))
+			}
+		}
+	case "assistant_message":
+		// Show message type and length
+		messageLen := len(step.Details)
+		if messageLen > maxDisplayLength {
+			lp.writeFlowLine(&output, fmt.Sprintf("   %sMessage (%d chars): %s...",
+				lp.icon("📝"), messageLen, lp.truncateString(step.Details, maxDisplayLength)))
+		}
+	}
+
+	return output.String()
+}
+
+// writeFlowLine writes a single operation flow line, truncated to the configured width
+func (lp *LogProcessor) writeFlowLine(output *strings.Builder, line string) {
+	if lp.opts.Width > 0 {
+		line = truncateRunes(line, lp.opts.Width)
+	}
+	output.WriteString(line)
+	output.WriteString("\n")
+}
+
+// wrapText word-wraps each line of s to the configured width
+func (lp *LogProcessor) wrapText(s string) string {
+	if lp.opts.Width <= 0 {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	wrapped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapLine(line, lp.opts.Width)...)
+	}
+	return strings.Join(wrapped, "\n")
+}
+
+// wrapLine breaks a single line at spaces so no piece exceeds width runes.
+// Words longer than width are split.
+func wrapLine(line string, width int) []string {
+	if utf8.RuneCountInString(line) <= width {
+		return []string{line}
+	}
+
+	var lines []string
+	var current []rune
+	for _, word := range strings.Fields(line) {
+		runes := []rune(word)
+		for len(runes) > width {
+			if len(current) > 0 {
+				lines = append(lines, string(current))
+				current = nil
+			}
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		if len(runes) == 0 {
+			continue
+		}
+
+		switch {
+		case len(current) == 0:
+			current = runes
+		case len(current)+1+len(runes) <= width:
+			current = append(append(current, ' '), runes...)
+		default:
+			lines = append(lines, string(current))
+			current = runes
+		}
+	}
+	if len(current) > 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
+
+// truncateRunes shortens s to at most width runes, marking the cut with "..."
+func truncateRunes(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
+
 // extractActualPrompt extracts the actual user prompt content,
 // removing execution metadata and showing only content starting from "# Task:"
 func (lp *LogProcessor) extractActualPrompt(fullPrompt string) string {
@@ -701,18 +1012,16 @@ func (lp *LogProcessor) processCostInfo(entry JSONLogEntry, totalCost float64, f
 	return totalCost, foundTotalCost
 }
 
-// processDurationInfo processes duration information from log entries
+// processDurationInfo processes duration information from log entries. The
+// duration of an entry is decoded into entry.Duration and is also present in
+// entry.Raw, so the raw value is only used when the decoded one is missing.
 func (lp *LogProcessor) processDurationInfo(entry JSONLogEntry, totalDuration int64) int64 {
-	// Accumulate duration
 	if entry.Duration > 0 {
-		totalDuration += entry.Duration
+		return totalDuration + entry.Duration
 	}
 
-	// Also check raw data for duration
-	if entry.Raw != nil {
-		if rawDuration, ok := entry.Raw["duration_ms"].(float64); ok && rawDuration > 0 {
-			totalDuration += int64(rawDuration)
-		}
+	if rawDuration, ok := entry.Raw["duration_ms"].(float64); ok && rawDuration > 0 {
+		totalDuration += int64(rawDuration)
 	}
 
 	return totalDuration
@@ -0,0 +1,209 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func formatFixture(t *testing.T, lp *LogProcessor, prompt string) string {
+	t.Helper()
+
+	entries, err := lp.loadJSONLog(filepath.Join("testdata", "execution.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-abc123",
+		Status:      ExecutionStatusCompleted,
+		StartTime:   time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+		Prompt:      prompt,
+	}
+
+	return lp.formatExecution(
+		metadata,
+		lp.extractConversations(entries),
+		lp.extractToolUses(entries),
+		lp.extractResults(entries),
+		lp.extractOperationFlow(entries),
+	)
+}
+
+func TestFormatExecutionNoIcons(t *testing.T) {
+	got := formatFixture(t, NewLogProcessorWithOptions(LogProcessorOptions{NoIcons: true}), "Fix the failing tests")
+
+	for _, r := range got {
+		if r >= utf8.RuneSelf {
+			t.Fatalf("expected ASCII-only output, found %q in:\n%s", r, got)
+		}
+	}
+
+	for _, want := range []string{"Prompt:\n", "Operation Flow:\n", "Total Cost:\n", "[ok]"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatExecutionDefaultKeepsIcons(t *testing.T) {
+	got := formatFixture(t, NewLogProcessor(), "Fix the failing tests")
+
+	for _, want := range []string{"💬 Prompt:", "⚡ Operation Flow:", "💰 Total Cost:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatExecutionWidth(t *testing.T) {
+	const width = 40
+	prompt := strings.Repeat("please fix the failing tests in the repository ", 5)
+	got := formatFixture(t, NewLogProcessorWithOptions(LogProcessorOptions{NoIcons: true, Width: width}), prompt)
+
+	for _, line := range strings.Split(got, "\n") {
+		if n := utf8.RuneCountInString(line); n > width {
+			t.Errorf("line exceeds width %d (%d runes): %q", width, n, line)
+		}
+	}
+
+	if !strings.Contains(got, "please fix the failing tests in the") {
+		t.Errorf("expected prompt to be wrapped at word boundaries, got:\n%s", got)
+	}
+}
+
+func TestDefaultLogProcessorOptionsNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if !DefaultLogProcessorOptions().NoIcons {
+		t.Error("expected NO_COLOR to disable icons")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	if DefaultLogProcessorOptions().NoIcons {
+		t.Error("expected icons when NO_COLOR is empty")
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		width int
+		want  []string
+	}{
+		{"short", "hello world", 20, []string{"hello world"}},
+		{"words", "hello brave new world", 11, []string{"hello brave", "new world"}},
+		{"long word", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapLine(tt.line, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wrapLine(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamedToolInputDeltas(t *testing.T) {
+	lp := NewLogProcessor()
+	entries, err := lp.loadJSONLog(filepath.Join("testdata", "streamed_tool_input.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+
+	wantInputs := []map[string]interface{}{
+		{"command": "go test ./...", "timeout": float64(60000)},
+		{"file_path": "main.go"},
+		{"pattern": "*.go"}, // Not streamed; the block's own input is used
+	}
+
+	toolUses := lp.extractToolUses(entries)
+	if len(toolUses) != len(wantInputs) {
+		t.Fatalf("extractToolUses() returned %d tool uses, want %d", len(toolUses), len(wantInputs))
+	}
+	for i, want := range wantInputs {
+		got := lp.parseJSONInput(toolUses[i].Input)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("tool use %d (%s) input = %v, want %v", i, toolUses[i].Name, got, want)
+		}
+		if !toolUses[i].Success {
+			t.Errorf("tool use %d (%s) should be matched to its successful result", i, toolUses[i].Name)
+		}
+	}
+
+	var toolConversations []string
+	for _, c := range lp.extractConversations(entries) {
+		if c.Type == "tool_use" {
+			toolConversations = append(toolConversations, c.Content)
+		}
+	}
+	if len(toolConversations) != 3 || !strings.Contains(toolConversations[0], `"command": "go test ./..."`) {
+		t.Errorf("conversation tool uses = %q", toolConversations)
+	}
+
+	var readInput string
+	for _, step := range lp.extractOperationFlow(entries) {
+		if step.Type == "tool_use" && step.Content == "Using Read" {
+			readInput = lp.formatToolInput(step.Details)
+		}
+	}
+	if readInput != "Read main.go" {
+		t.Errorf("formatted Read input = %q, want %q", readInput, "Read main.go")
+	}
+}
+
+func TestStreamedToolInputsIncomplete(t *testing.T) {
+	lp := NewLogProcessor()
+	var entries []JSONLogEntry
+	for _, line := range []string{
+		`{"type":"stream_event","event":{"type":"content_block_start","index":0,"content_block":{"type":"tool_use","id":"toolu_1","name":"Bash","input":{}}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"{\"command\": \"ls"}}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Bash","input":{}}]}}`,
+	} {
+		entry, ok := parseJSONLogLine(line)
+		if !ok {
+			t.Fatalf("failed to parse %s", line)
+		}
+		entries = append(entries, entry)
+	}
+
+	toolUses := lp.extractToolUses(entries)
+	if len(toolUses) != 1 || toolUses[0].Input != "{}" {
+		t.Errorf("truncated stream should fall back to the block input, got %+v", toolUses)
+	}
+}
+
+func TestSearchLogContentMixedWidthRunes(t *testing.T) {
+	// Ⱥ takes two bytes but lowercases to the three-byte ⱥ, so offsets in
+	// a lowercased copy do not line up with the original text
+	body := strings.Repeat("Ⱥ", 60) + " the NEEDLE is here " + strings.Repeat("Ⱥ", 60)
+	line := `{"type":"assistant","message":{"content":[{"type":"text","text":"` + body + `"}]}}`
+	logFile := filepath.Join(t.TempDir(), "exec.jsonl")
+	if err := os.WriteFile(logFile, []byte(line+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	lp := NewLogProcessor()
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "needle", want: "the NEEDLE is here"},
+		{text: "ⱥⱥ the", want: "ȺȺ the NEEDLE"},
+	}
+	for _, tt := range tests {
+		snippet, found, err := lp.SearchLogContent(logFile, tt.text)
+		if err != nil || !found {
+			t.Fatalf("SearchLogContent(%q) = %q, %v, %v; want a match", tt.text, snippet, found, err)
+		}
+		if !strings.Contains(snippet, tt.want) || !utf8.ValidString(snippet) {
+			t.Errorf("SearchLogContent(%q) snippet = %q, want valid UTF-8 containing %q", tt.text, snippet, tt.want)
+		}
+	}
+}
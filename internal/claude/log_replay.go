@@ -0,0 +1,36 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// OperationFlow loads a JSONL log and returns its operation flow
+func (lp *LogProcessor) OperationFlow(logFile string) ([]OperationStep, error) {
+	entries, err := lp.loadJSONLog(logFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load log: %w", err)
+	}
+	return lp.extractOperationFlow(entries), nil
+}
+
+// ReplayOperationFlow writes steps to w one at a time, calling next before
+// every step but the first to wait for the go-ahead. Replay stops with the
+// error of next, or of ctx once it is done.
+func (lp *LogProcessor) ReplayOperationFlow(ctx context.Context, w io.Writer, steps []OperationStep, next func(ctx context.Context) error) error {
+	for i, step := range steps {
+		if i > 0 {
+			if err := next(ctx); err != nil {
+				return err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, lp.FormatOperationStep(step)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplayOperationFlow(t *testing.T) {
+	lp := NewLogProcessorWithOptions(LogProcessorOptions{NoIcons: true})
+	steps, err := lp.OperationFlow(filepath.Join("testdata", "execution.jsonl"))
+	if err != nil {
+		t.Fatalf("OperationFlow() failed: %v", err)
+	}
+
+	wantTypes := []string{"system", "assistant_message", "tool_use", "tool_result", "tool_use", "tool_result", "assistant_message", "result"}
+	if len(steps) != len(wantTypes) {
+		t.Fatalf("OperationFlow() returned %d steps, want %d", len(steps), len(wantTypes))
+	}
+	for i, step := range steps {
+		if step.StepNumber != i+1 || step.Type != wantTypes[i] {
+			t.Errorf("step %d = #%d %s, want #%d %s", i, step.StepNumber, step.Type, i+1, wantTypes[i])
+		}
+	}
+
+	// Each call to next releases one more step
+	var buf bytes.Buffer
+	var printed []int
+	next := func(ctx context.Context) error {
+		printed = append(printed, strings.Count(buf.String(), "\n"))
+		return nil
+	}
+	if err := lp.ReplayOperationFlow(context.Background(), &buf, steps, next); err != nil {
+		t.Fatalf("ReplayOperationFlow() failed: %v", err)
+	}
+	if len(printed) != len(steps)-1 {
+		t.Errorf("next called %d times, want %d", len(printed), len(steps)-1)
+	}
+	for i := 1; i < len(printed); i++ {
+		if printed[i] <= printed[i-1] {
+			t.Errorf("no output between calls %d and %d", i-1, i)
+		}
+	}
+
+	var want strings.Builder
+	for _, step := range steps {
+		want.WriteString(lp.FormatOperationStep(step))
+	}
+	if buf.String() != want.String() {
+		t.Errorf("replay output =\n%s\nwant\n%s", buf.String(), want.String())
+	}
+	if !strings.HasPrefix(buf.String(), "1. ") || !strings.Contains(buf.String(), "\n8. ") {
+		t.Errorf("steps not numbered in order:\n%s", buf.String())
+	}
+}
+
+func TestReplayOperationFlowStops(t *testing.T) {
+	lp := NewLogProcessor()
+	steps := []OperationStep{
+		{StepNumber: 1, Type: "system", Content: "first"},
+		{StepNumber: 2, Type: "system", Content: "second"},
+		{StepNumber: 3, Type: "system", Content: "third"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	err := lp.ReplayOperationFlow(ctx, &buf, steps, func(ctx context.Context) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ReplayOperationFlow() error = %v, want context.Canceled", err)
+	}
+	if strings.Contains(buf.String(), "second") {
+		t.Errorf("step written after cancellation:\n%s", buf.String())
+	}
+
+	stop := errors.New("stop")
+	err = lp.ReplayOperationFlow(context.Background(), &buf, steps, func(ctx context.Context) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("ReplayOperationFlow() error = %v, want the error from next", err)
+	}
+}
@@ -0,0 +1,66 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// ToolStats aggregates the uses of a single tool across one or more logs
+type ToolStats struct {
+	Name            string  `json:"name"`
+	Uses            int     `json:"uses"`
+	Succeeded       int     `json:"succeeded"`
+	Failed          int     `json:"failed"`
+	FailureRatio    float64 `json:"failure_ratio"`     // Failed over finished uses; uses without a result are not counted
+	TotalDurationMS int64   `json:"total_duration_ms"` // Summed from timestamped uses only
+}
+
+// CollectToolStats aggregates tool usage from the given JSONL logs, most used tools
+// first. Logs that don't exist are skipped.
+func (lp *LogProcessor) CollectToolStats(logFiles ...string) ([]ToolStats, error) {
+	byName := make(map[string]*ToolStats)
+
+	for _, logFile := range logFiles {
+		entries, err := lp.loadJSONLog(logFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to load log: %w", err)
+		}
+
+		for _, toolUse := range lp.extractToolUses(entries) {
+			stats, ok := byName[toolUse.Name]
+			if !ok {
+				stats = &ToolStats{Name: toolUse.Name}
+				byName[toolUse.Name] = stats
+			}
+			stats.Uses++
+			stats.TotalDurationMS += toolUse.DurationMS
+			switch {
+			case !toolUse.completed:
+			case toolUse.Success:
+				stats.Succeeded++
+			default:
+				stats.Failed++
+			}
+		}
+	}
+
+	result := make([]ToolStats, 0, len(byName))
+	for _, stats := range byName {
+		if finished := stats.Succeeded + stats.Failed; finished > 0 {
+			stats.FailureRatio = float64(stats.Failed) / float64(finished)
+		}
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Uses != result[j].Uses {
+			return result[i].Uses > result[j].Uses
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
@@ -0,0 +1,29 @@
+package claude
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCollectToolStats(t *testing.T) {
+	lp := NewLogProcessor()
+	fixture := filepath.Join("testdata", "execution.jsonl")
+
+	stats, err := lp.CollectToolStats(fixture, fixture, filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil {
+		t.Fatalf("CollectToolStats() failed: %v", err)
+	}
+
+	want := []ToolStats{
+		{Name: "Bash", Uses: 2, Failed: 2, FailureRatio: 1, TotalDurationMS: 6000},
+		{Name: "Edit", Uses: 2, Succeeded: 2, TotalDurationMS: 2000},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("CollectToolStats() = %+v, want %+v", stats, want)
+	}
+	for i := range want {
+		if stats[i] != want[i] {
+			t.Errorf("stats[%d] = %+v, want %+v", i, stats[i], want[i])
+		}
+	}
+}
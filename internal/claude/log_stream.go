@@ -0,0 +1,138 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// LogEventType identifies the kind of a streamed log event
+type LogEventType string
+
+const (
+	// LogEventText is assistant text output
+	LogEventText LogEventType = "text"
+	// LogEventToolUse is a tool invocation by the assistant
+	LogEventToolUse LogEventType = "tool_use"
+	// LogEventToolResult is the output of a tool invocation
+	LogEventToolResult LogEventType = "tool_result"
+	// LogEventResult is the final result of the execution
+	LogEventResult LogEventType = "result"
+	// LogEventError reports that following the log failed
+	LogEventError LogEventType = "error"
+)
+
+// LogEvent is a typed event parsed from an execution's JSONL log
+type LogEvent struct {
+	Type       LogEventType `json:"type"`
+	Text       string       `json:"text,omitempty"`
+	ToolName   string       `json:"tool_name,omitempty"`
+	ToolUseID  string       `json:"tool_use_id,omitempty"`
+	ToolInput  string       `json:"tool_input,omitempty"`
+	IsError    bool         `json:"is_error,omitempty"`
+	CostUSD    float64      `json:"cost_usd,omitempty"`
+	DurationMS int64        `json:"duration_ms,omitempty"`
+}
+
+// StreamExecution follows an execution's log and emits typed events as they
+// are written. The channel is closed once the execution finishes or ctx is done.
+func (em *ExecutionManager) StreamExecution(ctx context.Context, executionID string) (<-chan LogEvent, error) {
+	if _, err := em.LoadMetadata(executionID); err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	events := make(chan LogEvent)
+	go func() {
+		defer close(events)
+
+		lp := NewLogProcessor()
+		send := func(event LogEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		err := em.followExecutionLog(ctx, executionID, watchPollInterval, func(line string) {
+			entry, ok := parseJSONLogLine(line)
+			if !ok {
+				return
+			}
+			for _, event := range lp.logEvents(entry) {
+				if !send(event) {
+					return
+				}
+			}
+		})
+		if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			send(LogEvent{Type: LogEventError, Text: err.Error(), IsError: true})
+		}
+	}()
+
+	return events, nil
+}
+
+// logEvents converts a parsed log entry into zero or more typed events
+func (lp *LogProcessor) logEvents(entry JSONLogEntry) []LogEvent {
+	switch entry.Type {
+	case "assistant", "user":
+		return lp.messageEvents(entry)
+	case "result":
+		result := lp.processResultEntry(entry)
+		event := LogEvent{
+			Type:       LogEventResult,
+			Text:       result.Message,
+			IsError:    !result.Success,
+			CostUSD:    result.CostUSD,
+			DurationMS: result.Duration,
+		}
+		// total_cost_usd covers the whole session and supersedes cost_usd
+		if total, ok := entry.Raw["total_cost_usd"].(float64); ok && total > 0 {
+			event.CostUSD = total
+		}
+		return []LogEvent{event}
+	default:
+		return nil
+	}
+}
+
+// messageEvents extracts text, tool_use and tool_result blocks from a message entry
+func (lp *LogProcessor) messageEvents(entry JSONLogEntry) []LogEvent {
+	content, ok := entry.Message["content"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var events []LogEvent
+	for _, item := range content {
+		block, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch block["type"] {
+		case "text":
+			if text, ok := block["text"].(string); ok {
+				events = append(events, LogEvent{Type: LogEventText, Text: text})
+			}
+		case "tool_use":
+			event := LogEvent{Type: LogEventToolUse}
+			event.ToolName, _ = block["name"].(string)
+			event.ToolUseID, _ = block["id"].(string)
+			if input, ok := block["input"].(map[string]interface{}); ok {
+				if data, err := json.Marshal(input); err == nil {
+					event.ToolInput = string(data)
+				}
+			}
+			events = append(events, event)
+		case "tool_result":
+			event := LogEvent{Type: LogEventToolResult, Text: lp.toolResultText(block["content"])}
+			event.ToolUseID, _ = block["tool_use_id"].(string)
+			event.IsError, _ = block["is_error"].(bool)
+			events = append(events, event)
+		}
+	}
+	return events
+}
@@ -0,0 +1,89 @@
+package claude
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// streamedToolInputs reassembles tool inputs that were streamed as
+// input_json_delta chunks, keyed by tool_use id. Stream events identify blocks
+// by their index within the current message, so content_block_start is used to
+// map each index to its tool_use id. Inputs that don't form a JSON object once
+// complete are left out.
+func (lp *LogProcessor) streamedToolInputs(entries []JSONLogEntry) map[string]map[string]interface{} {
+	blockIDs := make(map[float64]string) // Block index to tool_use id for the current message
+	partial := make(map[string]*strings.Builder)
+
+	for _, entry := range entries {
+		event := streamEvent(entry)
+		if event == nil {
+			continue
+		}
+
+		index, _ := event["index"].(float64)
+		switch event["type"] {
+		case "message_start":
+			blockIDs = make(map[float64]string)
+		case "content_block_start":
+			block, ok := event["content_block"].(map[string]interface{})
+			if !ok || block["type"] != "tool_use" {
+				continue
+			}
+			if id, ok := block["id"].(string); ok {
+				blockIDs[index] = id
+				partial[id] = &strings.Builder{}
+			}
+		case "content_block_delta":
+			delta, ok := event["delta"].(map[string]interface{})
+			if !ok || delta["type"] != "input_json_delta" {
+				continue
+			}
+			id, ok := blockIDs[index]
+			if !ok {
+				continue
+			}
+			if chunk, ok := delta["partial_json"].(string); ok {
+				partial[id].WriteString(chunk)
+			}
+		}
+	}
+
+	inputs := make(map[string]map[string]interface{})
+	for id, buf := range partial {
+		var input map[string]interface{}
+		if err := json.Unmarshal([]byte(buf.String()), &input); err == nil {
+			inputs[id] = input
+		}
+	}
+	return inputs
+}
+
+// streamEvent returns the API stream event carried by entry, either wrapped in
+// a stream_event entry or logged on its own, or nil for other entries
+func streamEvent(entry JSONLogEntry) map[string]interface{} {
+	switch entry.Type {
+	case "stream_event":
+		event, _ := entry.Raw["event"].(map[string]interface{})
+		return event
+	case "message_start", "content_block_start", "content_block_delta":
+		return entry.Raw
+	default:
+		return nil
+	}
+}
+
+// toolUseInput returns the input of a tool_use block, falling back to the
+// input reassembled from streamed deltas when the block's own is missing or
+// empty
+func toolUseInput(block map[string]interface{}, streamed map[string]map[string]interface{}) (map[string]interface{}, bool) {
+	if input, ok := block["input"].(map[string]interface{}); ok && len(input) > 0 {
+		return input, true
+	}
+	if id, ok := block["id"].(string); ok {
+		if input, ok := streamed[id]; ok {
+			return input, true
+		}
+	}
+	input, ok := block["input"].(map[string]interface{})
+	return input, ok
+}
@@ -0,0 +1,283 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/logging"
+)
+
+// metadataIndexFileName is the append-only index of execution metadata, kept
+// next to the metadata directory so listing doesn't have to open every file
+const metadataIndexFileName = "metadata-index.jsonl"
+
+// indexPromptSnippetLength is the number of prompt characters kept in the index
+const indexPromptSnippetLength = 200
+
+// ExecutionIndexEntry holds the metadata fields needed to list an execution
+type ExecutionIndexEntry struct {
+	ExecutionID      string          `json:"execution_id"`
+	Status           ExecutionStatus `json:"status"`
+	ExitCode         int             `json:"exit_code,omitempty"`
+	StartTime        time.Time       `json:"start_time"`
+	Prompt           string          `json:"prompt"`
+	Repository       string          `json:"repository"`
+	WorkingDirectory string          `json:"working_directory"`
+	MetadataFile     string          `json:"metadata_file"`
+
+	// State of the metadata file when indexed, to notice writes that bypass
+	// the index
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+// newExecutionIndexEntry builds an index entry for metadata stored in
+// metadataFile, whose current state is info
+func newExecutionIndexEntry(metadata *ExecutionMetadata, metadataFile string, info os.FileInfo) ExecutionIndexEntry {
+	return ExecutionIndexEntry{
+		ExecutionID:      metadata.ExecutionID,
+		Status:           metadata.Status,
+		ExitCode:         metadata.ExitCode,
+		StartTime:        metadata.StartTime,
+		Prompt:           truncateRunes(metadata.Prompt, indexPromptSnippetLength),
+		Repository:       metadata.Repository,
+		WorkingDirectory: metadata.WorkingDirectory,
+		MetadataFile:     filepath.Base(metadataFile),
+		ModTime:          info.ModTime(),
+		Size:             info.Size(),
+	}
+}
+
+// Metadata returns a partial ExecutionMetadata holding only the indexed fields
+func (e ExecutionIndexEntry) Metadata() ExecutionMetadata {
+	return ExecutionMetadata{
+		ExecutionID:      e.ExecutionID,
+		Status:           e.Status,
+		ExitCode:         e.ExitCode,
+		StartTime:        e.StartTime,
+		Prompt:           e.Prompt,
+		Repository:       e.Repository,
+		WorkingDirectory: e.WorkingDirectory,
+	}
+}
+
+// metadataIndexPath returns the path of the metadata index file
+func (em *ExecutionManager) metadataIndexPath() string {
+	return filepath.Join(em.logDir, metadataIndexFileName)
+}
+
+// appendMetadataIndex records the latest state of an execution in the index.
+// The caller must hold em.mu.
+func (em *ExecutionManager) appendMetadataIndex(metadata *ExecutionMetadata, metadataFile string) error {
+	info, err := os.Stat(metadataFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(newExecutionIndexEntry(metadata, metadataFile, info))
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(em.metadataIndexPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// LoadExecutionIndex returns the latest index entry of every execution,
+// rebuilding the index from the metadata files when it is missing, corrupt
+// or out of sync with the metadata directory
+func (em *ExecutionManager) LoadExecutionIndex() ([]ExecutionIndexEntry, error) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	metadataFiles, err := em.listMetadataFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := em.readMetadataIndex()
+	if err == nil && em.metadataIndexInSync(entries, metadataFiles) {
+		return entries, nil
+	}
+
+	return em.rebuildMetadataIndex(metadataFiles)
+}
+
+// metadataIndexInSync reports whether the index covers exactly the current
+// metadata files as they are now. Metadata written without updating the index,
+// such as by the worker, changes the file's mtime or size and so invalidates
+// it. Files missing from the index are tolerated only if they predate the
+// index, as unreadable files are skipped during a rebuild.
+func (em *ExecutionManager) metadataIndexInSync(entries []ExecutionIndexEntry, metadataFiles []string) bool {
+	present := make(map[string]bool, len(metadataFiles))
+	for _, name := range metadataFiles {
+		present[name] = true
+	}
+
+	metadataDir := filepath.Join(em.logDir, "metadata")
+	indexed := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if !present[entry.MetadataFile] {
+			return false // Metadata was removed since the index was written
+		}
+		info, err := os.Stat(filepath.Join(metadataDir, entry.MetadataFile))
+		if err != nil || !info.ModTime().Equal(entry.ModTime) || info.Size() != entry.Size {
+			return false // Metadata was rewritten since it was indexed
+		}
+		indexed[entry.MetadataFile] = true
+	}
+
+	if len(indexed) == len(metadataFiles) {
+		return true
+	}
+
+	indexInfo, err := os.Stat(em.metadataIndexPath())
+	if err != nil {
+		return false
+	}
+	for _, name := range metadataFiles {
+		if indexed[name] {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(metadataDir, name))
+		if err != nil || info.ModTime().After(indexInfo.ModTime()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RebuildMetadataIndex discards the index and rebuilds it from the metadata files
+func (em *ExecutionManager) RebuildMetadataIndex() error {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	metadataFiles, err := em.listMetadataFiles()
+	if err != nil {
+		return err
+	}
+
+	_, err = em.rebuildMetadataIndex(metadataFiles)
+	return err
+}
+
+// listMetadataFiles returns the names of all metadata files
+func (em *ExecutionManager) listMetadataFiles() ([]string, error) {
+	files, err := os.ReadDir(filepath.Join(em.logDir, "metadata"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read metadata directory: %w", err)
+	}
+
+	var names []string
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
+			names = append(names, file.Name())
+		}
+	}
+	return names, nil
+}
+
+// readMetadataIndex reads the index, keeping the last entry recorded for each
+// execution. Any unreadable line makes the whole index invalid.
+func (em *ExecutionManager) readMetadataIndex() ([]ExecutionIndexEntry, error) {
+	file, err := os.Open(em.metadataIndexPath())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []ExecutionIndexEntry
+	positions := make(map[string]int)
+	var parseErr error
+	err = forEachLine(file, func(line string) {
+		if parseErr != nil || strings.TrimSpace(line) == "" {
+			return
+		}
+
+		var entry ExecutionIndexEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			parseErr = fmt.Errorf("corrupt metadata index: %w", err)
+			return
+		}
+		if entry.ExecutionID == "" || entry.MetadataFile == "" {
+			parseErr = fmt.Errorf("corrupt metadata index: incomplete entry")
+			return
+		}
+
+		if i, ok := positions[entry.ExecutionID]; ok {
+			entries[i] = entry
+			return
+		}
+		positions[entry.ExecutionID] = len(entries)
+		entries = append(entries, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return entries, nil
+}
+
+// rebuildMetadataIndex reads every metadata file and atomically replaces the
+// index with one entry per execution. The caller must hold em.mu.
+func (em *ExecutionManager) rebuildMetadataIndex(metadataFiles []string) ([]ExecutionIndexEntry, error) {
+	metadataDir := filepath.Join(em.logDir, "metadata")
+
+	var entries []ExecutionIndexEntry
+	var buf strings.Builder
+	for _, name := range metadataFiles {
+		path := filepath.Join(metadataDir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			logging.Warnf("failed to read metadata file %s: %v", name, err)
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logging.Warnf("failed to read metadata file %s: %v", name, err)
+			continue
+		}
+
+		var metadata ExecutionMetadata
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			logging.Warnf("failed to unmarshal metadata file %s: %v", name, err)
+			continue
+		}
+
+		entry := newExecutionIndexEntry(&metadata, name, info)
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		entries = append(entries, entry)
+	}
+
+	tmpFile := em.metadataIndexPath() + ".tmp"
+	if err := os.WriteFile(tmpFile, []byte(buf.String()), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write metadata index: %w", err)
+	}
+	if err := os.Rename(tmpFile, em.metadataIndexPath()); err != nil {
+		_ = os.Remove(tmpFile)
+		return nil, fmt.Errorf("failed to replace metadata index: %w", err)
+	}
+
+	return entries, nil
+}
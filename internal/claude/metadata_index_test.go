@@ -0,0 +1,191 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func newIndexTestManager(t *testing.T) *ExecutionManager {
+	t.Helper()
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+	return em
+}
+
+func saveIndexTestMetadata(t *testing.T, em *ExecutionManager, metadata *ExecutionMetadata) string {
+	t.Helper()
+	path := filepath.Join(em.logDir, "metadata", GenerateMetadataFileName(metadata.StartTime, metadata.ExecutionID))
+	if err := em.saveMetadata(metadata, path); err != nil {
+		t.Fatalf("saveMetadata() failed: %v", err)
+	}
+	return path
+}
+
+func indexByID(t *testing.T, em *ExecutionManager) map[string]ExecutionIndexEntry {
+	t.Helper()
+	entries, err := em.LoadExecutionIndex()
+	if err != nil {
+		t.Fatalf("LoadExecutionIndex() failed: %v", err)
+	}
+	byID := make(map[string]ExecutionIndexEntry, len(entries))
+	for _, entry := range entries {
+		byID[entry.ExecutionID] = entry
+	}
+	return byID
+}
+
+func TestLoadExecutionIndexBuildsMissingIndex(t *testing.T) {
+	em := newIndexTestManager(t)
+	startTime := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	// Write metadata files directly, as an older version without the index would
+	for _, id := range []string{"exec-a", "exec-b"} {
+		metadata := ExecutionMetadata{
+			ExecutionID: id,
+			Status:      ExecutionStatusCompleted,
+			StartTime:   startTime,
+			Prompt:      strings.Repeat("x", indexPromptSnippetLength*2),
+			Repository:  "github.com/example/repo",
+		}
+		data, _ := json.Marshal(metadata)
+		path := filepath.Join(em.logDir, "metadata", GenerateMetadataFileName(startTime, id))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to write metadata: %v", err)
+		}
+	}
+
+	byID := indexByID(t, em)
+	if len(byID) != 2 {
+		t.Fatalf("expected 2 index entries, got %d", len(byID))
+	}
+	entry := byID["exec-a"]
+	if entry.Repository != "github.com/example/repo" || !entry.StartTime.Equal(startTime) {
+		t.Errorf("unexpected index entry: %+v", entry)
+	}
+	if len(entry.Prompt) != indexPromptSnippetLength {
+		t.Errorf("expected prompt snippet of %d chars, got %d", indexPromptSnippetLength, len(entry.Prompt))
+	}
+
+	if _, err := os.Stat(em.metadataIndexPath()); err != nil {
+		t.Errorf("expected index file to be written: %v", err)
+	}
+}
+
+func TestSaveMetadataUpdatesIndexIncrementally(t *testing.T) {
+	em := newIndexTestManager(t)
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-a",
+		Status:      ExecutionStatusRunning,
+		StartTime:   time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+		Prompt:      "Fix the failing tests",
+	}
+	saveIndexTestMetadata(t, em, metadata)
+
+	metadata.Status = ExecutionStatusCompleted
+	saveIndexTestMetadata(t, em, metadata)
+
+	data, err := os.ReadFile(em.metadataIndexPath())
+	if err != nil {
+		t.Fatalf("failed to read index: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("expected 2 appended index lines, got %d", lines)
+	}
+
+	byID := indexByID(t, em)
+	if len(byID) != 1 {
+		t.Fatalf("expected 1 index entry, got %d", len(byID))
+	}
+	if got := byID["exec-a"].Status; got != ExecutionStatusCompleted {
+		t.Errorf("expected latest status %s, got %s", ExecutionStatusCompleted, got)
+	}
+}
+
+func TestSaveMetadataIndexesExitCode(t *testing.T) {
+	em := newIndexTestManager(t)
+	metadata := &ExecutionMetadata{
+		ExecutionID: "exec-failed",
+		Status:      ExecutionStatusFailed,
+		ExitCode:    2,
+		StartTime:   time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+	saveIndexTestMetadata(t, em, metadata)
+
+	if got := indexByID(t, em)["exec-failed"].Metadata().ExitCode; got != 2 {
+		t.Errorf("expected exit code 2 in the index, got %d", got)
+	}
+}
+
+func TestLoadExecutionIndexRecoversFromCorruption(t *testing.T) {
+	em := newIndexTestManager(t)
+	startTime := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	saveIndexTestMetadata(t, em, &ExecutionMetadata{ExecutionID: "exec-a", Status: ExecutionStatusCompleted, StartTime: startTime})
+	saveIndexTestMetadata(t, em, &ExecutionMetadata{ExecutionID: "exec-b", Status: ExecutionStatusFailed, StartTime: startTime})
+
+	file, err := os.OpenFile(em.metadataIndexPath(), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	if _, err := file.WriteString(`{"execution_id":"exec-c","sta`); err != nil {
+		t.Fatalf("failed to corrupt index: %v", err)
+	}
+	_ = file.Close()
+
+	byID := indexByID(t, em)
+	if len(byID) != 2 || byID["exec-b"].Status != ExecutionStatusFailed {
+		t.Fatalf("expected index rebuilt from metadata, got %+v", byID)
+	}
+
+	if _, err := em.readMetadataIndex(); err != nil {
+		t.Errorf("expected rebuilt index to be readable: %v", err)
+	}
+}
+
+func TestLoadExecutionIndexDropsRemovedMetadata(t *testing.T) {
+	em := newIndexTestManager(t)
+	startTime := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	saveIndexTestMetadata(t, em, &ExecutionMetadata{ExecutionID: "exec-a", StartTime: startTime})
+	path := saveIndexTestMetadata(t, em, &ExecutionMetadata{ExecutionID: "exec-b", StartTime: startTime})
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("failed to remove metadata: %v", err)
+	}
+
+	byID := indexByID(t, em)
+	if _, ok := byID["exec-b"]; ok || len(byID) != 1 {
+		t.Errorf("expected removed execution to be dropped, got %+v", byID)
+	}
+}
+
+func TestLoadExecutionIndexNoticesMetadataRewrittenWithoutIndex(t *testing.T) {
+	em := newIndexTestManager(t)
+	startTime := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	metadata := &ExecutionMetadata{ExecutionID: "task-worker", Status: ExecutionStatusRunning, StartTime: startTime}
+	path := saveIndexTestMetadata(t, em, metadata)
+	if got := indexByID(t, em)["task-worker"].Status; got != ExecutionStatusRunning {
+		t.Fatalf("indexed status = %s, want running", got)
+	}
+
+	// The worker rewrites its metadata without going through the index
+	metadata.Status = ExecutionStatusCompleted
+	data, _ := json.Marshal(metadata)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := indexByID(t, em)["task-worker"].Status; got != ExecutionStatusCompleted {
+		t.Errorf("indexed status = %s after the metadata was rewritten, want completed", got)
+	}
+}
@@ -44,6 +44,8 @@ const (
 	StatusSkipped Status = "skipped"
 	// StatusCancelled indicates a task was manually cancelled.
 	StatusCancelled Status = "cancelled"
+	// StatusAborted indicates a task was interrupted because the worker shut down before it finished.
+	StatusAborted Status = "aborted"
 )
 
 // DependencyPolicy defines how to handle dependency failures
@@ -56,6 +58,8 @@ const (
 	DependencyPolicySkip DependencyPolicy = "skip"
 	// DependencyPolicyFail fails this task immediately if any dependency fails.
 	DependencyPolicyFail DependencyPolicy = "fail"
+	// DependencyPolicyContinue treats failed dependencies as satisfied and runs this task anyway.
+	DependencyPolicyContinue DependencyPolicy = "continue"
 )
 
 // TaskType represents the type of task
@@ -98,12 +102,25 @@ type Task struct {
 
 	SessionID string `json:"session_id,omitempty"`
 	AgentType string `json:"agent_type"`
+	Model     string `json:"model,omitempty"` // Claude model override (empty = CLI default)
+
+	Tags []string `json:"tags,omitempty"` // Free-form labels for filtering the queue
+
+	Env       map[string]string `json:"env,omitempty"`        // Extra environment for the Claude process
+	SecretEnv []string          `json:"secret_env,omitempty"` // Env keys redacted in execution logs
 
 	// Task dependencies
 	DependsOn        []string         `json:"depends_on"`        // Task IDs this task depends on
 	Blocks           []string         `json:"blocks,omitempty"`  // Task IDs blocked by this task (auto-populated)
 	DependencyPolicy DependencyPolicy `json:"dependency_policy"` // How to handle dependency failures
 
+	// Retry on failure
+	MaxRetries   int           `json:"max_retries,omitempty"`   // Additional attempts after the first failure
+	RetryBackoff time.Duration `json:"retry_backoff,omitempty"` // Delay before the first retry, doubled for each further retry
+	NextRetryAt  *time.Time    `json:"next_retry_at,omitempty"` // Task is not ready again before this time
+
+	MaxCostUSD float64 `json:"max_cost_usd,omitempty"` // Abort the execution once it costs more (0 = claude.max_cost_usd)
+
 	// Enhanced task definition based on Claude Code best practices
 	Prompt               string   `json:"prompt"`                // Complete task prompt for Claude
 	FilesToFocus         []string `json:"files_to_focus"`        // Key files to work on (relative to worktree)
@@ -135,6 +152,8 @@ type TaskResult struct {
 	DependenciesWaitTime time.Duration `json:"dependencies_wait_time"` // Time spent waiting for dependencies
 	DependencyFailures   []string      `json:"dependency_failures"`    // Failed dependencies that affected this task
 	Error                string        `json:"error,omitempty"`        // Error message if task failed
+	ErrorKind            ErrorKind     `json:"error_kind,omitempty"`   // Classification of Error
+	RetryCount           int           `json:"retry_count,omitempty"`  // Number of retries already attempted
 }
 
 // TaskFile represents the YAML structure for batch task creation
@@ -153,8 +172,13 @@ type TaskFileEntry struct {
 	Worktree             string           `yaml:"worktree"`             // Worktree name or path
 	BaseBranch           string           `yaml:"base_branch"`          // Base branch for worktree creation (required)
 	Priority             int              `yaml:"priority,omitempty"`
+	Model                string           `yaml:"model,omitempty"`
+	Tags                 []string         `yaml:"tags,omitempty"`
 	DependsOn            []string         `yaml:"depends_on,omitempty"`
 	DependencyPolicy     DependencyPolicy `yaml:"dependency_policy,omitempty"`
+	MaxRetries           int              `yaml:"max_retries,omitempty"`
+	RetryBackoff         time.Duration    `yaml:"retry_backoff,omitempty"`
+	MaxCostUSD           float64          `yaml:"max_cost_usd,omitempty"`
 	Prompt               string           `yaml:"prompt,omitempty"`
 	FilesToFocus         []string         `yaml:"files_to_focus,omitempty"`
 	VerificationCommands []string         `yaml:"verification_commands,omitempty"`
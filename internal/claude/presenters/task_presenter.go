@@ -255,6 +255,8 @@ func (p *TaskPresenter) getStatusIcon(status claude.Status) string {
 		return "⤵"
 	case claude.StatusCancelled:
 		return "✕"
+	case claude.StatusAborted:
+		return "⊘"
 	default:
 		return "?"
 	}
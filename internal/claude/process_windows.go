@@ -0,0 +1,9 @@
+//go:build windows
+
+package claude
+
+import "os/exec"
+
+// killProcessGroupOnCancel is a no-op on Windows, where only the direct child
+// is killed when its context is done
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
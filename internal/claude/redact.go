@@ -0,0 +1,92 @@
+package claude
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RedactedPlaceholder replaces secrets in captured logs
+const RedactedPlaceholder = "***REDACTED***"
+
+// DefaultRedactPatterns are always applied to captured logs, before any
+// configured patterns. When a pattern has a capturing group only the first
+// group is redacted, so the surrounding key or scheme stays readable.
+var DefaultRedactPatterns = []string{
+	// AWS access key IDs
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	// GitHub tokens
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+	// Authorization: Bearer <token>
+	`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`,
+	// .env-style assignments such as API_KEY=... or AWS_SECRET_ACCESS_KEY="..."
+	`\b[A-Z0-9_]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_KEY|ACCESS_KEY|PRIVATE_KEY)[A-Z0-9_]*\s*[=:]\s*["']?([^\s"']+)`,
+}
+
+// LogRedactor masks secrets in captured Claude output
+type LogRedactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewLogRedactor compiles the default patterns followed by extra
+func NewLogRedactor(extra []string) (*LogRedactor, error) {
+	r := &LogRedactor{}
+	for _, pattern := range append(append([]string{}, DefaultRedactPatterns...), extra...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// RedactString returns s with every pattern match replaced by
+// RedactedPlaceholder
+func (r *LogRedactor) RedactString(s string) string {
+	for _, re := range r.patterns {
+		s = redactMatches(re, s)
+	}
+	return s
+}
+
+// RedactJSON redacts the string values of a decoded JSON value in place and
+// returns it. Keys, numbers and the nesting are left untouched, so the
+// entry still encodes to the same structure.
+func (r *LogRedactor) RedactJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return r.RedactString(value)
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = r.RedactJSON(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = r.RedactJSON(item)
+		}
+	}
+	return v
+}
+
+// redactMatches replaces the first capturing group of each match of re, or
+// the whole match when re has no groups or the group did not participate
+func redactMatches(re *regexp.Regexp, s string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		out = append(out, s[last:start]...)
+		out = append(out, RedactedPlaceholder...)
+		last = end
+	}
+	out = append(out, s[last:]...)
+	return string(out)
+}
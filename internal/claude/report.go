@@ -0,0 +1,288 @@
+package claude
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// reportFileEditTools are the tools whose successful uses change the file in
+// their file_path or notebook_path input
+var reportFileEditTools = []string{"Edit", "MultiEdit", "Write", "NotebookEdit"}
+
+// reportEntry is one execution in a report, with its cost and duration taken
+// from the log when the metadata doesn't record them
+type reportEntry struct {
+	metadata   ExecutionMetadata
+	result     *Result
+	costUSD    float64
+	durationMS int64
+	files      []string
+}
+
+// GenerateReport renders executions as a single Markdown ("md") or HTML
+// ("html") document: a summary table followed by each execution's prompt,
+// result and changed files. Executions appear in the given order. Details
+// come from each execution's log; executions without a log are reported from
+// their metadata alone.
+func (em *ExecutionManager) GenerateReport(executions []ExecutionMetadata, format string) (string, error) {
+	var render func([]reportEntry) string
+	switch format {
+	case "", "md", "markdown":
+		render = renderMarkdownReport
+	case "html":
+		render = renderHTMLReport
+	default:
+		return "", fmt.Errorf("unsupported report format: %s (expected md or html)", format)
+	}
+
+	lp := NewLogProcessor()
+	entries := make([]reportEntry, 0, len(executions))
+	for _, metadata := range executions {
+		entry, err := lp.reportEntry(metadata, FindLogFileByExecutionID(em.GetLogDir(), metadata.StartTime, metadata.ExecutionID))
+		if err != nil {
+			return "", fmt.Errorf("failed to process %s: %w", metadata.ExecutionID, err)
+		}
+		entries = append(entries, entry)
+	}
+	return render(entries), nil
+}
+
+// reportEntry collects the report details of an execution from its log. A
+// missing log is not an error.
+func (lp *LogProcessor) reportEntry(metadata ExecutionMetadata, logFile string) (reportEntry, error) {
+	entry := reportEntry{
+		metadata:   metadata,
+		costUSD:    metadata.CostUSD,
+		durationMS: metadata.DurationMS,
+	}
+	if metadata.Result != nil {
+		entry.files = metadata.Result.FilesChanged
+	}
+
+	logEntries, err := lp.loadJSONLog(logFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entry, nil
+		}
+		return entry, fmt.Errorf("failed to load log: %w", err)
+	}
+
+	entry.result = lp.extractResults(logEntries)
+	if entry.result != nil {
+		if entry.costUSD == 0 {
+			entry.costUSD = entry.result.CostUSD
+		}
+		if entry.durationMS == 0 {
+			entry.durationMS = entry.result.Duration
+		}
+	}
+	if len(entry.files) == 0 {
+		entry.files = lp.changedFiles(lp.extractToolUses(logEntries))
+	}
+	return entry, nil
+}
+
+// changedFiles returns the files edited or written by successful tool uses,
+// in the order they were first changed
+func (lp *LogProcessor) changedFiles(toolUses []ToolUse) []string {
+	var files []string
+	for _, toolUse := range toolUses {
+		if !toolUse.Success || !slices.Contains(reportFileEditTools, toolUse.Name) {
+			continue
+		}
+		input := lp.parseJSONInput(toolUse.Input)
+		path, _ := input["file_path"].(string)
+		if path == "" {
+			path, _ = input["notebook_path"].(string)
+		}
+		if path != "" && !slices.Contains(files, path) {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// reportTotals sums the cost and duration of entries and counts them by status,
+// listing statuses in the order they first appear
+func reportTotals(entries []reportEntry) (costUSD float64, durationMS int64, statuses string) {
+	counts := make(map[ExecutionStatus]int)
+	var order []ExecutionStatus
+	for _, entry := range entries {
+		costUSD += entry.costUSD
+		durationMS += entry.durationMS
+		if _, seen := counts[entry.metadata.Status]; !seen {
+			order = append(order, entry.metadata.Status)
+		}
+		counts[entry.metadata.Status]++
+	}
+
+	parts := make([]string, 0, len(order))
+	for _, status := range order {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	return costUSD, durationMS, strings.Join(parts, ", ")
+}
+
+// reportPromptSummary returns the first line of the prompt, shortened for the
+// summary table
+func reportPromptSummary(lp *LogProcessor, prompt string) string {
+	first, _, _ := strings.Cut(strings.TrimSpace(lp.extractActualPrompt(prompt)), "\n")
+	return truncateRunes(first, maxSummaryLength)
+}
+
+// formatReportDuration formats a duration in milliseconds, rounded to the second
+func formatReportDuration(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+}
+
+// renderMarkdownReport renders entries as Markdown
+func renderMarkdownReport(entries []reportEntry) string {
+	lp := NewLogProcessor()
+	var output strings.Builder
+
+	output.WriteString("# Task Report\n\n")
+	output.WriteString("| Execution | Status | Started | Duration | Cost | Files | Prompt |\n")
+	output.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, entry := range entries {
+		m := entry.metadata
+		output.WriteString(fmt.Sprintf("| [%s](#%s) | %s | %s | %s | $%.4f | %d | %s |\n",
+			m.ExecutionID, strings.ToLower(m.ExecutionID), m.Status, m.StartTime.Format("2006-01-02 15:04:05"),
+			formatReportDuration(entry.durationMS), entry.costUSD, len(entry.files),
+			markdownTableCell(reportPromptSummary(lp, m.Prompt))))
+	}
+
+	costUSD, durationMS, statuses := reportTotals(entries)
+	output.WriteString(fmt.Sprintf("\n**Total:** %d execution(s)", len(entries)))
+	if statuses != "" {
+		output.WriteString(fmt.Sprintf(" (%s)", statuses))
+	}
+	output.WriteString(fmt.Sprintf(", $%.4f, %s\n", costUSD, formatReportDuration(durationMS)))
+
+	for _, entry := range entries {
+		m := entry.metadata
+		output.WriteString(fmt.Sprintf("\n## %s\n\n", m.ExecutionID))
+		output.WriteString("| Field | Value |\n")
+		output.WriteString("| --- | --- |\n")
+		output.WriteString(fmt.Sprintf("| Status | %s |\n", m.Status))
+		if m.Status == ExecutionStatusFailed || m.ExitCode != 0 {
+			output.WriteString(fmt.Sprintf("| Exit Code | %d |\n", m.ExitCode))
+		}
+		output.WriteString(fmt.Sprintf("| Started | %s |\n", m.StartTime.Format("2006-01-02 15:04:05")))
+		output.WriteString(fmt.Sprintf("| Duration | %s |\n", formatReportDuration(entry.durationMS)))
+		output.WriteString(fmt.Sprintf("| Cost | $%.4f |\n", entry.costUSD))
+		if m.Repository != "" {
+			output.WriteString(fmt.Sprintf("| Repository | %s |\n", markdownTableCell(m.Repository)))
+		}
+		if m.Model != "" {
+			output.WriteString(fmt.Sprintf("| Model | %s |\n", markdownTableCell(m.Model)))
+		}
+		if len(m.Tags) > 0 {
+			output.WriteString(fmt.Sprintf("| Tags | %s |\n", markdownTableCell(strings.Join(m.Tags, ", "))))
+		}
+
+		// Prompt and result text is shown verbatim, like the <pre> blocks of
+		// the HTML report, so Markdown in it can't break the report structure
+		output.WriteString("\n### Prompt\n")
+		output.WriteString(markdownCodeBlock(strings.TrimSpace(lp.extractActualPrompt(m.Prompt)), "", ""))
+
+		if entry.result != nil && entry.result.Message != "" {
+			heading := "Result"
+			if !entry.result.Success {
+				heading = "Error"
+			}
+			output.WriteString(fmt.Sprintf("\n### %s\n", heading))
+			output.WriteString(markdownCodeBlock(strings.TrimSpace(entry.result.Message), "", ""))
+		}
+
+		if len(entry.files) > 0 {
+			output.WriteString("\n### Changed Files\n\n")
+			for _, file := range entry.files {
+				output.WriteString(fmt.Sprintf("- `%s`\n", file))
+			}
+		}
+	}
+
+	return output.String()
+}
+
+// renderHTMLReport renders entries as a standalone HTML page
+func renderHTMLReport(entries []reportEntry) string {
+	lp := NewLogProcessor()
+	var output strings.Builder
+	esc := html.EscapeString
+
+	output.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Task Report</title>\n")
+	output.WriteString("<style>\nbody { font-family: sans-serif; margin: 2em; }\n")
+	output.WriteString("table { border-collapse: collapse; }\nth, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }\n")
+	output.WriteString("pre { background: #f6f8fa; padding: 8px; white-space: pre-wrap; }\n</style>\n</head>\n<body>\n")
+
+	output.WriteString("<h1>Task Report</h1>\n<table>\n")
+	output.WriteString("<tr><th>Execution</th><th>Status</th><th>Started</th><th>Duration</th><th>Cost</th><th>Files</th><th>Prompt</th></tr>\n")
+	for _, entry := range entries {
+		m := entry.metadata
+		output.WriteString(fmt.Sprintf("<tr><td><a href=\"#%s\">%s</a></td><td>%s</td><td>%s</td><td>%s</td><td>$%.4f</td><td>%d</td><td>%s</td></tr>\n",
+			esc(m.ExecutionID), esc(m.ExecutionID), esc(string(m.Status)), m.StartTime.Format("2006-01-02 15:04:05"),
+			formatReportDuration(entry.durationMS), entry.costUSD, len(entry.files),
+			esc(reportPromptSummary(lp, m.Prompt))))
+	}
+	output.WriteString("</table>\n")
+
+	costUSD, durationMS, statuses := reportTotals(entries)
+	output.WriteString(fmt.Sprintf("<p><strong>Total:</strong> %d execution(s)", len(entries)))
+	if statuses != "" {
+		output.WriteString(fmt.Sprintf(" (%s)", esc(statuses)))
+	}
+	output.WriteString(fmt.Sprintf(", $%.4f, %s</p>\n", costUSD, formatReportDuration(durationMS)))
+
+	for _, entry := range entries {
+		m := entry.metadata
+		output.WriteString(fmt.Sprintf("\n<h2 id=\"%s\">%s</h2>\n<table>\n", esc(m.ExecutionID), esc(m.ExecutionID)))
+		row := func(field, value string) {
+			output.WriteString(fmt.Sprintf("<tr><th>%s</th><td>%s</td></tr>\n", field, esc(value)))
+		}
+		row("Status", string(m.Status))
+		if m.Status == ExecutionStatusFailed || m.ExitCode != 0 {
+			row("Exit Code", fmt.Sprint(m.ExitCode))
+		}
+		row("Started", m.StartTime.Format("2006-01-02 15:04:05"))
+		row("Duration", formatReportDuration(entry.durationMS))
+		row("Cost", fmt.Sprintf("$%.4f", entry.costUSD))
+		if m.Repository != "" {
+			row("Repository", m.Repository)
+		}
+		if m.Model != "" {
+			row("Model", m.Model)
+		}
+		if len(m.Tags) > 0 {
+			row("Tags", strings.Join(m.Tags, ", "))
+		}
+		output.WriteString("</table>\n")
+
+		output.WriteString("<h3>Prompt</h3>\n<pre>")
+		output.WriteString(esc(strings.TrimSpace(lp.extractActualPrompt(m.Prompt))))
+		output.WriteString("</pre>\n")
+
+		if entry.result != nil && entry.result.Message != "" {
+			heading := "Result"
+			if !entry.result.Success {
+				heading = "Error"
+			}
+			output.WriteString(fmt.Sprintf("<h3>%s</h3>\n<pre>%s</pre>\n", heading, esc(strings.TrimSpace(entry.result.Message))))
+		}
+
+		if len(entry.files) > 0 {
+			output.WriteString("<h3>Changed Files</h3>\n<ul>\n")
+			for _, file := range entry.files {
+				output.WriteString(fmt.Sprintf("<li><code>%s</code></li>\n", esc(file)))
+			}
+			output.WriteString("</ul>\n")
+		}
+	}
+
+	output.WriteString("</body>\n</html>\n")
+	return output.String()
+}
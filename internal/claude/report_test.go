@@ -0,0 +1,146 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// newReportFixture returns an execution manager holding a completed execution
+// with the testdata log and a failed execution without one
+func newReportFixture(t *testing.T) (*ExecutionManager, []ExecutionMetadata) {
+	t.Helper()
+
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+
+	executions := []ExecutionMetadata{
+		{
+			ExecutionID: "exec-abc123",
+			Status:      ExecutionStatusCompleted,
+			StartTime:   time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+			Repository:  "github.com/example/repo",
+			Prompt:      "Fix the failing tests",
+			Model:       "claude-sonnet-4",
+			Tags:        []string{"backend"},
+		},
+		{
+			ExecutionID: "exec-def456",
+			Status:      ExecutionStatusFailed,
+			ExitCode:    1,
+			StartTime:   time.Date(2025, 1, 2, 11, 30, 0, 0, time.UTC),
+			Prompt:      "Update the docs | add examples\nKeep it short",
+			CostUSD:     0.02,
+			DurationMS:  3400,
+			Result:      &ExecutionResult{FilesChanged: []string{"README.md", "docs/usage.md"}},
+		},
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", "execution.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	logFile := filepath.Join(em.GetLogDir(), "executions", GenerateLogFileName(executions[0].StartTime, executions[0].ExecutionID))
+	if err := os.WriteFile(logFile, data, 0644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	return em, executions
+}
+
+func TestGenerateReportMarkdownGolden(t *testing.T) {
+	em, executions := newReportFixture(t)
+
+	got, err := em.GenerateReport(executions, "md")
+	if err != nil {
+		t.Fatalf("GenerateReport() failed: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "report.md")
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	if got != string(want) {
+		t.Errorf("GenerateReport() mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestGenerateReportHTML(t *testing.T) {
+	em, executions := newReportFixture(t)
+	executions[1].Prompt = "Escape <b>this</b>"
+
+	got, err := em.GenerateReport(executions, "html")
+	if err != nil {
+		t.Fatalf("GenerateReport() failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<a href="#exec-abc123">exec-abc123</a>`,
+		"<li><code>parse.go</code></li>",
+		"<li><code>docs/usage.md</code></li>",
+		"Escape &lt;b&gt;this&lt;/b&gt;",
+		"2 execution(s) (1 completed, 1 failed), $0.0621, 12s",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML report missing %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<b>this</b>") {
+		t.Error("HTML report contains an unescaped prompt")
+	}
+}
+
+func TestGenerateReportMarkdownEscapesPrompt(t *testing.T) {
+	em, executions := newReportFixture(t)
+	executions[1].Prompt = "## Not a heading\n```\nrm -rf /\n```"
+
+	got, err := em.GenerateReport(executions, "md")
+	if err != nil {
+		t.Fatalf("GenerateReport() failed: %v", err)
+	}
+
+	want := "### Prompt\n\n````\n## Not a heading\n```\nrm -rf /\n```\n````\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Markdown report missing the fenced prompt %q\n%s", want, got)
+	}
+}
+
+func TestGenerateReportUnsupportedFormat(t *testing.T) {
+	em, executions := newReportFixture(t)
+
+	if _, err := em.GenerateReport(executions, "pdf"); err == nil {
+		t.Error("GenerateReport() with an unsupported format should fail")
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	lp := NewLogProcessor()
+	toolUses := []ToolUse{
+		{Name: "Read", Input: `{"file_path": "a.go"}`, Success: true},
+		{Name: "Edit", Input: `{"file_path": "b.go"}`, Success: true},
+		{Name: "Write", Input: `{"file_path": "c.go"}`, Success: false},
+		{Name: "MultiEdit", Input: `{"file_path": "b.go"}`, Success: true},
+		{Name: "NotebookEdit", Input: `{"notebook_path": "n.ipynb"}`, Success: true},
+	}
+
+	got := lp.changedFiles(toolUses)
+	want := []string{"b.go", "n.ipynb"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("changedFiles() = %v, want %v", got, want)
+	}
+}
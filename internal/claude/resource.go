@@ -11,15 +11,38 @@ import (
 type ResourceManager struct {
 	maxClaude      int
 	maxDevelopment int
+	maxPerRepo     int // 0 = no per-repository limit
 	activeDev      int
+	activeByRepo   map[string]int
 	devSlots       chan struct{}
+	onEvent        func(SlotEvent) // Called after each acquisition and release, if set
 	mu             sync.RWMutex
 }
 
+// SlotEventKind identifies what happened to a slot
+type SlotEventKind string
+
+const (
+	// SlotAcquired is emitted when a task acquires a slot
+	SlotAcquired SlotEventKind = "acquire"
+	// SlotReleased is emitted when a task releases its slot
+	SlotReleased SlotEventKind = "release"
+)
+
+// SlotEvent describes a slot being acquired or released
+type SlotEvent struct {
+	Kind       SlotEventKind
+	TaskID     string
+	Repository string
+	Active     int // Slots held once the event took effect
+	Time       time.Time
+}
+
 // Slot represents a resource slot allocation
 type Slot struct {
 	ID         string
 	TaskType   TaskType
+	Repository string // Repository counted against the per-repository limit, if any
 	AcquiredAt time.Time
 	manager    *ResourceManager
 }
@@ -33,10 +56,46 @@ func NewResourceManager(maxClaude, maxDevelopment int) *ResourceManager {
 	return &ResourceManager{
 		maxClaude:      maxClaude,
 		maxDevelopment: maxDevelopment,
+		activeByRepo:   make(map[string]int),
 		devSlots:       make(chan struct{}, maxDevelopment),
 	}
 }
 
+// SetMaxPerRepository limits how many slots TryAcquireSlot hands out for the
+// same repository at once (0 disables the limit)
+func (r *ResourceManager) SetMaxPerRepository(max int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxPerRepo = max
+}
+
+// SetEventHandler registers fn to be called after every slot acquisition and
+// release. fn runs on the acquiring or releasing goroutine, outside the
+// manager's lock, and must not block.
+func (r *ResourceManager) SetEventHandler(fn func(SlotEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onEvent = fn
+}
+
+// slotEvent builds an event for slot; the caller must hold r.mu
+func (r *ResourceManager) slotEvent(kind SlotEventKind, slot *Slot) SlotEvent {
+	return SlotEvent{
+		Kind:       kind,
+		TaskID:     slot.ID,
+		Repository: slot.Repository,
+		Active:     r.activeDev,
+		Time:       time.Now(),
+	}
+}
+
+// emitSlotEvent passes event to handler, if any
+func emitSlotEvent(handler func(SlotEvent), event SlotEvent) {
+	if handler != nil {
+		handler(event)
+	}
+}
+
 // AcquireSlot attempts to acquire a resource slot for the given task type
 func (r *ResourceManager) AcquireSlot(ctx context.Context, taskType TaskType, taskID string) (*Slot, error) {
 	slot := &Slot{
@@ -52,7 +111,9 @@ func (r *ResourceManager) AcquireSlot(ctx context.Context, taskType TaskType, ta
 		case r.devSlots <- struct{}{}:
 			r.mu.Lock()
 			r.activeDev++
+			handler, event := r.onEvent, r.slotEvent(SlotAcquired, slot)
 			r.mu.Unlock()
+			emitSlotEvent(handler, event)
 			return slot, nil
 		case <-ctx.Done():
 			return nil, ctx.Err()
@@ -62,24 +123,39 @@ func (r *ResourceManager) AcquireSlot(ctx context.Context, taskType TaskType, ta
 	}
 }
 
-// TryAcquireSlot attempts to acquire a slot without blocking
-func (r *ResourceManager) TryAcquireSlot(taskType TaskType, taskID string) (*Slot, error) {
+// TryAcquireSlot attempts to acquire a slot without blocking. A non-empty
+// repository is refused once it holds the per-repository limit of slots, even
+// if global capacity remains.
+func (r *ResourceManager) TryAcquireSlot(taskType TaskType, taskID string, repository string) (*Slot, error) {
 	slot := &Slot{
 		ID:         taskID,
 		TaskType:   taskType,
+		Repository: repository,
 		AcquiredAt: time.Now(),
 		manager:    r,
 	}
 
 	switch taskType {
 	case TaskTypeDevelopment:
+		r.mu.Lock()
+
+		if repository != "" && r.maxPerRepo > 0 && r.activeByRepo[repository] >= r.maxPerRepo {
+			r.mu.Unlock()
+			return nil, fmt.Errorf("repository %s already has %d running tasks", repository, r.maxPerRepo)
+		}
+
 		select {
 		case r.devSlots <- struct{}{}:
-			r.mu.Lock()
 			r.activeDev++
+			if repository != "" {
+				r.activeByRepo[repository]++
+			}
+			handler, event := r.onEvent, r.slotEvent(SlotAcquired, slot)
 			r.mu.Unlock()
+			emitSlotEvent(handler, event)
 			return slot, nil
 		default:
+			r.mu.Unlock()
 			return nil, fmt.Errorf("no development slots available")
 		}
 	default:
@@ -94,7 +170,15 @@ func (s *Slot) Release() {
 		<-s.manager.devSlots
 		s.manager.mu.Lock()
 		s.manager.activeDev--
+		if s.Repository != "" {
+			s.manager.activeByRepo[s.Repository]--
+			if s.manager.activeByRepo[s.Repository] <= 0 {
+				delete(s.manager.activeByRepo, s.Repository)
+			}
+		}
+		handler, event := s.manager.onEvent, s.manager.slotEvent(SlotReleased, s)
 		s.manager.mu.Unlock()
+		emitSlotEvent(handler, event)
 	}
 }
 
@@ -178,7 +262,7 @@ func NewResourceWaiter(manager *ResourceManager, taskType TaskType, taskID strin
 // Wait waits for a resource slot to become available
 func (w *ResourceWaiter) Wait(ctx context.Context) (*Slot, error) {
 	// Check if we can acquire immediately
-	if slot, err := w.manager.TryAcquireSlot(w.taskType, w.taskID); err == nil {
+	if slot, err := w.manager.TryAcquireSlot(w.taskType, w.taskID, ""); err == nil {
 		return slot, nil
 	}
 
@@ -0,0 +1,72 @@
+package claude
+
+import (
+	"testing"
+)
+
+func TestTryAcquireSlotPerRepositoryLimit(t *testing.T) {
+	rm := NewResourceManager(4, 4)
+	rm.SetMaxPerRepository(2)
+
+	acquire := func(id, repo string) *Slot {
+		t.Helper()
+		slot, err := rm.TryAcquireSlot(TaskTypeDevelopment, id, repo)
+		if err != nil {
+			t.Fatalf("TryAcquireSlot(%s, %s) error = %v", id, repo, err)
+		}
+		return slot
+	}
+
+	a1 := acquire("a1", "/repo/a")
+	acquire("a2", "/repo/a")
+
+	// Repository a is at its cap although global capacity remains
+	if _, err := rm.TryAcquireSlot(TaskTypeDevelopment, "a3", "/repo/a"); err == nil {
+		t.Fatal("TryAcquireSlot() should refuse a third task in /repo/a")
+	}
+	if got := rm.GetStats().ActiveDevelopment; got != 2 {
+		t.Errorf("ActiveDevelopment = %d after refusal, want 2", got)
+	}
+
+	// Repository b is limited independently
+	acquire("b1", "/repo/b")
+	acquire("b2", "/repo/b")
+	if _, err := rm.TryAcquireSlot(TaskTypeDevelopment, "b3", "/repo/b"); err == nil {
+		t.Fatal("TryAcquireSlot() should refuse a third task in /repo/b")
+	}
+
+	// Releasing a slot frees capacity for its own repository only
+	a1.Release()
+	acquire("a3", "/repo/a")
+	if _, err := rm.TryAcquireSlot(TaskTypeDevelopment, "b3", "/repo/b"); err == nil {
+		t.Fatal("TryAcquireSlot() should still refuse /repo/b")
+	}
+}
+
+func TestTryAcquireSlotWithoutRepositoryLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxPerRepo int
+		repo       string
+	}{
+		{name: "limit disabled", maxPerRepo: 0, repo: "/repo/a"},
+		{name: "unknown repository", maxPerRepo: 1, repo: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := NewResourceManager(3, 3)
+			rm.SetMaxPerRepository(tt.maxPerRepo)
+
+			for _, id := range []string{"t1", "t2", "t3"} {
+				if _, err := rm.TryAcquireSlot(TaskTypeDevelopment, id, tt.repo); err != nil {
+					t.Fatalf("TryAcquireSlot(%s) error = %v", id, err)
+				}
+			}
+			// Only the global limit applies
+			if _, err := rm.TryAcquireSlot(TaskTypeDevelopment, "t4", tt.repo); err == nil {
+				t.Error("TryAcquireSlot() should refuse once global capacity is used")
+			}
+		})
+	}
+}
@@ -187,6 +187,8 @@ func (f *FuzzyFinderService) getStatusIcon(status claude.Status) string {
 		return "⤵"
 	case claude.StatusCancelled:
 		return "✕"
+	case claude.StatusAborted:
+		return "⊘"
 	default:
 		return "?"
 	}
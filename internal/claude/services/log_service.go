@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -59,10 +58,7 @@ func (l *LogService) LoadExecutions() ([]claude.ExecutionMetadata, error) {
 		}
 	}
 
-	// Sort by start time (newest first)
-	sort.Slice(executions, func(i, j int) bool {
-		return executions[i].StartTime.After(executions[j].StartTime)
-	})
+	claude.SortExecutionsNewestFirst(executions)
 
 	return executions, nil
 }
@@ -143,9 +139,9 @@ func (l *LogService) CleanOldLogs(olderThan time.Duration) (int, error) {
 	deletedCount := 0
 
 	for _, exec := range toDelete {
-		// Delete log file
+		// Delete log file, with any rotated parts
 		logFile := claude.FindLogFileByExecutionID(logDir, exec.StartTime, exec.ExecutionID)
-		if err := os.Remove(logFile); err == nil {
+		if err := claude.RemoveLogFile(logFile); err == nil {
 			deletedCount++
 		}
 
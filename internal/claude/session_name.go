@@ -0,0 +1,59 @@
+package claude
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultSessionNameTemplate names tmux sessions after the execution ID
+const DefaultSessionNameTemplate = "{id}"
+
+// SessionNameVars are the values available to claude.session_name_template
+type SessionNameVars struct {
+	Repo     string // {repo}: last element of the repository path or URL
+	Branch   string // {branch}: worktree branch, when known
+	TaskName string // {task_name}: task name, when run from the queue
+	ID       string // {id}: execution ID
+}
+
+var (
+	// tmux reserves "." and ":" in target names; stick to a portable set
+	invalidSessionNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+	repeatedDashes          = regexp.MustCompile(`-{2,}`)
+)
+
+// RenderSessionName expands {repo}, {branch}, {task_name} and {id} in
+// template and makes the result safe to use in a tmux session name. An empty
+// template means DefaultSessionNameTemplate, and the ID is used when nothing
+// is left after sanitizing.
+func RenderSessionName(template string, vars SessionNameVars) string {
+	if template == "" {
+		template = DefaultSessionNameTemplate
+	}
+
+	name := strings.NewReplacer(
+		"{repo}", vars.Repo,
+		"{branch}", vars.Branch,
+		"{task_name}", vars.TaskName,
+		"{id}", vars.ID,
+	).Replace(template)
+
+	name = invalidSessionNameChars.ReplaceAllString(name, "-")
+	name = repeatedDashes.ReplaceAllString(name, "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return vars.ID
+	}
+	return name
+}
+
+// sessionRepoName returns the name {repo} expands to for a repository path
+// or URL
+func sessionRepoName(repository string) string {
+	repository = strings.TrimSuffix(strings.TrimRight(repository, "/"), ".git")
+	if repository == "" {
+		return ""
+	}
+	return path.Base(strings.ReplaceAll(repository, "\\", "/"))
+}
@@ -0,0 +1,90 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestRenderSessionName(t *testing.T) {
+	vars := SessionNameVars{
+		Repo:     "gwq",
+		Branch:   "feature/auth",
+		TaskName: "Add login page",
+		ID:       "task-abc123",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		vars     SessionNameVars
+		want     string
+	}{
+		{name: "default", template: "", vars: vars, want: "task-abc123"},
+		{name: "all placeholders", template: "{repo}-{branch}-{task_name}-{id}", vars: vars, want: "gwq-feature-auth-Add-login-page-task-abc123"},
+		{name: "slashes and spaces", template: "{repo}/{task_name}", vars: vars, want: "gwq-Add-login-page"},
+		{name: "tmux separators", template: "{repo}.{branch}:{id}", vars: vars, want: "gwq-feature-auth-task-abc123"},
+		{name: "missing values collapse", template: "{repo}-{branch}-{id}", vars: SessionNameVars{ID: "exec-1"}, want: "exec-1"},
+		{name: "nothing left falls back to id", template: "{task_name}", vars: SessionNameVars{ID: "exec-1"}, want: "exec-1"},
+		{name: "unicode", template: "{task_name}", vars: SessionNameVars{TaskName: "修正 bug", ID: "exec-1"}, want: "bug"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderSessionName(tt.template, tt.vars); got != tt.want {
+				t.Errorf("RenderSessionName(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRepoName(t *testing.T) {
+	tests := map[string]string{
+		"":                                  "",
+		"/home/user/src/gwq":                "gwq",
+		"/home/user/src/gwq/":               "gwq",
+		"github.com/d-kuro/gwq":             "gwq",
+		"https://github.com/d-kuro/gwq.git": "gwq",
+		`C:\src\gwq`:                        "gwq",
+	}
+	for repository, want := range tests {
+		if got := sessionRepoName(repository); got != want {
+			t.Errorf("sessionRepoName(%q) = %q, want %q", repository, got, want)
+		}
+	}
+}
+
+func TestExecutionManagerSessionNameTemplate(t *testing.T) {
+	sessions := &mockSessionManager{}
+	config := &models.ClaudeConfig{
+		ConfigDir:           t.TempDir(),
+		Executable:          writeFakeClaude(t, "exit 0"),
+		SessionNameTemplate: "{repo}-{id}",
+	}
+	em, err := NewExecutionManagerWithSessions(config, sessions)
+	if err != nil {
+		t.Fatalf("NewExecutionManagerWithSessions() failed: %v", err)
+	}
+	em.monitorInterval = 10 * 1000 * 1000 // 10ms
+
+	metadata := &ExecutionMetadata{
+		ExecutionID:      "exec-named",
+		Prompt:           "test",
+		Repository:       "/src/my repo",
+		WorkingDirectory: t.TempDir(),
+	}
+	// The mock session never opens the pipe, and the test ends before the
+	// monitor gives up on it
+	t.Cleanup(func() { _ = os.Remove(namedPipePath("exec-named")) })
+
+	session, err := em.Execute(context.Background(), metadata)
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if !strings.HasSuffix(session.SessionName, "my-repo-exec-named") {
+		t.Errorf("session name = %q, want it to end with my-repo-exec-named", session.SessionName)
+	}
+}
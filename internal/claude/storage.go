@@ -45,7 +45,15 @@ func (s *Storage) SaveTask(task *Task) error {
 		return fmt.Errorf("task ID cannot be empty")
 	}
 
-	data, err := json.MarshalIndent(task, "", "  ")
+	// Secret values go to a separate file only the owner can read
+	persisted := *task
+	var secrets map[string]string
+	persisted.Env, secrets = splitSecretEnv(task.Env, task.SecretEnv)
+	if err := s.saveSecretEnv(task.ID, secrets); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(&persisted, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal task: %w", err)
 	}
@@ -58,6 +66,52 @@ func (s *Storage) SaveTask(task *Task) error {
 	return nil
 }
 
+// saveSecretEnv stores the secret environment values of a task, removing
+// the stored values when there are none
+func (s *Storage) saveSecretEnv(taskID string, secrets map[string]string) error {
+	filename := s.secretsFilename(taskID)
+	if len(secrets) == 0 {
+		if err := s.fs.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove task secrets: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.fs.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task secrets: %w", err)
+	}
+	if err := s.fs.WriteFile(filename, data, 0600); err != nil {
+		return fmt.Errorf("failed to write task secrets: %w", err)
+	}
+	return nil
+}
+
+// loadSecretEnv adds the stored secret environment values to task
+func (s *Storage) loadSecretEnv(task *Task) {
+	if len(task.SecretEnv) == 0 {
+		return
+	}
+
+	data, err := s.fs.ReadFile(s.secretsFilename(task.ID))
+	if err != nil {
+		return
+	}
+	var secrets map[string]string
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return
+	}
+	if task.Env == nil && len(secrets) > 0 {
+		task.Env = make(map[string]string, len(secrets))
+	}
+	for key, value := range secrets {
+		task.Env[key] = value
+	}
+}
+
 // LoadTask loads a task from storage by ID
 func (s *Storage) LoadTask(taskID string) (*Task, error) {
 	s.mu.RLock()
@@ -76,6 +130,7 @@ func (s *Storage) LoadTask(taskID string) (*Task, error) {
 	if err := json.Unmarshal(data, &task); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal task: %w", err)
 	}
+	s.loadSecretEnv(&task)
 
 	return &task, nil
 }
@@ -92,6 +147,7 @@ func (s *Storage) DeleteTask(taskID string) error {
 		}
 		return fmt.Errorf("failed to delete task file: %w", err)
 	}
+	_ = s.fs.Remove(s.secretsFilename(taskID))
 
 	return nil
 }
@@ -124,6 +180,7 @@ func (s *Storage) ListTasks() ([]*Task, error) {
 			// Skip files that can't be unmarshalled
 			continue
 		}
+		s.loadSecretEnv(&task)
 
 		tasks = append(tasks, &task)
 	}
@@ -147,7 +204,7 @@ func (s *Storage) UpdateTaskStatus(taskID string, status Status) error {
 		if task.StartedAt == nil {
 			task.StartedAt = &now
 		}
-	case StatusCompleted, StatusFailed, StatusCancelled, StatusSkipped:
+	case StatusCompleted, StatusFailed, StatusCancelled, StatusSkipped, StatusAborted:
 		if task.CompletedAt == nil {
 			task.CompletedAt = &now
 		}
@@ -244,13 +301,15 @@ func (s *Storage) Cleanup(olderThan time.Duration) (int, error) {
 	for _, task := range tasks {
 		// Only cleanup terminal states
 		if task.Status != StatusCompleted && task.Status != StatusFailed &&
-			task.Status != StatusCancelled && task.Status != StatusSkipped {
+			task.Status != StatusCancelled && task.Status != StatusSkipped &&
+			task.Status != StatusAborted {
 			continue
 		}
 
 		// Check if task is old enough
 		if task.CompletedAt != nil && task.CompletedAt.Before(cutoff) {
 			if err := s.fs.Remove(s.taskFilename(task.ID)); err == nil {
+				_ = s.fs.Remove(s.secretsFilename(task.ID))
 				removed++
 			}
 		}
@@ -264,6 +323,11 @@ func (s *Storage) taskFilename(taskID string) string {
 	return filepath.Join(s.queueDir, fmt.Sprintf("task-%s.json", taskID))
 }
 
+// secretsFilename returns the file holding the secret environment values of a task
+func (s *Storage) secretsFilename(taskID string) string {
+	return filepath.Join(s.queueDir, "secrets", fmt.Sprintf("task-%s.json", taskID))
+}
+
 // isTaskFile checks if a filename is a task file
 func isTaskFile(filename string) bool {
 	return filepath.Ext(filename) == ".json" && len(filename) > 5 && filename[:5] == "task-"
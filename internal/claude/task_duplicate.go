@@ -0,0 +1,95 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/logging"
+)
+
+// ErrDuplicateTask is returned by CreateTask when a queued or running task for
+// the same worktree already has a similar prompt
+var ErrDuplicateTask = errors.New("duplicate task")
+
+// checkDuplicateTask refuses req when a queued or running task for the same
+// worktree has a prompt at least claude.queue.duplicate_similarity alike, or
+// only warns about it when req.AllowDuplicate is set. A zero threshold
+// disables the check, and tasks without a prompt are never duplicates since
+// there is nothing to compare.
+func (tm *TaskManager) checkDuplicateTask(req *CreateTaskRequest, repoRoot string) error {
+	threshold := tm.config.Claude.Queue.DuplicateSimilarity
+	if threshold <= 0 || strings.TrimSpace(req.Prompt) == "" {
+		return nil
+	}
+
+	duplicate, similarity, err := tm.findDuplicateTask(repoRoot, req.Worktree, req.Prompt, threshold)
+	if err != nil || duplicate == nil {
+		return err
+	}
+
+	detail := fmt.Sprintf("%s task %s for worktree %s has a %.0f%% similar prompt", duplicate.Status, duplicate.ID, duplicate.Worktree, similarity*100)
+	if !req.AllowDuplicate {
+		return fmt.Errorf("%w: %s", ErrDuplicateTask, detail)
+	}
+	logging.Warnf("%s", detail)
+	return nil
+}
+
+// findDuplicateTask returns the queued or running task of repoRoot's worktree
+// whose prompt is most similar to prompt, if that similarity reaches threshold
+func (tm *TaskManager) findDuplicateTask(repoRoot, worktree, prompt string, threshold float64) (*Task, float64, error) {
+	tasks, err := tm.storage.ListTasks()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	var duplicate *Task
+	var best float64
+	for _, task := range tasks {
+		switch task.Status {
+		case StatusPending, StatusWaiting, StatusRunning:
+		default:
+			continue
+		}
+		if task.Worktree != worktree || task.RepositoryRoot != repoRoot || strings.TrimSpace(task.Prompt) == "" {
+			continue
+		}
+		if similarity := PromptSimilarity(task.Prompt, prompt); similarity >= threshold && similarity > best {
+			duplicate, best = task, similarity
+		}
+	}
+	return duplicate, best, nil
+}
+
+// PromptSimilarity returns how alike two prompts are, from 0 to 1. Prompts
+// are compared word by word, ignoring case and whitespace, as one minus the
+// word edit distance over the length of the longer prompt; 1 means identical.
+func PromptSimilarity(a, b string) float64 {
+	wordsA := strings.Fields(strings.ToLower(a))
+	wordsB := strings.Fields(strings.ToLower(b))
+	longest := max(len(wordsA), len(wordsB))
+	if longest == 0 {
+		return 1
+	}
+
+	// Levenshtein distance over words, keeping only the previous row
+	prev := make([]int, len(wordsB)+1)
+	curr := make([]int, len(wordsB)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(wordsA); i++ {
+		curr[0] = i
+		for j := 1; j <= len(wordsB); j++ {
+			cost := 1
+			if wordsA[i-1] == wordsB[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return 1 - float64(prev[len(wordsB)])/float64(longest)
+}
@@ -0,0 +1,179 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/d-kuro/gwq/pkg/utils"
+)
+
+// redactedEnvValue replaces secret environment values in logged metadata
+const redactedEnvValue = "[REDACTED]"
+
+// ParseEnvAssignments parses KEY=VALUE strings into an environment map
+func ParseEnvAssignments(assignments []string) (map[string]string, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+
+	env := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\n") {
+			return nil, fmt.Errorf("invalid environment variable %q (expected KEY=VALUE)", assignment)
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+// RedactEnv returns a copy of env with the values of secret keys replaced
+func RedactEnv(env map[string]string, secretKeys []string) map[string]string {
+	if len(env) == 0 {
+		return nil
+	}
+
+	secret := make(map[string]bool, len(secretKeys))
+	for _, key := range secretKeys {
+		secret[key] = true
+	}
+
+	redacted := make(map[string]string, len(env))
+	for key, value := range env {
+		if secret[key] {
+			value = redactedEnvValue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// mergeEnv returns base with each variable of overrides set, replacing any
+// inherited value of the same name. Overrides are appended in key order.
+func mergeEnv(base []string, overrides map[string]string) []string {
+	merged := make([]string, 0, len(base)+len(overrides))
+	for _, entry := range base {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, ok := overrides[key]; ok {
+			continue
+		}
+		merged = append(merged, entry)
+	}
+
+	for _, key := range sortedEnvKeys(overrides) {
+		merged = append(merged, key+"="+overrides[key])
+	}
+	return merged
+}
+
+// writeEnvFile writes env as shell export statements to a new file in dir
+// that only the owner can read, so the values never appear on a command line.
+// It returns an empty path when env is empty.
+func writeEnvFile(dir string, env map[string]string) (string, error) {
+	if len(env) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	for _, key := range sortedEnvKeys(env) {
+		fmt.Fprintf(&b, "export %s\n", utils.ShellQuote(key+"="+env[key]))
+	}
+	path, err := writePrivateFile(dir, "env-*.sh", b.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to write env file: %w", err)
+	}
+	return path, nil
+}
+
+// writePromptFile writes prompt to a new file in dir that only the owner can
+// read, for Claude to read on stdin instead of from its arguments
+func writePromptFile(dir, prompt string) (string, error) {
+	path, err := writePrivateFile(dir, "prompt-*.txt", prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to write prompt file: %w", err)
+	}
+	return path, nil
+}
+
+// writePrivateFile writes content to a new file in dir named after pattern,
+// as os.CreateTemp does, with mode 0600
+func writePrivateFile(dir, pattern, content string) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	// CreateTemp creates the file with mode 0600
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// sourceEnvFileCommand returns a shell prefix loading the env file at path
+// and removing it, or an empty string when path is empty
+func sourceEnvFileCommand(path string) string {
+	if path == "" {
+		return ""
+	}
+	quoted := utils.ShellQuote(path)
+	return fmt.Sprintf(". %s; rm -f %s; ", quoted, quoted)
+}
+
+// splitSecretEnv returns env without the secret keys, and the values of the
+// secret keys separately
+func splitSecretEnv(env map[string]string, secretKeys []string) (public, secrets map[string]string) {
+	if len(env) == 0 {
+		return env, nil
+	}
+
+	public = make(map[string]string, len(env))
+	for key, value := range env {
+		public[key] = value
+	}
+	for _, key := range secretKeys {
+		value, ok := public[key]
+		if !ok {
+			continue
+		}
+		if secrets == nil {
+			secrets = make(map[string]string)
+		}
+		secrets[key] = value
+		delete(public, key)
+	}
+	if len(public) == 0 {
+		public = nil
+	}
+	return public, secrets
+}
+
+// sortedEnvKeys returns the keys of env in sorted order
+func sortedEnvKeys(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MarshalJSON encodes the execution with secret environment values redacted,
+// so saved execution records never contain them
+func (ue UnifiedExecution) MarshalJSON() ([]byte, error) {
+	type unifiedExecution UnifiedExecution
+	redacted := unifiedExecution(ue)
+	redacted.Env = RedactEnv(ue.Env, ue.SecretEnv)
+	return json.Marshal(redacted)
+}
@@ -0,0 +1,214 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// envValue returns the last value of key in a KEY=VALUE list, as exec does
+func envValue(env []string, key string) (string, bool) {
+	value, found := "", false
+	for _, entry := range env {
+		if k, v, ok := strings.Cut(entry, "="); ok && k == key {
+			value, found = v, true
+		}
+	}
+	return value, found
+}
+
+func TestSetupCommandExecutionEnv(t *testing.T) {
+	t.Setenv("GWQ_TEST_INHERITED", "inherited")
+	t.Setenv("GWQ_TEST_OVERRIDDEN", "from-os")
+
+	execution := &UnifiedExecution{
+		ExecutionID: "task-env",
+		SessionID:   "session-env",
+		WorkingDir:  t.TempDir(),
+		Env: map[string]string{
+			"GWQ_TEST_OVERRIDDEN": "from-task",
+			"API_BASE_URL":        "https://staging.example.com",
+			"CLAUDE_EXECUTION_ID": "spoofed",
+		},
+	}
+
+	cce := NewClaudeCodeExecutor(&models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: "claude"})
+	cmd, err := cce.setupCommandExecution(context.Background(), execution, "/tmp/pipe")
+	if err != nil {
+		t.Fatalf("setupCommandExecution() failed: %v", err)
+	}
+
+	want := map[string]string{
+		"GWQ_TEST_INHERITED":  "inherited",
+		"GWQ_TEST_OVERRIDDEN": "from-task",
+		"API_BASE_URL":        "https://staging.example.com",
+		"CLAUDE_EXECUTION_ID": "task-env",
+		"CLAUDE_SESSION_ID":   "session-env",
+	}
+	for key, value := range want {
+		if got, ok := envValue(cmd.Env, key); !ok || got != value {
+			t.Errorf("%s = %q (set: %v), want %q", key, got, ok, value)
+		}
+	}
+
+	count := 0
+	for _, entry := range cmd.Env {
+		if strings.HasPrefix(entry, "GWQ_TEST_OVERRIDDEN=") {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("GWQ_TEST_OVERRIDDEN appears %d times, want the inherited value replaced", count)
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	base := []string{"A=1", "B=2", "PATH=/bin"}
+	got := mergeEnv(base, map[string]string{"B": "override", "C": "3"})
+	want := []string{"A=1", "PATH=/bin", "B=override", "C=3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestParseEnvAssignments(t *testing.T) {
+	env, err := ParseEnvAssignments([]string{"A=1", "B=x=y", "EMPTY="})
+	if err != nil {
+		t.Fatalf("ParseEnvAssignments() failed: %v", err)
+	}
+	want := map[string]string{"A": "1", "B": "x=y", "EMPTY": ""}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("ParseEnvAssignments() = %v, want %v", env, want)
+	}
+
+	for _, invalid := range []string{"NOVALUE", "=value", "BAD KEY=1"} {
+		if _, err := ParseEnvAssignments([]string{invalid}); err == nil {
+			t.Errorf("ParseEnvAssignments(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestUnifiedExecutionMarshalRedactsSecretEnv(t *testing.T) {
+	execution := &UnifiedExecution{
+		ExecutionID: "task-secret",
+		Env:         map[string]string{"API_BASE_URL": "https://example.com", "API_TOKEN": "s3cret"},
+		SecretEnv:   []string{"API_TOKEN"},
+	}
+
+	data, err := json.Marshal(execution)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("marshaled execution leaks secret value: %s", data)
+	}
+
+	var decoded UnifiedExecution
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+	want := map[string]string{"API_BASE_URL": "https://example.com", "API_TOKEN": redactedEnvValue}
+	if !reflect.DeepEqual(decoded.Env, want) {
+		t.Errorf("saved env = %v, want %v", decoded.Env, want)
+	}
+
+	// The in-memory execution keeps the real value for the Claude process
+	if execution.Env["API_TOKEN"] != "s3cret" {
+		t.Error("marshaling must not modify the execution's environment")
+	}
+}
+
+func TestWriteEnvFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tmp")
+	if path, err := writeEnvFile(dir, nil); err != nil || path != "" {
+		t.Errorf("writeEnvFile(nil) = %q, %v, want no file", path, err)
+	}
+	if got := sourceEnvFileCommand(""); got != "" {
+		t.Errorf("sourceEnvFileCommand(\"\") = %q, want empty", got)
+	}
+
+	path, err := writeEnvFile(dir, map[string]string{"B": "it's secret", "A": "1"})
+	if err != nil {
+		t.Fatalf("writeEnvFile() failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("env file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	command := sourceEnvFileCommand(path)
+	if strings.Contains(command, "secret") {
+		t.Errorf("sourceEnvFileCommand() = %q, must not contain the values", command)
+	}
+	out, err := exec.Command("bash", "-c", command+`printf '%s|%s' "$A" "$B"`).Output()
+	if err != nil {
+		t.Fatalf("running %q failed: %v", command, err)
+	}
+	if string(out) != "1|it's secret" {
+		t.Errorf("sourced environment = %q, want %q", out, "1|it's secret")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("the env file should be removed once sourced")
+	}
+}
+
+func TestStorageKeepsSecretEnvOutOfTaskFile(t *testing.T) {
+	queueDir := t.TempDir()
+	storage, err := NewStorage(queueDir)
+	if err != nil {
+		t.Fatalf("NewStorage() failed: %v", err)
+	}
+
+	task := &Task{
+		ID:        "secret",
+		Env:       map[string]string{"API_BASE_URL": "https://example.com", "API_TOKEN": "s3cret"},
+		SecretEnv: []string{"API_TOKEN"},
+	}
+	if err := storage.SaveTask(task); err != nil {
+		t.Fatalf("SaveTask() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(queueDir, "task-secret.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("task file leaks the secret value: %s", data)
+	}
+	info, err := os.Stat(storage.secretsFilename("secret"))
+	if err != nil {
+		t.Fatalf("secret values were not stored: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("secrets file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := storage.LoadTask("secret")
+	if err != nil {
+		t.Fatalf("LoadTask() failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Env, task.Env) {
+		t.Errorf("loaded env = %v, want %v", loaded.Env, task.Env)
+	}
+	listed, err := storage.ListTasks()
+	if err != nil || len(listed) != 1 || listed[0].Env["API_TOKEN"] != "s3cret" {
+		t.Errorf("ListTasks() = %v, %v, want the task with its secret value", listed, err)
+	}
+
+	if err := storage.DeleteTask("secret"); err != nil {
+		t.Fatalf("DeleteTask() failed: %v", err)
+	}
+	if _, err := os.Stat(storage.secretsFilename("secret")); !os.IsNotExist(err) {
+		t.Error("DeleteTask() should remove the stored secret values")
+	}
+}
@@ -0,0 +1,110 @@
+package claude
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// taskFileParser parses a task file of one schema version
+type taskFileParser func(data []byte) (*TaskFile, error)
+
+// taskFileParsers maps each supported task file version to its parser
+var taskFileParsers = map[string]taskFileParser{
+	"1.0": parseTaskFileV1_0,
+	"1.1": parseTaskFileV1_1,
+}
+
+// ParseTaskFile parses a YAML task file using the parser for its version
+func ParseTaskFile(data []byte) (*TaskFile, error) {
+	var header struct {
+		Version string `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	parser, ok := taskFileParsers[header.Version]
+	if !ok {
+		return nil, unsupportedTaskFileVersionError(header.Version)
+	}
+	return parser(data)
+}
+
+// parseTaskFileV1_0 parses the original task file format. Per-task tags and
+// model were read from 1.0 files before they were documented in 1.1, so they
+// stay valid here.
+func parseTaskFileV1_0(data []byte) (*TaskFile, error) {
+	return parseTaskFileV1_1(data)
+}
+
+// parseTaskFileV1_1 parses version 1.1, which adds optional per-task tags
+// and model to version 1.0
+func parseTaskFileV1_1(data []byte) (*TaskFile, error) {
+	var taskFile TaskFile
+	if err := yaml.Unmarshal(data, &taskFile); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return &taskFile, nil
+}
+
+// supportedTaskFileVersions returns the known versions, oldest first
+func supportedTaskFileVersions() []string {
+	versions := make([]string, 0, len(taskFileParsers))
+	for version := range taskFileParsers {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return compareTaskFileVersions(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
+// unsupportedTaskFileVersionError explains why a version is not accepted
+func unsupportedTaskFileVersionError(version string) error {
+	versions := supportedTaskFileVersions()
+	supported := strings.Join(versions, ", ")
+
+	if version == "" {
+		return fmt.Errorf("task file has no version (supported versions: %s)", supported)
+	}
+	if _, _, ok := parseTaskFileVersion(version); !ok {
+		return fmt.Errorf("invalid task file version %q (supported versions: %s)", version, supported)
+	}
+
+	switch latest := versions[len(versions)-1]; {
+	case compareTaskFileVersions(version, latest) > 0:
+		return fmt.Errorf("unsupported task file version %s: newer than the latest supported version %s; upgrade gwq (supported versions: %s)", version, latest, supported)
+	case compareTaskFileVersions(version, versions[0]) < 0:
+		return fmt.Errorf("unsupported task file version %s: older than the oldest supported version %s; update the file to version %s (supported versions: %s)", version, versions[0], latest, supported)
+	default:
+		return fmt.Errorf("unsupported task file version %s (supported versions: %s)", version, supported)
+	}
+}
+
+// parseTaskFileVersion splits a "major.minor" version
+func parseTaskFileVersion(version string) (major, minor int, ok bool) {
+	majorStr, minorStr, found := strings.Cut(version, ".")
+	if !found {
+		return 0, 0, false
+	}
+	major, errMajor := strconv.Atoi(majorStr)
+	minor, errMinor := strconv.Atoi(minorStr)
+	if errMajor != nil || errMinor != nil || major < 0 || minor < 0 {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// compareTaskFileVersions compares two valid "major.minor" versions
+func compareTaskFileVersions(a, b string) int {
+	aMajor, aMinor, _ := parseTaskFileVersion(a)
+	bMajor, bMinor, _ := parseTaskFileVersion(b)
+	if aMajor != bMajor {
+		return aMajor - bMajor
+	}
+	return aMinor - bMinor
+}
@@ -0,0 +1,115 @@
+package claude
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTaskFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		yaml      string
+		wantTags  []string
+		wantModel string
+		wantErr   []string // Substrings expected in the error
+	}{
+		{
+			name: "version 1.0",
+			yaml: `version: "1.0"
+tasks:
+  - id: setup
+    name: Setup
+    worktree: feature/setup
+`,
+		},
+		{
+			name: "version 1.0 keeps tags and model",
+			yaml: `version: "1.0"
+tasks:
+  - id: setup
+    name: Setup
+    worktree: feature/setup
+    tags: [backend]
+    model: opus
+`,
+			wantTags:  []string{"backend"},
+			wantModel: "opus",
+		},
+		{
+			name: "version 1.1 adds tags and model",
+			yaml: `version: "1.1"
+tasks:
+  - id: setup
+    name: Setup
+    worktree: feature/setup
+    tags: [backend, urgent]
+    model: sonnet
+`,
+			wantTags:  []string{"backend", "urgent"},
+			wantModel: "sonnet",
+		},
+		{
+			name: "version 1.1 accepts 1.0 content",
+			yaml: `version: "1.1"
+tasks:
+  - id: setup
+    name: Setup
+    worktree: feature/setup
+`,
+		},
+		{
+			name:    "newer version",
+			yaml:    "version: \"2.0\"\ntasks: []\n",
+			wantErr: []string{"unsupported task file version 2.0", "newer than the latest supported version 1.1", "supported versions: 1.0, 1.1"},
+		},
+		{
+			name:    "older version",
+			yaml:    "version: \"0.9\"\ntasks: []\n",
+			wantErr: []string{"unsupported task file version 0.9", "older than the oldest supported version 1.0", "supported versions: 1.0, 1.1"},
+		},
+		{
+			name:    "missing version",
+			yaml:    "tasks: []\n",
+			wantErr: []string{"task file has no version", "supported versions: 1.0, 1.1"},
+		},
+		{
+			name:    "invalid version",
+			yaml:    "version: latest\ntasks: []\n",
+			wantErr: []string{`invalid task file version "latest"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskFile, err := ParseTaskFile([]byte(tt.yaml))
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("ParseTaskFile() succeeded, want error")
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("ParseTaskFile() error = %q, want it to contain %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTaskFile() error = %v", err)
+			}
+
+			if len(taskFile.Tasks) != 1 {
+				t.Fatalf("ParseTaskFile() returned %d tasks, want 1", len(taskFile.Tasks))
+			}
+			entry := taskFile.Tasks[0]
+			if entry.ID != "setup" || entry.Worktree != "feature/setup" {
+				t.Errorf("entry = %+v, want id setup and worktree feature/setup", entry)
+			}
+			if strings.Join(entry.Tags, ",") != strings.Join(tt.wantTags, ",") {
+				t.Errorf("Tags = %v, want %v", entry.Tags, tt.wantTags)
+			}
+			if entry.Model != tt.wantModel {
+				t.Errorf("Model = %q, want %q", entry.Model, tt.wantModel)
+			}
+		})
+	}
+}
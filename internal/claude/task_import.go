@@ -0,0 +1,180 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/utils"
+)
+
+// ImportSkip records a task that ImportTasks did not import
+type ImportSkip struct {
+	TaskID string
+	Reason string
+}
+
+// ImportResult summarizes an ImportTasks call
+type ImportResult struct {
+	Imported []*Task
+	Skipped  []ImportSkip
+}
+
+// ImportTasks copies tasks from another queue directory into this queue.
+// Tasks accepted by filter (nil accepts all) get new IDs, are reset to
+// pending and are pointed at the current repository. Dependencies between
+// imported tasks are remapped to the new IDs; tasks that fail validation or
+// depend on a task that is not imported are skipped.
+func (tm *TaskManager) ImportTasks(sourceQueueDir string, filter func(*Task) bool) (*ImportResult, error) {
+	if sameDir(sourceQueueDir, tm.storage.queueDir) {
+		return nil, fmt.Errorf("source queue is the current queue: %s", sourceQueueDir)
+	}
+	if tm.gitClient == nil {
+		return nil, fmt.Errorf("not in a git repository")
+	}
+	repoRoot, err := tm.gitClient.GetRepositoryPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve repository: %w", err)
+	}
+
+	// NewStorage creates a missing queue directory; a mistyped source must
+	// fail instead
+	info, err := os.Stat(sourceQueueDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open source queue: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("source queue is not a directory: %s", sourceQueueDir)
+	}
+
+	source, err := NewStorage(sourceQueueDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open source queue: %w", err)
+	}
+	tasks, err := source.ListTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source tasks: %w", err)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+
+	result := &ImportResult{}
+	candidates := make(map[string]*Task)
+	for _, task := range tasks {
+		if filter != nil && !filter(task) {
+			continue
+		}
+		if err := tm.validateImportedTask(task); err != nil {
+			result.Skipped = append(result.Skipped, ImportSkip{TaskID: task.ID, Reason: err.Error()})
+			continue
+		}
+		candidates[task.ID] = task
+	}
+
+	// Drop tasks whose dependencies are not imported, repeating until no
+	// further task loses a dependency
+	for changed := true; changed; {
+		changed = false
+		for _, task := range tasks {
+			if candidates[task.ID] == nil {
+				continue
+			}
+			for _, depID := range task.DependsOn {
+				if candidates[depID] == nil {
+					result.Skipped = append(result.Skipped, ImportSkip{TaskID: task.ID, Reason: fmt.Sprintf("dependency %s is not imported", depID)})
+					delete(candidates, task.ID)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	newIDs := make(map[string]string, len(candidates))
+	used := make(map[string]bool, len(candidates))
+	for _, task := range tasks {
+		if candidates[task.ID] != nil {
+			newIDs[task.ID] = tm.newImportID(used)
+		}
+	}
+
+	now := time.Now()
+	for _, task := range tasks {
+		if candidates[task.ID] == nil {
+			continue
+		}
+
+		imported := cloneTask(task)
+		imported.ID = newIDs[task.ID]
+		imported.DependsOn = make([]string, len(task.DependsOn))
+		for i, depID := range task.DependsOn {
+			imported.DependsOn[i] = newIDs[depID]
+		}
+		imported.Blocks = nil
+		imported.RepositoryRoot = repoRoot
+		imported.WorktreePath = "" // Paths in the source repository do not apply here
+		if filepath.IsAbs(imported.Worktree) {
+			imported.Worktree = filepath.Base(imported.Worktree)
+		}
+		imported.Status = StatusPending
+		imported.CreatedAt = now
+		imported.StartedAt = nil
+		imported.CompletedAt = nil
+		imported.NextRetryAt = nil
+		imported.SessionID = ""
+		imported.Result = nil
+
+		if err := tm.storage.SaveTask(imported); err != nil {
+			return result, fmt.Errorf("failed to save task %s: %w", imported.ID, err)
+		}
+		result.Imported = append(result.Imported, imported)
+	}
+
+	return result, nil
+}
+
+// validateImportedTask applies the checks CreateTask makes to a stored task
+func (tm *TaskManager) validateImportedTask(task *Task) error {
+	if task.Name == "" {
+		return fmt.Errorf("task name is required")
+	}
+	if task.Worktree == "" {
+		return fmt.Errorf("worktree must be specified")
+	}
+	if task.Priority < 1 || task.Priority > 100 {
+		return fmt.Errorf("priority must be between 1 and 100")
+	}
+	if task.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative")
+	}
+	if task.MaxCostUSD < 0 {
+		return fmt.Errorf("max cost must not be negative")
+	}
+	return ValidateModel(task.Model, tm.config.Claude.AllowedModels)
+}
+
+// newImportID generates a task ID that is unused in this queue and not yet
+// assigned, and marks it as used
+func (tm *TaskManager) newImportID(used map[string]bool) string {
+	for {
+		id := utils.GenerateShortID()
+		if used[id] {
+			continue
+		}
+		if _, err := tm.storage.LoadTask(id); err == nil {
+			continue
+		}
+		used[id] = true
+		return id
+	}
+}
+
+// sameDir reports whether a and b refer to the same directory path
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -1,8 +1,10 @@
 package claude
 
 import (
+	"cmp"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -10,7 +12,6 @@ import (
 	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/d-kuro/gwq/pkg/utils"
-	"gopkg.in/yaml.v3"
 )
 
 // TaskManager handles task operations with simplified architecture
@@ -33,6 +34,10 @@ func NewTaskManager(storage *Storage, config *models.Config) *TaskManager {
 	}
 }
 
+// DefaultRetryBackoff is the delay before the first retry of a task that
+// doesn't set its own
+const DefaultRetryBackoff = 30 * time.Second
+
 // CreateTaskRequest represents a simplified task creation request
 type CreateTaskRequest struct {
 	Name                 string
@@ -45,6 +50,14 @@ type CreateTaskRequest struct {
 	VerificationCommands []string
 	AutoCommit           bool
 	Repository           string
+	Model                string
+	Tags                 []string
+	Env                  map[string]string
+	SecretEnv            []string
+	MaxRetries           int
+	RetryBackoff         time.Duration
+	MaxCostUSD           float64
+	AllowDuplicate       bool // Enqueue even if a similar task is queued for the worktree, only warning about it
 }
 
 // CreateTask creates a new task with simplified logic
@@ -59,6 +72,15 @@ func (tm *TaskManager) CreateTask(req *CreateTaskRequest) (*Task, error) {
 	if req.Priority < 1 || req.Priority > 100 {
 		return nil, fmt.Errorf("priority must be between 1 and 100")
 	}
+	if req.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries must not be negative")
+	}
+	if req.MaxCostUSD < 0 {
+		return nil, fmt.Errorf("max cost must not be negative")
+	}
+	if err := ValidateModel(req.Model, tm.config.Claude.AllowedModels); err != nil {
+		return nil, err
+	}
 
 	// Resolve repository using existing git package
 	repoRoot, err := tm.resolveRepository(req.Repository)
@@ -66,6 +88,10 @@ func (tm *TaskManager) CreateTask(req *CreateTaskRequest) (*Task, error) {
 		return nil, fmt.Errorf("failed to resolve repository: %w", err)
 	}
 
+	if err := tm.checkDuplicateTask(req, repoRoot); err != nil {
+		return nil, err
+	}
+
 	// Create simplified task with essential fields only
 	simplifiedTask := NewSimplifiedTask(
 		utils.GenerateShortID(),
@@ -75,9 +101,17 @@ func (tm *TaskManager) CreateTask(req *CreateTaskRequest) (*Task, error) {
 		Priority(req.Priority),
 	)
 	simplifiedTask.DependsOn = req.DependsOn
+	simplifiedTask.Tags = req.Tags
 
 	// Convert to legacy format for storage compatibility
 	task := simplifiedTask.ToLegacyTask()
+	task.Model = req.Model
+	task.Env = req.Env
+	task.SecretEnv = req.SecretEnv
+	task.MaxRetries = req.MaxRetries
+	task.RetryBackoff = req.RetryBackoff
+	task.MaxCostUSD = req.MaxCostUSD
+	task.RepositoryRoot = repoRoot
 
 	// Setup worktree information
 	if err := tm.setupWorktree(task, req, repoRoot); err != nil {
@@ -100,15 +134,10 @@ func (tm *TaskManager) CreateTasksFromFile(filePath string) ([]*Task, error) {
 		return nil, fmt.Errorf("failed to read task file: %w", err)
 	}
 
-	// Parse YAML
-	var tasksDefinition TaskFile
-	if err := yaml.Unmarshal(data, &tasksDefinition); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
-	}
-
-	// Validate version
-	if tasksDefinition.Version != "1.0" {
-		return nil, fmt.Errorf("unsupported task file version: %s (expected 1.0)", tasksDefinition.Version)
+	// Parse YAML with the parser for the file's version
+	tasksDefinition, err := ParseTaskFile(data)
+	if err != nil {
+		return nil, err
 	}
 
 	// Resolve default repository
@@ -175,6 +204,17 @@ func (tm *TaskManager) FilterTasksByStatus(tasks []*Task, status string) []*Task
 	return filtered
 }
 
+// FilterTasksByTag filters tasks carrying the given tag
+func (tm *TaskManager) FilterTasksByTag(tasks []*Task, tag string) []*Task {
+	var filtered []*Task
+	for _, task := range tasks {
+		if slices.Contains(task.Tags, tag) {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
 // FilterTasksByPriority filters tasks by minimum priority
 func (tm *TaskManager) FilterTasksByPriority(tasks []*Task, minPriority int) []*Task {
 	var filtered []*Task
@@ -186,6 +226,68 @@ func (tm *TaskManager) FilterTasksByPriority(tasks []*Task, minPriority int) []*
 	return filtered
 }
 
+// TaskSortKeys lists the keys accepted by SortTasks
+var TaskSortKeys = []string{"priority", "created", "status", "name"}
+
+// taskStatusOrder ranks statuses for sorting, active tasks first
+var taskStatusOrder = map[Status]int{
+	StatusRunning:   0,
+	StatusPending:   1,
+	StatusWaiting:   2,
+	StatusFailed:    3,
+	StatusAborted:   4,
+	StatusCancelled: 5,
+	StatusSkipped:   6,
+	StatusCompleted: 7,
+}
+
+// SortTasks sorts tasks in place by key: "priority" (highest first, then
+// oldest first, like the scheduler), "created" (oldest first), "status"
+// (running, pending, waiting, then finished ones, each by priority) or "name".
+// An empty key means "priority". reverse inverts the order; tasks with equal
+// keys keep their relative order either way.
+func (tm *TaskManager) SortTasks(tasks []*Task, key string, reverse bool) error {
+	byCreated := func(a, b *Task) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+	byPriority := func(a, b *Task) int {
+		return cmp.Or(cmp.Compare(b.Priority, a.Priority), byCreated(a, b))
+	}
+
+	var compare func(a, b *Task) int
+	switch key {
+	case "", "priority":
+		compare = byPriority
+	case "created":
+		compare = byCreated
+	case "status":
+		compare = func(a, b *Task) int {
+			return cmp.Or(cmp.Compare(taskStatusRank(a.Status), taskStatusRank(b.Status)), byPriority(a, b))
+		}
+	case "name":
+		compare = func(a, b *Task) int {
+			return cmp.Or(cmp.Compare(a.Name, b.Name), byCreated(a, b))
+		}
+	default:
+		return fmt.Errorf("invalid sort key %q (must be one of %s)", key, strings.Join(TaskSortKeys, ", "))
+	}
+
+	if reverse {
+		forward := compare
+		compare = func(a, b *Task) int { return forward(b, a) }
+	}
+	slices.SortStableFunc(tasks, compare)
+	return nil
+}
+
+// taskStatusRank returns the sort rank of status; unknown statuses sort last
+func taskStatusRank(status Status) int {
+	if rank, ok := taskStatusOrder[status]; ok {
+		return rank
+	}
+	return len(taskStatusOrder)
+}
+
 // resolveRepository resolves repository path using existing git package
 func (tm *TaskManager) resolveRepository(repo string) (string, error) {
 	if repo == "" {
@@ -245,13 +347,18 @@ func (tm *TaskManager) createTaskFromEntry(entry TaskFileEntry, defaultRepo stri
 	if entry.Worktree == "" {
 		return nil, fmt.Errorf("worktree must be specified")
 	}
+	if err := ValidateModel(entry.Model, tm.config.Claude.AllowedModels); err != nil {
+		return nil, err
+	}
 
 	// Determine repository for this task - use defaultRepo unless overridden
+	repoRoot := defaultRepo
 	if entry.Repository != "" {
-		_, err := tm.resolveRepository(entry.Repository)
+		resolved, err := tm.resolveRepository(entry.Repository)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve repository: %w", err)
 		}
+		repoRoot = resolved
 	}
 
 	// Create simplified task using the new model
@@ -269,10 +376,22 @@ func (tm *TaskManager) createTaskFromEntry(entry TaskFileEntry, defaultRepo stri
 		CreatedAt: time.Now(),
 		Prompt:    entry.Prompt,
 		DependsOn: entry.DependsOn,
+		Tags:      entry.Tags,
 	}
 
 	// Convert to legacy format for storage compatibility
 	task := simplifiedTask.ToLegacyTask()
+	task.Model = entry.Model
+	task.MaxRetries = entry.MaxRetries
+	task.RetryBackoff = entry.RetryBackoff
+	if task.RetryBackoff == 0 {
+		task.RetryBackoff = DefaultRetryBackoff
+	}
+	task.MaxCostUSD = entry.MaxCostUSD
+	task.RepositoryRoot = repoRoot
+	if entry.DependencyPolicy != "" {
+		task.DependencyPolicy = entry.DependencyPolicy
+	}
 
 	// Save task
 	if err := tm.storage.SaveTask(task); err != nil {
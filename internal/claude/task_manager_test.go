@@ -0,0 +1,424 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestFilterTasksByTag(t *testing.T) {
+	tasks := []*Task{
+		{ID: "a", Tags: []string{"backend", "urgent"}},
+		{ID: "b", Tags: []string{"frontend"}},
+		{ID: "c"},
+		{ID: "d", Tags: []string{"backend"}},
+	}
+
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{tag: "backend", want: []string{"a", "d"}},
+		{tag: "frontend", want: []string{"b"}},
+		{tag: "back", want: nil},
+		{tag: "missing", want: nil},
+	}
+
+	tm := &TaskManager{}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			var got []string
+			for _, task := range tm.FilterTasksByTag(tasks, tt.tag) {
+				got = append(got, task.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("FilterTasksByTag(%q) = %v, want %v", tt.tag, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("FilterTasksByTag(%q) = %v, want %v", tt.tag, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLegacyTaskRoundTripPreservesTags(t *testing.T) {
+	st := NewSimplifiedTask("task-1", "tagged", "feature/x", "do it", 50)
+	st.Tags = []string{"backend", "urgent"}
+
+	task := st.ToLegacyTask()
+	if len(task.Tags) != 2 || task.Tags[0] != "backend" || task.Tags[1] != "urgent" {
+		t.Fatalf("ToLegacyTask() tags = %v, want [backend urgent]", task.Tags)
+	}
+
+	back := FromLegacyTask(task)
+	if len(back.Tags) != 2 || back.Tags[0] != "backend" || back.Tags[1] != "urgent" {
+		t.Errorf("FromLegacyTask() tags = %v, want [backend urgent]", back.Tags)
+	}
+}
+
+func TestImportTasks(t *testing.T) {
+	repoDir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repoDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	repoRoot, err := git.New(repoDir).GetRepositoryPath()
+	if err != nil {
+		t.Fatalf("GetRepositoryPath() failed: %v", err)
+	}
+
+	sourceDir := t.TempDir()
+	source, err := NewStorage(sourceDir)
+	if err != nil {
+		t.Fatalf("NewStorage(source) failed: %v", err)
+	}
+	created := time.Now().Add(-time.Hour)
+	sourceTasks := []*Task{
+		{ID: "setup", Name: "Setup", Worktree: "feature/setup", Priority: 60, Status: StatusPending, CreatedAt: created, RepositoryRoot: "/other/repo", WorktreePath: "/other/worktrees/setup"},
+		{ID: "api", Name: "API", Worktree: "/other/worktrees/feature-api", Priority: 50, Status: StatusPending, CreatedAt: created.Add(time.Minute), DependsOn: []string{"setup"}, RepositoryRoot: "/other/repo"},
+		{ID: "done", Name: "Done", Worktree: "feature/done", Priority: 50, Status: StatusCompleted, CreatedAt: created.Add(2 * time.Minute)},
+		{ID: "invalid", Name: "", Worktree: "feature/invalid", Priority: 50, Status: StatusPending, CreatedAt: created.Add(3 * time.Minute)},
+		{ID: "orphan", Name: "Orphan", Worktree: "feature/orphan", Priority: 50, Status: StatusPending, CreatedAt: created.Add(4 * time.Minute), DependsOn: []string{"invalid"}},
+	}
+	for _, task := range sourceTasks {
+		if err := source.SaveTask(task); err != nil {
+			t.Fatalf("SaveTask(%s) failed: %v", task.ID, err)
+		}
+	}
+
+	target, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStorage(target) failed: %v", err)
+	}
+	tm := &TaskManager{storage: target, config: &models.Config{}, gitClient: git.New(repoDir)}
+
+	result, err := tm.ImportTasks(sourceDir, func(task *Task) bool {
+		return task.Status == StatusPending
+	})
+	if err != nil {
+		t.Fatalf("ImportTasks() error = %v", err)
+	}
+
+	if len(result.Imported) != 2 {
+		t.Fatalf("imported %d tasks, want 2", len(result.Imported))
+	}
+	var skipped []string
+	for _, skip := range result.Skipped {
+		skipped = append(skipped, skip.TaskID)
+	}
+	if strings.Join(skipped, ",") != "invalid,orphan" {
+		t.Errorf("skipped = %v, want [invalid orphan]", skipped)
+	}
+
+	setup, api := result.Imported[0], result.Imported[1]
+	if setup.Name != "Setup" || api.Name != "API" {
+		t.Fatalf("imported tasks = %s, %s, want Setup, API", setup.Name, api.Name)
+	}
+	for _, task := range result.Imported {
+		if task.ID == "setup" || task.ID == "api" {
+			t.Errorf("task %s kept its source ID", task.Name)
+		}
+		if task.RepositoryRoot != repoRoot {
+			t.Errorf("task %s RepositoryRoot = %q, want %q", task.Name, task.RepositoryRoot, repoRoot)
+		}
+		if task.WorktreePath != "" {
+			t.Errorf("task %s WorktreePath = %q, want empty", task.Name, task.WorktreePath)
+		}
+		if _, err := target.LoadTask(task.ID); err != nil {
+			t.Errorf("task %s was not saved: %v", task.Name, err)
+		}
+	}
+	if len(api.DependsOn) != 1 || api.DependsOn[0] != setup.ID {
+		t.Errorf("API DependsOn = %v, want [%s]", api.DependsOn, setup.ID)
+	}
+	if api.Worktree != "feature-api" {
+		t.Errorf("API Worktree = %q, want feature-api", api.Worktree)
+	}
+
+	// The source queue is left untouched
+	original, err := source.LoadTask("setup")
+	if err != nil || original.RepositoryRoot != "/other/repo" {
+		t.Errorf("source task was modified: %+v, %v", original, err)
+	}
+
+	if _, err := tm.ImportTasks(target.queueDir, nil); err == nil {
+		t.Error("ImportTasks() should refuse to import a queue into itself")
+	}
+
+	missing := filepath.Join(t.TempDir(), "no-such-queue")
+	if _, err := tm.ImportTasks(missing, nil); err == nil {
+		t.Error("ImportTasks() should fail when the source queue does not exist")
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("ImportTasks() created the missing source queue directory")
+	}
+}
+
+func TestRerunExecutionCreatesNewTask(t *testing.T) {
+	repoDir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repoDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	em, err := NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+	startTime := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	fixture := `{
+  "execution_id": "exec-rerun",
+  "prompt": "Fix the flaky login test\nIt fails on CI only",
+  "start_time": "2025-01-02T10:00:00Z",
+  "status": "failed",
+  "repository": "` + repoDir + `",
+  "working_directory": "` + repoDir + `",
+  "cost_usd": 1.5,
+  "duration_ms": 60000,
+  "model": "sonnet",
+  "tags": ["backend", "flaky"],
+  "priority": "75"
+}`
+	path := filepath.Join(em.GetLogDir(), "metadata", GenerateMetadataFileName(startTime, "exec-rerun"))
+	if err := os.WriteFile(path, []byte(fixture), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	metadata, err := em.LoadMetadata("exec-rerun")
+	if err != nil {
+		t.Fatalf("LoadMetadata() failed: %v", err)
+	}
+
+	storage, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStorage() failed: %v", err)
+	}
+	tm := &TaskManager{storage: storage, config: &models.Config{}}
+
+	task, err := tm.CreateTask(NewRerunTaskRequest(metadata))
+	if err != nil {
+		t.Fatalf("CreateTask() failed: %v", err)
+	}
+
+	if task.ID == "" || task.ID == metadata.ExecutionID {
+		t.Errorf("task ID = %q, want a new ID", task.ID)
+	}
+	if task.Prompt != metadata.Prompt || task.Model != "sonnet" {
+		t.Errorf("prompt = %q, model = %q, want them copied from the execution", task.Prompt, task.Model)
+	}
+	if strings.Join(task.Tags, ",") != "backend,flaky" {
+		t.Errorf("tags = %v, want [backend flaky]", task.Tags)
+	}
+	if task.Name != "Fix the flaky login test" || task.Priority != 75 || task.Worktree != repoDir {
+		t.Errorf("unexpected task: name %q, priority %d, worktree %q", task.Name, task.Priority, task.Worktree)
+	}
+	if task.Status != StatusPending || task.Result != nil {
+		t.Errorf("status = %s, result = %+v, want a fresh pending task", task.Status, task.Result)
+	}
+
+	again, err := tm.CreateTask(NewRerunTaskRequest(metadata))
+	if err != nil {
+		t.Fatalf("CreateTask() failed: %v", err)
+	}
+	if again.ID == task.ID {
+		t.Errorf("second rerun reused task ID %s", task.ID)
+	}
+}
+
+func TestCreateTaskFromEntryRetryBackoff(t *testing.T) {
+	storage, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStorage() failed: %v", err)
+	}
+	tm := &TaskManager{storage: storage, config: &models.Config{}}
+
+	for _, tt := range []struct {
+		backoff time.Duration
+		want    time.Duration
+	}{
+		{0, DefaultRetryBackoff},
+		{time.Minute, time.Minute},
+	} {
+		entry := TaskFileEntry{ID: fmt.Sprintf("task-%s", tt.backoff), Worktree: "feature/retry", MaxRetries: 2, RetryBackoff: tt.backoff}
+		task, err := tm.createTaskFromEntry(entry, t.TempDir())
+		if err != nil {
+			t.Fatalf("createTaskFromEntry() failed: %v", err)
+		}
+		if task.RetryBackoff != tt.want {
+			t.Errorf("RetryBackoff = %v for retry_backoff %v, want %v", task.RetryBackoff, tt.backoff, tt.want)
+		}
+	}
+}
+
+func TestCreateTaskDuplicate(t *testing.T) {
+	repoDir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repoDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	storage, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStorage() failed: %v", err)
+	}
+	cfg := &models.Config{}
+	cfg.Claude.Queue.DuplicateSimilarity = 0.8
+	tm := &TaskManager{storage: storage, config: cfg}
+
+	newRequest := func(worktree, prompt string) *CreateTaskRequest {
+		return &CreateTaskRequest{
+			Name:       "Fix login",
+			Worktree:   worktree,
+			Priority:   50,
+			Prompt:     prompt,
+			Repository: repoDir,
+		}
+	}
+
+	first, err := tm.CreateTask(newRequest("feature/login", "Fix the flaky login test and add a regression test"))
+	if err != nil {
+		t.Fatalf("CreateTask() failed: %v", err)
+	}
+
+	t.Run("IdenticalRefused", func(t *testing.T) {
+		_, err := tm.CreateTask(newRequest("feature/login", "Fix the flaky login test and add a regression test"))
+		if !errors.Is(err, ErrDuplicateTask) {
+			t.Fatalf("CreateTask() error = %v, want ErrDuplicateTask", err)
+		}
+		if !strings.Contains(err.Error(), first.ID) {
+			t.Errorf("error %q does not name the existing task %s", err, first.ID)
+		}
+	})
+
+	t.Run("SimilarRefused", func(t *testing.T) {
+		_, err := tm.CreateTask(newRequest("feature/login", "fix the flaky login test and add a  regression test please"))
+		if !errors.Is(err, ErrDuplicateTask) {
+			t.Fatalf("CreateTask() error = %v, want ErrDuplicateTask", err)
+		}
+	})
+
+	t.Run("DistinctPromptAllowed", func(t *testing.T) {
+		if _, err := tm.CreateTask(newRequest("feature/login", "Document the login flow in the README")); err != nil {
+			t.Errorf("CreateTask() failed: %v", err)
+		}
+	})
+
+	t.Run("OtherWorktreeAllowed", func(t *testing.T) {
+		if _, err := tm.CreateTask(newRequest("feature/signup", "Fix the flaky login test and add a regression test")); err != nil {
+			t.Errorf("CreateTask() failed: %v", err)
+		}
+	})
+
+	t.Run("NameOnlyTasksAllowed", func(t *testing.T) {
+		for range 2 {
+			if _, err := tm.CreateTask(newRequest("feature/login", "")); err != nil {
+				t.Errorf("CreateTask() without a prompt failed: %v", err)
+			}
+		}
+	})
+
+	t.Run("AllowDuplicate", func(t *testing.T) {
+		req := newRequest("feature/login", "Fix the flaky login test and add a regression test")
+		req.AllowDuplicate = true
+		if _, err := tm.CreateTask(req); err != nil {
+			t.Errorf("CreateTask() with AllowDuplicate failed: %v", err)
+		}
+	})
+
+	t.Run("FinishedTaskIgnored", func(t *testing.T) {
+		tasks, err := storage.ListTasks()
+		if err != nil {
+			t.Fatalf("ListTasks() failed: %v", err)
+		}
+		for _, task := range tasks {
+			if task.Worktree == "feature/login" {
+				if err := storage.UpdateTaskStatus(task.ID, StatusCompleted); err != nil {
+					t.Fatalf("UpdateTaskStatus() failed: %v", err)
+				}
+			}
+		}
+		if _, err := tm.CreateTask(newRequest("feature/login", "Fix the flaky login test and add a regression test")); err != nil {
+			t.Errorf("CreateTask() after the duplicate completed failed: %v", err)
+		}
+	})
+}
+
+func TestPromptSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "Fix the bug", b: "fix  the\nbug", want: 1},
+		{a: "", b: "", want: 1},
+		{a: "Fix the bug", b: "", want: 0},
+		{a: "Fix the login bug", b: "Fix the signup bug", want: 0.75},
+		{a: "Add tests", b: "Update docs", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := PromptSimilarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("PromptSimilarity(%q, %q) = %g, want %g", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	base := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	newTasks := func() []*Task {
+		return []*Task{
+			{ID: "a", Name: "lint", Priority: 50, Status: StatusCompleted, CreatedAt: base.Add(3 * time.Hour)},
+			{ID: "b", Name: "build", Priority: 75, Status: StatusPending, CreatedAt: base.Add(2 * time.Hour)},
+			{ID: "c", Name: "deploy", Priority: 50, Status: StatusRunning, CreatedAt: base.Add(time.Hour)},
+			{ID: "d", Name: "build", Priority: 75, Status: StatusPending, CreatedAt: base.Add(2 * time.Hour)},
+			{ID: "e", Name: "audit", Priority: 25, Status: StatusFailed, CreatedAt: base},
+		}
+	}
+
+	tests := []struct {
+		key     string
+		reverse bool
+		want    string
+	}{
+		{key: "", want: "b,d,c,a,e"},
+		{key: "priority", want: "b,d,c,a,e"},
+		{key: "priority", reverse: true, want: "e,a,c,b,d"},
+		{key: "created", want: "e,c,b,d,a"},
+		{key: "created", reverse: true, want: "a,b,d,c,e"},
+		{key: "status", want: "c,b,d,e,a"},
+		{key: "status", reverse: true, want: "a,e,b,d,c"},
+		{key: "name", want: "e,b,d,c,a"},
+		{key: "name", reverse: true, want: "a,c,b,d,e"},
+	}
+
+	tm := &TaskManager{}
+	for _, tt := range tests {
+		name := tt.key
+		if tt.reverse {
+			name += "/reverse"
+		}
+		t.Run(name, func(t *testing.T) {
+			tasks := newTasks()
+			if err := tm.SortTasks(tasks, tt.key, tt.reverse); err != nil {
+				t.Fatalf("SortTasks() error = %v", err)
+			}
+			var got []string
+			for _, task := range tasks {
+				got = append(got, task.ID)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("SortTasks(%q, %v) = %v, want %s", tt.key, tt.reverse, got, tt.want)
+			}
+		})
+	}
+
+	if err := tm.SortTasks(newTasks(), "size", false); err == nil {
+		t.Error("SortTasks() with an unknown key should fail")
+	}
+}
@@ -0,0 +1,46 @@
+package claude
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rerunTaskNameLength is the maximum length of a rerun task's name
+const rerunTaskNameLength = 60
+
+// NewRerunTaskRequest builds a request for a new task that repeats a past
+// execution. The prompt, repository, model and tags are copied, and the
+// execution's working directory becomes the task's worktree. Results such
+// as cost and duration are not carried over.
+func NewRerunTaskRequest(metadata *ExecutionMetadata) *CreateTaskRequest {
+	priority := int(PriorityNormal)
+	if p, err := strconv.Atoi(metadata.Priority); err == nil && p >= 1 && p <= 100 {
+		priority = p
+	}
+
+	var tags []string
+	if len(metadata.Tags) > 0 {
+		tags = append(tags, metadata.Tags...)
+	}
+
+	return &CreateTaskRequest{
+		Name:       rerunTaskName(metadata),
+		Worktree:   metadata.WorkingDirectory,
+		Priority:   priority,
+		Prompt:     metadata.Prompt,
+		Repository: metadata.Repository,
+		Model:      metadata.Model,
+		Tags:       tags,
+	}
+}
+
+// rerunTaskName names a rerun task after the first line of its prompt
+func rerunTaskName(metadata *ExecutionMetadata) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(metadata.Prompt), "\n")
+	firstLine = strings.TrimSpace(firstLine)
+	if firstLine == "" {
+		return fmt.Sprintf("Rerun of %s", metadata.ExecutionID)
+	}
+	return truncateString(firstLine, rerunTaskNameLength)
+}
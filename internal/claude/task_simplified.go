@@ -15,6 +15,7 @@ type SimplifiedTask struct {
 	CreatedAt time.Time   `json:"created_at"`
 	Prompt    string      `json:"prompt"`
 	DependsOn []string    `json:"depends_on"`
+	Tags      []string    `json:"tags,omitempty"`
 	Result    *TaskResult `json:"result,omitempty"`
 }
 
@@ -94,6 +95,7 @@ func (st *SimplifiedTask) ToLegacyTask() *Task {
 		CreatedAt: st.CreatedAt,
 		Prompt:    st.Prompt,
 		DependsOn: st.DependsOn,
+		Tags:      st.Tags,
 		Result:    st.Result,
 
 		// Set reasonable defaults for legacy fields
@@ -127,6 +129,7 @@ func FromLegacyTask(task *Task) *SimplifiedTask {
 		CreatedAt: task.CreatedAt,
 		Prompt:    task.Prompt,
 		DependsOn: task.DependsOn,
+		Tags:      task.Tags,
 		Result:    task.Result,
 	}
 
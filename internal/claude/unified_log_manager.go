@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/d-kuro/gwq/internal/logging"
+	"github.com/d-kuro/gwq/pkg/filesystem"
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
@@ -16,11 +18,17 @@ import (
 type UnifiedLogManager struct {
 	config *models.ClaudeConfig
 	logDir string
+	fs     filesystem.FileSystemInterface
 }
 
 // NewUnifiedLogManager creates a new unified log manager
 func NewUnifiedLogManager(config *models.ClaudeConfig) (*UnifiedLogManager, error) {
-	logDir := filepath.Join(config.ConfigDir, "logs")
+	return NewUnifiedLogManagerWithFS(config, filesystem.NewStandardFileSystem())
+}
+
+// NewUnifiedLogManagerWithFS creates a new unified log manager with custom filesystem
+func NewUnifiedLogManagerWithFS(config *models.ClaudeConfig, fs filesystem.FileSystemInterface) (*UnifiedLogManager, error) {
+	logDir := LogDir(config)
 
 	// Create unified log directory structure
 	dirs := []string{
@@ -29,7 +37,7 @@ func NewUnifiedLogManager(config *models.ClaudeConfig) (*UnifiedLogManager, erro
 	}
 
 	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
 			return nil, fmt.Errorf("failed to create log directory %s: %w", dir, err)
 		}
 	}
@@ -37,6 +45,7 @@ func NewUnifiedLogManager(config *models.ClaudeConfig) (*UnifiedLogManager, erro
 	return &UnifiedLogManager{
 		config: config,
 		logDir: logDir,
+		fs:     fs,
 	}, nil
 }
 
@@ -44,7 +53,7 @@ func NewUnifiedLogManager(config *models.ClaudeConfig) (*UnifiedLogManager, erro
 func (ulm *UnifiedLogManager) StartLogging(execution *UnifiedExecution) (string, error) {
 	// Create log file paths in flat structure (design-compliant)
 	execLogDir := filepath.Join(ulm.logDir, "executions")
-	if err := os.MkdirAll(execLogDir, 0755); err != nil {
+	if err := ulm.fs.MkdirAll(execLogDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create execution log directory: %w", err)
 	}
 
@@ -81,7 +90,7 @@ func (ulm *UnifiedLogManager) LoadExecution(executionID string) (*UnifiedExecuti
 	metadataDir := filepath.Join(ulm.logDir, "metadata")
 
 	// Find the metadata file with timestamp prefix
-	files, err := os.ReadDir(metadataDir)
+	files, err := ulm.fs.ReadDir(metadataDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read metadata directory: %w", err)
 	}
@@ -99,7 +108,7 @@ func (ulm *UnifiedLogManager) LoadExecution(executionID string) (*UnifiedExecuti
 		return nil, fmt.Errorf("metadata file not found for execution ID: %s", executionID)
 	}
 
-	data, err := os.ReadFile(metadataFile)
+	data, err := ulm.fs.ReadFile(metadataFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read metadata file: %w", err)
 	}
@@ -117,7 +126,7 @@ func (ulm *UnifiedLogManager) ListExecutions(filters ...ExecutionFilter) ([]*Uni
 	// Load executions directly from metadata directory
 	metadataDir := filepath.Join(ulm.logDir, "metadata")
 
-	files, err := os.ReadDir(metadataDir)
+	files, err := ulm.fs.ReadDir(metadataDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []*UnifiedExecution{}, nil
@@ -132,15 +141,15 @@ func (ulm *UnifiedLogManager) ListExecutions(filters ...ExecutionFilter) ([]*Uni
 		}
 
 		metadataFile := filepath.Join(metadataDir, file.Name())
-		data, err := os.ReadFile(metadataFile)
+		data, err := ulm.fs.ReadFile(metadataFile)
 		if err != nil {
-			fmt.Printf("Warning: failed to read metadata file %s: %v\n", metadataFile, err)
+			logging.Warnf("failed to read metadata file %s: %v", metadataFile, err)
 			continue
 		}
 
 		var execution UnifiedExecution
 		if err := json.Unmarshal(data, &execution); err != nil {
-			fmt.Printf("Warning: failed to unmarshal metadata file %s: %v\n", metadataFile, err)
+			logging.Warnf("failed to unmarshal metadata file %s: %v", metadataFile, err)
 			continue
 		}
 
@@ -164,7 +173,7 @@ func (ulm *UnifiedLogManager) ListExecutions(filters ...ExecutionFilter) ([]*Uni
 
 	// Sort by start time (newest first)
 	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].StartTime.After(filtered[j].StartTime)
+		return startedAfter(filtered[i].StartTime, filtered[i].ExecutionID, filtered[j].StartTime, filtered[j].ExecutionID)
 	})
 
 	return filtered, nil
@@ -188,7 +197,7 @@ func (ulm *UnifiedLogManager) saveExecutionMetadata(execution *UnifiedExecution)
 		return fmt.Errorf("failed to marshal execution metadata: %w", err)
 	}
 
-	if err := os.WriteFile(metadataFile, data, 0644); err != nil {
+	if err := writeFileAtomic(ulm.fs, metadataFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write metadata file: %w", err)
 	}
 
@@ -214,21 +223,21 @@ func (ulm *UnifiedLogManager) CleanupOldLogs(olderThan time.Duration) error {
 
 	deletedCount := 0
 	for _, exec := range toDelete {
-		// Delete log file
-		logFile := ulm.GetLogFile(exec)
-		if err := os.Remove(logFile); err == nil {
+		// Delete log file, with any rotated parts
+		logFile := FindLogFileByExecutionID(ulm.logDir, exec.StartTime, exec.ExecutionID)
+		if err := RemoveLogFile(logFile); err == nil {
 			deletedCount++
 		}
 
 		// Delete metadata file with timestamp prefix
 		metadataDir := filepath.Join(ulm.logDir, "metadata")
-		files, _ := os.ReadDir(metadataDir)
+		files, _ := ulm.fs.ReadDir(metadataDir)
 		suffix := fmt.Sprintf("-%s.json", exec.ExecutionID)
 		for _, file := range files {
 			if strings.HasSuffix(file.Name(), suffix) {
 				metadataFile := filepath.Join(metadataDir, file.Name())
-				if err := os.Remove(metadataFile); err != nil {
-					fmt.Printf("Warning: failed to delete metadata file %s: %v\n", metadataFile, err)
+				if err := ulm.fs.Remove(metadataFile); err != nil {
+					logging.Warnf("failed to delete metadata file %s: %v", metadataFile, err)
 				}
 				break
 			}
@@ -2,13 +2,16 @@ package claude
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/d-kuro/gwq/pkg/filesystem"
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
@@ -40,15 +43,244 @@ func TestNewUnifiedLogManager(t *testing.T) {
 	}
 }
 
+// faultyFileSystem is the standard filesystem with injected failures. An
+// operation fails with fail[op] when the path contains failPath (any path if
+// failPath is empty).
+type faultyFileSystem struct {
+	*filesystem.StandardFileSystem
+	fail     map[string]error
+	failPath string
+}
+
+func newFaultyFileSystem(op string, err error) *faultyFileSystem {
+	return &faultyFileSystem{
+		StandardFileSystem: filesystem.NewStandardFileSystem(),
+		fail:               map[string]error{op: err},
+	}
+}
+
+func (f *faultyFileSystem) injected(op, path string) error {
+	if err, ok := f.fail[op]; ok && strings.Contains(path, f.failPath) {
+		return err
+	}
+	return nil
+}
+
+func (f *faultyFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	if err := f.injected("MkdirAll", path); err != nil {
+		return err
+	}
+	return f.StandardFileSystem.MkdirAll(path, perm)
+}
+
+func (f *faultyFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if err := f.injected("WriteFile", name); err != nil {
+		return err
+	}
+	return f.StandardFileSystem.WriteFile(name, data, perm)
+}
+
+func (f *faultyFileSystem) Rename(oldpath, newpath string) error {
+	if err := f.injected("Rename", newpath); err != nil {
+		return err
+	}
+	return f.StandardFileSystem.Rename(oldpath, newpath)
+}
+
+func (f *faultyFileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	if err := f.injected("ReadDir", name); err != nil {
+		return nil, err
+	}
+	return f.StandardFileSystem.ReadDir(name)
+}
+
+func (f *faultyFileSystem) ReadFile(name string) ([]byte, error) {
+	if err := f.injected("ReadFile", name); err != nil {
+		return nil, err
+	}
+	return f.StandardFileSystem.ReadFile(name)
+}
+
 func TestNewUnifiedLogManagerError(t *testing.T) {
-	// Use an invalid path to trigger error
 	config := &models.ClaudeConfig{
-		ConfigDir: "/root/invalid/path/that/should/fail",
+		ConfigDir: t.TempDir(),
 	}
 
-	_, err := NewUnifiedLogManager(config)
+	_, err := NewUnifiedLogManagerWithFS(config, newFaultyFileSystem("MkdirAll", os.ErrPermission))
 	if err == nil {
-		t.Error("Expected error for invalid config directory")
+		t.Fatal("Expected error when the log directory can't be created")
+	}
+	if !errors.Is(err, os.ErrPermission) {
+		t.Errorf("error = %v, want it to wrap %v", err, os.ErrPermission)
+	}
+}
+
+func TestSaveExecutionFilesystemErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		op   string
+	}{
+		{name: "write fails", op: "WriteFile"},
+		{name: "rename fails", op: "Rename"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			fs := newFaultyFileSystem(tt.op, os.ErrPermission)
+			fs.failPath = "metadata"
+			ulm, err := NewUnifiedLogManagerWithFS(&models.ClaudeConfig{ConfigDir: tempDir}, fs)
+			if err != nil {
+				t.Fatalf("NewUnifiedLogManagerWithFS() failed: %v", err)
+			}
+
+			err = ulm.SaveExecution(&UnifiedExecution{
+				ExecutionID:   "task-fail",
+				ExecutionType: ExecutionTypeTask,
+				StartTime:     time.Now(),
+				Status:        ExecutionStatusRunning,
+			})
+			if err == nil {
+				t.Fatal("SaveExecution() should fail")
+			}
+			if !errors.Is(err, os.ErrPermission) || !strings.Contains(err.Error(), "failed to save execution metadata") {
+				t.Errorf("SaveExecution() error = %v", err)
+			}
+
+			// Neither the metadata nor a temporary file is left behind
+			entries, err := os.ReadDir(filepath.Join(tempDir, "logs", "metadata"))
+			if err != nil {
+				t.Fatalf("failed to read metadata dir: %v", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("metadata dir has %d entries after a failed save, want 0", len(entries))
+			}
+		})
+	}
+}
+
+func TestListExecutionsFilesystemErrors(t *testing.T) {
+	save := func(t *testing.T, ulm *UnifiedLogManager, ids ...string) {
+		t.Helper()
+		for i, id := range ids {
+			err := ulm.SaveExecution(&UnifiedExecution{
+				ExecutionID:   id,
+				ExecutionType: ExecutionTypeTask,
+				StartTime:     time.Now().Add(time.Duration(i) * time.Second),
+				Status:        ExecutionStatusCompleted,
+			})
+			if err != nil {
+				t.Fatalf("SaveExecution(%s) failed: %v", id, err)
+			}
+		}
+	}
+
+	t.Run("unreadable metadata directory", func(t *testing.T) {
+		fs := newFaultyFileSystem("ReadDir", os.ErrPermission)
+		ulm, err := NewUnifiedLogManagerWithFS(&models.ClaudeConfig{ConfigDir: t.TempDir()}, fs)
+		if err != nil {
+			t.Fatalf("NewUnifiedLogManagerWithFS() failed: %v", err)
+		}
+
+		_, err = ulm.ListExecutions()
+		if err == nil || !errors.Is(err, os.ErrPermission) {
+			t.Errorf("ListExecutions() error = %v, want a wrapped %v", err, os.ErrPermission)
+		}
+	})
+
+	t.Run("missing metadata directory", func(t *testing.T) {
+		fs := newFaultyFileSystem("ReadDir", os.ErrNotExist)
+		ulm, err := NewUnifiedLogManagerWithFS(&models.ClaudeConfig{ConfigDir: t.TempDir()}, fs)
+		if err != nil {
+			t.Fatalf("NewUnifiedLogManagerWithFS() failed: %v", err)
+		}
+
+		executions, err := ulm.ListExecutions()
+		if err != nil {
+			t.Fatalf("ListExecutions() error = %v, want nil", err)
+		}
+		if len(executions) != 0 {
+			t.Errorf("ListExecutions() returned %d executions, want 0", len(executions))
+		}
+	})
+
+	t.Run("unreadable metadata file is skipped", func(t *testing.T) {
+		fs := newFaultyFileSystem("ReadFile", os.ErrPermission)
+		fs.failPath = "task-broken"
+		ulm, err := NewUnifiedLogManagerWithFS(&models.ClaudeConfig{ConfigDir: t.TempDir()}, fs)
+		if err != nil {
+			t.Fatalf("NewUnifiedLogManagerWithFS() failed: %v", err)
+		}
+		save(t, ulm, "task-ok", "task-broken")
+
+		executions, err := ulm.ListExecutions()
+		if err != nil {
+			t.Fatalf("ListExecutions() error = %v", err)
+		}
+		if len(executions) != 1 || executions[0].ExecutionID != "task-ok" {
+			t.Errorf("ListExecutions() = %d executions, want only task-ok", len(executions))
+		}
+	})
+}
+
+func TestCustomLogDir(t *testing.T) {
+	configDir := t.TempDir()
+	logDir := filepath.Join(t.TempDir(), "shared", "logs")
+	config := &models.ClaudeConfig{
+		ConfigDir: configDir,
+		LogDir:    logDir,
+	}
+
+	ulm, err := NewUnifiedLogManager(config)
+	if err != nil {
+		t.Fatalf("NewUnifiedLogManager() failed: %v", err)
+	}
+	em, err := NewExecutionManager(config)
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+	if got := em.GetLogDir(); got != logDir {
+		t.Errorf("GetLogDir() = %s, want %s", got, logDir)
+	}
+
+	execution := &UnifiedExecution{
+		ExecutionID:   "task-789",
+		ExecutionType: ExecutionTypeTask,
+		StartTime:     time.Now(),
+		Status:        ExecutionStatusRunning,
+	}
+	logFile, err := ulm.StartLogging(execution)
+	if err != nil {
+		t.Fatalf("StartLogging() failed: %v", err)
+	}
+	if !strings.HasPrefix(logFile, filepath.Join(logDir, "executions")) {
+		t.Errorf("log file %s is outside %s", logFile, logDir)
+	}
+
+	if _, err := em.LoadMetadata("task-789"); err != nil {
+		t.Errorf("LoadMetadata() from the custom log dir failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "logs")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing under %s/logs, stat err = %v", configDir, err)
+	}
+}
+
+func TestCustomLogDirNotWritable(t *testing.T) {
+	// A regular file in the path fails even for root
+	parent := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(parent, nil, 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	config := &models.ClaudeConfig{
+		ConfigDir: t.TempDir(),
+		LogDir:    filepath.Join(parent, "logs"),
+	}
+
+	if _, err := NewUnifiedLogManager(config); err == nil {
+		t.Error("NewUnifiedLogManager() should fail for an unusable log directory")
+	}
+	if _, err := NewExecutionManager(config); err == nil {
+		t.Error("NewExecutionManager() should fail for an unusable log directory")
 	}
 }
 
@@ -269,6 +501,36 @@ func TestListExecutions(t *testing.T) {
 	}
 }
 
+func TestListExecutionsSameStartTime(t *testing.T) {
+	ulm, err := NewUnifiedLogManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewUnifiedLogManager() failed: %v", err)
+	}
+
+	// Tasks enqueued in one batch share a start time
+	batch := time.Now().Truncate(time.Second)
+	for _, id := range []string{"task-c", "task-a", "task-b"} {
+		exec := &UnifiedExecution{ExecutionID: id, ExecutionType: ExecutionTypeTask, StartTime: batch, Status: ExecutionStatusRunning}
+		if err := ulm.SaveExecution(exec); err != nil {
+			t.Fatalf("SaveExecution() failed: %v", err)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		listed, err := ulm.ListExecutions()
+		if err != nil {
+			t.Fatalf("ListExecutions() failed: %v", err)
+		}
+		var ids []string
+		for _, exec := range listed {
+			ids = append(ids, exec.ExecutionID)
+		}
+		if want := []string{"task-a", "task-b", "task-c"}; !reflect.DeepEqual(ids, want) {
+			t.Fatalf("ListExecutions() order = %v, want %v", ids, want)
+		}
+	}
+}
+
 func TestListExecutionsWithFilters(t *testing.T) {
 	tempDir := t.TempDir()
 	config := &models.ClaudeConfig{
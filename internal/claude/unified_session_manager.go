@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/internal/tmux"
 	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/d-kuro/gwq/pkg/utils"
 )
 
 // UnifiedSessionManager manages tmux sessions for all execution types
@@ -25,6 +26,11 @@ type SessionConfig struct {
 	TmuxCommand  string
 	HistoryLimit int
 	ConfigDir    string
+	LogDir       string
+	Executable   string
+	OutputFormat string
+
+	SessionNameTemplate string // See RenderSessionName
 }
 
 // NewUnifiedSessionManager creates a new unified session manager
@@ -34,6 +40,11 @@ func NewUnifiedSessionManager(config *models.ClaudeConfig) (*UnifiedSessionManag
 		TmuxCommand:  "tmux",
 		HistoryLimit: 50000,
 		ConfigDir:    config.ConfigDir,
+		LogDir:       LogDir(config),
+		Executable:   config.Executable,
+		OutputFormat: outputFormat(config),
+
+		SessionNameTemplate: config.SessionNameTemplate,
 	}
 
 	tmuxConfig := &tmux.SessionConfig{
@@ -50,21 +61,43 @@ func NewUnifiedSessionManager(config *models.ClaudeConfig) (*UnifiedSessionManag
 	}, nil
 }
 
+// sessionNameVars collects the session name template values of an execution
+func sessionNameVars(execution *UnifiedExecution) SessionNameVars {
+	vars := SessionNameVars{
+		Repo: sessionRepoName(execution.Repository),
+		ID:   execution.ExecutionID,
+	}
+	if execution.TaskInfo != nil {
+		vars.Branch = execution.TaskInfo.Worktree
+		vars.TaskName = execution.TaskInfo.TaskName
+	}
+	return vars
+}
+
 // CreateSession creates a tmux session for unified execution
 func (usm *UnifiedSessionManager) CreateSession(ctx context.Context, execution *UnifiedExecution) (*tmux.Session, error) {
-	// Create metadata file for the execution
-	if err := usm.createMetadataFile(execution); err != nil {
-		// Log error but don't fail the execution
-		fmt.Printf("Warning: Failed to create metadata file: %v\n", err)
+	// The environment and prompt are passed in files the session reads,
+	// keeping them off the command line
+	tmpDir := filepath.Join(usm.config.ConfigDir, "tmp")
+	envFile, err := writeEnvFile(tmpDir, execution.Env)
+	if err != nil {
+		return nil, err
+	}
+	promptFile, err := writePromptFile(tmpDir, execution.Prompt)
+	if err != nil {
+		if envFile != "" {
+			_ = os.Remove(envFile)
+		}
+		return nil, err
 	}
 
 	// Build Claude command based on execution type
-	command := usm.buildClaudeCommand(execution)
+	command := usm.buildClaudeCommand(execution, envFile, promptFile)
 
 	// Create session with unified metadata
 	sessionOpts := tmux.SessionOptions{
 		Context:    fmt.Sprintf("claude-%s", execution.ExecutionType),
-		Identifier: execution.ExecutionID,
+		Identifier: RenderSessionName(usm.config.SessionNameTemplate, sessionNameVars(execution)),
 		WorkingDir: execution.WorkingDir,
 		Command:    command,
 		Metadata: map[string]string{
@@ -84,47 +117,60 @@ func (usm *UnifiedSessionManager) CreateSession(ctx context.Context, execution *
 		sessionOpts.Metadata["worktree_path"] = execution.TaskInfo.WorktreePath
 	}
 
-	return usm.tmuxManager.CreateSession(ctx, sessionOpts)
+	session, err := usm.tmuxManager.CreateSession(ctx, sessionOpts)
+	if err != nil {
+		_ = os.Remove(promptFile)
+		if envFile != "" {
+			_ = os.Remove(envFile)
+		}
+		return nil, err
+	}
+
+	// Written once the session exists, so the metadata names the session
+	// tmux actually created and reconciliation can find it
+	if err := usm.createMetadataFile(execution, session.SessionName); err != nil {
+		// Log error but don't fail the execution
+		logging.Warnf("Failed to create metadata file: %v", err)
+	}
+	return session, nil
 }
 
 // buildClaudeCommand builds the appropriate Claude command for task execution
-func (usm *UnifiedSessionManager) buildClaudeCommand(execution *UnifiedExecution) string {
-	return usm.buildTaskCommand(execution)
+func (usm *UnifiedSessionManager) buildClaudeCommand(execution *UnifiedExecution, envFile, promptFile string) string {
+	return usm.buildTaskCommand(execution, envFile, promptFile)
 }
 
-// buildTaskCommand builds Claude command for task execution
-func (usm *UnifiedSessionManager) buildTaskCommand(execution *UnifiedExecution) string {
-	// Escape the prompt for shell
-	escapedPrompt := escapeForShell(execution.Prompt)
-
-	// Generate log file path based on execution ID and timestamp
-	// Note: ExecutionID already includes type prefix (e.g., "task-{id}"), so use it directly
-	logDir := filepath.Join(usm.config.ConfigDir, "logs", "executions")
-	timestamp := time.Now().Format("20060102-150405")
-	logFile := filepath.Join(logDir, fmt.Sprintf("%s-%s.jsonl", timestamp, execution.ExecutionID))
-
-	// Ensure log directory exists
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		// If we can't create the log directory, proceed without logging to file
-		return fmt.Sprintf(`claude --verbose --dangerously-skip-permissions --output-format stream-json -p "%s"`, escapedPrompt)
+// buildTaskCommand builds Claude command for task execution, loading the
+// environment from envFile when set. Claude reads the prompt from promptFile
+// on stdin, avoiding argument length limits; both files are removed once
+// opened.
+func (usm *UnifiedSessionManager) buildTaskCommand(execution *UnifiedExecution, envFile, promptFile string) string {
+	modelFlag := ""
+	if execution.Model != "" {
+		modelFlag = " --model " + utils.ShellQuote(execution.Model)
 	}
 
-	// Build command with task-specific flags and log capture
-	return fmt.Sprintf(`claude --verbose --dangerously-skip-permissions --output-format stream-json -p "%s" | tee "%s"`, escapedPrompt, logFile)
+	envPrefix := sourceEnvFileCommand(envFile)
+	quotedPromptFile := utils.ShellQuote(promptFile)
+
+	// The output is only shown in the session; ClaudeCodeExecutor records the
+	// execution log through the redacting capture
+	return fmt.Sprintf(`{ rm -f %s; %s%s --verbose --dangerously-skip-permissions --output-format %s%s -p; } < %s`,
+		quotedPromptFile, envPrefix, usm.config.Executable, usm.config.OutputFormat, modelFlag, quotedPromptFile)
 }
 
-// createMetadataFile creates a metadata file for the execution
-func (usm *UnifiedSessionManager) createMetadataFile(execution *UnifiedExecution) error {
+// createMetadataFile creates a metadata file for the execution running in
+// the tmux session sessionName
+func (usm *UnifiedSessionManager) createMetadataFile(execution *UnifiedExecution, sessionName string) error {
 	// Create metadata directory
-	metadataDir := filepath.Join(usm.config.ConfigDir, "logs", "metadata")
+	metadataDir := filepath.Join(usm.config.LogDir, "metadata")
 	if err := os.MkdirAll(metadataDir, 0755); err != nil {
 		return fmt.Errorf("failed to create metadata directory: %w", err)
 	}
 
-	// Generate metadata file path
+	// Generate metadata file path, the same file the log manager updates
 	// Note: ExecutionID already includes type prefix (e.g., "task-{id}"), so use it directly
-	timestamp := time.Now().Format("20060102-150405")
-	filename := fmt.Sprintf("%s-%s.json", timestamp, execution.ExecutionID)
+	filename := GenerateMetadataFileName(execution.StartTime, execution.ExecutionID)
 
 	metadataFile := filepath.Join(metadataDir, filename)
 
@@ -137,11 +183,14 @@ func (usm *UnifiedSessionManager) createMetadataFile(execution *UnifiedExecution
 		"status":            "running",
 		"repository":        execution.Repository,
 		"working_directory": execution.WorkingDir,
-		"tmux_session":      fmt.Sprintf("gwq-claude-%s-%s", execution.ExecutionID, timestamp),
+		"tmux_session":      sessionName,
 		"prompt":            execution.Prompt,
 		"tags":              execution.Tags,
 		"priority":          execution.Priority,
 	}
+	if len(execution.Env) > 0 {
+		metadata["env"] = RedactEnv(execution.Env, execution.SecretEnv)
+	}
 
 	// Add task-specific information if present
 	if execution.TaskInfo != nil {
@@ -184,17 +233,12 @@ func (usm *UnifiedSessionManager) KillSession(sessionName string) error {
 	return usm.tmuxManager.KillSession(sessionName)
 }
 
+// KillSessionDirect terminates session if it is still running
+func (usm *UnifiedSessionManager) KillSessionDirect(session *tmux.Session) error {
+	return usm.tmuxManager.KillSessionDirect(session)
+}
+
 // ListSessions lists all sessions
 func (usm *UnifiedSessionManager) ListSessions() ([]*tmux.Session, error) {
 	return usm.tmuxManager.ListSessions()
 }
-
-// escapeForShell escapes a string for safe shell usage
-func escapeForShell(s string) string {
-	// Replace problematic characters
-	s = strings.ReplaceAll(s, `"`, `\"`)
-	s = strings.ReplaceAll(s, `$`, `\$`)
-	s = strings.ReplaceAll(s, "`", "\\`")
-	s = strings.ReplaceAll(s, `\`, `\\`)
-	return s
-}
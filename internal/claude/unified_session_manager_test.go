@@ -0,0 +1,159 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestUnifiedSessionManagerUsesConfiguredLogDir(t *testing.T) {
+	logDir := filepath.Join(t.TempDir(), "custom-logs")
+	usm, err := NewUnifiedSessionManager(&models.ClaudeConfig{ConfigDir: t.TempDir(), LogDir: logDir})
+	if err != nil {
+		t.Fatalf("NewUnifiedSessionManager() failed: %v", err)
+	}
+
+	execution := &UnifiedExecution{ExecutionID: "task-logdir", ExecutionType: ExecutionTypeTask, StartTime: time.Now()}
+	if err := usm.createMetadataFile(execution, "gwq-claude-task-logdir"); err != nil {
+		t.Fatalf("createMetadataFile() failed: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(logDir, "metadata", "*-task-logdir.json"))
+	if err != nil || len(files) != 1 {
+		t.Errorf("metadata files under the configured log dir = %v, %v, want one", files, err)
+	}
+	if _, err := os.Stat(filepath.Join(usm.config.ConfigDir, "logs")); !os.IsNotExist(err) {
+		t.Error("nothing should be written under ConfigDir/logs when LogDir is configured")
+	}
+}
+
+func TestReconcileKeepsRunningWorkerSession(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	// A private tmux server keeps the test away from the user's sessions
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+
+	config := &models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: writeFakeClaude(t, "sleep 30")}
+	usm, err := NewUnifiedSessionManager(config)
+	if err != nil {
+		t.Fatalf("NewUnifiedSessionManager() failed: %v", err)
+	}
+
+	execution := &UnifiedExecution{
+		ExecutionID:   "task-reconcile",
+		ExecutionType: ExecutionTypeTask,
+		StartTime:     time.Now(),
+		WorkingDir:    t.TempDir(),
+		Prompt:        "do it",
+	}
+	session, err := usm.CreateSession(context.Background(), execution)
+	if err != nil {
+		t.Fatalf("CreateSession() failed: %v", err)
+	}
+
+	em, err := NewExecutionManager(config)
+	if err != nil {
+		t.Fatalf("NewExecutionManager() failed: %v", err)
+	}
+	if repaired, err := em.ReconcileExecutions(); err != nil || repaired != 0 {
+		t.Errorf("ReconcileExecutions() = %d, %v; want the live worker execution left alone", repaired, err)
+	}
+	metadata, err := em.LoadMetadata("task-reconcile")
+	if err != nil {
+		t.Fatalf("LoadMetadata() failed: %v", err)
+	}
+	if metadata.Status != ExecutionStatusRunning || metadata.TmuxSession != session.SessionName {
+		t.Errorf("metadata status %s, session %q; want running in %q", metadata.Status, metadata.TmuxSession, session.SessionName)
+	}
+
+	// Once the session is gone the execution is finalized
+	if err := usm.KillSession(session.SessionName); err != nil {
+		t.Fatalf("KillSession() failed: %v", err)
+	}
+	if repaired, err := em.ReconcileExecutions(); err != nil || repaired != 1 {
+		t.Errorf("ReconcileExecutions() after the session ended = %d, %v; want 1", repaired, err)
+	}
+}
+
+func TestBuildTaskCommandKeepsOutputInSession(t *testing.T) {
+	configDir := t.TempDir()
+	usm, err := NewUnifiedSessionManager(&models.ClaudeConfig{ConfigDir: configDir})
+	if err != nil {
+		t.Fatalf("NewUnifiedSessionManager() failed: %v", err)
+	}
+
+	command := usm.buildTaskCommand(&UnifiedExecution{ExecutionID: "task-tee", Prompt: "do it"}, "", "")
+	if strings.Contains(command, "tee") {
+		t.Errorf("buildTaskCommand() = %q, must not write an unredacted log", command)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "logs")); !os.IsNotExist(err) {
+		t.Error("buildTaskCommand() should not create log files")
+	}
+}
+
+func TestBuildTaskCommandQuotesModel(t *testing.T) {
+	usm, err := NewUnifiedSessionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewUnifiedSessionManager() failed: %v", err)
+	}
+
+	command := usm.buildTaskCommand(&UnifiedExecution{Prompt: "do it", Model: "opus; rm -rf ~"}, "", "")
+	if want := " --model 'opus; rm -rf ~' "; !strings.Contains(command, want) {
+		t.Errorf("buildTaskCommand() = %q, want the model quoted as %q", command, want)
+	}
+}
+
+func TestBuildTaskCommandUsesConfiguredExecutable(t *testing.T) {
+	usm, err := NewUnifiedSessionManager(&models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: "/opt/claude/bin/claude"})
+	if err != nil {
+		t.Fatalf("NewUnifiedSessionManager() failed: %v", err)
+	}
+
+	command := usm.buildTaskCommand(&UnifiedExecution{Prompt: "do it"}, "", "")
+	if !strings.Contains(command, " /opt/claude/bin/claude --verbose ") {
+		t.Errorf("buildTaskCommand() = %q, want it to run the configured executable", command)
+	}
+}
+
+func TestBuildTaskCommandReadsPromptFromFile(t *testing.T) {
+	received := filepath.Join(t.TempDir(), "received.txt")
+	executable := writeFakeClaude(t, `cat > '`+received+`'`)
+	usm, err := NewUnifiedSessionManager(&models.ClaudeConfig{ConfigDir: t.TempDir(), Executable: executable})
+	if err != nil {
+		t.Fatalf("NewUnifiedSessionManager() failed: %v", err)
+	}
+
+	prompt := "Fix Bob's \"flaky\" test\n" + strings.Repeat("then run `make test`; ", 10000)
+	promptFile, err := writePromptFile(t.TempDir(), prompt)
+	if err != nil {
+		t.Fatalf("writePromptFile() failed: %v", err)
+	}
+
+	command := usm.buildTaskCommand(&UnifiedExecution{Prompt: prompt}, "", promptFile)
+	if strings.Contains(command, "make test") {
+		t.Errorf("buildTaskCommand() = %q, want the prompt kept off the command line", command)
+	}
+	if out, err := exec.Command("bash", "-c", command).CombinedOutput(); err != nil {
+		t.Fatalf("running the task command failed: %v\n%s", err, out)
+	}
+
+	got, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatalf("failed to read prompt: %v", err)
+	}
+	if string(got) != prompt {
+		t.Errorf("prompt received by claude has %d bytes, want %d", len(got), len(prompt))
+	}
+	if _, err := os.Stat(promptFile); !os.IsNotExist(err) {
+		t.Error("the prompt file should be removed once opened")
+	}
+}
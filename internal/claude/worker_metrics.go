@@ -0,0 +1,223 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// workerMetricsBufferSize is how many events can be queued for writing before
+// new ones are dropped
+const workerMetricsBufferSize = 256
+
+// workerMetricsMaxSize is the size at which a worker metrics file is rotated
+// when a recorder opens it. Only the previous file is kept.
+const workerMetricsMaxSize = 10 * 1024 * 1024
+
+// WorkerMetricEvent is one line of the worker metrics file
+type WorkerMetricEvent struct {
+	Time   time.Time     `json:"time"`
+	Event  SlotEventKind `json:"event"`
+	TaskID string        `json:"task_id"`
+	Active int           `json:"active"`            // Tasks running once the event took effect
+	WaitMS int64         `json:"wait_ms,omitempty"` // Time the task was queued before acquiring its slot
+}
+
+// WorkerMetricsRecorder appends metric events to a JSONL file from a
+// background goroutine. Record never blocks; events are dropped when the
+// writer falls behind.
+type WorkerMetricsRecorder struct {
+	file    *os.File
+	events  chan WorkerMetricEvent
+	done    chan struct{}
+	err     error // First write error, reported by Close
+	mu      sync.Mutex
+	closed  bool
+	dropped int
+}
+
+// NewWorkerMetricsRecorder opens path for appending and starts the writer.
+// A file that has grown past workerMetricsMaxSize is rotated first.
+func NewWorkerMetricsRecorder(path string) (*WorkerMetricsRecorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	if err := rotateWorkerMetrics(path, workerMetricsMaxSize); err != nil {
+		return nil, fmt.Errorf("failed to rotate metrics file: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics file: %w", err)
+	}
+
+	r := &WorkerMetricsRecorder{
+		file:   file,
+		events: make(chan WorkerMetricEvent, workerMetricsBufferSize),
+		done:   make(chan struct{}),
+	}
+	go r.write()
+	return r, nil
+}
+
+// rotatedWorkerMetricsPath returns where the previous metrics file of path is kept
+func rotatedWorkerMetricsPath(path string) string {
+	return path + ".1"
+}
+
+// rotateWorkerMetrics moves path over its rotated file once it reaches
+// maxSize bytes. A missing file is left alone.
+func rotateWorkerMetrics(path string, maxSize int64) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() < maxSize {
+		return nil
+	}
+	return os.Rename(path, rotatedWorkerMetricsPath(path))
+}
+
+// write encodes queued events, flushing whenever the queue is drained so
+// bursts are batched into a single write
+func (r *WorkerMetricsRecorder) write() {
+	defer close(r.done)
+
+	w := bufio.NewWriter(r.file)
+	encoder := json.NewEncoder(w)
+	for event := range r.events {
+		if err := encoder.Encode(event); err != nil && r.err == nil {
+			r.err = err
+		}
+		if len(r.events) == 0 {
+			if err := w.Flush(); err != nil && r.err == nil {
+				r.err = err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// Record queues event for writing. Events recorded after Close, or while the
+// queue is full, are dropped.
+func (r *WorkerMetricsRecorder) Record(event WorkerMetricEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+	select {
+	case r.events <- event:
+	default:
+		r.dropped++
+	}
+}
+
+// Close writes the queued events and closes the file
+func (r *WorkerMetricsRecorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.events)
+	dropped := r.dropped
+	r.mu.Unlock()
+
+	<-r.done
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if r.err != nil {
+		return fmt.Errorf("failed to write metrics: %w", r.err)
+	}
+	if dropped > 0 {
+		return fmt.Errorf("dropped %d metric event(s) because the writer fell behind", dropped)
+	}
+	return nil
+}
+
+// LoadWorkerMetrics reads the events of a worker metrics file, preceded by
+// those of its rotated file if there is one, skipping lines that can't be
+// parsed
+func LoadWorkerMetrics(path string) ([]WorkerMetricEvent, error) {
+	events, err := loadWorkerMetricsFile(rotatedWorkerMetricsPath(path), nil)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return loadWorkerMetricsFile(path, events)
+}
+
+// loadWorkerMetricsFile appends the events of one metrics file to events
+func loadWorkerMetricsFile(path string, events []WorkerMetricEvent) ([]WorkerMetricEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	err = forEachLine(file, func(line string) {
+		var event WorkerMetricEvent
+		if err := json.Unmarshal([]byte(line), &event); err == nil {
+			events = append(events, event)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+	return events, nil
+}
+
+// WorkerMetricsSummary aggregates worker metric events
+type WorkerMetricsSummary struct {
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	Acquisitions    int       `json:"acquisitions"`
+	Releases        int       `json:"releases"`
+	PeakConcurrency int       `json:"peak_concurrency"`
+	AverageWaitMS   int64     `json:"average_wait_ms"` // Mean queue wait over acquisitions
+	MaxWaitMS       int64     `json:"max_wait_ms"`
+}
+
+// SummarizeWorkerMetrics reports the peak concurrency and queue wait times of
+// events, which need not be in order
+func SummarizeWorkerMetrics(events []WorkerMetricEvent) WorkerMetricsSummary {
+	var summary WorkerMetricsSummary
+	var totalWaitMS int64
+
+	for _, event := range events {
+		if summary.From.IsZero() || event.Time.Before(summary.From) {
+			summary.From = event.Time
+		}
+		if event.Time.After(summary.To) {
+			summary.To = event.Time
+		}
+		summary.PeakConcurrency = max(summary.PeakConcurrency, event.Active)
+
+		switch event.Event {
+		case SlotAcquired:
+			summary.Acquisitions++
+			totalWaitMS += event.WaitMS
+			summary.MaxWaitMS = max(summary.MaxWaitMS, event.WaitMS)
+		case SlotReleased:
+			summary.Releases++
+		}
+	}
+
+	if summary.Acquisitions > 0 {
+		summary.AverageWaitMS = totalWaitMS / int64(summary.Acquisitions)
+	}
+	return summary
+}
@@ -0,0 +1,184 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummarizeWorkerMetrics(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		name   string
+		events []WorkerMetricEvent
+		want   WorkerMetricsSummary
+	}{
+		{
+			name: "no events",
+			want: WorkerMetricsSummary{},
+		},
+		{
+			name: "overlapping tasks",
+			events: []WorkerMetricEvent{
+				{Time: at(0), Event: SlotAcquired, TaskID: "a", Active: 1, WaitMS: 1000},
+				{Time: at(1), Event: SlotAcquired, TaskID: "b", Active: 2, WaitMS: 3000},
+				{Time: at(2), Event: SlotAcquired, TaskID: "c", Active: 3, WaitMS: 8000},
+				{Time: at(5), Event: SlotReleased, TaskID: "a", Active: 2},
+				{Time: at(6), Event: SlotReleased, TaskID: "b", Active: 1},
+				{Time: at(7), Event: SlotAcquired, TaskID: "d", Active: 2, WaitMS: 0},
+				{Time: at(9), Event: SlotReleased, TaskID: "c", Active: 1},
+				{Time: at(10), Event: SlotReleased, TaskID: "d", Active: 0},
+			},
+			want: WorkerMetricsSummary{
+				From:            at(0),
+				To:              at(10),
+				Acquisitions:    4,
+				Releases:        4,
+				PeakConcurrency: 3,
+				AverageWaitMS:   3000,
+				MaxWaitMS:       8000,
+			},
+		},
+		{
+			name: "events out of order across worker runs",
+			events: []WorkerMetricEvent{
+				{Time: at(60), Event: SlotAcquired, TaskID: "late", Active: 1, WaitMS: 500},
+				{Time: at(0), Event: SlotAcquired, TaskID: "early", Active: 1, WaitMS: 1500},
+				{Time: at(61), Event: SlotReleased, TaskID: "late", Active: 0},
+			},
+			want: WorkerMetricsSummary{
+				From:            at(0),
+				To:              at(61),
+				Acquisitions:    2,
+				Releases:        1,
+				PeakConcurrency: 1,
+				AverageWaitMS:   1000,
+				MaxWaitMS:       1500,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SummarizeWorkerMetrics(tt.events); got != tt.want {
+				t.Errorf("SummarizeWorkerMetrics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkerMetricsRecorderRecordsSlotEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics", "worker-metrics.jsonl")
+	recorder, err := NewWorkerMetricsRecorder(path)
+	if err != nil {
+		t.Fatalf("NewWorkerMetricsRecorder() error = %v", err)
+	}
+
+	rm := NewResourceManager(2, 2)
+	rm.SetEventHandler(func(e SlotEvent) {
+		recorder.Record(WorkerMetricEvent{Time: e.Time, Event: e.Kind, TaskID: e.TaskID, Active: e.Active})
+	})
+
+	a, err := rm.TryAcquireSlot(TaskTypeDevelopment, "a", "")
+	if err != nil {
+		t.Fatalf("TryAcquireSlot(a) error = %v", err)
+	}
+	b, err := rm.TryAcquireSlot(TaskTypeDevelopment, "b", "")
+	if err != nil {
+		t.Fatalf("TryAcquireSlot(b) error = %v", err)
+	}
+	// A refused acquisition is not an event
+	if _, err := rm.TryAcquireSlot(TaskTypeDevelopment, "c", ""); err == nil {
+		t.Fatal("TryAcquireSlot(c) should fail with no slots left")
+	}
+	a.Release()
+	b.Release()
+
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	recorder.Record(WorkerMetricEvent{TaskID: "after-close"}) // Dropped, must not panic
+
+	events, err := LoadWorkerMetrics(path)
+	if err != nil {
+		t.Fatalf("LoadWorkerMetrics() error = %v", err)
+	}
+	want := []struct {
+		kind   SlotEventKind
+		taskID string
+		active int
+	}{
+		{SlotAcquired, "a", 1},
+		{SlotAcquired, "b", 2},
+		{SlotReleased, "a", 1},
+		{SlotReleased, "b", 0},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("loaded %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Event != w.kind || events[i].TaskID != w.taskID || events[i].Active != w.active {
+			t.Errorf("event %d = %+v, want %s %s active=%d", i, events[i], w.kind, w.taskID, w.active)
+		}
+	}
+
+	summary := SummarizeWorkerMetrics(events)
+	if summary.PeakConcurrency != 2 || summary.Acquisitions != 2 || summary.Releases != 2 {
+		t.Errorf("summary = %+v, want peak 2 with 2 acquisitions and 2 releases", summary)
+	}
+}
+
+func TestRotateWorkerMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worker-metrics.jsonl")
+	record := func(taskID string) {
+		t.Helper()
+		recorder, err := NewWorkerMetricsRecorder(path)
+		if err != nil {
+			t.Fatalf("NewWorkerMetricsRecorder() error = %v", err)
+		}
+		recorder.Record(WorkerMetricEvent{Event: SlotAcquired, TaskID: taskID, Active: 1})
+		if err := recorder.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	if err := rotateWorkerMetrics(path, 1); err != nil {
+		t.Errorf("rotateWorkerMetrics() of a missing file error = %v", err)
+	}
+	record("a")
+	if err := rotateWorkerMetrics(path, 1<<20); err != nil {
+		t.Fatalf("rotateWorkerMetrics() error = %v", err)
+	}
+	if _, err := os.Stat(rotatedWorkerMetricsPath(path)); !os.IsNotExist(err) {
+		t.Fatal("rotateWorkerMetrics() rotated a file under the size limit")
+	}
+
+	// The full file moves aside and the events of both files are still loaded
+	if err := rotateWorkerMetrics(path, 1); err != nil {
+		t.Fatalf("rotateWorkerMetrics() error = %v", err)
+	}
+	record("b")
+	events, err := LoadWorkerMetrics(path)
+	if err != nil {
+		t.Fatalf("LoadWorkerMetrics() error = %v", err)
+	}
+	if len(events) != 2 || events[0].TaskID != "a" || events[1].TaskID != "b" {
+		t.Errorf("LoadWorkerMetrics() = %+v, want the events of a then b", events)
+	}
+
+	// Only the previous file is kept
+	if err := rotateWorkerMetrics(path, 1); err != nil {
+		t.Fatalf("rotateWorkerMetrics() error = %v", err)
+	}
+	record("c")
+	events, err = LoadWorkerMetrics(path)
+	if err != nil {
+		t.Fatalf("LoadWorkerMetrics() error = %v", err)
+	}
+	if len(events) != 2 || events[0].TaskID != "b" || events[1].TaskID != "c" {
+		t.Errorf("LoadWorkerMetrics() = %+v, want the events of b then c", events)
+	}
+}
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
+	"github.com/d-kuro/gwq/internal/github"
+	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
@@ -10,6 +14,9 @@ var (
 	addBranch      bool
 	addInteractive bool
 	addForce       bool
+	addCopyFrom    string
+	addCopy        []string
+	addFromPR      int
 )
 
 // addCmd represents the add command.
@@ -19,7 +26,14 @@ var addCmd = &cobra.Command{
 	Long: `Create a new worktree for the specified branch.
 
 If no path is provided, it will be generated based on the configuration template.
-Use -i flag to interactively select a branch using fuzzy finder.`,
+Use -i flag to interactively select a branch using fuzzy finder.
+
+A remote branch without a local counterpart, given as origin/feature or as
+feature when only one remote has it, gets a local branch that tracks it.
+
+With --from-pr, the head of a GitHub pull request is fetched from the origin
+remote into a new pr/<number> branch. Fetching uses git's credentials; for
+private repositories cloned over HTTPS, $GITHUB_TOKEN is used when set.`,
 	Example: `  # Create worktree from existing branch
   gwq add feature/new-ui
 
@@ -30,7 +44,16 @@ Use -i flag to interactively select a branch using fuzzy finder.`,
   gwq add -b feature/api-v2
 
   # Interactive branch selection
-  gwq add -i`,
+  gwq add -i
+
+  # Seed untracked files from the main worktree (patterns from worktree.copy_patterns)
+  gwq add -b feature/api --copy-from main
+
+  # Override the patterns to copy
+  gwq add -b feature/api --copy-from main --copy ".env*" --copy "config/local.yml"
+
+  # Check out pull request #123 for review
+  gwq add --from-pr 123`,
 	RunE:              runAdd,
 	ValidArgsFunction: getBranchCompletions,
 }
@@ -41,10 +64,17 @@ func init() {
 	addCmd.Flags().BoolVarP(&addBranch, "branch", "b", false, "Create new branch")
 	addCmd.Flags().BoolVarP(&addInteractive, "interactive", "i", false, "Select branch using fuzzy finder")
 	addCmd.Flags().BoolVarP(&addForce, "force", "f", false, "Overwrite existing directory")
+	addCmd.Flags().StringVar(&addCopyFrom, "copy-from", "", "Worktree (path or pattern) to copy untracked files from")
+	addCmd.Flags().StringSliceVar(&addCopy, "copy", nil, "Glob of files to copy with --copy-from (overrides worktree.copy_patterns)")
+	addCmd.Flags().IntVar(&addFromPR, "from-pr", 0, "Create a worktree for a GitHub pull request number")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
 	return ExecuteWithArgs(true, func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+		if addFromPR != 0 {
+			return addFromPullRequest(ctx, addFromPR, args)
+		}
+
 		var branch string
 		var path string
 
@@ -63,11 +93,9 @@ func runAdd(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("branch selection cancelled")
 			}
 
+			// Remote branches are resolved to a local tracking branch by
+			// the worktree manager
 			branch = selectedBranch.Name
-			if selectedBranch.IsRemote {
-				branch = selectedBranch.Name[len("origin/"):]
-				addBranch = true
-			}
 		} else {
 			if len(args) < 1 {
 				return fmt.Errorf("branch name is required")
@@ -84,11 +112,87 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		if err := ctx.WorktreeManager.Add(branch, path, addBranch); err != nil {
+		opts := worktree.AddOptions{CopyPatterns: addCopy}
+		if addCopyFrom != "" {
+			source, err := resolveCopySource(ctx, addCopyFrom)
+			if err != nil {
+				return err
+			}
+			opts.CopyFrom = source
+		}
+
+		copied, err := ctx.WorktreeManager.AddWithOptions(branch, path, addBranch, opts)
+		if err != nil && !errors.Is(err, worktree.ErrCopyFailed) {
 			return err
 		}
 
 		ctx.Printer.PrintSuccess(fmt.Sprintf("Created worktree for branch '%s'", branch))
-		return nil
+		for _, file := range copied {
+			ctx.Printer.PrintInfo(fmt.Sprintf("Copied %s", file))
+		}
+		if opts.CopyFrom != "" && len(copied) == 0 && err == nil {
+			ctx.Printer.PrintInfo(fmt.Sprintf("No files copied from %s", opts.CopyFrom))
+		}
+		return err
 	})(cmd, args)
 }
+
+// addFromPullRequest fetches the head of a GitHub pull request from origin
+// and creates a worktree on a new pr/<number> branch for it. args may hold
+// the worktree path.
+func addFromPullRequest(ctx *CommandContext, number int, args []string) error {
+	if number < 0 {
+		return fmt.Errorf("invalid pull request number: %d", number)
+	}
+	if addBranch || addInteractive {
+		return fmt.Errorf("--from-pr cannot be combined with -b or -i")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("--from-pr accepts only an optional path argument")
+	}
+
+	var path string
+	if len(args) == 1 {
+		path = args[0]
+		if !addForce {
+			if err := ctx.WorktreeManager.ValidateWorktreePath(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	remoteURL, err := ctx.Git.GetRepositoryURL()
+	if err != nil {
+		return err
+	}
+	if _, err := github.ParseRemote(remoteURL); err != nil {
+		return fmt.Errorf("--from-pr requires a GitHub origin remote: %w", err)
+	}
+
+	auth := github.AuthConfig(remoteURL, os.Getenv(github.TokenEnv))
+	if err := ctx.Git.FetchRef("origin", github.PullRequestRef(number), auth...); err != nil {
+		return fmt.Errorf("failed to fetch pull request #%d: %w", number, err)
+	}
+
+	branch := github.PullRequestBranch(number)
+	if err := ctx.WorktreeManager.AddFromBase(branch, "FETCH_HEAD", path); err != nil {
+		return err
+	}
+
+	ctx.Printer.PrintSuccess(fmt.Sprintf("Created worktree for pull request #%d on branch '%s'", number, branch))
+	return nil
+}
+
+// resolveCopySource returns the directory for --copy-from, accepting either a
+// path or a pattern matching an existing worktree.
+func resolveCopySource(ctx *CommandContext, source string) (string, error) {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return source, nil
+	}
+
+	path, err := ctx.WorktreeManager.GetWorktreePath(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve --copy-from: %w", err)
+	}
+	return path, nil
+}
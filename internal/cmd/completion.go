@@ -107,6 +107,7 @@ func getConfigKeyCompletions(_ *cobra.Command, args []string, toComplete string)
 	}{
 		{"worktree.basedir", "Base directory for worktrees"},
 		{"worktree.auto_mkdir", "Automatically create directories"},
+		{"worktree.path_template", "Layout of generated worktree paths"},
 		{"finder.preview", "Enable preview window"},
 		{"finder.preview_size", "Preview window size"},
 		{"finder.keybind_select", "Key binding for selection"},
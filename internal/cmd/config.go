@@ -60,11 +60,26 @@ var configGetCmd = &cobra.Command{
 	ValidArgsFunction: getConfigKeyCompletions,
 }
 
+// configValidateCmd represents the config validate command.
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate configuration",
+	Long: `Check the configuration for invalid values.
+
+Every problem found is reported, and the command exits with a non-zero
+status if the configuration is invalid.`,
+	Example: `  # Validate the current configuration
+  gwq config validate`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configValidateCmd)
 }
 
 func runConfigList(cmd *cobra.Command, args []string) error {
@@ -118,3 +133,17 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 	fmt.Println(value)
 	return nil
 }
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	fmt.Println("Configuration is valid")
+	return nil
+}
@@ -16,12 +16,13 @@ import (
 // CommandContext encapsulates common dependencies used across commands.
 // This eliminates boilerplate code and provides consistent initialization.
 type CommandContext struct {
-	Config          *models.Config
-	Git             *git.Git
-	Printer         *ui.Printer
-	WorktreeManager *worktree.Manager
-	finder          *finder.Finder // Lazy-loaded
-	IsGitRepo       bool
+	Config           *models.Config
+	Git              *git.Git
+	Printer          *ui.Printer
+	WorktreeManager  *worktree.Manager
+	finder           *finder.Finder // Lazy-loaded
+	IsGitRepo        bool
+	RefreshDiscovery bool // Bypass the global discovery cache
 }
 
 // NewCommandContext creates a new command context for commands that don't require git.
@@ -68,7 +69,7 @@ func NewGitCommandContext() (*CommandContext, error) {
 // This provides lazy initialization to avoid creating finders for commands that don't need them.
 func (ctx *CommandContext) GetFinder() *finder.Finder {
 	if ctx.finder == nil && ctx.Git != nil {
-		ctx.finder = finder.NewWithUI(ctx.Git, &ctx.Config.Finder, &ctx.Config.UI)
+		ctx.finder = newFinder(ctx.Git, ctx.Config)
 	}
 	return ctx.finder
 }
@@ -78,25 +79,32 @@ func (ctx *CommandContext) GetFinder() *finder.Finder {
 func (ctx *CommandContext) GetGlobalFinder() *finder.Finder {
 	// For global operations, we use an empty git instance
 	emptyGit := &git.Git{}
-	return finder.NewWithUI(emptyGit, &ctx.Config.Finder, &ctx.Config.UI)
+	return newFinder(emptyGit, ctx.Config)
 }
 
 // Factory functions for commands that haven't been refactored to use CommandContext yet
 
 // CreateFinder creates a finder instance for local operations with the given git instance.
 func CreateFinder(g *git.Git, cfg *models.Config) *finder.Finder {
-	return finder.NewWithUI(g, &cfg.Finder, &cfg.UI)
+	return newFinder(g, cfg)
 }
 
 // CreateGlobalFinder creates a finder instance for global operations.
 func CreateGlobalFinder(cfg *models.Config) *finder.Finder {
 	emptyGit := &git.Git{}
-	return finder.NewWithUI(emptyGit, &cfg.Finder, &cfg.UI)
+	return newFinder(emptyGit, cfg)
+}
+
+// newFinder creates a finder that can sort worktrees by last activity.
+func newFinder(g *git.Git, cfg *models.Config) *finder.Finder {
+	f := finder.NewWithUI(g, &cfg.Finder, &cfg.UI)
+	f.SetActivityFunc(NewStatusCollector(false, false).getLastActivity)
+	return f
 }
 
 // DiscoverGlobalWorktrees discovers global worktrees when -g flag is used.
 func (ctx *CommandContext) DiscoverGlobalWorktrees() ([]*models.Worktree, error) {
-	entries, err := discovery.DiscoverGlobalWorktrees(ctx.Config.Worktree.BaseDir)
+	entries, err := discovery.DiscoverGlobalWorktrees(ctx.Config.Worktree.BaseDir, ctx.RefreshDiscovery)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +117,8 @@ func (ctx *CommandContext) DiscoverGlobalWorktrees() ([]*models.Worktree, error)
 			Branch:     entry.Branch,
 			CommitHash: entry.CommitHash,
 			IsMain:     entry.IsMain,
+			Locked:     entry.Locked,
+			LockReason: entry.LockReason,
 		})
 	}
 
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/logging"
+	"github.com/d-kuro/gwq/pkg/command"
+	"github.com/d-kuro/gwq/pkg/filesystem"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/d-kuro/gwq/pkg/system"
+	"github.com/spf13/cobra"
+)
+
+// minGitVersion is the oldest git with every worktree subcommand gwq uses
+// (git worktree remove was added in 2.17).
+var minGitVersion = [2]int{2, 17}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose environment issues",
+	Long: `Check that gwq's dependencies and directories are usable.
+
+Each check is reported with a hint on how to fix a failure. git, the
+configuration and the task directories are required; tmux, named pipes and
+the Claude CLI are only needed for Claude tasks and are reported as warnings.
+The command exits with a non-zero status if a required check fails.
+--json prints the checks and the number of failed required checks for CI.`,
+	Example: `  # Check the environment
+  gwq doctor
+
+  # Machine-readable output for CI
+  gwq doctor --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+var doctorJSON bool
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output in JSON format")
+}
+
+// doctorCheck is the outcome of a single doctor check
+type doctorCheck struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Critical bool   `json:"critical"`       // A failure makes doctor exit non-zero
+	Detail   string `json:"detail"`         // What was found, or why the check failed
+	Hint     string `json:"hint,omitempty"` // How to fix a failure, only set for failed checks in JSON
+}
+
+// doctorEnv holds the system abstractions the checks run against
+type doctorEnv struct {
+	sys  system.SystemInterface
+	fs   filesystem.FileSystemInterface
+	exec command.CommandExecutor
+}
+
+func newDoctorEnv() *doctorEnv {
+	return &doctorEnv{
+		sys:  system.NewStandardSystem(),
+		fs:   filesystem.NewStandardFileSystem(),
+		exec: command.NewStandardExecutor(),
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfg, loadErr := config.Load()
+	checks := newDoctorEnv().runChecks(cmd.Context(), cfg, loadErr)
+
+	var failed int
+	if doctorJSON {
+		var err error
+		if failed, err = writeDoctorJSON(os.Stdout, checks); err != nil {
+			return err
+		}
+	} else {
+		useIcons := cfg != nil && cfg.UI.Icons
+		failed = printDoctorChecks(os.Stdout, checks, useIcons)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d required check(s) failed", failed)
+	}
+	return nil
+}
+
+// runChecks runs every check. The directory and executable checks need a
+// loaded configuration and are skipped when it couldn't be loaded.
+func (env *doctorEnv) runChecks(ctx context.Context, cfg *models.Config, loadErr error) []doctorCheck {
+	checks := []doctorCheck{
+		env.checkGit(ctx),
+		checkConfig(cfg, loadErr),
+	}
+	if loadErr != nil {
+		return checks
+	}
+
+	return append(checks,
+		env.checkWritableDir("log directory", claude.LogDir(&cfg.Claude), "set claude.log_dir to a writable directory"),
+		env.checkWritableDir("queue directory", cfg.Claude.Queue.QueueDir, "set claude.queue.queue_dir to a writable directory"),
+		env.checkTmux(),
+		env.checkNamedPipes(),
+		env.checkClaude(cfg.Claude.Executable),
+	)
+}
+
+// checkGit verifies that git is installed and new enough for gwq
+func (env *doctorEnv) checkGit(ctx context.Context) doctorCheck {
+	check := doctorCheck{Name: "git", Critical: true, Hint: "install git from https://git-scm.com/downloads"}
+
+	if _, err := env.sys.LookPath("git"); err != nil {
+		check.Detail = "not found in PATH"
+		return check
+	}
+
+	output, err := env.exec.ExecuteWithOutput(ctx, "git", "--version")
+	if err != nil {
+		check.Detail = fmt.Sprintf("failed to run git --version: %v", err)
+		return check
+	}
+
+	major, minor, ok := parseGitVersion(output)
+	if !ok {
+		check.Detail = fmt.Sprintf("unrecognized version %q", output)
+		return check
+	}
+	check.Detail = fmt.Sprintf("%d.%d", major, minor)
+	if major < minGitVersion[0] || (major == minGitVersion[0] && minor < minGitVersion[1]) {
+		check.Detail += fmt.Sprintf(" is older than %d.%d", minGitVersion[0], minGitVersion[1])
+		check.Hint = fmt.Sprintf("upgrade git to %d.%d or later", minGitVersion[0], minGitVersion[1])
+		return check
+	}
+
+	check.OK = true
+	return check
+}
+
+var gitVersionPattern = regexp.MustCompile(`git version (\d+)\.(\d+)`)
+
+// parseGitVersion extracts the major and minor version from git --version output
+func parseGitVersion(output string) (int, int, bool) {
+	m := gitVersionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return 0, 0, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	return major, minor, true
+}
+
+// checkConfig reports whether the configuration loaded and is valid
+func checkConfig(cfg *models.Config, loadErr error) doctorCheck {
+	check := doctorCheck{Name: "config", Critical: true, Hint: "fix the reported keys with 'gwq config set' or edit ~/.config/gwq/config.toml"}
+
+	if loadErr != nil {
+		check.Detail = loadErr.Error()
+		return check
+	}
+	if err := cfg.Validate(); err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+
+	check.OK = true
+	check.Detail = "valid"
+	return check
+}
+
+// checkWritableDir verifies that dir exists, or can be created, and that a
+// file can be written to it
+func (env *doctorEnv) checkWritableDir(name, dir, hint string) doctorCheck {
+	check := doctorCheck{Name: name, Critical: true, Detail: dir, Hint: hint}
+
+	if err := env.fs.MkdirAll(dir, 0755); err != nil {
+		check.Detail = fmt.Sprintf("cannot create %s: %v", dir, err)
+		return check
+	}
+
+	probe := filepath.Join(dir, ".gwq-doctor")
+	if err := env.fs.WriteFile(probe, nil, 0644); err != nil {
+		check.Detail = fmt.Sprintf("%s is not writable: %v", dir, err)
+		return check
+	}
+	if err := env.fs.Remove(probe); err != nil {
+		logging.Warnf("failed to remove %s: %v", probe, err)
+	}
+
+	check.OK = true
+	return check
+}
+
+// checkTmux verifies that tmux, which runs Claude tasks, is installed
+func (env *doctorEnv) checkTmux() doctorCheck {
+	check := doctorCheck{Name: "tmux", Hint: "install tmux to run Claude tasks (e.g. brew install tmux, apt install tmux)"}
+
+	path, err := env.sys.LookPath("tmux")
+	if err != nil {
+		check.Detail = "not found in PATH"
+		return check
+	}
+
+	check.OK = true
+	check.Detail = path
+	return check
+}
+
+// checkNamedPipes verifies that a named pipe, used to capture Claude output,
+// can be created
+func (env *doctorEnv) checkNamedPipes() doctorCheck {
+	check := doctorCheck{Name: "named pipes", Hint: "Claude tasks need mkfifo support; run gwq on Linux or macOS"}
+
+	// The probe sits next to the pipes Claude executions use; one left by an
+	// earlier run of this process ID is replaced
+	probe := filepath.Join(os.TempDir(), fmt.Sprintf("gwq-doctor-%d.pipe", os.Getpid()))
+	if err := env.fs.Remove(probe); err != nil && !os.IsNotExist(err) {
+		check.Detail = fmt.Sprintf("failed to remove stale %s: %v", probe, err)
+		return check
+	}
+
+	if err := env.sys.CreateNamedPipe(probe, 0600); err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	if err := env.fs.Remove(probe); err != nil {
+		logging.Warnf("failed to remove %s: %v", probe, err)
+	}
+
+	check.OK = true
+	check.Detail = "supported"
+	return check
+}
+
+// checkClaude verifies that the configured Claude executable is installed
+func (env *doctorEnv) checkClaude(executable string) doctorCheck {
+	check := doctorCheck{Name: "claude", Hint: "install Claude Code or set claude.executable to its path"}
+
+	path, err := env.sys.LookPath(executable)
+	if err != nil {
+		check.Detail = fmt.Sprintf("'%s' not found in PATH", executable)
+		return check
+	}
+
+	check.OK = true
+	check.Detail = path
+	return check
+}
+
+// printDoctorChecks writes one line per check, with a hint under each
+// failure, and returns the number of failed required checks
+func printDoctorChecks(w io.Writer, checks []doctorCheck, useIcons bool) int {
+	failed := 0
+	for _, check := range checks {
+		var status string
+		switch {
+		case check.OK && useIcons:
+			status = "✓"
+		case check.OK:
+			status = "[ok]"
+		case check.Critical && useIcons:
+			status = "✗"
+		case check.Critical:
+			status = "[fail]"
+		case useIcons:
+			status = "!"
+		default:
+			status = "[warn]"
+		}
+
+		_, _ = fmt.Fprintf(w, "%s %s: %s\n", status, check.Name, check.Detail)
+		if !check.OK {
+			_, _ = fmt.Fprintf(w, "    hint: %s\n", check.Hint)
+			if check.Critical {
+				failed++
+			}
+		}
+	}
+	return failed
+}
+
+// writeDoctorJSON writes the checks and the number of failed required checks
+// as JSON, and returns that number
+func writeDoctorJSON(w io.Writer, checks []doctorCheck) (int, error) {
+	output := struct {
+		Checks []doctorCheck `json:"checks"`
+		Failed int           `json:"failed"` // Failed required checks
+	}{Checks: make([]doctorCheck, 0, len(checks))}
+
+	for _, check := range checks {
+		if check.OK {
+			check.Hint = ""
+		} else if check.Critical {
+			output.Failed++
+		}
+		output.Checks = append(output.Checks, check)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return output.Failed, encoder.Encode(output)
+}
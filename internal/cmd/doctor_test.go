@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/command"
+	"github.com/d-kuro/gwq/pkg/filesystem"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/d-kuro/gwq/pkg/system"
+)
+
+// doctorSystem finds only the executables in paths and fails to create pipes
+// when pipeErr is set
+type doctorSystem struct {
+	system.SystemInterface
+	paths   map[string]string
+	pipeErr error
+}
+
+func (s *doctorSystem) LookPath(file string) (string, error) {
+	if path, ok := s.paths[file]; ok {
+		return path, nil
+	}
+	return "", errors.New("executable file not found in $PATH")
+}
+
+func (s *doctorSystem) CreateNamedPipe(path string, mode uint32) error {
+	if s.pipeErr != nil {
+		return s.pipeErr
+	}
+	return os.WriteFile(path, nil, 0600)
+}
+
+// doctorExecutor returns output for every command
+type doctorExecutor struct {
+	command.CommandExecutor
+	output string
+	err    error
+}
+
+func (e *doctorExecutor) ExecuteWithOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return e.output, e.err
+}
+
+// readOnlyFileSystem refuses to write files
+type readOnlyFileSystem struct {
+	*filesystem.StandardFileSystem
+}
+
+func (fs readOnlyFileSystem) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	return &os.PathError{Op: "open", Path: filename, Err: os.ErrPermission}
+}
+
+func TestDoctorCheckGit(t *testing.T) {
+	tests := []struct {
+		name       string
+		found      bool
+		output     string
+		err        error
+		wantOK     bool
+		wantDetail string
+	}{
+		{name: "supported", found: true, output: "git version 2.43.0\n", wantOK: true, wantDetail: "2.43"},
+		{name: "apple git", found: true, output: "git version 2.39.3 (Apple Git-146)\n", wantOK: true, wantDetail: "2.39"},
+		{name: "too old", found: true, output: "git version 2.16.1\n", wantDetail: "2.16 is older than 2.17"},
+		{name: "unrecognized", found: true, output: "hub version 2.14", wantDetail: "unrecognized version"},
+		{name: "fails to run", found: true, err: errors.New("exit status 1"), wantDetail: "failed to run git --version"},
+		{name: "missing", wantDetail: "not found in PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &doctorSystem{paths: map[string]string{}}
+			if tt.found {
+				sys.paths["git"] = "/usr/bin/git"
+			}
+			env := &doctorEnv{sys: sys, exec: &doctorExecutor{output: tt.output, err: tt.err}}
+
+			check := env.checkGit(context.Background())
+			if check.OK != tt.wantOK {
+				t.Errorf("OK = %v, want %v (detail %q)", check.OK, tt.wantOK, check.Detail)
+			}
+			if !check.Critical {
+				t.Error("git check should be critical")
+			}
+			if !strings.Contains(check.Detail, tt.wantDetail) {
+				t.Errorf("Detail = %q, want it to contain %q", check.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestDoctorCheckConfig(t *testing.T) {
+	valid := &models.Config{
+		Worktree: models.WorktreeConfig{BaseDir: "/tmp/worktrees"},
+		Claude:   models.ClaudeConfig{MaxParallel: 1, MaxDevelopmentTasks: 1},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("fixture config is invalid: %v", err)
+	}
+
+	if check := checkConfig(valid, nil); !check.OK {
+		t.Errorf("valid config failed: %s", check.Detail)
+	}
+
+	invalid := *valid
+	invalid.Worktree.BaseDir = "relative"
+	if check := checkConfig(&invalid, nil); check.OK || !strings.Contains(check.Detail, "worktree.basedir") {
+		t.Errorf("invalid config check = %+v, want a failure naming worktree.basedir", check)
+	}
+
+	if check := checkConfig(nil, errors.New("failed to unmarshal config")); check.OK || !check.Critical {
+		t.Errorf("load error check = %+v, want a critical failure", check)
+	}
+}
+
+func TestDoctorCheckWritableDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+
+	env := &doctorEnv{fs: filesystem.NewStandardFileSystem()}
+	if check := env.checkWritableDir("log directory", dir, "hint"); !check.OK {
+		t.Errorf("writable dir failed: %s", check.Detail)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("probe file left behind: %v, %v", entries, err)
+	}
+
+	env = &doctorEnv{fs: readOnlyFileSystem{filesystem.NewStandardFileSystem()}}
+	check := env.checkWritableDir("log directory", dir, "set claude.log_dir")
+	if check.OK || !check.Critical || !strings.Contains(check.Detail, "not writable") {
+		t.Errorf("read-only dir check = %+v, want a critical not writable failure", check)
+	}
+
+	// A regular file where the directory should be
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	env = &doctorEnv{fs: filesystem.NewStandardFileSystem()}
+	if check := env.checkWritableDir("queue directory", filepath.Join(file, "queue"), "hint"); check.OK || !strings.Contains(check.Detail, "cannot create") {
+		t.Errorf("uncreatable dir check = %+v, want a cannot create failure", check)
+	}
+}
+
+func TestDoctorCheckExecutables(t *testing.T) {
+	found := &doctorEnv{sys: &doctorSystem{paths: map[string]string{"tmux": "/usr/bin/tmux", "claude": "/usr/local/bin/claude"}}}
+	missing := &doctorEnv{sys: &doctorSystem{}}
+
+	if check := found.checkTmux(); !check.OK || check.Detail != "/usr/bin/tmux" {
+		t.Errorf("checkTmux() = %+v, want the tmux path", check)
+	}
+	if check := missing.checkTmux(); check.OK || check.Critical {
+		t.Errorf("missing tmux check = %+v, want a non-critical failure", check)
+	}
+
+	if check := found.checkClaude("claude"); !check.OK || check.Detail != "/usr/local/bin/claude" {
+		t.Errorf("checkClaude() = %+v, want the claude path", check)
+	}
+	if check := missing.checkClaude("claude-custom"); check.OK || check.Critical || !strings.Contains(check.Detail, "claude-custom") {
+		t.Errorf("missing claude check = %+v, want a non-critical failure naming the executable", check)
+	}
+}
+
+// removeRecordingFileSystem records the paths it removes
+type removeRecordingFileSystem struct {
+	*filesystem.StandardFileSystem
+	removed []string
+}
+
+func (fs *removeRecordingFileSystem) Remove(name string) error {
+	fs.removed = append(fs.removed, name)
+	return fs.StandardFileSystem.Remove(name)
+}
+
+func TestDoctorCheckNamedPipes(t *testing.T) {
+	fs := &removeRecordingFileSystem{StandardFileSystem: filesystem.NewStandardFileSystem()}
+	env := &doctorEnv{sys: &doctorSystem{}, fs: fs}
+	if check := env.checkNamedPipes(); !check.OK {
+		t.Errorf("checkNamedPipes() failed: %s", check.Detail)
+	}
+	// The probe is cleaned up through the injected filesystem
+	if len(fs.removed) == 0 {
+		t.Fatal("checkNamedPipes() didn't remove its probe through env.fs")
+	}
+	probe := fs.removed[len(fs.removed)-1]
+	if _, err := os.Stat(probe); !os.IsNotExist(err) {
+		t.Errorf("checkNamedPipes() left %s behind", probe)
+	}
+
+	env.sys = &doctorSystem{pipeErr: errors.New("named pipes are not supported on Windows")}
+	if check := env.checkNamedPipes(); check.OK || check.Critical {
+		t.Errorf("unsupported pipes check = %+v, want a non-critical failure", check)
+	}
+}
+
+func TestPrintDoctorChecks(t *testing.T) {
+	checks := []doctorCheck{
+		{Name: "git", OK: true, Critical: true, Detail: "2.43"},
+		{Name: "tmux", Detail: "not found in PATH", Hint: "install tmux"},
+		{Name: "config", Critical: true, Detail: "invalid", Hint: "fix it"},
+	}
+
+	var buf bytes.Buffer
+	if failed := printDoctorChecks(&buf, checks, false); failed != 1 {
+		t.Errorf("printDoctorChecks() = %d failed, want 1", failed)
+	}
+
+	want := `[ok] git: 2.43
+[warn] tmux: not found in PATH
+    hint: install tmux
+[fail] config: invalid
+    hint: fix it
+`
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteDoctorJSON(t *testing.T) {
+	checks := []doctorCheck{
+		{Name: "git", OK: true, Critical: true, Detail: "2.43", Hint: "install git"},
+		{Name: "tmux", Detail: "not found in PATH", Hint: "install tmux"},
+		{Name: "config", Critical: true, Detail: "invalid", Hint: "fix it"},
+	}
+
+	var buf bytes.Buffer
+	failed, err := writeDoctorJSON(&buf, checks)
+	if err != nil {
+		t.Fatalf("writeDoctorJSON() error = %v", err)
+	}
+	if failed != 1 {
+		t.Errorf("writeDoctorJSON() = %d failed, want 1", failed)
+	}
+
+	var got struct {
+		Checks []doctorCheck `json:"checks"`
+		Failed int           `json:"failed"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Failed != 1 || len(got.Checks) != 3 {
+		t.Fatalf("output = %+v, want 3 checks and 1 failure", got)
+	}
+	if got.Checks[0].Hint != "" {
+		t.Errorf("passing check has hint %q", got.Checks[0].Hint)
+	}
+	if want := checks[2]; got.Checks[2] != want {
+		t.Errorf("failed check = %+v, want %+v", got.Checks[2], want)
+	}
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/spf13/cobra"
@@ -10,7 +12,9 @@ import (
 var (
 	listVerbose bool
 	listJSON    bool
+	listFormat  string
 	listGlobal  bool
+	listRefresh bool
 )
 
 // listCmd represents the list command.
@@ -23,7 +27,9 @@ When run inside a git repository, shows worktrees for the current repository.
 When run outside a git repository, shows all worktrees in the configured base directory.
 Use -g flag to always show all worktrees from the base directory.
 Use -v flag for detailed information including commit hashes and creation times.
-Use --json flag to output in JSON format for scripting.`,
+Use --json flag to output in JSON format for scripting.
+Use --format csv or --format tsv to output one row per worktree with its
+path, commit, status and ahead/behind counts for spreadsheets and scripts.`,
 	Example: `  # Simple list
   gwq list
 
@@ -33,8 +39,14 @@ Use --json flag to output in JSON format for scripting.`,
   # JSON format for scripting
   gwq list --json
 
+  # CSV including status and ahead/behind counts
+  gwq list --format csv
+
   # Show all worktrees from base directory (from anywhere)
-  gwq list -g`,
+  gwq list -g
+
+  # Rescan the base directory, ignoring the discovery cache
+  gwq list -g --refresh`,
 	RunE: runList,
 }
 
@@ -43,10 +55,16 @@ func init() {
 
 	listCmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "Show detailed information")
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output in JSON format")
+	listCmd.Flags().StringVar(&listFormat, "format", "", "Output format (table, json, csv, tsv)")
 	listCmd.Flags().BoolVarP(&listGlobal, "global", "g", false, "Show all worktrees from the configured base directory")
+	listCmd.Flags().BoolVar(&listRefresh, "refresh", false, "Ignore the discovery cache when listing global worktrees")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if _, err := resolveOutputFormat(listFormat, listJSON, false); err != nil {
+		return err
+	}
+
 	// Try git context first, fall back to non-git if needed
 	ctx, err := NewGitCommandContext()
 	if err != nil {
@@ -56,6 +74,7 @@ func runList(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	}
+	ctx.RefreshDiscovery = listRefresh
 
 	return ctx.WithGlobalLocalSupport(
 		listGlobal,
@@ -66,12 +85,7 @@ func runList(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("failed to list worktrees: %w", err)
 			}
 
-			if listJSON {
-				return ctx.Printer.PrintWorktreesJSON(worktrees)
-			}
-
-			ctx.Printer.PrintWorktrees(worktrees, listVerbose)
-			return nil
+			return printWorktreeList(ctx, worktrees)
 		},
 		func(ctx *CommandContext) error {
 			// Global mode - show all worktrees from base directory
@@ -97,10 +111,38 @@ func showGlobalWorktrees(ctx *CommandContext) error {
 		worktrees = append(worktrees, *w)
 	}
 
-	if listJSON {
-		return ctx.Printer.PrintWorktreesJSON(worktrees)
+	return printWorktreeList(ctx, worktrees)
+}
+
+// printWorktreeList prints worktrees in the format selected by the list flags.
+// Delimited formats also collect each worktree's status.
+func printWorktreeList(ctx *CommandContext, worktrees []models.Worktree) error {
+	format, err := resolveOutputFormat(listFormat, listJSON, false)
+	if err != nil {
+		return err
 	}
 
-	ctx.Printer.PrintWorktrees(worktrees, listVerbose)
-	return nil
+	switch {
+	case format == outputFormatJSON:
+		return ctx.Printer.PrintWorktreesJSON(worktrees)
+	case isDelimitedFormat(format):
+		pointers := make([]*models.Worktree, len(worktrees))
+		for i := range worktrees {
+			pointers[i] = &worktrees[i]
+		}
+
+		collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+			FetchRemote: true,
+			BaseDir:     ctx.Config.Worktree.BaseDir,
+			GitTimeout:  defaultStatusGitTimeout,
+		})
+		statuses, err := collector.CollectAll(context.Background(), pointers)
+		if err != nil {
+			return fmt.Errorf("failed to collect worktree statuses: %w", err)
+		}
+		return outputDelimited(os.Stdout, statuses, format)
+	default:
+		ctx.Printer.PrintWorktrees(worktrees, listVerbose)
+		return nil
+	}
 }
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/d-kuro/gwq/internal/logging"
+	"github.com/d-kuro/gwq/internal/registry"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// moveCmd represents the move command.
+var moveCmd = &cobra.Command{
+	Use:     "move <branch|path> <new-path>",
+	Aliases: []string{"mv"},
+	Short:   "Move a worktree to a new path",
+	Long: `Move a worktree to a new location, keeping its branch and local changes.
+
+The worktree is given by its path or by a pattern matched against branch
+names and paths, like gwq remove; when several worktrees match, one is
+selected with the fuzzy finder. The new path must not exist yet; its parent
+directory is created when worktree.auto_mkdir is enabled. The main worktree
+can't be moved.
+
+Worktrees moved outside worktree.basedir are no longer found by commands
+run with -g.`,
+	Example: `  # Move a worktree by branch name
+  gwq move feature/login ~/src/login
+
+  # Move the worktree at a path
+  gwq mv ./old-location ../new-location`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMove,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+		return getRemoveCompletions(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	return ExecuteWithContext(true, func(ctx *CommandContext) error {
+		source, err := resolveMoveSource(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		newPath, err := ctx.WorktreeManager.Move(source.Path, args[1])
+		if err != nil {
+			return err
+		}
+		updateMovedRegistryEntry(source.Path, newPath)
+
+		if !isPathWithin(newPath, ctx.Config.Worktree.BaseDir) {
+			logging.Warnf("%s is outside worktree.basedir (%s); commands run with -g won't find it", newPath, ctx.Config.Worktree.BaseDir)
+		}
+
+		ctx.Printer.PrintSuccess(fmt.Sprintf("Moved worktree %s", source.Branch))
+		ctx.Printer.PrintWorktreePath(newPath)
+		return nil
+	})(cmd, args)
+}
+
+// resolveMoveSource returns the worktree at arg when it is the path of one,
+// otherwise the worktree whose branch or path matches arg, selected with the
+// finder when several do. The main worktree is only returned when named
+// exactly, so that Move can refuse it.
+func resolveMoveSource(ctx *CommandContext, arg string) (*models.Worktree, error) {
+	worktrees, err := ctx.WorktreeManager.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	if abs, err := filepath.Abs(arg); err == nil {
+		resolved, _ := filepath.EvalSymlinks(abs)
+		for i, wt := range worktrees {
+			if wt.Path == abs || wt.Path == resolved || wt.Branch == arg {
+				return &worktrees[i], nil
+			}
+		}
+	}
+
+	matches, err := ctx.WorktreeManager.GetMatchingWorktrees(arg)
+	if err != nil {
+		return nil, err
+	}
+	matches = filterNonMainWorktrees(matches)
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no worktree found matching pattern: %s", arg)
+	case 1:
+		return &matches[0], nil
+	default:
+		selected, err := ctx.GetFinder().SelectWorktree(matches)
+		if err != nil {
+			return nil, fmt.Errorf("worktree selection cancelled")
+		}
+		return selected, nil
+	}
+}
+
+// updateMovedRegistryEntry points the global registry entry of a moved
+// worktree, if there is one, at its new path
+func updateMovedRegistryEntry(oldPath, newPath string) {
+	reg, err := registry.New()
+	if err != nil {
+		logging.Debugf("failed to open worktree registry: %v", err)
+		return
+	}
+
+	entry, ok := reg.Get(oldPath)
+	if !ok {
+		return
+	}
+	moved := *entry
+	moved.Path = newPath
+	if err := reg.Unregister(oldPath); err != nil {
+		logging.Warnf("failed to update worktree registry: %v", err)
+		return
+	}
+	if err := reg.Register(&moved); err != nil {
+		logging.Warnf("failed to update worktree registry: %v", err)
+	}
+}
@@ -15,21 +15,52 @@ var pruneCmd = &cobra.Command{
 This command removes administrative files from .git/worktrees for worktrees
 whose working directories have been deleted from the filesystem.`,
 	Example: `  # Clean up stale worktree information
-  gwq prune`,
+  gwq prune
+
+  # Show what would be cleaned up without removing anything
+  gwq prune --dry-run`,
 	RunE: runPrune,
 }
 
+var pruneDryRun bool
+
 func init() {
 	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "n", false, "Show worktrees that would be pruned without removing them")
 }
 
 func runPrune(cmd *cobra.Command, args []string) error {
 	return ExecuteWithContext(true, func(ctx *CommandContext) error {
-		if err := ctx.WorktreeManager.Prune(); err != nil {
+		if pruneDryRun {
+			paths, err := ctx.WorktreeManager.PruneDryRun()
+			if err != nil {
+				return fmt.Errorf("failed to check prunable worktrees: %w", err)
+			}
+			if len(paths) == 0 {
+				ctx.Printer.PrintInfo("No stale worktree information to prune")
+				return nil
+			}
+			ctx.Printer.PrintInfo("Would prune:")
+			for _, path := range paths {
+				ctx.Printer.PrintWorktreePath(path)
+			}
+			return nil
+		}
+
+		paths, err := ctx.WorktreeManager.Prune()
+		if err != nil {
 			return fmt.Errorf("failed to prune worktrees: %w", err)
 		}
+		if len(paths) == 0 {
+			ctx.Printer.PrintSuccess("No stale worktree information to prune")
+			return nil
+		}
 
-		ctx.Printer.PrintSuccess("Pruned stale worktree information")
+		for _, path := range paths {
+			ctx.Printer.PrintWorktreePath(path)
+		}
+		ctx.Printer.PrintSuccess(fmt.Sprintf("Pruned stale worktree information for %d worktree(s)", len(paths)))
 		return nil
 	})(cmd, args)
 }
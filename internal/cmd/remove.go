@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/d-kuro/gwq/internal/discovery"
 	"github.com/d-kuro/gwq/internal/finder"
 	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/spf13/cobra"
@@ -17,6 +19,7 @@ var (
 	removeForce       bool
 	removeDryRun      bool
 	removeGlobal      bool
+	removeAutoStash   bool
 	deleteBranch      bool
 	forceDeleteBranch bool
 )
@@ -29,7 +32,8 @@ var removeCmd = &cobra.Command{
 	Long: `Delete a worktree from the repository.
 
 If no pattern is provided, shows a fuzzy finder to select the worktree.
-The pattern can match against branch name or path.
+The pattern can match against branch name or path. When several worktrees
+are selected, they are listed and removed after a single confirmation.
 
 By default, only the worktree directory is removed and the branch is preserved.
 Use -b flag to also delete the branch after removing the worktree.
@@ -46,6 +50,9 @@ Use -g flag to always show all worktrees from the base directory.`,
   # Force delete even if dirty
   gwq remove -f feature/broken
 
+  # Stash uncommitted changes before deleting
+  gwq remove --auto-stash feature/wip
+
   # Delete worktree and branch
   gwq remove -b feature/completed
 
@@ -72,6 +79,7 @@ func init() {
 	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Force delete even if dirty")
 	removeCmd.Flags().BoolVarP(&removeDryRun, "dry-run", "d", false, "Show deletion targets only")
 	removeCmd.Flags().BoolVarP(&removeGlobal, "global", "g", false, "Remove from any worktree in the configured base directory")
+	removeCmd.Flags().BoolVar(&removeAutoStash, "auto-stash", false, "Stash uncommitted changes before removing the worktree")
 	removeCmd.Flags().BoolVarP(&deleteBranch, "delete-branch", "b", false, "Also delete the branch after removing worktree")
 	removeCmd.Flags().BoolVar(&forceDeleteBranch, "force-delete-branch", false, "Force delete the branch even if not merged")
 }
@@ -144,6 +152,8 @@ func removeLocalWorktree(ctx *CommandContext, args []string) error {
 		toRemove = selected
 	}
 
+	warnLockedWorktrees(toRemove)
+
 	if removeDryRun {
 		fmt.Println("Would remove the following worktrees:")
 		for _, wt := range toRemove {
@@ -155,28 +165,121 @@ func removeLocalWorktree(ctx *CommandContext, args []string) error {
 		return nil
 	}
 
-	for _, wt := range toRemove {
-		if deleteBranch {
-			if err := ctx.WorktreeManager.RemoveWithBranch(wt.Path, wt.Branch, removeForce, deleteBranch, forceDeleteBranch); err != nil {
-				ctx.Printer.PrintError(fmt.Errorf("failed to remove %s: %v", wt.Branch, err))
-				continue
-			}
-			ctx.Printer.PrintSuccess(fmt.Sprintf("Removed worktree: %s", wt.Branch))
-			if wt.Branch != "" {
-				ctx.Printer.PrintSuccess(fmt.Sprintf("Deleted branch: %s", wt.Branch))
-			}
-		} else {
-			if err := ctx.WorktreeManager.Remove(wt.Path, removeForce); err != nil {
-				ctx.Printer.PrintError(fmt.Errorf("failed to remove %s: %v", wt.Branch, err))
-				continue
+	// Confirm once when several worktrees were selected
+	if len(toRemove) > 1 && !confirmRemoveWorktrees(toRemove) {
+		fmt.Println("Operation cancelled")
+		return nil
+	}
+
+	if removeAutoStash {
+		var stashed []models.Worktree
+		for _, wt := range toRemove {
+			if autoStashWorktree(ctx, ctx.WorktreeManager, wt.Path, wt.Branch) {
+				stashed = append(stashed, wt)
 			}
-			ctx.Printer.PrintSuccess(fmt.Sprintf("Removed worktree: %s", wt.Branch))
 		}
+		toRemove = stashed
+	}
+
+	if !deleteBranch {
+		return removeWorktrees(ctx, toRemove)
+	}
+
+	for _, wt := range toRemove {
+		if err := ctx.WorktreeManager.RemoveWithBranch(wt.Path, wt.Branch, removeForce, deleteBranch, forceDeleteBranch); err != nil {
+			ctx.Printer.PrintError(fmt.Errorf("failed to remove %s: %v", wt.Branch, err))
+			printRemoveHint(err)
+			continue
+		}
+		ctx.Printer.PrintSuccess(fmt.Sprintf("Removed worktree: %s", wt.Branch))
+		if wt.Branch != "" {
+			ctx.Printer.PrintSuccess(fmt.Sprintf("Deleted branch: %s", wt.Branch))
+		}
+	}
+
+	return nil
+}
+
+// printRemoveHint explains how to get past a refused worktree removal or
+// branch deletion
+func printRemoveHint(err error) {
+	switch {
+	case errors.Is(err, worktree.ErrDirtyWorktree):
+		fmt.Println("Use --auto-stash to keep the changes in the stash, or --force to discard them")
+	case errors.Is(err, git.ErrBranchNotMerged):
+		fmt.Println("The branch has unmerged commits; use --force-delete-branch to delete it anyway")
+	}
+}
+
+// autoStashWorktree stashes the uncommitted changes of the worktree at path
+// and tells the user how to restore them. It reports false if stashing
+// failed, in which case the worktree should be kept.
+func autoStashWorktree(ctx *CommandContext, wm *worktree.Manager, path, label string) bool {
+	stashRef, err := wm.StashChanges(path)
+	if err != nil {
+		ctx.Printer.PrintError(fmt.Errorf("failed to stash %s, keeping worktree: %v", label, err))
+		return false
+	}
+	if stashRef != "" {
+		fmt.Printf("Stashed changes of %s as %s (restore with: git stash apply %s)\n", label, stashRef, stashRef)
+	}
+	return true
+}
+
+// removeWorktrees removes the worktrees, continuing past failures, and
+// reports each outcome
+func removeWorktrees(ctx *CommandContext, worktrees []models.Worktree) error {
+	paths := make([]string, len(worktrees))
+	branches := make(map[string]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.Path
+		branches[wt.Path] = wt.Branch
 	}
 
+	result := ctx.WorktreeManager.RemoveMany(paths, removeForce)
+	for _, path := range result.Removed {
+		ctx.Printer.PrintSuccess(fmt.Sprintf("Removed worktree: %s", branches[path]))
+	}
+	for _, failure := range result.Failed {
+		ctx.Printer.PrintError(fmt.Errorf("failed to remove %s: %v", branches[failure.Path], failure.Err))
+		printRemoveHint(failure.Err)
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("failed to remove %d of %d worktrees", len(result.Failed), len(worktrees))
+	}
 	return nil
 }
 
+// warnLockedWorktrees warns about locked worktrees, which git refuses to remove
+func warnLockedWorktrees(worktrees []models.Worktree) {
+	for _, wt := range worktrees {
+		if !wt.Locked {
+			continue
+		}
+		reason := ""
+		if wt.LockReason != "" {
+			reason = fmt.Sprintf(" (%s)", wt.LockReason)
+		}
+		logging.Warnf("worktree %s is locked%s; unlock it with 'git worktree unlock %s' before removing", wt.Branch, reason, wt.Path)
+	}
+}
+
+// confirmRemoveWorktrees lists the selected worktrees and asks for confirmation
+func confirmRemoveWorktrees(worktrees []models.Worktree) bool {
+	fmt.Printf("\nThis will remove %d worktree(s):\n", len(worktrees))
+	for _, wt := range worktrees {
+		fmt.Printf("  ● %s (%s)\n", wt.Branch, wt.Path)
+	}
+
+	fmt.Print("\nAre you sure? (y/N): ")
+	var response string
+	_, _ = fmt.Scanln(&response)
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
 func filterNonMainWorktrees(worktrees []models.Worktree) []models.Worktree {
 	var filtered []models.Worktree
 	for _, wt := range worktrees {
@@ -188,7 +291,7 @@ func filterNonMainWorktrees(worktrees []models.Worktree) []models.Worktree {
 }
 
 func removeGlobalWorktree(ctx *CommandContext, args []string) error {
-	entries, err := discovery.DiscoverGlobalWorktrees(ctx.Config.Worktree.BaseDir)
+	entries, err := discovery.DiscoverGlobalWorktrees(ctx.Config.Worktree.BaseDir, false)
 	if err != nil {
 		return fmt.Errorf("failed to discover worktrees: %w", err)
 	}
@@ -331,6 +434,11 @@ func removeGlobalWorktree(ctx *CommandContext, args []string) error {
 		g := git.New(repoPath)
 		wm := worktree.New(g, ctx.Config)
 
+		if removeAutoStash && !autoStashWorktree(ctx, wm, entry.Path, entry.Branch) {
+			_ = os.Chdir(originalDir)
+			continue
+		}
+
 		if deleteBranch {
 			if err := wm.RemoveWithBranch(entry.Path, entry.Branch, removeForce, deleteBranch, forceDeleteBranch); err != nil {
 				repoName := "unknown"
@@ -338,6 +446,7 @@ func removeGlobalWorktree(ctx *CommandContext, args []string) error {
 					repoName = entry.RepositoryInfo.Repository
 				}
 				ctx.Printer.PrintError(fmt.Errorf("failed to remove %s:%s: %v", repoName, entry.Branch, err))
+				printRemoveHint(err)
 				_ = os.Chdir(originalDir)
 				continue
 			}
@@ -348,6 +457,7 @@ func removeGlobalWorktree(ctx *CommandContext, args []string) error {
 					repoName = entry.RepositoryInfo.Repository
 				}
 				ctx.Printer.PrintError(fmt.Errorf("failed to remove %s:%s: %v", repoName, entry.Branch, err))
+				printRemoveHint(err)
 				_ = os.Chdir(originalDir)
 				continue
 			}
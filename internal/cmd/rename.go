@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// renameCmd represents the rename command.
+var renameCmd = &cobra.Command{
+	Use:   "rename <old-branch> <new-branch>",
+	Short: "Rename a branch and its worktree",
+	Long: `Rename a local branch, including one checked out in a worktree.
+
+If the worktree's path was generated from the old branch name, the worktree
+is moved to the path for the new name so the layout stays consistent.
+Worktrees at custom paths stay where they are. The rename is refused if the
+new branch name already exists.`,
+	Example: `  # Rename a branch and move its worktree
+  gwq rename feature/login feature/auth`,
+	Args:              cobra.ExactArgs(2),
+	RunE:              runRename,
+	ValidArgsFunction: getBranchCompletions,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	return ExecuteWithContext(true, func(ctx *CommandContext) error {
+		oldName, newName := args[0], args[1]
+
+		newPath, err := ctx.WorktreeManager.RenameBranch(oldName, newName)
+		if err != nil {
+			return err
+		}
+
+		ctx.Printer.PrintSuccess(fmt.Sprintf("Renamed branch %s to %s", oldName, newName))
+		if newPath != "" {
+			ctx.Printer.PrintInfo("Moved worktree to:")
+			ctx.Printer.PrintWorktreePath(newPath)
+		}
+		return nil
+	})(cmd, args)
+}
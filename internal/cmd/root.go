@@ -7,6 +7,7 @@ import (
 	"runtime/debug"
 
 	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +17,11 @@ var (
 	date    = "unknown"
 )
 
+var (
+	rootQuiet   bool
+	rootVerbose bool
+)
+
 // rootCmd represents the base command when called without any subcommands.
 var rootCmd = &cobra.Command{
 	Use:   "gwq",
@@ -36,12 +42,28 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initLogging, initConfig)
+
+	rootCmd.PersistentFlags().BoolVarP(&rootQuiet, "quiet", "q", false, "Suppress warnings")
+	rootCmd.PersistentFlags().BoolVar(&rootVerbose, "verbose", false, "Show debug messages (commands with their own --verbose keep its meaning)")
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = false
 	rootCmd.CompletionOptions.HiddenDefaultCmd = true
 }
 
+// initLogging sets the level of warnings and debug messages, which go to
+// stderr, from --quiet and --verbose. --quiet wins when both are given.
+func initLogging() {
+	switch {
+	case rootQuiet:
+		logging.SetLevel(logging.LevelQuiet)
+	case rootVerbose:
+		logging.SetLevel(logging.LevelVerbose)
+	default:
+		logging.SetLevel(logging.LevelNormal)
+	}
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if err := config.Init(); err != nil {
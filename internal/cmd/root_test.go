@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/logging"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// captureOutput runs fn and returns what it wrote to stdout and stderr
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+
+	read := func(r *os.File, out *string, done chan<- struct{}) {
+		data, _ := io.ReadAll(r)
+		*out = string(data)
+		close(done)
+	}
+
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	var stdout, stderr string
+	stdoutDone, stderrDone := make(chan struct{}), make(chan struct{})
+	go read(stdoutR, &stdout, stdoutDone)
+	go read(stderrR, &stderr, stderrDone)
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutW, stderrW
+	defer func() {
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+	}()
+
+	fn()
+
+	_ = stdoutW.Close()
+	_ = stderrW.Close()
+	<-stdoutDone
+	<-stderrDone
+	return stdout, stderr
+}
+
+func TestQuietSuppressesWarnings(t *testing.T) {
+	locked := []models.Worktree{{Path: "/tmp/wt", Branch: "feature", Locked: true}}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantWarning bool
+	}{
+		{name: "default", args: nil, wantWarning: true},
+		{name: "quiet", args: []string{"--quiet"}, wantWarning: false},
+		{name: "quiet and verbose", args: []string{"-q", "--verbose"}, wantWarning: false},
+		{name: "verbose", args: []string{"--verbose"}, wantWarning: true},
+	}
+
+	defer func() {
+		rootQuiet, rootVerbose = false, false
+		initLogging()
+	}()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootQuiet, rootVerbose = false, false
+			if err := rootCmd.PersistentFlags().Parse(tt.args); err != nil {
+				t.Fatalf("failed to parse flags: %v", err)
+			}
+			initLogging()
+
+			stdout, stderr := captureOutput(t, func() {
+				fmt.Println("real output")
+				warnLockedWorktrees(locked)
+			})
+
+			if stdout != "real output\n" {
+				t.Errorf("stdout = %q, want only the real output", stdout)
+			}
+			if got := strings.Contains(stderr, "Warning: worktree feature is locked"); got != tt.wantWarning {
+				t.Errorf("stderr = %q, want warning %v", stderr, tt.wantWarning)
+			}
+		})
+	}
+
+	if logging.GetLevel() != logging.LevelVerbose {
+		t.Errorf("level after --verbose = %d, want %d", logging.GetLevel(), logging.LevelVerbose)
+	}
+}
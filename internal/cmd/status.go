@@ -24,11 +24,18 @@ var (
 	statusSort        string
 	statusJSON        bool
 	statusCSV         bool
+	statusFormat      string
 	statusVerbose     bool
 	statusGlobal      bool
 	statusShowProcess bool
 	statusNoFetch     bool
+	statusFetch       bool
+	statusNoSubmodule bool
+	statusGitTimeout  time.Duration
 	statusStaleDays   int
+	statusStaleOnly   bool
+	statusSize        bool
+	statusSizeInclude []string
 )
 
 var statusCmd = &cobra.Command{
@@ -44,15 +51,28 @@ for managing multiple AI coding agents working in parallel across different work
   # JSON output for scripting
   gwq status --json
   
+  # CSV or TSV for spreadsheets
+  gwq status --format csv > status.csv
+  gwq status --format tsv
+  
   # Watch mode with 5 second interval
   gwq status --watch
   
   # Include process information
   gwq status --show-processes
   
+  # Fetch remotes first so ahead/behind counts are current
+  gwq status --fetch
+  
   # Filter modified worktrees
   gwq status --filter modified
   
+  # Worktrees untouched for a week
+  gwq status --stale-only --stale-days 7
+  
+  # Disk usage, counting node_modules too
+  gwq status --size --size-include node_modules
+  
   # Global status from anywhere
   gwq status --global`,
 	RunE: runStatus,
@@ -66,15 +86,29 @@ func init() {
 	statusCmd.Flags().StringVarP(&statusFilter, "filter", "f", "", "Filter by status (changed, up to date, inactive)")
 	statusCmd.Flags().StringVarP(&statusSort, "sort", "s", "", "Sort by field (branch, modified, activity)")
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output as JSON")
-	statusCmd.Flags().BoolVar(&statusCSV, "csv", false, "Output as CSV")
+	statusCmd.Flags().BoolVar(&statusCSV, "csv", false, "Output as CSV (same as --format csv)")
+	statusCmd.Flags().StringVar(&statusFormat, "format", "", "Output format (table, json, csv, tsv)")
 	statusCmd.Flags().BoolVarP(&statusVerbose, "verbose", "v", false, "Show additional information")
 	statusCmd.Flags().BoolVarP(&statusGlobal, "global", "g", false, "Show all worktrees from base directory")
 	statusCmd.Flags().BoolVar(&statusShowProcess, "show-processes", false, "Include running processes (slower)")
 	statusCmd.Flags().BoolVar(&statusNoFetch, "no-fetch", false, "Skip remote status check (faster)")
+	statusCmd.Flags().BoolVar(&statusFetch, "fetch", false, "Fetch from remotes before computing ahead/behind (slower)")
+	statusCmd.Flags().BoolVar(&statusNoSubmodule, "no-submodules", false, "Skip submodule status check (faster)")
+	statusCmd.Flags().DurationVar(&statusGitTimeout, "git-timeout", defaultStatusGitTimeout, "Timeout for each git command (0 = no timeout)")
 	statusCmd.Flags().IntVar(&statusStaleDays, "stale-days", 14, "Days of inactivity before marking as stale")
+	statusCmd.Flags().BoolVar(&statusStaleOnly, "stale-only", false, "Show only stale worktrees")
+	statusCmd.Flags().BoolVar(&statusSize, "size", false, "Include disk usage of each worktree (slower)")
+	statusCmd.Flags().StringSliceVar(&statusSizeInclude, "size-include", nil, "Directories to count in --size despite being skipped by default (e.g. node_modules,vendor)")
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	if _, err := resolveOutputFormat(statusFormat, statusJSON, statusCSV); err != nil {
+		return err
+	}
+	if err := validateStaleFlags(); err != nil {
+		return err
+	}
+
 	if statusWatch {
 		return runStatusWatch(cmd, time.Duration(statusInterval)*time.Second)
 	}
@@ -115,7 +149,10 @@ func runStatusWatch(cmd *cobra.Command, interval time.Duration) error {
 	defer cleanup()
 
 	// Create refresh function for status updates
-	refresh := createRefreshFunction(ctx, cfg, printer)
+	watcher := newStatusWatcher(newStatusCollector(cfg), func() ([]*models.Worktree, error) {
+		return listStatusWorktrees(cfg)
+	})
+	refresh := createRefreshFunction(ctx, watcher, cfg, printer)
 
 	// Run the watch loop with periodic refreshes
 	return runWatchLoop(ctx, refresh, interval)
@@ -148,17 +185,18 @@ func setupWatchMode() (func(), context.Context) {
 }
 
 // createRefreshFunction creates the refresh function for watch mode
-func createRefreshFunction(ctx context.Context, cfg *models.Config, printer *ui.Printer) func() error {
+func createRefreshFunction(ctx context.Context, watcher *statusWatcher, cfg *models.Config, printer *ui.Printer) func() error {
 	clearScreen := "\033[H\033[2J"
 
 	return func() error {
-		fmt.Print(clearScreen)
-
-		statuses, err := collectWorktreeStatuses(ctx, cfg, printer)
+		// Collect before clearing so the previous table stays visible while git runs
+		statuses, changed, err := watcher.tick(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to collect worktree statuses: %w", err)
 		}
 
+		fmt.Print(clearScreen)
+
 		statuses = applyFiltersAndSort(statuses)
 
 		// Display summary header
@@ -166,8 +204,13 @@ func createRefreshFunction(ctx context.Context, cfg *models.Config, printer *ui.
 			return err
 		}
 
-		// Output status details
-		if err := outputStatuses(statuses, printer, cfg); err != nil {
+		// Output status details, highlighting worktrees that changed since the last refresh
+		if format, _ := resolveOutputFormat(statusFormat, statusJSON, statusCSV); format != outputFormatTable {
+			err = outputStatuses(statuses, printer, cfg)
+		} else {
+			err = outputTableWithChanges(statuses, printer, statusVerbose, changed)
+		}
+		if err != nil {
 			return err
 		}
 
@@ -206,6 +249,9 @@ func runWatchLoop(ctx context.Context, refresh func() error, interval time.Durat
 			return nil
 		case <-ticker.C:
 			if err := refresh(); err != nil {
+				if ctx.Err() != nil {
+					return nil // Interrupted mid-collection
+				}
 				fmt.Printf("Error: %v\n", err)
 			}
 		}
@@ -213,11 +259,20 @@ func runWatchLoop(ctx context.Context, refresh func() error, interval time.Durat
 }
 
 func collectWorktreeStatuses(ctx context.Context, cfg *models.Config, printer *ui.Printer) ([]*models.WorktreeStatus, error) {
+	worktrees, err := listStatusWorktrees(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newStatusCollector(cfg).CollectAll(ctx, worktrees)
+}
+
+// listStatusWorktrees returns the worktrees shown by the status command
+func listStatusWorktrees(cfg *models.Config) ([]*models.Worktree, error) {
 	var worktrees []*models.Worktree
 
 	g, err := git.NewFromCwd()
 	if err != nil || statusGlobal {
-		globalEntries, err := discovery.DiscoverGlobalWorktrees(cfg.Worktree.BaseDir)
+		globalEntries, err := discovery.DiscoverGlobalWorktrees(cfg.Worktree.BaseDir, false)
 		if err != nil {
 			return nil, fmt.Errorf("failed to discover worktrees: %w", err)
 		}
@@ -242,17 +297,46 @@ func collectWorktreeStatuses(ctx context.Context, cfg *models.Config, printer *u
 		}
 	}
 
-	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
-		IncludeProcess: statusShowProcess,
-		FetchRemote:    !statusNoFetch,
-		StaleThreshold: time.Duration(statusStaleDays) * 24 * time.Hour,
-		BaseDir:        cfg.Worktree.BaseDir,
+	return worktrees, nil
+}
+
+// newStatusCollector creates a status collector configured from the status flags
+func newStatusCollector(cfg *models.Config) *StatusCollector {
+	return NewStatusCollectorWithOptions(StatusCollectorOptions{
+		IncludeProcess:  statusShowProcess,
+		FetchRemote:     !statusNoFetch,
+		Fetch:           statusFetch,
+		StaleThreshold:  time.Duration(statusStaleDays) * 24 * time.Hour,
+		BaseDir:         cfg.Worktree.BaseDir,
+		SkipSubmodules:  statusNoSubmodule,
+		GitTimeout:      statusGitTimeout,
+		IncludeSize:     statusSize,
+		SizeIncludeDirs: statusSizeInclude,
 	})
-	return collector.CollectAll(ctx, worktrees)
+}
+
+// validateStaleFlags checks --stale-days and that --stale-only doesn't
+// contradict --filter
+func validateStaleFlags() error {
+	if statusStaleDays < 1 {
+		return fmt.Errorf("--stale-days must be at least 1")
+	}
+	if statusStaleOnly {
+		switch statusFilter {
+		case "", "stale", "inactive":
+		default:
+			return fmt.Errorf("--stale-only cannot be combined with --filter %s", statusFilter)
+		}
+	}
+	return nil
 }
 
 func applyFiltersAndSort(statuses []*models.WorktreeStatus) []*models.WorktreeStatus {
-	if statusFilter != "" {
+	// Staleness is decided during collection, so filtering afterwards leaves
+	// the other states computed as usual
+	if statusStaleOnly {
+		statuses = filterStatuses(statuses, "stale")
+	} else if statusFilter != "" {
 		statuses = filterStatuses(statuses, statusFilter)
 	}
 
@@ -264,11 +348,16 @@ func applyFiltersAndSort(statuses []*models.WorktreeStatus) []*models.WorktreeSt
 }
 
 func outputStatuses(statuses []*models.WorktreeStatus, printer *ui.Printer, cfg *models.Config) error {
+	format, err := resolveOutputFormat(statusFormat, statusJSON, statusCSV)
+	if err != nil {
+		return err
+	}
+
 	switch {
-	case statusJSON:
-		return outputJSON(statuses)
-	case statusCSV:
-		return outputCSV(statuses)
+	case format == outputFormatJSON:
+		return outputJSON(os.Stdout, statuses)
+	case isDelimitedFormat(format):
+		return outputDelimited(os.Stdout, statuses, format)
 	default:
 		return outputTable(statuses, printer, statusVerbose)
 	}
@@ -288,11 +377,17 @@ func getCurrentRepository() string {
 	return remote
 }
 
+// statusSummary counts worktrees by state. It is part of the --json output,
+// so fields are only ever added and keep the capitalized keys consumers
+// already read.
 type statusSummary struct {
-	Total    int
-	Modified int
-	Clean    int
-	Stale    int
+	Total    int `json:"Total"`
+	Clean    int `json:"Clean"`
+	Modified int `json:"Modified"`
+	Staged   int `json:"Staged"`
+	Conflict int `json:"Conflict"`
+	Stale    int `json:"Stale"`
+	Unknown  int `json:"Unknown"`
 }
 
 func calculateSummary(statuses []*models.WorktreeStatus) statusSummary {
@@ -304,8 +399,14 @@ func calculateSummary(statuses []*models.WorktreeStatus) statusSummary {
 			summary.Modified++
 		case models.WorktreeStatusClean:
 			summary.Clean++
+		case models.WorktreeStatusStaged:
+			summary.Staged++
+		case models.WorktreeStatusConflict:
+			summary.Conflict++
 		case models.WorktreeStatusStale:
 			summary.Stale++
+		case models.WorktreeStatusUnknown:
+			summary.Unknown++
 		}
 	}
 
@@ -326,7 +427,7 @@ func filterStatuses(statuses []*models.WorktreeStatus, filter string) []*models.
 				filtered = append(filtered, s)
 			}
 		case "stale", "inactive":
-			if s.Status == models.WorktreeStatusStale {
+			if s.Stale || s.Status == models.WorktreeStatusStale {
 				filtered = append(filtered, s)
 			}
 		case "staged":
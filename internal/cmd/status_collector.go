@@ -3,30 +3,69 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
+// defaultStatusGitTimeout is the timeout applied to each git command by default.
+const defaultStatusGitTimeout = 5 * time.Second
+
 // StatusCollectorOptions contains optional parameters for StatusCollector.
 type StatusCollectorOptions struct {
-	IncludeProcess bool
-	FetchRemote    bool
-	StaleThreshold time.Duration
-	BaseDir        string
+	IncludeProcess  bool
+	FetchRemote     bool
+	Fetch           bool // Run git fetch before computing ahead/behind (requires FetchRemote)
+	StaleThreshold  time.Duration
+	BaseDir         string
+	SkipSubmodules  bool          // Skip the extra git process that checks submodule status
+	GitTimeout      time.Duration // Timeout for each git command (0 = no timeout)
+	IncludeSize     bool          // Sum the size of each worktree's files (slower)
+	SizeIncludeDirs []string      // Directory names counted in sizes despite being in defaultSkipDirs
+}
+
+// defaultSkipDirs are large generated or tool directories skipped when
+// walking a worktree for activity or size.
+var defaultSkipDirs = map[string]bool{
+	".git":          true,
+	"node_modules":  true,
+	"vendor":        true,
+	".next":         true,
+	"dist":          true,
+	"build":         true,
+	"target":        true,
+	".cache":        true,
+	"coverage":      true,
+	"__pycache__":   true,
+	".pytest_cache": true,
+	".venv":         true,
+	"venv":          true,
+	".idea":         true,
+	".vscode":       true,
 }
 
 // StatusCollector collects status information for worktrees.
 type StatusCollector struct {
 	includeProcess bool
 	fetchRemote    bool
+	fetch          bool
 	staleThreshold time.Duration
 	basedir        string
+	skipSubmodules bool
+	gitTimeout     time.Duration
+	includeSize    bool
+	sizeSkipDirs   map[string]bool
+
+	clientsMu sync.Mutex
+	clients   map[string]*git.Git // Git clients by worktree path, reused across collections
 }
 
 // NewStatusCollector creates a new status collector instance.
@@ -35,6 +74,7 @@ func NewStatusCollector(includeProcess, fetchRemote bool) *StatusCollector {
 		includeProcess: includeProcess,
 		fetchRemote:    fetchRemote,
 		staleThreshold: 14 * 24 * time.Hour, // 14 days
+		gitTimeout:     defaultStatusGitTimeout,
 	}
 }
 
@@ -48,11 +88,28 @@ func NewStatusCollectorWithOptions(opts StatusCollectorOptions) *StatusCollector
 	return &StatusCollector{
 		includeProcess: opts.IncludeProcess,
 		fetchRemote:    opts.FetchRemote,
+		fetch:          opts.Fetch,
 		staleThreshold: opts.StaleThreshold,
 		basedir:        opts.BaseDir,
+		skipSubmodules: opts.SkipSubmodules,
+		gitTimeout:     opts.GitTimeout,
+		includeSize:    opts.IncludeSize,
+		sizeSkipDirs:   skipDirsExcept(opts.SizeIncludeDirs),
 	}
 }
 
+// skipDirsExcept returns defaultSkipDirs without the given directory names.
+func skipDirsExcept(include []string) map[string]bool {
+	skip := make(map[string]bool, len(defaultSkipDirs))
+	for name := range defaultSkipDirs {
+		skip[name] = true
+	}
+	for _, name := range include {
+		delete(skip, name)
+	}
+	return skip
+}
+
 // CollectAll collects status for all provided worktrees in parallel.
 func (c *StatusCollector) CollectAll(ctx context.Context, worktrees []*models.Worktree) ([]*models.WorktreeStatus, error) {
 	statuses := make([]*models.WorktreeStatus, len(worktrees))
@@ -62,6 +119,12 @@ func (c *StatusCollector) CollectAll(ctx context.Context, worktrees []*models.Wo
 
 	currentPath, _ := os.Getwd()
 
+	// Processes are listed once and matched against every worktree
+	var processes *processSnapshot
+	if c.includeProcess {
+		processes = snapshotProcesses(ctx)
+	}
+
 	for i, wt := range worktrees {
 		wg.Add(1)
 		go func(idx int, worktree *models.Worktree) {
@@ -73,7 +136,7 @@ func (c *StatusCollector) CollectAll(ctx context.Context, worktrees []*models.Wo
 			default:
 			}
 
-			status, err := c.collectOne(ctx, worktree)
+			status, err := c.collectOne(ctx, worktree, processes)
 			if err != nil {
 				mu.Lock()
 				if firstErr == nil {
@@ -107,24 +170,28 @@ func (c *StatusCollector) CollectAll(ctx context.Context, worktrees []*models.Wo
 	return validStatuses, nil
 }
 
-func (c *StatusCollector) collectOne(ctx context.Context, worktree *models.Worktree) (*models.WorktreeStatus, error) {
+// collectOne collects the status of a worktree. Active processes are
+// reported when processes is not nil.
+func (c *StatusCollector) collectOne(ctx context.Context, worktree *models.Worktree, processes *processSnapshot) (*models.WorktreeStatus, error) {
 	status := &models.WorktreeStatus{
 		Path:       worktree.Path,
 		Branch:     worktree.Branch,
+		CommitHash: worktree.CommitHash,
 		Repository: c.extractRepository(worktree.Path),
 		Status:     models.WorktreeStatusClean,
 	}
 
-	g := git.New(worktree.Path)
+	g := c.gitClient(worktree.Path)
 
-	gitStatus, err := c.collectGitStatus(ctx, g)
+	gitStatus, conflictFiles, err := c.collectGitStatus(ctx, g)
 	if err != nil {
 		// Log error but continue with minimal status
-		// fmt.Fprintf(os.Stderr, "Warning: Failed to collect git status for %s: %v\n", worktree.Path, err)
+		logging.Debugf("failed to collect git status for %s: %v", worktree.Path, err)
 		status.GitStatus = models.GitStatus{}
 		status.Status = models.WorktreeStatusUnknown
 	} else {
 		status.GitStatus = *gitStatus
+		status.ConflictFiles = conflictFiles
 		status.Status = c.determineWorktreeState(gitStatus)
 	}
 
@@ -132,32 +199,83 @@ func (c *StatusCollector) collectOne(ctx context.Context, worktree *models.Workt
 	if err == nil {
 		status.LastActivity = lastActivity
 		if time.Since(lastActivity) > c.staleThreshold {
-			status.Status = models.WorktreeStatusStale
+			status.Stale = true
+			// A worktree whose git status couldn't be read stays unknown
+			if status.Status != models.WorktreeStatusUnknown {
+				status.Status = models.WorktreeStatusStale
+			}
 		}
 	}
 
-	if c.includeProcess {
-		processes, err := c.collectProcesses(ctx, worktree.Path)
+	if processes != nil {
+		status.ActiveProcess = processes.within(worktree.Path)
+	}
+
+	if c.includeSize {
+		size, err := c.diskUsage(ctx, worktree.Path)
 		if err == nil {
-			status.ActiveProcess = processes
+			status.Size = &size
 		}
 	}
 
 	return status, nil
 }
 
-func (c *StatusCollector) collectGitStatus(ctx context.Context, g *git.Git) (*models.GitStatus, error) {
+// gitClient returns the cached Git client for a worktree path, creating it on first use.
+func (c *StatusCollector) gitClient(path string) *git.Git {
+	c.clientsMu.Lock()
+	defer c.clientsMu.Unlock()
+
+	if c.clients == nil {
+		c.clients = make(map[string]*git.Git)
+	}
+	g, ok := c.clients[path]
+	if !ok {
+		g = git.New(path)
+		c.clients[path] = g
+	}
+	return g
+}
+
+// gitContext returns a context bounded by the collector's git timeout.
+func (c *StatusCollector) gitContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.gitTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.gitTimeout)
+}
+
+// collectGitStatus counts the file states of a worktree and returns them with
+// the paths of conflicted files
+func (c *StatusCollector) collectGitStatus(ctx context.Context, g *git.Git) (*models.GitStatus, []string, error) {
 	status := &models.GitStatus{}
+	var conflictFiles []string
 
-	// Count modified, staged, and other file states
-	if err := c.countFileStates(ctx, g, status); err != nil {
-		return nil, err
+	clean, err := c.isClean(ctx, g)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Count untracked files separately for more accurate count
-	if err := c.countUntrackedFiles(ctx, g, status); err != nil {
-		// Non-fatal: continue even if we can't count untracked files
-		status.Untracked = 0
+	// A clean worktree has no files to count
+	if !clean {
+		// Count modified, staged, and other file states
+		conflictFiles, err = c.countFileStates(ctx, g, status)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Count untracked files separately for more accurate count
+		if err := c.countUntrackedFiles(ctx, g, status); err != nil {
+			// Non-fatal: continue even if we can't count untracked files
+			status.Untracked = 0
+		}
+	}
+
+	if !c.skipSubmodules {
+		// Non-fatal: repositories without submodules simply report none
+		if err := c.countSubmodules(ctx, g, status); err != nil {
+			status.SubmoduleModified = 0
+		}
 	}
 
 	if c.fetchRemote {
@@ -165,35 +283,54 @@ func (c *StatusCollector) collectGitStatus(ctx context.Context, g *git.Git) (*mo
 		_ = c.fetchRemoteStatus(ctx, g, status)
 	}
 
-	return status, nil
+	return status, conflictFiles, nil
+}
+
+// isClean reports whether the worktree has no changes, untracked files included
+func (c *StatusCollector) isClean(ctx context.Context, g *git.Git) (bool, error) {
+	gitCtx, cancel := c.gitContext(ctx)
+	defer cancel()
+
+	return g.IsCleanWithContext(gitCtx)
 }
 
 // countFileStates counts modified, staged, added, deleted, and conflicted files
-func (c *StatusCollector) countFileStates(ctx context.Context, g *git.Git, status *models.GitStatus) error {
-	gitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+// and returns the paths of the conflicted ones
+func (c *StatusCollector) countFileStates(ctx context.Context, g *git.Git, status *models.GitStatus) ([]string, error) {
+	gitCtx, cancel := c.gitContext(ctx)
 	defer cancel()
 
 	output, err := g.RunWithContext(gitCtx, "status", "--porcelain=v1", "-uno")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var conflictFiles []string
 	for _, line := range strings.Split(output, "\n") {
-		if len(line) < 3 {
+		if len(line) < 4 {
 			continue
 		}
 
-		c.processStatusLine(line, status)
+		if c.processStatusLine(line, status) {
+			conflictFiles = append(conflictFiles, statusLinePath(line))
+		}
 	}
 
-	return nil
+	return conflictFiles, nil
 }
 
-// processStatusLine processes a single line from git status output
-func (c *StatusCollector) processStatusLine(line string, status *models.GitStatus) {
+// processStatusLine processes a single line from git status output and
+// reports whether it is an unmerged (conflicted) path
+func (c *StatusCollector) processStatusLine(line string, status *models.GitStatus) bool {
 	index := line[0]
 	worktree := line[1]
 
+	// Unmerged paths are conflicts only, not staged or modified changes
+	if isUnmergedStatus(index, worktree) {
+		status.Conflicts++
+		return true
+	}
+
 	if index != ' ' && index != '?' {
 		status.Staged++
 	}
@@ -207,14 +344,29 @@ func (c *StatusCollector) processStatusLine(line string, status *models.GitStatu
 		status.Deleted++
 	case '?':
 		status.Untracked++
-	case 'U':
-		status.Conflicts++
 	}
+	return false
+}
+
+// isUnmergedStatus reports whether a porcelain XY code marks an unmerged
+// path: DD, AU, UD, UA, DU, AA or UU
+func isUnmergedStatus(index, worktree byte) bool {
+	return index == 'U' || worktree == 'U' || (index == 'A' && worktree == 'A') || (index == 'D' && worktree == 'D')
+}
+
+// statusLinePath returns the path of a porcelain v1 status line, unquoting
+// paths git quoted because of special characters
+func statusLinePath(line string) string {
+	path := line[3:]
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+	return path
 }
 
 // countUntrackedFiles counts untracked files using ls-files
 func (c *StatusCollector) countUntrackedFiles(ctx context.Context, g *git.Git, status *models.GitStatus) error {
-	gitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	gitCtx, cancel := c.gitContext(ctx)
 	defer cancel()
 
 	untrackedFiles, err := g.RunWithContext(gitCtx, "ls-files", "--others", "--exclude-standard")
@@ -229,6 +381,33 @@ func (c *StatusCollector) countUntrackedFiles(ctx context.Context, g *git.Git, s
 	return nil
 }
 
+// countSubmodules counts submodules that are not at the recorded commit (+)
+// or not initialized (-)
+func (c *StatusCollector) countSubmodules(ctx context.Context, g *git.Git, status *models.GitStatus) error {
+	gitCtx, cancel := c.gitContext(ctx)
+	defer cancel()
+
+	output, err := g.RunWithContext(gitCtx, "submodule", "status")
+	if err != nil {
+		return err
+	}
+
+	status.SubmoduleModified = countDirtySubmodules(output)
+	return nil
+}
+
+// countDirtySubmodules counts the lines of git submodule status output
+// marked with a leading + or -
+func countDirtySubmodules(output string) int {
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			count++
+		}
+	}
+	return count
+}
+
 func (c *StatusCollector) fetchRemoteStatus(ctx context.Context, g *git.Git, status *models.GitStatus) error {
 	// Get current branch and upstream
 	currentBranch, err := c.getCurrentBranch(ctx, g)
@@ -241,15 +420,39 @@ func (c *StatusCollector) fetchRemoteStatus(ctx context.Context, g *git.Git, sta
 		return err
 	}
 
+	if c.fetch {
+		// Best effort: offline machines still report counts against the last fetch
+		c.fetchUpstreamRemote(ctx, g, currentBranch)
+	}
+
 	// Count ahead/behind commits
 	c.countAheadBehind(ctx, g, upstream, status)
 
 	return nil
 }
 
+// fetchUpstreamRemote fetches the remote tracked by the current branch,
+// ignoring errors. Branches tracking a local branch are skipped.
+func (c *StatusCollector) fetchUpstreamRemote(ctx context.Context, g *git.Git, currentBranch string) {
+	gitCtx, cancel := c.gitContext(ctx)
+	defer cancel()
+
+	remote, err := g.RunWithContext(gitCtx, "config", "--get", "branch."+currentBranch+".remote")
+	if err != nil {
+		return
+	}
+
+	remote = strings.TrimSpace(remote)
+	if remote == "" || remote == "." {
+		return
+	}
+
+	_ = g.FetchWithContext(gitCtx, remote)
+}
+
 // getCurrentBranch gets the current branch name
 func (c *StatusCollector) getCurrentBranch(ctx context.Context, g *git.Git) (string, error) {
-	gitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	gitCtx, cancel := c.gitContext(ctx)
 	defer cancel()
 
 	currentBranch, err := g.RunWithContext(gitCtx, "rev-parse", "--abbrev-ref", "HEAD")
@@ -261,7 +464,7 @@ func (c *StatusCollector) getCurrentBranch(ctx context.Context, g *git.Git) (str
 
 // getUpstreamBranch gets the upstream branch for the current branch
 func (c *StatusCollector) getUpstreamBranch(ctx context.Context, g *git.Git, currentBranch string) (string, error) {
-	gitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	gitCtx, cancel := c.gitContext(ctx)
 	defer cancel()
 
 	upstream, err := g.RunWithContext(gitCtx, "rev-parse", "--abbrev-ref", currentBranch+"@{upstream}")
@@ -282,7 +485,7 @@ func (c *StatusCollector) countAheadBehind(ctx context.Context, g *git.Git, upst
 
 // countRevList counts commits in a revision range
 func (c *StatusCollector) countRevList(ctx context.Context, g *git.Git, revRange string) int {
-	gitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	gitCtx, cancel := c.gitContext(ctx)
 	defer cancel()
 
 	output, err := g.RunWithContext(gitCtx, "rev-list", "--count", revRange)
@@ -304,12 +507,18 @@ func (c *StatusCollector) determineWorktreeState(status *models.GitStatus) model
 	if status.Staged > 0 {
 		return models.WorktreeStatusStaged
 	}
-	if status.Modified > 0 || status.Added > 0 || status.Deleted > 0 || status.Untracked > 0 {
+	if hasWorkingTreeChanges(status) {
 		return models.WorktreeStatusModified
 	}
 	return models.WorktreeStatusClean
 }
 
+// hasWorkingTreeChanges reports whether files or submodules differ from HEAD
+func hasWorkingTreeChanges(status *models.GitStatus) bool {
+	return status.Modified > 0 || status.Added > 0 || status.Deleted > 0 ||
+		status.Untracked > 0 || status.SubmoduleModified > 0
+}
+
 func (c *StatusCollector) getLastActivity(path string) (time.Time, error) {
 	// Use git ls-files to get tracked files efficiently
 	// This approach respects .gitignore patterns automatically and is much faster
@@ -403,25 +612,6 @@ func (c *StatusCollector) getLastActivityFromUntrackedFiles(g *git.Git, path str
 func (c *StatusCollector) getLastActivityFallback(path string) (time.Time, error) {
 	var latestTime time.Time
 
-	// Common large directories to skip
-	skipDirs := map[string]bool{
-		".git":          true,
-		"node_modules":  true,
-		"vendor":        true,
-		".next":         true,
-		"dist":          true,
-		"build":         true,
-		"target":        true,
-		".cache":        true,
-		"coverage":      true,
-		"__pycache__":   true,
-		".pytest_cache": true,
-		".venv":         true,
-		"venv":          true,
-		".idea":         true,
-		".vscode":       true,
-	}
-
 	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Continue even if we can't access a file
@@ -430,7 +620,7 @@ func (c *StatusCollector) getLastActivityFallback(path string) (time.Time, error
 		// Skip directories
 		if info.IsDir() {
 			dirName := filepath.Base(p)
-			if skipDirs[dirName] {
+			if defaultSkipDirs[dirName] {
 				return filepath.SkipDir
 			}
 			// Also skip hidden directories (except the root)
@@ -453,6 +643,41 @@ func (c *StatusCollector) getLastActivityFallback(path string) (time.Time, error
 	return latestTime, nil
 }
 
+// diskUsage sums the sizes of the regular files under path, skipping the
+// collector's skip directories. Unreadable entries are ignored.
+func (c *StatusCollector) diskUsage(ctx context.Context, path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Continue even if we can't access a file
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if d.IsDir() {
+			if p != path && c.sizeSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil // Symlinks and special files take no space of their own
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
 func (c *StatusCollector) extractRepository(path string) string {
 	// Return basename if basedir is not set
 	if c.basedir == "" {
@@ -490,17 +715,3 @@ func (c *StatusCollector) extractRepository(path string) string {
 
 	return filepath.Base(path)
 }
-
-func (c *StatusCollector) collectProcesses(ctx context.Context, worktreePath string) ([]models.ProcessInfo, error) {
-	// TODO: Implement process detection for AI agents and other tools
-	// This would involve:
-	// 1. Scanning for processes with working directory in worktreePath
-	// 2. Identifying known AI agent processes (claude, copilot, cursor, etc.)
-	// 3. Detecting common development tools (npm, cargo, python, etc.)
-	// 4. Platform-specific process enumeration (ps on Unix, tasklist on Windows)
-	//
-	// For now, this is a stub that returns an empty slice.
-	// The actual implementation is deferred as it requires platform-specific code
-	// and careful consideration of performance implications.
-	return []models.ProcessInfo{}, nil
-}
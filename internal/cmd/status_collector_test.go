@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// runGit runs git in dir and fails the test on error
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "protocol.file.allow=always"}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// initRepoWithCommit creates a repository containing one committed file
+func initRepoWithCommit(t *testing.T, dir, file string) {
+	t.Helper()
+	runGit(t, dir, "init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(file+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", file, err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "add "+file)
+}
+
+func TestCountDirtySubmodules(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   int
+	}{
+		{name: "no submodules", output: "", want: 0},
+		{name: "clean", output: " 1234abcd lib (heads/main)", want: 0},
+		{
+			name:   "changed and uninitialized",
+			output: "+1234abcd lib (heads/main)\n-5678ef01 vendor/tool\n 9abcdef0 docs (heads/main)",
+			want:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countDirtySubmodules(tt.output); got != tt.want {
+				t.Errorf("countDirtySubmodules() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectGitStatusSubmodules(t *testing.T) {
+	lib := t.TempDir()
+	initRepoWithCommit(t, lib, "lib.txt")
+
+	repo := t.TempDir()
+	initRepoWithCommit(t, repo, "README.md")
+	runGit(t, repo, "submodule", "add", "-q", lib, "lib")
+	runGit(t, repo, "commit", "-q", "-m", "add submodule")
+
+	// Move the submodule checkout away from the recorded commit
+	sub := filepath.Join(repo, "lib")
+	if err := os.WriteFile(filepath.Join(sub, "more.txt"), []byte("more\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runGit(t, sub, "add", ".")
+	runGit(t, sub, "commit", "-q", "-m", "advance")
+
+	tests := []struct {
+		name string
+		skip bool
+		want int
+	}{
+		{name: "counted", want: 1},
+		{name: "skipped", skip: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewStatusCollectorWithOptions(StatusCollectorOptions{SkipSubmodules: tt.skip})
+			status, _, err := collector.collectGitStatus(context.Background(), git.New(repo))
+			if err != nil {
+				t.Fatalf("collectGitStatus() failed: %v", err)
+			}
+			if status.SubmoduleModified != tt.want {
+				t.Errorf("SubmoduleModified = %d, want %d", status.SubmoduleModified, tt.want)
+			}
+			if tt.want > 0 && collector.determineWorktreeState(status) != models.WorktreeStatusModified {
+				t.Errorf("determineWorktreeState() = %s, want %s", collector.determineWorktreeState(status), models.WorktreeStatusModified)
+			}
+		})
+	}
+}
+
+func TestCollectGitStatusFetch(t *testing.T) {
+	remote := t.TempDir()
+	initRepoWithCommit(t, remote, "README.md")
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, remote, "clone", "-q", remote, clone)
+
+	// Advance the remote after cloning so the clone is behind only once fetched
+	if err := os.WriteFile(filepath.Join(remote, "remote.txt"), []byte("remote\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runGit(t, remote, "add", ".")
+	runGit(t, remote, "commit", "-q", "-m", "remote commit")
+
+	tests := []struct {
+		name       string
+		fetch      bool
+		wantBehind int
+	}{
+		{name: "without fetch", fetch: false, wantBehind: 0},
+		{name: "with fetch", fetch: true, wantBehind: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewStatusCollectorWithOptions(StatusCollectorOptions{FetchRemote: true, Fetch: tt.fetch, SkipSubmodules: true})
+			status, _, err := collector.collectGitStatus(context.Background(), git.New(clone))
+			if err != nil {
+				t.Fatalf("collectGitStatus() failed: %v", err)
+			}
+			if status.Behind != tt.wantBehind {
+				t.Errorf("Behind = %d, want %d", status.Behind, tt.wantBehind)
+			}
+		})
+	}
+}
+
+func TestStatusCollectorGitTimeout(t *testing.T) {
+	repo := t.TempDir()
+	initRepoWithCommit(t, repo, "README.md")
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    models.WorktreeState
+	}{
+		{name: "expired timeout", timeout: time.Nanosecond, want: models.WorktreeStatusUnknown},
+		{name: "no timeout", timeout: 0, want: models.WorktreeStatusClean},
+		{name: "default timeout", timeout: defaultStatusGitTimeout, want: models.WorktreeStatusClean},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewStatusCollectorWithOptions(StatusCollectorOptions{GitTimeout: tt.timeout})
+
+			done := make(chan *models.WorktreeStatus, 1)
+			go func() {
+				status, err := collector.collectOne(context.Background(), &models.Worktree{Path: repo, Branch: "main"}, nil)
+				if err != nil {
+					t.Errorf("collectOne() failed: %v", err)
+				}
+				done <- status
+			}()
+
+			select {
+			case status := <-done:
+				if status != nil && status.Status != tt.want {
+					t.Errorf("Status = %s, want %s", status.Status, tt.want)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("collectOne() did not return")
+			}
+		})
+	}
+}
+
+func TestStatusCollectorStaleThreshold(t *testing.T) {
+	recent := t.TempDir()
+	initRepoWithCommit(t, recent, "README.md")
+
+	old := t.TempDir()
+	initRepoWithCommit(t, old, "README.md")
+	tenDaysAgo := time.Now().Add(-10 * 24 * time.Hour)
+	if err := os.Chtimes(filepath.Join(old, "README.md"), tenDaysAgo, tenDaysAgo); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+
+	worktrees := []*models.Worktree{
+		{Path: recent, Branch: "recent"},
+		{Path: old, Branch: "old"},
+	}
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		wantStale []string
+	}{
+		{name: "default threshold", threshold: 0, wantStale: nil},
+		{name: "one week", threshold: 7 * 24 * time.Hour, wantStale: []string{"old"}},
+		{name: "one day", threshold: 24 * time.Hour, wantStale: []string{"old"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewStatusCollectorWithOptions(StatusCollectorOptions{StaleThreshold: tt.threshold, SkipSubmodules: true})
+			statuses, err := collector.CollectAll(context.Background(), worktrees)
+			if err != nil {
+				t.Fatalf("CollectAll() failed: %v", err)
+			}
+
+			var stale []string
+			for _, s := range filterStatuses(statuses, "stale") {
+				stale = append(stale, s.Branch)
+			}
+			if len(stale) != len(tt.wantStale) || (len(stale) > 0 && stale[0] != tt.wantStale[0]) {
+				t.Errorf("stale worktrees = %v, want %v", stale, tt.wantStale)
+			}
+
+			// Worktrees that aren't stale keep their computed state
+			for _, s := range statuses {
+				if s.Branch == "recent" && s.Status != models.WorktreeStatusClean {
+					t.Errorf("recent worktree status = %s, want %s", s.Status, models.WorktreeStatusClean)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusCollectorSize(t *testing.T) {
+	dir := t.TempDir()
+	initRepoWithCommit(t, dir, "README.md") // "README.md\n" is 10 bytes
+
+	files := map[string]int{
+		"src/main.go":               100,
+		"src/deep/util.go":          50,
+		"node_modules/pkg/index.js": 1000,
+	}
+	for name, size := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	worktrees := []*models.Worktree{{Path: dir, Branch: "main"}}
+
+	tests := []struct {
+		name     string
+		opts     StatusCollectorOptions
+		wantSize *int64
+	}{
+		{name: "off by default", opts: StatusCollectorOptions{}},
+		{name: "skips node_modules", opts: StatusCollectorOptions{IncludeSize: true}, wantSize: ptrInt64(160)},
+		{name: "includes node_modules", opts: StatusCollectorOptions{IncludeSize: true, SizeIncludeDirs: []string{"node_modules"}}, wantSize: ptrInt64(1160)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.SkipSubmodules = true
+			statuses, err := NewStatusCollectorWithOptions(tt.opts).CollectAll(context.Background(), worktrees)
+			if err != nil {
+				t.Fatalf("CollectAll() failed: %v", err)
+			}
+
+			got := statuses[0].Size
+			switch {
+			case tt.wantSize == nil && got != nil:
+				t.Errorf("Size = %d, want not collected", *got)
+			case tt.wantSize != nil && got == nil:
+				t.Errorf("Size not collected, want %d", *tt.wantSize)
+			case tt.wantSize != nil && *got != *tt.wantSize:
+				t.Errorf("Size = %d, want %d", *got, *tt.wantSize)
+			}
+		})
+	}
+}
+
+func ptrInt64(n int64) *int64 {
+	return &n
+}
+
+func TestProcessStatusLineConflicts(t *testing.T) {
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{})
+	lines := []string{
+		"UU both-modified.go",
+		"AA both-added.go",
+		"DD both-deleted.go",
+		"UD deleted-by-them.go",
+		"M  staged.go",
+		" M modified.go",
+		`UU "with space.go"`,
+	}
+
+	var status models.GitStatus
+	var conflicted []string
+	for _, line := range lines {
+		if collector.processStatusLine(line, &status) {
+			conflicted = append(conflicted, statusLinePath(line))
+		}
+	}
+
+	want := []string{"both-modified.go", "both-added.go", "both-deleted.go", "deleted-by-them.go", "with space.go"}
+	if strings.Join(conflicted, ",") != strings.Join(want, ",") {
+		t.Errorf("conflicted files = %q, want %q", conflicted, want)
+	}
+	if status.Conflicts != len(want) || status.Staged != 1 || status.Modified != 1 || status.Added != 0 {
+		t.Errorf("status = %+v, want %d conflicts, 1 staged, 1 modified and nothing added", status, len(want))
+	}
+}
+
+func TestStatusCollectorConflictFiles(t *testing.T) {
+	repo := t.TempDir()
+	initRepoWithCommit(t, repo, "shared.txt")
+	runGit(t, repo, "checkout", "-q", "-b", "other")
+	if err := os.WriteFile(filepath.Join(repo, "shared.txt"), []byte("other\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runGit(t, repo, "commit", "-q", "-am", "other change")
+	runGit(t, repo, "checkout", "-q", "-")
+	if err := os.WriteFile(filepath.Join(repo, "shared.txt"), []byte("mine\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runGit(t, repo, "commit", "-q", "-am", "my change")
+
+	// The merge is expected to stop on the conflict
+	merge := exec.Command("git", "-C", repo, "merge", "-q", "other")
+	merge.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if err := merge.Run(); err == nil {
+		t.Fatal("merge should have conflicted")
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{SkipSubmodules: true})
+	statuses, err := collector.CollectAll(context.Background(), []*models.Worktree{{Path: repo, Branch: "main"}})
+	if err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+
+	s := statuses[0]
+	if s.Status != models.WorktreeStatusConflict || s.GitStatus.Conflicts != 1 {
+		t.Errorf("status = %s with %d conflicts, want conflict with 1", s.Status, s.GitStatus.Conflicts)
+	}
+	if len(s.ConflictFiles) != 1 || s.ConflictFiles[0] != "shared.txt" {
+		t.Errorf("ConflictFiles = %q, want [shared.txt]", s.ConflictFiles)
+	}
+	if got := formatConflictFiles(statuses); !strings.Contains(got, "Conflicts in main:\n  shared.txt\n") {
+		t.Errorf("formatConflictFiles() = %q", got)
+	}
+}
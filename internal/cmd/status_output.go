@@ -3,7 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -14,28 +14,85 @@ import (
 )
 
 // outputJSON outputs worktree statuses in JSON format.
-func outputJSON(statuses []*models.WorktreeStatus) error {
-	summary := calculateSummary(statuses)
+func outputJSON(w io.Writer, statuses []*models.WorktreeStatus) error {
+	if statuses == nil {
+		statuses = []*models.WorktreeStatus{} // Keep "worktrees" an array for consumers
+	}
 
 	output := struct {
 		Summary   statusSummary            `json:"summary"`
 		Worktrees []*models.WorktreeStatus `json:"worktrees"`
 	}{
-		Summary:   summary,
+		Summary:   calculateSummary(statuses),
 		Worktrees: statuses,
 	}
 
-	encoder := json.NewEncoder(os.Stdout)
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
 }
 
-// outputCSV outputs worktree statuses in CSV format.
-func outputCSV(statuses []*models.WorktreeStatus) error {
-	t := table.New().Headers(
-		"branch", "status", "modified", "added", "deleted",
-		"ahead", "behind", "last_activity", "process",
-	)
+// Output formats accepted by --format on the list and status commands.
+const (
+	outputFormatTable = "table"
+	outputFormatJSON  = "json"
+	outputFormatCSV   = "csv"
+	outputFormatTSV   = "tsv"
+)
+
+// resolveOutputFormat returns the output format selected by --format, or by
+// the older --json and --csv flags. Flags selecting different formats are
+// rejected rather than one silently winning.
+func resolveOutputFormat(format string, jsonFlag, csvFlag bool) (string, error) {
+	var flagFormat string
+	switch {
+	case jsonFlag && csvFlag:
+		return "", fmt.Errorf("--json and --csv cannot be used together")
+	case jsonFlag:
+		flagFormat = outputFormatJSON
+	case csvFlag:
+		flagFormat = outputFormatCSV
+	}
+	if flagFormat != "" {
+		if format != "" && format != flagFormat {
+			return "", fmt.Errorf("--%s conflicts with --format %s", flagFormat, format)
+		}
+		return flagFormat, nil
+	}
+
+	switch format {
+	case "":
+		return outputFormatTable, nil
+	case outputFormatTable, outputFormatJSON, outputFormatCSV, outputFormatTSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (supported: table, json, csv, tsv)", format)
+	}
+}
+
+// isDelimitedFormat reports whether format is written by outputDelimited.
+func isDelimitedFormat(format string) bool {
+	return format == outputFormatCSV || format == outputFormatTSV
+}
+
+// delimitedRenderer returns the renderer for a delimited format.
+func delimitedRenderer(format string) ui.CSVRenderer {
+	if format == outputFormatTSV {
+		return ui.CSVRenderer{Comma: '\t'}
+	}
+	return ui.CSVRenderer{}
+}
+
+// outputDelimited writes worktree statuses as CSV, or as TSV when format is
+// "tsv". Fields containing the delimiter, quotes or newlines are quoted.
+func outputDelimited(w io.Writer, statuses []*models.WorktreeStatus, format string) error {
+	t := ui.Table{
+		RecordHeaders: []string{
+			"branch", "path", "commit", "status", "modified", "added", "deleted",
+			"ahead", "behind", "last_activity", "process",
+		},
+		Records: make([][]string, 0, len(statuses)),
+	}
 
 	for _, s := range statuses {
 		process := ""
@@ -47,36 +104,57 @@ func outputCSV(statuses []*models.WorktreeStatus) error {
 			process = strings.Join(processes, ",")
 		}
 
-		t.Row(
+		lastActivity := ""
+		if !s.LastActivity.IsZero() {
+			lastActivity = s.LastActivity.Format(time.RFC3339)
+		}
+
+		t.Records = append(t.Records, []string{
 			s.Branch,
+			s.Path,
+			s.CommitHash,
 			string(s.Status),
 			strconv.Itoa(s.GitStatus.Modified),
 			strconv.Itoa(s.GitStatus.Added),
 			strconv.Itoa(s.GitStatus.Deleted),
 			strconv.Itoa(s.GitStatus.Ahead),
 			strconv.Itoa(s.GitStatus.Behind),
-			s.LastActivity.Format(time.RFC3339),
+			lastActivity,
 			process,
-		)
+		})
 	}
 
-	return t.WriteCSV()
+	return delimitedRenderer(format).Render(w, t)
 }
 
 // outputTable outputs worktree statuses in table format.
 func outputTable(statuses []*models.WorktreeStatus, printer *ui.Printer, verbose bool) error {
+	return outputTableWithChanges(statuses, printer, verbose, nil)
+}
+
+// outputTableWithChanges outputs worktree statuses in table format, marking
+// the status of worktrees whose path is in changed.
+func outputTableWithChanges(statuses []*models.WorktreeStatus, printer *ui.Printer, verbose bool, changed map[string]bool) error {
 	if len(statuses) == 0 {
 		fmt.Println("No worktrees found")
 		return nil
 	}
 
-	var t *table.Builder
+	showSize := hasSizes(statuses)
+	headers := []string{"BRANCH", "STATUS", "CHANGES"}
+	if verbose {
+		headers = append(headers, "AHEAD/BEHIND")
+	}
+	if showSize {
+		headers = append(headers, "SIZE")
+	}
+	headers = append(headers, "ACTIVITY")
 	if verbose {
-		t = table.New().Headers("BRANCH", "STATUS", "CHANGES", "AHEAD/BEHIND", "ACTIVITY", "PROCESS")
-	} else {
-		t = table.New().Headers("BRANCH", "STATUS", "CHANGES", "ACTIVITY")
+		headers = append(headers, "PROCESS")
 	}
+	t := table.New().Headers(headers...)
 
+	marked := false
 	for _, s := range statuses {
 		// Apply marker for current worktree, with consistent spacing
 		var branchWithMarker string
@@ -87,19 +165,50 @@ func outputTable(statuses []*models.WorktreeStatus, printer *ui.Printer, verbose
 		}
 
 		status := formatStatusNoColor(s.Status)
-		changes := formatChanges(s.GitStatus)
-		activity := formatActivity(s.LastActivity)
-
+		if changed[s.Path] {
+			status += " *"
+			marked = true
+		}
+		row := []string{branchWithMarker, status, formatChanges(s.GitStatus)}
 		if verbose {
-			aheadBehind := formatAheadBehind(s.GitStatus.Ahead, s.GitStatus.Behind)
-			process := formatProcess(s.ActiveProcess)
-			t.Row(branchWithMarker, status, changes, aheadBehind, activity, process)
-		} else {
-			t.Row(branchWithMarker, status, changes, activity)
+			row = append(row, formatAheadBehind(s.GitStatus.Ahead, s.GitStatus.Behind))
+		}
+		if showSize {
+			row = append(row, formatSize(s.Size))
+		}
+		row = append(row, formatActivity(s.LastActivity))
+		if verbose {
+			row = append(row, formatProcess(s.ActiveProcess))
 		}
+		t.Row(row...)
+	}
+
+	if err := t.Println(); err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Print(formatConflictFiles(statuses))
+	}
+	if marked {
+		fmt.Println("\n* changed since last refresh")
 	}
+	return nil
+}
 
-	return t.Println()
+// formatConflictFiles lists the conflicted files of each worktree that has
+// any, for the verbose table
+func formatConflictFiles(statuses []*models.WorktreeStatus) string {
+	var b strings.Builder
+	for _, s := range statuses {
+		if len(s.ConflictFiles) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\nConflicts in %s:\n", s.Branch)
+		for _, file := range s.ConflictFiles {
+			fmt.Fprintf(&b, "  %s\n", file)
+		}
+	}
+	return b.String()
 }
 
 func formatStatusNoColor(status models.WorktreeState) string {
@@ -120,7 +229,7 @@ func formatStatusNoColor(status models.WorktreeState) string {
 }
 
 func formatChanges(gs models.GitStatus) string {
-	if gs.Modified == 0 && gs.Added == 0 && gs.Deleted == 0 && gs.Untracked == 0 {
+	if !hasWorkingTreeChanges(&gs) {
 		return "-"
 	}
 
@@ -137,6 +246,9 @@ func formatChanges(gs models.GitStatus) string {
 	if gs.Untracked > 0 {
 		parts = append(parts, fmt.Sprintf("%d untracked", gs.Untracked))
 	}
+	if gs.SubmoduleModified > 0 {
+		parts = append(parts, fmt.Sprintf("%d submodules", gs.SubmoduleModified))
+	}
 
 	return strings.Join(parts, ", ")
 }
@@ -187,6 +299,36 @@ func formatActivity(lastActivity time.Time) string {
 	}
 }
 
+// hasSizes reports whether disk usage was collected for any worktree.
+func hasSizes(statuses []*models.WorktreeStatus) bool {
+	for _, s := range statuses {
+		if s.Size != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSize formats a disk usage in bytes with binary units, e.g. "1.5 MB".
+func formatSize(size *int64) string {
+	if size == nil {
+		return "-"
+	}
+
+	const unit = 1024
+	suffixes := []string{"B", "KB", "MB", "GB", "TB"}
+	value := float64(*size)
+	i := 0
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", *size)
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
+
 func formatProcess(processes []models.ProcessInfo) string {
 	if len(processes) == 0 {
 		return "-"
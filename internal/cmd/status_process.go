@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/logging"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// Process types reported in models.ProcessInfo.Type.
+const (
+	processTypeAIAgent = "ai_agent"
+	processTypeDevTool = "dev_tool"
+)
+
+// processEntry is a recognized process together with its working directory.
+type processEntry struct {
+	pid  int
+	name string
+	kind string
+	cwd  string
+}
+
+// processSnapshot is the list of recognized processes taken once per status
+// run, so the process table is read once rather than for every worktree.
+type processSnapshot struct {
+	entries []processEntry
+}
+
+// snapshotProcesses lists the recognized processes. Processes that cannot be
+// inspected are skipped so that partial results are still reported; it
+// returns nil when nothing could be listed.
+func snapshotProcesses(ctx context.Context) *processSnapshot {
+	entries, err := listProcesses(ctx)
+	if err != nil && len(entries) == 0 {
+		logging.Debugf("failed to list processes: %v", err)
+		return nil
+	}
+	return &processSnapshot{entries: entries}
+}
+
+// within returns the processes, other than gwq itself, whose working
+// directory is inside worktreePath.
+func (s *processSnapshot) within(worktreePath string) []models.ProcessInfo {
+	root := worktreePath
+	if resolved, err := filepath.EvalSymlinks(worktreePath); err == nil {
+		root = resolved
+	}
+
+	processes := []models.ProcessInfo{}
+	for _, p := range s.entries {
+		if p.pid == os.Getpid() || !isPathWithin(p.cwd, root) {
+			continue
+		}
+		processes = append(processes, models.ProcessInfo{
+			PID:     p.pid,
+			Command: p.name,
+			Type:    p.kind,
+		})
+	}
+	return processes
+}
+
+// classifyProcess returns the process type for a known command name, or an
+// empty string if the process is not one gwq reports.
+func classifyProcess(command string) string {
+	name := filepath.Base(strings.TrimSpace(command))
+
+	switch name {
+	case "claude":
+		return processTypeAIAgent
+	case "node", "cargo", "go":
+		return processTypeDevTool
+	}
+
+	// python, python3, python3.12, ...
+	if strings.HasPrefix(name, "python") {
+		return processTypeDevTool
+	}
+
+	return ""
+}
+
+// isPathWithin reports whether path is root or a descendant of root.
+func isPathWithin(path, root string) bool {
+	if path == "" || root == "" {
+		return false
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
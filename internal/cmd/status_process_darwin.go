@@ -0,0 +1,84 @@
+//go:build darwin
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// listProcesses enumerates recognized processes using ps and resolves their
+// working directories with lsof.
+func listProcesses(ctx context.Context) ([]processEntry, error) {
+	out, err := exec.CommandContext(ctx, "ps", "-axo", "pid=,comm=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	candidates := make(map[int]processEntry)
+	var pids []string
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		name := filepath.Base(strings.Join(fields[1:], " "))
+		kind := classifyProcess(name)
+		if kind == "" {
+			continue
+		}
+
+		candidates[pid] = processEntry{pid: pid, name: name, kind: kind}
+		pids = append(pids, fields[0])
+	}
+
+	if len(pids) == 0 {
+		return nil, nil
+	}
+
+	// lsof exits non-zero when some PIDs cannot be inspected, so keep whatever
+	// output it produced
+	out, err = exec.CommandContext(ctx, "lsof", "-a", "-d", "cwd", "-p", strings.Join(pids, ","), "-Fn").Output()
+	if len(out) == 0 && err != nil {
+		return nil, fmt.Errorf("failed to resolve process working directories: %w", err)
+	}
+
+	var processes []processEntry
+	currentPID := -1
+	scanner = bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < 2 {
+			continue
+		}
+
+		switch line[0] {
+		case 'p':
+			pid, err := strconv.Atoi(line[1:])
+			if err != nil {
+				currentPID = -1
+				continue
+			}
+			currentPID = pid
+		case 'n':
+			if p, ok := candidates[currentPID]; ok {
+				p.cwd = line[1:]
+				processes = append(processes, p)
+			}
+		}
+	}
+
+	return processes, nil
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// listProcesses enumerates recognized processes by reading /proc.
+func listProcesses(ctx context.Context) ([]processEntry, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read /proc: %w", err)
+	}
+
+	var processes []processEntry
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return processes, ctx.Err()
+		}
+
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		procDir := filepath.Join("/proc", entry.Name())
+		comm, err := os.ReadFile(filepath.Join(procDir, "comm"))
+		if err != nil {
+			continue // Process exited or is not readable
+		}
+
+		name := strings.TrimSpace(string(comm))
+		kind := classifyProcess(name)
+		if kind == "" {
+			continue
+		}
+
+		cwd, err := os.Readlink(filepath.Join(procDir, "cwd"))
+		if err != nil {
+			continue // Typically a permission error for other users' processes
+		}
+
+		processes = append(processes, processEntry{pid: pid, name: name, kind: kind, cwd: cwd})
+	}
+
+	return processes, nil
+}
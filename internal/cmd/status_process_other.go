@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package cmd
+
+import "context"
+
+// listProcesses is not supported on this platform and reports no processes.
+func listProcesses(ctx context.Context) ([]processEntry, error) {
+	return nil, nil
+}
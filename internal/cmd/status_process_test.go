@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestClassifyProcess(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"claude", processTypeAIAgent},
+		{"/usr/local/bin/node", processTypeDevTool},
+		{"cargo", processTypeDevTool},
+		{"python3.12", processTypeDevTool},
+		{"go", processTypeDevTool},
+		{"bash", ""},
+		{"gopls", ""},
+	}
+
+	for _, tt := range tests {
+		if got := classifyProcess(tt.command); got != tt.want {
+			t.Errorf("classifyProcess(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestIsPathWithin(t *testing.T) {
+	root := filepath.Join("/home", "user", "worktrees", "feature")
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"same directory", root, true},
+		{"subdirectory", filepath.Join(root, "src", "pkg"), true},
+		{"sibling with shared prefix", root + "-2", false},
+		{"parent", filepath.Dir(root), false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPathWithin(tt.path, root); got != tt.want {
+				t.Errorf("isPathWithin(%q, %q) = %v, want %v", tt.path, root, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessSnapshotWithin(t *testing.T) {
+	base := t.TempDir()
+	feature := filepath.Join(base, "feature")
+	other := filepath.Join(base, "feature-2")
+	for _, dir := range []string{feature, other} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resolved := func(path string) string {
+		t.Helper()
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return real
+	}
+
+	snapshot := &processSnapshot{entries: []processEntry{
+		{pid: 100, name: "claude", kind: processTypeAIAgent, cwd: resolved(feature)},
+		{pid: 101, name: "node", kind: processTypeDevTool, cwd: filepath.Join(resolved(feature), "web")},
+		{pid: 102, name: "go", kind: processTypeDevTool, cwd: resolved(other)},
+		{pid: os.Getpid(), name: "go", kind: processTypeDevTool, cwd: resolved(feature)},
+	}}
+
+	// One snapshot serves every worktree
+	want := []models.ProcessInfo{
+		{PID: 100, Command: "claude", Type: processTypeAIAgent},
+		{PID: 101, Command: "node", Type: processTypeDevTool},
+	}
+	if got := snapshot.within(feature); !reflect.DeepEqual(got, want) {
+		t.Errorf("within(feature) = %+v, want %+v", got, want)
+	}
+	want = []models.ProcessInfo{{PID: 102, Command: "go", Type: processTypeDevTool}}
+	if got := snapshot.within(other); !reflect.DeepEqual(got, want) {
+		t.Errorf("within(feature-2) = %+v, want %+v", got, want)
+	}
+	if got := snapshot.within(filepath.Join(base, "missing")); len(got) != 0 {
+		t.Errorf("within(missing) = %+v, want no processes", got)
+	}
+}
@@ -1,6 +1,13 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -36,6 +43,16 @@ func TestCalculateSummary(t *testing.T) {
 			},
 			want: statusSummary{Total: 2, Modified: 0, Clean: 2, Stale: 0},
 		},
+		{
+			name: "every state",
+			statuses: []*models.WorktreeStatus{
+				{Status: models.WorktreeStatusStaged},
+				{Status: models.WorktreeStatusConflict},
+				{Status: models.WorktreeStatusUnknown},
+				{Status: models.WorktreeStatusUnknown},
+			},
+			want: statusSummary{Total: 4, Staged: 1, Conflict: 1, Unknown: 2},
+		},
 	}
 
 	for _, tt := range tests {
@@ -93,6 +110,58 @@ func TestFilterStatuses(t *testing.T) {
 	}
 }
 
+func TestApplyFiltersAndSortStaleOnly(t *testing.T) {
+	statuses := []*models.WorktreeStatus{
+		{Branch: "main", Status: models.WorktreeStatusClean},
+		{Branch: "feature", Status: models.WorktreeStatusModified},
+		{Branch: "old", Status: models.WorktreeStatusStale},
+		{Branch: "older", Status: models.WorktreeStatusStale},
+	}
+
+	oldStaleOnly, oldFilter, oldSort := statusStaleOnly, statusFilter, statusSort
+	t.Cleanup(func() { statusStaleOnly, statusFilter, statusSort = oldStaleOnly, oldFilter, oldSort })
+
+	statusStaleOnly, statusFilter, statusSort = true, "", ""
+	got := applyFiltersAndSort(statuses)
+	if len(got) != 2 || got[0].Branch != "old" || got[1].Branch != "older" {
+		t.Errorf("applyFiltersAndSort() = %v, want the two stale worktrees", got)
+	}
+
+	statusStaleOnly = false
+	if got := applyFiltersAndSort(statuses); len(got) != len(statuses) {
+		t.Errorf("applyFiltersAndSort() without --stale-only returned %d items, want %d", len(got), len(statuses))
+	}
+}
+
+func TestValidateStaleFlags(t *testing.T) {
+	oldStaleOnly, oldFilter, oldDays := statusStaleOnly, statusFilter, statusStaleDays
+	t.Cleanup(func() { statusStaleOnly, statusFilter, statusStaleDays = oldStaleOnly, oldFilter, oldDays })
+
+	tests := []struct {
+		name      string
+		staleOnly bool
+		filter    string
+		days      int
+		wantErr   bool
+	}{
+		{name: "defaults", days: 14},
+		{name: "stale only", staleOnly: true, days: 7},
+		{name: "stale only with stale filter", staleOnly: true, filter: "inactive", days: 7},
+		{name: "stale only with other filter", staleOnly: true, filter: "modified", days: 7, wantErr: true},
+		{name: "zero days", days: 0, wantErr: true},
+		{name: "negative days", days: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusStaleOnly, statusFilter, statusStaleDays = tt.staleOnly, tt.filter, tt.days
+			if err := validateStaleFlags(); (err != nil) != tt.wantErr {
+				t.Errorf("validateStaleFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestFormatActivity(t *testing.T) {
 	now := time.Now()
 
@@ -222,6 +291,13 @@ func TestFormatChanges(t *testing.T) {
 			},
 			expected: "5 added, 3 modified, 2 deleted, 1 untracked",
 		},
+		{
+			name: "only submodules",
+			status: models.GitStatus{
+				SubmoduleModified: 2,
+			},
+			expected: "2 submodules",
+		},
 	}
 
 	for _, tt := range tests {
@@ -314,3 +390,230 @@ func TestFormatProcess(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     *int64
+		expected string
+	}{
+		{name: "not collected", size: nil, expected: "-"},
+		{name: "bytes", size: ptrInt64(512), expected: "512 B"},
+		{name: "kilobytes", size: ptrInt64(1536), expected: "1.5 KB"},
+		{name: "megabytes", size: ptrInt64(10 << 20), expected: "10.0 MB"},
+		{name: "terabytes cap", size: ptrInt64(2048 << 40), expected: "2048.0 TB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatSize(tt.size)
+			if got != tt.expected {
+				t.Errorf("formatSize() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOutputDelimited(t *testing.T) {
+	activity := time.Date(2024, 1, 15, 10, 20, 0, 0, time.UTC)
+	statuses := []*models.WorktreeStatus{
+		{
+			Branch:       "main",
+			Path:         "/src/app",
+			CommitHash:   "1a2b3c4d",
+			Status:       models.WorktreeStatusClean,
+			LastActivity: activity,
+		},
+		{
+			Branch:        "feature/a,b",
+			Path:          "/worktrees/app/feature-a-b",
+			CommitHash:    "5e6f7a8b",
+			Status:        models.WorktreeStatusModified,
+			GitStatus:     models.GitStatus{Modified: 5, Added: 3, Ahead: 2, Behind: 1},
+			LastActivity:  activity,
+			ActiveProcess: []models.ProcessInfo{{Command: "claude", PID: 8923}, {Command: "cursor", PID: 9102}},
+		},
+	}
+
+	header := []string{
+		"branch", "path", "commit", "status", "modified", "added", "deleted",
+		"ahead", "behind", "last_activity", "process",
+	}
+	wantRows := [][]string{
+		header,
+		{"main", "/src/app", "1a2b3c4d", "clean", "0", "0", "0", "0", "0", "2024-01-15T10:20:00Z", ""},
+		{"feature/a,b", "/worktrees/app/feature-a-b", "5e6f7a8b", "modified", "5", "3", "0", "2", "1", "2024-01-15T10:20:00Z", "claude:8923,cursor:9102"},
+	}
+
+	tests := []struct {
+		format    string
+		delimiter rune
+	}{
+		{format: outputFormatCSV, delimiter: ','},
+		{format: outputFormatTSV, delimiter: '\t'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := outputDelimited(&buf, statuses, tt.format); err != nil {
+				t.Fatalf("outputDelimited() error = %v", err)
+			}
+
+			reader := csv.NewReader(bytes.NewReader(buf.Bytes()))
+			reader.Comma = tt.delimiter
+			rows, err := reader.ReadAll()
+			if err != nil {
+				t.Fatalf("output is not valid %s: %v\n%s", tt.format, err, buf.String())
+			}
+			if len(rows) != len(wantRows) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(wantRows))
+			}
+			for i := range wantRows {
+				if strings.Join(rows[i], "|") != strings.Join(wantRows[i], "|") {
+					t.Errorf("row %d = %q, want %q", i, rows[i], wantRows[i])
+				}
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := outputDelimited(&buf, statuses, outputFormatCSV); err != nil {
+		t.Fatalf("outputDelimited() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"feature/a,b"`) {
+		t.Errorf("branch containing a comma should be quoted:\n%s", buf.String())
+	}
+}
+
+func TestResolveOutputFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		jsonFlag bool
+		csvFlag  bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "default", want: outputFormatTable},
+		{name: "tsv", format: "tsv", want: outputFormatTSV},
+		{name: "json flag", jsonFlag: true, want: outputFormatJSON},
+		{name: "json flag matching format", format: "json", jsonFlag: true, want: outputFormatJSON},
+		{name: "json flag conflicting with format", format: "tsv", jsonFlag: true, wantErr: true},
+		{name: "csv flag", csvFlag: true, want: outputFormatCSV},
+		{name: "csv flag conflicting with format", format: "table", csvFlag: true, wantErr: true},
+		{name: "json and csv flags", jsonFlag: true, csvFlag: true, wantErr: true},
+		{name: "unknown", format: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveOutputFormat(tt.format, tt.jsonFlag, tt.csvFlag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveOutputFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveOutputFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputJSON(t *testing.T) {
+	clean := t.TempDir()
+	initRepoWithCommit(t, clean, "README.md")
+
+	modified := t.TempDir()
+	initRepoWithCommit(t, modified, "README.md")
+	if err := os.WriteFile(filepath.Join(modified, "README.md"), []byte("changed\n"), 0644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+
+	tenDaysAgo := time.Now().Add(-10 * 24 * time.Hour)
+	stale := t.TempDir()
+	initRepoWithCommit(t, stale, "README.md")
+	if err := os.Chtimes(filepath.Join(stale, "README.md"), tenDaysAgo, tenDaysAgo); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+
+	// Not a git repository, so its status can't be determined
+	unknown := t.TempDir()
+	if err := os.Chtimes(unknown, tenDaysAgo, tenDaysAgo); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{StaleThreshold: 7 * 24 * time.Hour, SkipSubmodules: true})
+	statuses, err := collector.CollectAll(context.Background(), []*models.Worktree{
+		{Path: clean, Branch: "clean"},
+		{Path: modified, Branch: "modified"},
+		{Path: stale, Branch: "stale"},
+		{Path: unknown, Branch: "unknown"},
+	})
+	if err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := outputJSON(&buf, statuses); err != nil {
+		t.Fatalf("outputJSON() error = %v", err)
+	}
+
+	var got struct {
+		Summary   map[string]int               `json:"summary"`
+		Worktrees []map[string]json.RawMessage `json:"worktrees"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	wantSummary := map[string]int{"Total": 4, "Clean": 1, "Modified": 1, "Staged": 0, "Conflict": 0, "Stale": 1, "Unknown": 1}
+	for key, want := range wantSummary {
+		if count, ok := got.Summary[key]; !ok || count != want {
+			t.Errorf("summary[%s] = %d (present %v), want %d", key, count, ok, want)
+		}
+	}
+
+	wantStates := map[string]struct {
+		status string
+		stale  bool
+	}{
+		"clean":    {"clean", false},
+		"modified": {"modified", false},
+		"stale":    {"stale", true},
+		"unknown":  {"unknown", true},
+	}
+	if len(got.Worktrees) != len(wantStates) {
+		t.Fatalf("got %d worktrees, want %d", len(got.Worktrees), len(wantStates))
+	}
+	for _, wt := range got.Worktrees {
+		for _, key := range []string{"path", "branch", "status", "git_status", "last_activity", "stale"} {
+			if _, ok := wt[key]; !ok {
+				t.Errorf("worktree %s is missing %q", wt["branch"], key)
+			}
+		}
+
+		var branch, status string
+		var isStale bool
+		var gitStatus map[string]int
+		_ = json.Unmarshal(wt["branch"], &branch)
+		_ = json.Unmarshal(wt["status"], &status)
+		_ = json.Unmarshal(wt["stale"], &isStale)
+		if err := json.Unmarshal(wt["git_status"], &gitStatus); err != nil {
+			t.Errorf("git_status of %s: %v", branch, err)
+		}
+		for _, key := range []string{"ahead", "behind", "conflicts"} {
+			if _, ok := gitStatus[key]; !ok {
+				t.Errorf("git_status of %s is missing %q", branch, key)
+			}
+		}
+
+		want, ok := wantStates[branch]
+		if !ok {
+			t.Errorf("unexpected worktree %s", branch)
+			continue
+		}
+		if status != want.status || isStale != want.stale {
+			t.Errorf("%s: status = %s, stale = %v, want %s, %v", branch, status, isStale, want.status, want.stale)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// statusSnapshot is the part of a worktree status compared between watch ticks.
+type statusSnapshot struct {
+	state     models.WorktreeState
+	gitStatus models.GitStatus
+}
+
+// statusWatcher re-collects worktree statuses with a long-lived collector and
+// reports which worktrees changed since the previous collection.
+type statusWatcher struct {
+	collector *StatusCollector
+	list      func() ([]*models.Worktree, error)
+	previous  map[string]statusSnapshot // Keyed by worktree path; nil before the first tick
+}
+
+// newStatusWatcher creates a watcher that lists worktrees with list on every tick.
+func newStatusWatcher(collector *StatusCollector, list func() ([]*models.Worktree, error)) *statusWatcher {
+	return &statusWatcher{
+		collector: collector,
+		list:      list,
+	}
+}
+
+// tick collects the current statuses and returns them with the set of
+// worktree paths whose status differs from the previous tick. Nothing is
+// reported as changed on the first tick.
+func (w *statusWatcher) tick(ctx context.Context) ([]*models.WorktreeStatus, map[string]bool, error) {
+	worktrees, err := w.list()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statuses, err := w.collector.CollectAll(ctx, worktrees)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changed := make(map[string]bool)
+	current := make(map[string]statusSnapshot, len(statuses))
+	for _, s := range statuses {
+		snapshot := statusSnapshot{state: s.Status, gitStatus: s.GitStatus}
+		current[s.Path] = snapshot
+
+		if w.previous != nil {
+			if prev, ok := w.previous[s.Path]; !ok || prev != snapshot {
+				changed[s.Path] = true
+			}
+		}
+	}
+	w.previous = current
+
+	return statuses, changed, nil
+}
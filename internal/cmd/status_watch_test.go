@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestStatusWatcherDetectsChanges(t *testing.T) {
+	repo := t.TempDir()
+	initRepoWithCommit(t, repo, "README.md")
+	file := filepath.Join(repo, "README.md")
+
+	worktrees := []*models.Worktree{{Path: repo, Branch: "main", IsMain: true}}
+	watcher := newStatusWatcher(NewStatusCollector(false, false), func() ([]*models.Worktree, error) {
+		return worktrees, nil
+	})
+	ctx := context.Background()
+
+	statuses, changed, err := watcher.tick(ctx)
+	if err != nil {
+		t.Fatalf("first tick failed: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Status != models.WorktreeStatusClean {
+		t.Fatalf("first tick statuses = %+v, want one clean worktree", statuses)
+	}
+	if len(changed) != 0 {
+		t.Errorf("first tick changed = %v, want none", changed)
+	}
+
+	if err := os.WriteFile(file, []byte("hello, world\n"), 0644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+
+	statuses, changed, err = watcher.tick(ctx)
+	if err != nil {
+		t.Fatalf("second tick failed: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Status != models.WorktreeStatusModified {
+		t.Fatalf("second tick statuses = %+v, want one modified worktree", statuses)
+	}
+	if !changed[repo] {
+		t.Errorf("second tick changed = %v, want %s marked", changed, repo)
+	}
+
+	// A third tick without further edits reports no changes
+	if _, changed, err = watcher.tick(ctx); err != nil {
+		t.Fatalf("third tick failed: %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("third tick changed = %v, want none", changed)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/d-kuro/gwq/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -33,9 +34,22 @@ All commands should be executed from the git repository root directory.`,
   gwq task worker status
 
   # View task details
-  gwq task show task-id`,
+  gwq task show task-id
+
+  # Keep execution logs with the project instead of in ~/.config
+  gwq task worker start --output-dir ./.gwq-logs`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if taskOutputDir != "" {
+			config.Override("claude.log_dir", taskOutputDir)
+		}
+	},
 }
 
+// taskOutputDir overrides claude.log_dir for a single invocation
+var taskOutputDir string
+
 func init() {
 	rootCmd.AddCommand(taskCmd)
+
+	taskCmd.PersistentFlags().StringVar(&taskOutputDir, "output-dir", "", "Directory for execution logs (overrides claude.log_dir)")
 }
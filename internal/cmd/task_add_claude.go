@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/d-kuro/gwq/internal/claude"
 	"github.com/d-kuro/gwq/internal/claude/presenters"
@@ -25,19 +31,43 @@ Tasks support:
 - Dependencies on other tasks
 - Detailed context and instructions
 - Verification commands to ensure success
-- Custom configuration options`,
+- Custom configuration options
+
+A task is refused when a pending or running task for the same worktree has a
+similar prompt (see claude.queue.duplicate_similarity). Use --allow-duplicate
+to enqueue it anyway with a warning.`,
 	Example: `  # Basic task (creates worktree from current branch if needed)
   gwq task add claude -w feature/auth "Implement JWT authentication"
 
   # Task with specific base branch for worktree creation
   gwq task add claude -w feature/api --base develop "REST API endpoints" -p 80
 
+  # Task pinned to a specific Claude model
+  gwq task add claude -w feature/docs "Update API docs" --model sonnet
+
+  # Tagged task, listable with: gwq task list --tag backend
+  gwq task add claude -w feature/cache "Add response caching" --tag backend
+
+  # Custom environment, with the token redacted in execution logs
+  gwq task add claude -w feature/api "Migrate API client" \
+    --env API_BASE_URL=https://staging.example.com --secret-env API_TOKEN=xyz
+
+  # Retry a flaky task up to 2 more times, waiting 1m then 2m
+  gwq task add claude -w feature/e2e "Fix e2e tests" --max-retries 2 --retry-backoff 1m
+
   # Task with dependencies and detailed prompt
   gwq task add claude -w feature/tests "Add comprehensive tests" \
     --depends-on api-endpoints \
     --prompt "Add comprehensive unit tests. Target 90% coverage. Focus on error handling." \
     --verify "make test" \
-    --verify "make coverage"`,
+    --verify "make coverage"
+
+  # Enqueue the same work again on purpose
+  gwq task add claude -w feature/auth "Implement JWT authentication" --allow-duplicate
+
+  # Multi-line prompt from a file, or from stdin with -
+  gwq task add claude -w feature/refactor "Refactor storage" --prompt-file prompt.md
+  cat prompt.md | gwq task add claude -w feature/refactor "Refactor storage" --prompt-file -`,
 	Args: cobra.RangeArgs(0, 1),
 	RunE: runTaskAddClaude,
 }
@@ -49,10 +79,19 @@ var (
 	taskAddClaudePriority     int
 	taskAddClaudeDependsOn    []string
 	taskAddClaudePrompt       string
+	taskAddClaudePromptFile   string
 	taskAddClaudeFilesToFocus []string
 	taskAddClaudeVerify       []string
 	taskAddClaudeAutoCommit   bool
 	taskAddClaudeFile         string
+	taskAddClaudeModel        string
+	taskAddClaudeTags         []string
+	taskAddClaudeEnv          []string
+	taskAddClaudeSecretEnv    []string
+	taskAddClaudeMaxRetries   int
+	taskAddClaudeRetryBackoff time.Duration
+	taskAddClaudeMaxCost      float64
+	taskAddClaudeAllowDup     bool
 )
 
 func init() {
@@ -64,10 +103,19 @@ func init() {
 	taskAddClaudeCmd.Flags().IntVarP(&taskAddClaudePriority, "priority", "p", 50, "Task priority (1-100, higher = more important)")
 	taskAddClaudeCmd.Flags().StringSliceVar(&taskAddClaudeDependsOn, "depends-on", nil, "Task IDs this task depends on")
 	taskAddClaudeCmd.Flags().StringVar(&taskAddClaudePrompt, "prompt", "", "Complete task prompt for Claude")
+	taskAddClaudeCmd.Flags().StringVar(&taskAddClaudePromptFile, "prompt-file", "", "Read the task prompt from a file (- for stdin)")
 	taskAddClaudeCmd.Flags().StringSliceVar(&taskAddClaudeFilesToFocus, "files", nil, "Key files to focus on")
 	taskAddClaudeCmd.Flags().StringSliceVar(&taskAddClaudeVerify, "verify", nil, "Commands to verify task completion")
 	taskAddClaudeCmd.Flags().BoolVar(&taskAddClaudeAutoCommit, "auto-commit", false, "Enable automatic commits")
 	taskAddClaudeCmd.Flags().StringVarP(&taskAddClaudeFile, "file", "f", "", "Load tasks from YAML file")
+	taskAddClaudeCmd.Flags().IntVar(&taskAddClaudeMaxRetries, "max-retries", 0, "Number of times to retry the task after a failure")
+	taskAddClaudeCmd.Flags().DurationVar(&taskAddClaudeRetryBackoff, "retry-backoff", claude.DefaultRetryBackoff, "Delay before the first retry (doubled for each further retry)")
+	taskAddClaudeCmd.Flags().Float64Var(&taskAddClaudeMaxCost, "max-cost", 0, "Abort the task once it costs more than this many USD (0 = claude.max_cost_usd)")
+	taskAddClaudeCmd.Flags().StringSliceVar(&taskAddClaudeTags, "tag", nil, "Tags for filtering the task queue (repeatable)")
+	taskAddClaudeCmd.Flags().StringArrayVar(&taskAddClaudeEnv, "env", nil, "Environment variable for Claude as KEY=VALUE (repeatable)")
+	taskAddClaudeCmd.Flags().StringArrayVar(&taskAddClaudeSecretEnv, "secret-env", nil, "Like --env, but the value is redacted in execution logs (repeatable)")
+	taskAddClaudeCmd.Flags().StringVar(&taskAddClaudeModel, "model", "", "Claude model to use for this task (defaults to the Claude CLI default)")
+	taskAddClaudeCmd.Flags().BoolVar(&taskAddClaudeAllowDup, "allow-duplicate", false, "Enqueue even if a similar task is pending or running for the worktree")
 }
 
 func runTaskAddClaude(cmd *cobra.Command, args []string) error {
@@ -94,7 +142,7 @@ func runTaskAddClaude(cmd *cobra.Command, args []string) error {
 	}
 
 	// Handle single task creation
-	return handleTaskAddClaudeSingleTaskCreation(args[0], taskManager, presenter)
+	return handleTaskAddClaudeSingleTaskCreation(cmd.InOrStdin(), args[0], taskManager, presenter)
 }
 
 func handleTaskAddClaudeFileCreation(taskManager *claude.TaskManager, presenter *presenters.TaskPresenter) error {
@@ -107,12 +155,22 @@ func handleTaskAddClaudeFileCreation(taskManager *claude.TaskManager, presenter
 	return nil
 }
 
-func handleTaskAddClaudeSingleTaskCreation(name string, taskManager *claude.TaskManager, presenter *presenters.TaskPresenter) error {
+func handleTaskAddClaudeSingleTaskCreation(stdin io.Reader, name string, taskManager *claude.TaskManager, presenter *presenters.TaskPresenter) error {
 	// Validate required flags
 	if err := validateTaskAddClaudeFlags(); err != nil {
 		return err
 	}
 
+	prompt, err := resolveTaskAddClaudePrompt(taskAddClaudePrompt, taskAddClaudePromptFile, stdin)
+	if err != nil {
+		return err
+	}
+
+	env, secretEnv, err := parseTaskAddClaudeEnv()
+	if err != nil {
+		return err
+	}
+
 	// Create task request
 	req := &claude.CreateTaskRequest{
 		Name:                 name,
@@ -120,14 +178,25 @@ func handleTaskAddClaudeSingleTaskCreation(name string, taskManager *claude.Task
 		BaseBranch:           taskAddClaudeBaseBranch,
 		Priority:             taskAddClaudePriority,
 		DependsOn:            taskAddClaudeDependsOn,
-		Prompt:               taskAddClaudePrompt,
+		Prompt:               prompt,
 		FilesToFocus:         taskAddClaudeFilesToFocus,
 		VerificationCommands: taskAddClaudeVerify,
 		AutoCommit:           taskAddClaudeAutoCommit,
+		Model:                taskAddClaudeModel,
+		Tags:                 taskAddClaudeTags,
+		Env:                  env,
+		SecretEnv:            secretEnv,
+		MaxRetries:           taskAddClaudeMaxRetries,
+		RetryBackoff:         taskAddClaudeRetryBackoff,
+		MaxCostUSD:           taskAddClaudeMaxCost,
+		AllowDuplicate:       taskAddClaudeAllowDup,
 	}
 
 	// Create task
 	task, err := taskManager.CreateTask(req)
+	if errors.Is(err, claude.ErrDuplicateTask) {
+		return fmt.Errorf("%w (use --allow-duplicate to enqueue it anyway)", err)
+	}
 	if err != nil {
 		return err
 	}
@@ -137,6 +206,61 @@ func handleTaskAddClaudeSingleTaskCreation(name string, taskManager *claude.Task
 	return nil
 }
 
+// parseTaskAddClaudeEnv merges --env and --secret-env into one environment
+// and returns the keys whose values must be redacted
+func parseTaskAddClaudeEnv() (map[string]string, []string, error) {
+	env, err := claude.ParseEnvAssignments(taskAddClaudeEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets, err := claude.ParseEnvAssignments(taskAddClaudeSecretEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var secretKeys []string
+	for key, value := range secrets {
+		if env == nil {
+			env = make(map[string]string, len(secrets))
+		}
+		env[key] = value
+		secretKeys = append(secretKeys, key)
+	}
+	sort.Strings(secretKeys)
+
+	return env, secretKeys, nil
+}
+
+// resolveTaskAddClaudePrompt returns the inline prompt, or the contents of
+// promptFile when one is given, read from stdin when it is "-"
+func resolveTaskAddClaudePrompt(inline, promptFile string, stdin io.Reader) (string, error) {
+	if promptFile == "" {
+		return inline, nil
+	}
+	if inline != "" {
+		return "", fmt.Errorf("--prompt and --prompt-file cannot be used together")
+	}
+
+	source := promptFile
+	var content []byte
+	var err error
+	if promptFile == "-" {
+		source = "stdin"
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(promptFile)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt from %s: %w", source, err)
+	}
+
+	prompt := strings.TrimSpace(string(content))
+	if prompt == "" {
+		return "", fmt.Errorf("prompt from %s is empty", source)
+	}
+	return prompt, nil
+}
+
 func validateTaskAddClaudeFlags() error {
 	if taskAddClaudeWorktree == "" {
 		return fmt.Errorf("--worktree must be specified")
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveTaskAddClaudePrompt(t *testing.T) {
+	// Multi-line content with shell metacharacters must come through untouched
+	content := "Fix the \"login\" flow:\n- don't break $HOME\n- keep `make test` green\n"
+	promptFile := filepath.Join(t.TempDir(), "prompt.md")
+	if err := os.WriteFile(promptFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write prompt file: %v", err)
+	}
+	emptyFile := filepath.Join(t.TempDir(), "empty.md")
+	if err := os.WriteFile(emptyFile, []byte("\n\n"), 0644); err != nil {
+		t.Fatalf("failed to write prompt file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		inline     string
+		promptFile string
+		stdin      string
+		want       string
+		wantErr    string
+	}{
+		{name: "inline prompt", inline: "Add tests", want: "Add tests"},
+		{name: "no prompt", want: ""},
+		{name: "from file", promptFile: promptFile, want: strings.TrimSpace(content)},
+		{name: "from stdin", promptFile: "-", stdin: content, want: strings.TrimSpace(content)},
+		{name: "both inline and file", inline: "Add tests", promptFile: promptFile, wantErr: "cannot be used together"},
+		{name: "missing file", promptFile: filepath.Join(t.TempDir(), "missing.md"), wantErr: "failed to read prompt"},
+		{name: "empty file", promptFile: emptyFile, wantErr: "is empty"},
+		{name: "empty stdin", promptFile: "-", wantErr: "prompt from stdin is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveTaskAddClaudePrompt(tt.inline, tt.promptFile, strings.NewReader(tt.stdin))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveTaskAddClaudePrompt() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveTaskAddClaudePrompt() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveTaskAddClaudePrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
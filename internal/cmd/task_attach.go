@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var taskAttachCmd = &cobra.Command{
+	Use:   "attach [EXECUTION_ID]",
+	Short: "Attach to the tmux session of a running execution",
+	Long: `Attach to the tmux session a Claude execution is running in.
+
+The session is looked up from the execution's metadata, so there is no need
+to find the session name manually. When no execution ID is given, a running
+execution is selected with the fuzzy finder. An unambiguous prefix of the
+execution ID is accepted.
+
+If gwq is not running in a terminal or tmux is not installed, the tmux
+command to run is printed instead.`,
+	Example: `  # Attach to a running execution (interactive selection)
+  gwq task attach
+
+  # Attach to a specific execution
+  gwq task attach exec-a1b2c3`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskAttach,
+}
+
+func init() {
+	taskCmd.AddCommand(taskAttachCmd)
+}
+
+func runTaskAttach(cmd *cobra.Command, args []string) error {
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+	reconcileTaskExecutions(execMgr)
+
+	executions, err := loadTaskExecutionsFromMetadata(execMgr)
+	if err != nil {
+		return fmt.Errorf("failed to load executions: %w", err)
+	}
+
+	var execution *claude.ExecutionMetadata
+	if len(args) > 0 {
+		execution, err = findTaskExecution(executions, args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		running := filterTaskExecutionsByStatus(executions, string(claude.ExecutionStatusRunning))
+		if len(running) == 0 {
+			fmt.Println("No running executions.")
+			return nil
+		}
+
+		execution, err = selectTaskExecutionWithFinder(running, nil)
+		if err != nil {
+			return fmt.Errorf("failed to select execution: %w", err)
+		}
+		if execution == nil {
+			return nil
+		}
+	}
+
+	session, err := taskAttachSession(execution)
+	if err != nil {
+		return err
+	}
+
+	tmuxPath, lookErr := exec.LookPath("tmux")
+	if lookErr != nil || !isInteractiveTerminal() {
+		fmt.Printf("Run this command to attach to execution %s:\n  tmux attach -t %s\n", execution.ExecutionID, session)
+		return nil
+	}
+
+	return execTmuxAttach(tmuxPath, session, os.Getenv("TMUX") != "")
+}
+
+// findTaskExecution returns the execution whose ID equals id or, failing
+// that, the single execution whose ID starts with id
+func findTaskExecution(executions []claude.ExecutionMetadata, id string) (*claude.ExecutionMetadata, error) {
+	var matches []*claude.ExecutionMetadata
+	for i := range executions {
+		if executions[i].ExecutionID == id {
+			return &executions[i], nil
+		}
+		if strings.HasPrefix(executions[i].ExecutionID, id) {
+			matches = append(matches, &executions[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("execution not found: %s", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("multiple executions match %q: %d matches", id, len(matches))
+	}
+}
+
+// taskAttachSession returns the tmux session of an execution that can be attached to
+func taskAttachSession(execution *claude.ExecutionMetadata) (string, error) {
+	if execution.TmuxSession == "" {
+		return "", fmt.Errorf("execution %s has no tmux session", execution.ExecutionID)
+	}
+	if execution.Status != claude.ExecutionStatusRunning {
+		return "", fmt.Errorf("execution %s is %s; its tmux session %s has ended", execution.ExecutionID, execution.Status, execution.TmuxSession)
+	}
+	return execution.TmuxSession, nil
+}
+
+// isInteractiveTerminal reports whether stdin and stdout are terminals
+func isInteractiveTerminal() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/claude"
+)
+
+func TestTaskAttachSessionResolution(t *testing.T) {
+	executions := []claude.ExecutionMetadata{
+		{ExecutionID: "task-abc123", Status: claude.ExecutionStatusRunning, TmuxSession: "gwq-claude-exec-abc123"},
+		{ExecutionID: "task-abc456", Status: claude.ExecutionStatusCompleted, TmuxSession: "gwq-claude-exec-abc456"},
+		{ExecutionID: "task-def789", Status: claude.ExecutionStatusRunning},
+		{ExecutionID: "task-abc", Status: claude.ExecutionStatusRunning, TmuxSession: "gwq-claude-exec-abc"},
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{name: "exact match", id: "task-abc123", want: "gwq-claude-exec-abc123"},
+		{name: "exact match wins over prefix", id: "task-abc", want: "gwq-claude-exec-abc"},
+		{name: "unique prefix", id: "task-abc1", want: "gwq-claude-exec-abc123"},
+		{name: "prefix of finished execution", id: "task-abc4", wantErr: true},
+		{name: "multiple matches", id: "task-", wantErr: true},
+		{name: "not found", id: "task-zzz", wantErr: true},
+		{name: "no tmux session", id: "task-def789", wantErr: true},
+		{name: "execution finished", id: "task-abc456", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			execution, err := findTaskExecution(executions, tt.id)
+			if err == nil {
+				got, err = taskAttachSession(execution)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolution of %q error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("session = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// execTmuxAttach replaces the current process with tmux attached to session.
+// Inside tmux the client is switched instead, since attaching would nest sessions.
+func execTmuxAttach(tmuxPath, session string, insideTmux bool) error {
+	args := []string{"tmux", "attach-session", "-t", session}
+	if insideTmux {
+		args = []string{"tmux", "switch-client", "-t", session}
+	}
+	return syscall.Exec(tmuxPath, args, os.Environ())
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+)
+
+// execTmuxAttach runs tmux attached to session. Windows cannot replace the
+// current process, so tmux runs as a child until it detaches.
+func execTmuxAttach(tmuxPath, session string, insideTmux bool) error {
+	action := "attach-session"
+	if insideTmux {
+		action = "switch-client"
+	}
+	cmd := exec.Command(tmuxPath, action, "-t", session)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var taskCancelAll bool
+
+var taskCancelCmd = &cobra.Command{
+	Use:   "cancel [EXECUTION_ID]",
+	Short: "Cancel a running execution",
+	Long: `Cancel a running Claude execution by killing its tmux session.
+
+The execution is recorded as aborted with its end time and duration. When no
+execution ID is given, a running execution is selected with the fuzzy
+finder. An unambiguous prefix of the execution ID is accepted.`,
+	Example: `  # Cancel a running execution (interactive selection)
+  gwq task cancel
+
+  # Cancel a specific execution
+  gwq task cancel exec-a1b2c3
+
+  # Cancel every running execution
+  gwq task cancel --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskCancel,
+}
+
+func init() {
+	taskCmd.AddCommand(taskCancelCmd)
+
+	taskCancelCmd.Flags().BoolVar(&taskCancelAll, "all", false, "Cancel all running executions")
+}
+
+func runTaskCancel(cmd *cobra.Command, args []string) error {
+	if taskCancelAll && len(args) > 0 {
+		return fmt.Errorf("cannot specify an execution ID with --all")
+	}
+
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+
+	executions, err := loadTaskExecutionsFromMetadata(execMgr)
+	if err != nil {
+		return fmt.Errorf("failed to load executions: %w", err)
+	}
+
+	if len(args) > 0 {
+		execution, err := findTaskExecution(executions, args[0])
+		if err != nil {
+			return err
+		}
+		return cancelTaskExecution(execMgr, execution.ExecutionID)
+	}
+
+	running := filterTaskExecutionsByStatus(executions, string(claude.ExecutionStatusRunning))
+	if len(running) == 0 {
+		fmt.Println("No running executions.")
+		return nil
+	}
+
+	if taskCancelAll {
+		var failed int
+		for _, execution := range running {
+			if err := cancelTaskExecution(execMgr, execution.ExecutionID); err != nil {
+				logging.Warnf("%v", err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to cancel %d of %d executions", failed, len(running))
+		}
+		return nil
+	}
+
+	execution, err := selectTaskExecutionWithFinder(running, nil)
+	if err != nil {
+		return fmt.Errorf("failed to select execution: %w", err)
+	}
+	if execution == nil {
+		return nil
+	}
+	return cancelTaskExecution(execMgr, execution.ExecutionID)
+}
+
+// cancelTaskExecution cancels one execution and reports the result
+func cancelTaskExecution(execMgr *claude.ExecutionManager, executionID string) error {
+	metadata, err := execMgr.CancelExecution(executionID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Cancelled execution %s after %s\n", metadata.ExecutionID, formatTaskWorkerDuration(time.Duration(metadata.DurationMS)*time.Millisecond))
+	return nil
+}
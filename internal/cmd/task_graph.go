@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var taskGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the task dependency graph",
+	Long: `Export the task dependency graph as Graphviz DOT or a Mermaid flowchart.
+
+Each node shows the task name and short ID and is colored by status. Edges
+point from a dependency to the tasks that depend on it.`,
+	Example: `  # Render the graph with Graphviz
+  gwq task graph | dot -Tsvg > tasks.svg
+
+  # Emit a Mermaid flowchart for Markdown documents
+  gwq task graph --format mermaid`,
+	Args: cobra.NoArgs,
+	RunE: runTaskGraph,
+}
+
+// Task graph flags
+var taskGraphFormat string
+
+func init() {
+	taskCmd.AddCommand(taskGraphCmd)
+
+	taskGraphCmd.Flags().StringVar(&taskGraphFormat, "format", "dot", "Output format (dot, mermaid)")
+}
+
+func runTaskGraph(cmd *cobra.Command, args []string) error {
+	if taskGraphFormat != "dot" && taskGraphFormat != "mermaid" {
+		return fmt.Errorf("unsupported graph format %q (expected dot or mermaid)", taskGraphFormat)
+	}
+
+	cfg := config.Get()
+
+	// Initialize storage
+	storage, err := claude.NewStorage(cfg.Claude.Queue.QueueDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	tasks, err := storage.ListTasks()
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	return writeTaskGraph(os.Stdout, tasks, taskGraphFormat)
+}
+
+// writeTaskGraph builds a dependency graph from tasks and writes it in the given format
+func writeTaskGraph(w io.Writer, tasks []*claude.Task, format string) error {
+	graph := claude.NewDependencyGraph()
+	for _, task := range tasks {
+		if err := graph.AddTask(task); err != nil {
+			return fmt.Errorf("failed to add task %s to graph: %w", task.ID, err)
+		}
+	}
+
+	if format == "mermaid" {
+		return graph.WriteMermaid(w)
+	}
+	return graph.WriteDOT(w)
+}
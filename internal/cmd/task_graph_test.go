@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/claude"
+)
+
+func graphTestTasks() []*claude.Task {
+	return []*claude.Task{
+		{ID: "setup", Name: "Setup project", Status: claude.StatusCompleted},
+		{ID: "api-endpoints", Name: "REST API", Status: claude.StatusRunning, DependsOn: []string{"setup"}},
+		{ID: "tests", Name: `Add "e2e" tests`, Status: claude.StatusPending, DependsOn: []string{"setup", "api-endpoints"}},
+	}
+}
+
+func TestWriteTaskGraphDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTaskGraph(&buf, graphTestTasks(), "dot"); err != nil {
+		t.Fatalf("writeTaskGraph() failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"digraph tasks {",
+		`  "setup" [label="Setup project\n(setup)", fillcolor="#b5e8b0"];`,
+		`  "api-endpoints" [label="REST API\n(api-endp)", fillcolor="#9ecbff"];`,
+		`  "tests" [label="Add \"e2e\" tests\n(tests)", fillcolor="#e0e0e0"];`,
+		`  "setup" -> "api-endpoints";`,
+		`  "setup" -> "tests";`,
+		`  "api-endpoints" -> "tests";`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("DOT output missing line %q\n%s", want, out)
+		}
+	}
+	if got := strings.Count(out, "->"); got != 3 {
+		t.Errorf("DOT output has %d edges, want 3\n%s", got, out)
+	}
+}
+
+func TestWriteTaskGraphMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTaskGraph(&buf, graphTestTasks(), "mermaid"); err != nil {
+		t.Fatalf("writeTaskGraph() failed: %v", err)
+	}
+	out := buf.String()
+
+	// Nodes are numbered by sorted task ID: api-endpoints, setup, tests
+	for _, want := range []string{
+		"flowchart LR",
+		`  t0["REST API<br/>(api-endp)"]:::running`,
+		`  t1["Setup project<br/>(setup)"]:::completed`,
+		`  t2["Add #quot;e2e#quot; tests<br/>(tests)"]:::pending`,
+		"  t1 --> t0",
+		"  t1 --> t2",
+		"  t0 --> t2",
+		"  classDef completed fill:#b5e8b0",
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("Mermaid output missing line %q\n%s", want, out)
+		}
+	}
+}
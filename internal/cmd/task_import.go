@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var taskImportCmd = &cobra.Command{
+	Use:   "import SOURCE_QUEUE_DIR",
+	Short: "Import tasks from another task queue",
+	Long: `Copy tasks from another task queue directory into the current queue.
+
+Imported tasks get new IDs, are reset to pending and are assigned to the git
+repository of the current directory. Dependencies between imported tasks are
+kept. Tasks that fail validation, or depend on a task that is not imported,
+are skipped and reported.
+
+Only pending tasks are imported unless --all is given.`,
+	Example: `  # Import pending tasks from another queue
+  gwq task import ~/other-config/claude/queue
+
+  # Import every task tagged "backend"
+  gwq task import ~/other-config/claude/queue --all --tag backend`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskImport,
+}
+
+var (
+	taskImportAll bool
+	taskImportTag string
+)
+
+func init() {
+	taskCmd.AddCommand(taskImportCmd)
+
+	taskImportCmd.Flags().BoolVar(&taskImportAll, "all", false, "Import tasks in any status, not only pending ones")
+	taskImportCmd.Flags().StringVar(&taskImportTag, "tag", "", "Import only tasks with this tag")
+}
+
+func runTaskImport(cmd *cobra.Command, args []string) error {
+	cfg := config.Get()
+
+	sourceDir, err := utils.ExpandPath(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to expand source queue dir: %w", err)
+	}
+
+	storage, err := claude.NewStorage(cfg.Claude.Queue.QueueDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	taskManager := claude.NewTaskManager(storage, cfg)
+
+	result, err := taskManager.ImportTasks(sourceDir, taskImportFilter(taskImportAll, taskImportTag))
+	if err != nil {
+		return err
+	}
+
+	for _, task := range result.Imported {
+		fmt.Printf("Imported %s: %s\n", task.ID, task.Name)
+	}
+	for _, skip := range result.Skipped {
+		fmt.Printf("Skipped %s: %s\n", skip.TaskID, skip.Reason)
+	}
+	fmt.Printf("Imported %d task(s), skipped %d\n", len(result.Imported), len(result.Skipped))
+	return nil
+}
+
+// taskImportFilter selects the source tasks to import
+func taskImportFilter(all bool, tag string) func(*claude.Task) bool {
+	return func(task *claude.Task) bool {
+		if !all && task.Status != claude.StatusPending {
+			return false
+		}
+		return tag == "" || slices.Contains(task.Tags, tag)
+	}
+}
@@ -22,16 +22,26 @@ easy to understand the relationship between tasks. The display includes:
 - Task status (pending, waiting, running, completed, failed)
 - Priority level (1-100)
 - Dependencies and dependent tasks
-- Duration for completed tasks`,
+- Duration for completed tasks
+
+Tasks are listed by priority, highest first, and then by creation time,
+oldest first, the order the scheduler picks them in. Use --sort to order by
+created, status or name instead, and --reverse to invert the order.`,
 	Example: `  # List all tasks
   gwq task list
 
   # Filter by status
   gwq task list --filter running
 
+  # Show only tasks tagged "backend"
+  gwq task list --tag backend
+
   # Show only high priority tasks
   gwq task list --priority-min 75
 
+  # Newest tasks first
+  gwq task list --sort created --reverse
+
   # Watch for real-time updates
   gwq task list --watch`,
 	RunE: runTaskList,
@@ -41,6 +51,9 @@ easy to understand the relationship between tasks. The display includes:
 var (
 	taskListFilter      string
 	taskListPriorityMin int
+	taskListTag         string
+	taskListSort        string
+	taskListReverse     bool
 	taskListWatch       bool
 	taskListVerbose     bool
 	taskListJSON        bool
@@ -53,6 +66,9 @@ func init() {
 	// Task list flags
 	taskListCmd.Flags().StringVar(&taskListFilter, "filter", "", "Filter by status (pending, running, completed, failed)")
 	taskListCmd.Flags().IntVar(&taskListPriorityMin, "priority-min", 0, "Show only tasks with priority >= value")
+	taskListCmd.Flags().StringVar(&taskListTag, "tag", "", "Show only tasks with this tag")
+	taskListCmd.Flags().StringVar(&taskListSort, "sort", "priority", "Sort by priority, created, status or name")
+	taskListCmd.Flags().BoolVar(&taskListReverse, "reverse", false, "Reverse the sort order")
 	taskListCmd.Flags().BoolVar(&taskListWatch, "watch", false, "Watch for real-time updates")
 	taskListCmd.Flags().BoolVarP(&taskListVerbose, "verbose", "v", false, "Show detailed information")
 	taskListCmd.Flags().BoolVar(&taskListJSON, "json", false, "Output in JSON format")
@@ -80,6 +96,9 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 
 	// Apply filters
 	tasks = applyTaskListFilters(tasks, taskManager)
+	if err := taskManager.SortTasks(tasks, taskListSort, taskListReverse); err != nil {
+		return err
+	}
 
 	// Output tasks based on format
 	return outputTaskList(tasks, presenter)
@@ -91,6 +110,11 @@ func applyTaskListFilters(tasks []*claude.Task, taskManager *claude.TaskManager)
 		tasks = taskManager.FilterTasksByStatus(tasks, taskListFilter)
 	}
 
+	// Apply tag filter
+	if taskListTag != "" {
+		tasks = taskManager.FilterTasksByTag(tasks, taskListTag)
+	}
+
 	// Apply priority filter
 	if taskListPriorityMin > 0 {
 		tasks = taskManager.FilterTasksByPriority(tasks, taskListPriorityMin)
@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/claude"
 	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/internal/tui"
 	"github.com/ktr0731/go-fuzzyfinder"
 	"github.com/spf13/cobra"
@@ -27,8 +27,9 @@ This command provides access to all Claude execution logs, allowing you to:
 - Filter logs by status, date, or content
 - Clean up old logs
 
-Logs are stored in ~/.config/gwq/claude/logs/ and include both raw JSON
-output and formatted metadata for easy browsing.`,
+Logs are stored in ~/.config/gwq/claude/logs/ (or claude.log_dir, or
+--output-dir) and include both raw JSON output and formatted metadata for
+easy browsing.`,
 	Example: `  # Interactive log selection (default)
   gwq task logs
   
@@ -42,9 +43,42 @@ output and formatted metadata for easy browsing.`,
   # Filter by date
   gwq task logs --date 2024-01-15
   
+  # Failed executions from the last 7 days
+  gwq task logs --since 7d --failed-only
+  
+  # Executions in a time range
+  gwq task logs --since 2024-01-15T09:00:00Z --until 2024-01-15T18:00:00Z
+  
   # Search logs containing text
   gwq task logs --contains "authentication"
   
+  # Also search Claude's responses and tool output
+  gwq task logs --contains "authentication" --deep
+  
+  # Show total cost and duration for completed executions
+  gwq task logs --summary --status completed
+  
+  # Export an execution log as Markdown
+  gwq task logs exec-a1b2c3 --format md > execution.md
+  
+  # Plain text without icons, wrapped to 80 columns
+  NO_COLOR=1 gwq task logs exec-a1b2c3 --plain --width 80
+  
+  # Follow a running execution live
+  gwq task logs tail exec-a1b2c3
+  
+  # Open the raw JSONL log in $EDITOR
+  gwq task logs open exec-a1b2c3
+  
+  # Compare two executions
+  gwq task logs diff exec-a1b2c3 exec-d4e5f6
+  
+  # Per-tool usage and failure rates across all executions
+  gwq task logs stats
+  
+  # Bundle executions into a tarball for sharing
+  gwq task logs export exec-a1b2c3 exec-d4e5f6 -o repro.tar.gz
+  
   # Clean up old logs
   gwq task logs clean --older-than 30d`,
 	Args: cobra.MaximumNArgs(1),
@@ -63,13 +97,20 @@ recent execution history.`,
 
 // Flags for logs command
 var (
-	taskLogsStatus    string
-	taskLogsDate      string
-	taskLogsContains  string
-	taskLogsLimit     int
-	taskLogsJSON      bool
-	taskLogsOlderThan string
-	taskLogsPlain     bool
+	taskLogsStatus     string
+	taskLogsFailedOnly bool
+	taskLogsDate       string
+	taskLogsSince      string
+	taskLogsUntil      string
+	taskLogsContains   string
+	taskLogsLimit      int
+	taskLogsJSON       bool
+	taskLogsOlderThan  string
+	taskLogsPlain      bool
+	taskLogsFormat     string
+	taskLogsDeep       bool
+	taskLogsSummary    bool
+	taskLogsWidth      int
 )
 
 func init() {
@@ -78,17 +119,28 @@ func init() {
 
 	// List command flags
 	taskLogsCmd.Flags().StringVar(&taskLogsStatus, "status", "", "Filter by status (running, completed, failed)")
+	taskLogsCmd.Flags().BoolVar(&taskLogsFailedOnly, "failed-only", false, "Show only failed executions (same as --status failed)")
 	taskLogsCmd.Flags().StringVar(&taskLogsDate, "date", "", "Filter by date (YYYY-MM-DD)")
+	taskLogsCmd.Flags().StringVar(&taskLogsSince, "since", "", "Show executions started at or after this time (RFC3339, YYYY-MM-DD, or duration ago like 24h, 7d)")
+	taskLogsCmd.Flags().StringVar(&taskLogsUntil, "until", "", "Show executions started at or before this time (RFC3339, YYYY-MM-DD, or duration ago like 24h, 7d)")
 	taskLogsCmd.Flags().StringVar(&taskLogsContains, "contains", "", "Filter by content containing text")
 	taskLogsCmd.Flags().IntVar(&taskLogsLimit, "limit", 20, "Limit number of results")
 	taskLogsCmd.Flags().BoolVar(&taskLogsJSON, "json", false, "Output in JSON format")
 	taskLogsCmd.Flags().BoolVar(&taskLogsPlain, "plain", false, "Use plain text output instead of TUI")
+	taskLogsCmd.Flags().BoolVar(&taskLogsSummary, "summary", false, "Show aggregate cost and duration instead of selecting an execution")
+	taskLogsCmd.Flags().BoolVar(&taskLogsDeep, "deep", false, "With --contains, also search Claude's responses and tool output (slower)")
+	taskLogsCmd.Flags().StringVar(&taskLogsFormat, "format", "text", "Execution log output format (text, md, json)")
+	taskLogsCmd.Flags().IntVar(&taskLogsWidth, "width", 0, "Wrap plain text output to this many columns (0 disables wrapping)")
 
 	// Clean command flags
 	taskLogsCleanCmd.Flags().StringVar(&taskLogsOlderThan, "older-than", "30d", "Remove logs older than specified duration (e.g., 30d, 1w)")
 }
 
 func runTaskLogsMain(cmd *cobra.Command, args []string) error {
+	if _, err := claude.ParseLogFormat(taskLogsFormat); err != nil {
+		return err
+	}
+
 	// If execution ID is provided as argument, show that specific execution
 	if len(args) > 0 {
 		return runTaskLogsShow(cmd, args)
@@ -104,22 +156,46 @@ func runTaskLogsList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	since, until, err := parseTaskLogsTimeRange(taskLogsSince, taskLogsUntil, time.Now())
+	if err != nil {
+		return err
+	}
+	status, err := taskLogsStatusFilter(taskLogsStatus, taskLogsFailedOnly)
+	if err != nil {
+		return err
+	}
 
-	// Load executions from metadata directory
-	executions, err := loadTaskExecutionsFromMetadata(execMgr)
+	reconcileTaskExecutions(execMgr)
+
+	// Content search, summaries and JSON output need full metadata; plain
+	// browsing only needs the index
+	loadExecutions := loadTaskExecutionsFromIndex
+	if taskLogsContains != "" || taskLogsSummary || taskLogsJSON {
+		loadExecutions = loadTaskExecutionsFromMetadata
+	}
+	executions, err := loadExecutions(execMgr)
 	if err != nil {
 		return fmt.Errorf("failed to load executions: %w", err)
 	}
 
 	// Apply filters
-	if taskLogsStatus != "" {
-		executions = filterTaskExecutionsByStatus(executions, taskLogsStatus)
+	if status != "" {
+		executions = filterTaskExecutionsByStatus(executions, status)
 	}
 	if taskLogsDate != "" {
 		executions = filterTaskExecutionsByDate(executions, taskLogsDate)
 	}
+	if !since.IsZero() || !until.IsZero() {
+		executions = filterTaskExecutionsByTimeRange(executions, since, until)
+	}
+	var snippets map[string]string
 	if taskLogsContains != "" {
-		executions = filterTaskExecutionsByContent(executions, taskLogsContains, execMgr)
+		executions, snippets = filterTaskExecutionsByContent(executions, taskLogsContains, execMgr, taskLogsDeep)
+	}
+
+	// Summaries aggregate every matching execution, so apply them before the limit
+	if taskLogsSummary {
+		return outputTaskLogsSummary(executions, taskLogsJSON)
 	}
 
 	// Limit results
@@ -139,7 +215,7 @@ func runTaskLogsList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Show fuzzy finder for selection
-	selectedExecution, err := selectTaskExecutionWithFinder(executions)
+	selectedExecution, err := selectTaskExecutionWithFinder(executions, snippets)
 	if err != nil {
 		return fmt.Errorf("failed to select execution: %w", err)
 	}
@@ -149,8 +225,14 @@ func runTaskLogsList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Index entries only hold listing fields, so load the full metadata
+	metadata, err := execMgr.LoadMetadata(selectedExecution.ExecutionID)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata for %s: %w", selectedExecution.ExecutionID, err)
+	}
+
 	// Show the selected execution
-	return showTaskExecution(selectedExecution, execMgr)
+	return showTaskExecution(metadata, execMgr)
 }
 
 func runTaskLogsShow(cmd *cobra.Command, args []string) error {
@@ -158,18 +240,19 @@ func runTaskLogsShow(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	reconcileTaskExecutions(execMgr)
 
 	var executionID string
 	if len(args) > 0 {
 		executionID = args[0]
 	} else {
 		// Interactive selection
-		executions, err := loadTaskExecutionsFromMetadata(execMgr)
+		executions, err := loadTaskExecutionsFromIndex(execMgr)
 		if err != nil {
 			return fmt.Errorf("failed to load executions: %w", err)
 		}
 
-		selectedExecution, err := selectTaskExecutionWithFinder(executions)
+		selectedExecution, err := selectTaskExecutionWithFinder(executions, nil)
 		if err != nil {
 			return fmt.Errorf("failed to select execution: %w", err)
 		}
@@ -196,20 +279,9 @@ func runTaskLogsClean(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Parse duration
-	duration, err := time.ParseDuration(taskLogsOlderThan)
+	duration, err := parseTaskLogsDuration(taskLogsOlderThan)
 	if err != nil {
-		// Try parsing as days (e.g., "30d")
-		if strings.HasSuffix(taskLogsOlderThan, "d") {
-			days := strings.TrimSuffix(taskLogsOlderThan, "d")
-			if d, err := time.ParseDuration(days + "h"); err == nil {
-				duration = d * 24
-			} else {
-				return fmt.Errorf("invalid duration format: %s", taskLogsOlderThan)
-			}
-		} else {
-			return fmt.Errorf("invalid duration format: %s", taskLogsOlderThan)
-		}
+		return err
 	}
 
 	cutoff := time.Now().Add(-duration)
@@ -248,13 +320,13 @@ func runTaskLogsClean(cmd *cobra.Command, args []string) error {
 
 	// Delete log files and metadata
 	cfg := config.Get()
-	logDir := filepath.Join(cfg.Claude.ConfigDir, "logs")
+	logDir := claude.LogDir(&cfg.Claude)
 	deletedCount := 0
 
 	for _, exec := range toDelete {
-		// Delete log file using new helper function
+		// Delete log file, with any rotated parts, using new helper function
 		logFile := claude.FindLogFileByExecutionID(logDir, exec.StartTime, exec.ExecutionID)
-		if err := os.Remove(logFile); err == nil {
+		if err := claude.RemoveLogFile(logFile); err == nil {
 			deletedCount++
 		}
 
@@ -269,13 +341,15 @@ func runTaskLogsClean(cmd *cobra.Command, args []string) error {
 		}
 		if err := os.Remove(metadataFile); err != nil {
 			// Ignore errors for metadata files as they're not critical
-			fmt.Printf("Warning: failed to delete metadata file %s: %v\n", metadataFile, err)
+			logging.Warnf("failed to delete metadata file %s: %v", metadataFile, err)
 		}
 	}
 
 	fmt.Printf("Cleaned %d log files.\n", deletedCount)
 
-	// TODO: Update index to remove deleted entries
+	if err := execMgr.RebuildMetadataIndex(); err != nil {
+		logging.Warnf("failed to rebuild metadata index: %v", err)
+	}
 
 	return nil
 }
@@ -292,6 +366,39 @@ func createTaskExecutionManager() (*claude.ExecutionManager, error) {
 	return execMgr, nil
 }
 
+// reconcileTaskExecutions repairs executions left running by a crashed worker
+func reconcileTaskExecutions(execMgr *claude.ExecutionManager) {
+	if _, err := execMgr.ReconcileExecutions(); err != nil {
+		logging.Warnf("failed to reconcile executions: %v", err)
+	}
+}
+
+// loadTaskExecutionsFromIndex loads executions from the metadata index. Only the
+// listing fields (ID, status, start time, prompt snippet, repository and working
+// directory) are populated; use LoadMetadata for the full record.
+func loadTaskExecutionsFromIndex(execMgr *claude.ExecutionManager) ([]claude.ExecutionMetadata, error) {
+	entries, err := execMgr.LoadExecutionIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	executions := make([]claude.ExecutionMetadata, 0, len(entries))
+	for _, entry := range entries {
+		execution := entry.Metadata()
+
+		// Mark executions whose log file is missing as aborted
+		logFile := claude.FindLogFileByExecutionID(execMgr.GetLogDir(), execution.StartTime, execution.ExecutionID)
+		if _, err := os.Stat(logFile); err != nil {
+			execution.Status = claude.ExecutionStatusAborted
+		}
+		executions = append(executions, execution)
+	}
+
+	claude.SortExecutionsNewestFirst(executions)
+
+	return executions, nil
+}
+
 func loadTaskExecutionsFromMetadata(execMgr *claude.ExecutionManager) ([]claude.ExecutionMetadata, error) {
 	// Load executions directly from metadata directory - no index file needed
 	metadataDir := filepath.Join(execMgr.GetLogDir(), "metadata")
@@ -314,13 +421,13 @@ func loadTaskExecutionsFromMetadata(execMgr *claude.ExecutionManager) ([]claude.
 		metadataFile := filepath.Join(metadataDir, file.Name())
 		data, err := os.ReadFile(metadataFile)
 		if err != nil {
-			fmt.Printf("Warning: failed to read metadata file %s: %v\n", metadataFile, err)
+			logging.Warnf("failed to read metadata file %s: %v", metadataFile, err)
 			continue
 		}
 
 		var execution claude.ExecutionMetadata
 		if err := json.Unmarshal(data, &execution); err != nil {
-			fmt.Printf("Warning: failed to unmarshal metadata file %s: %v\n", metadataFile, err)
+			logging.Warnf("failed to unmarshal metadata file %s: %v", metadataFile, err)
 			continue
 		}
 
@@ -335,14 +442,23 @@ func loadTaskExecutionsFromMetadata(execMgr *claude.ExecutionManager) ([]claude.
 		}
 	}
 
-	// Sort by start time (newest first)
-	sort.Slice(executions, func(i, j int) bool {
-		return executions[i].StartTime.After(executions[j].StartTime)
-	})
+	claude.SortExecutionsNewestFirst(executions)
 
 	return executions, nil
 }
 
+// taskLogsStatusFilter returns the status to filter by, folding --failed-only
+// into --status
+func taskLogsStatusFilter(status string, failedOnly bool) (string, error) {
+	if !failedOnly {
+		return status, nil
+	}
+	if status != "" && status != string(claude.ExecutionStatusFailed) {
+		return "", fmt.Errorf("--failed-only cannot be combined with --status %s", status)
+	}
+	return string(claude.ExecutionStatusFailed), nil
+}
+
 func filterTaskExecutionsByStatus(executions []claude.ExecutionMetadata, status string) []claude.ExecutionMetadata {
 	var filtered []claude.ExecutionMetadata
 	for _, exec := range executions {
@@ -368,30 +484,120 @@ func filterTaskExecutionsByDate(executions []claude.ExecutionMetadata, date stri
 	return filtered
 }
 
-func filterTaskExecutionsByContent(executions []claude.ExecutionMetadata, text string, execMgr *claude.ExecutionManager) []claude.ExecutionMetadata {
+// filterTaskExecutionsByTimeRange keeps executions started within [since, until].
+// A zero bound leaves that side of the range open.
+func filterTaskExecutionsByTimeRange(executions []claude.ExecutionMetadata, since, until time.Time) []claude.ExecutionMetadata {
+	var filtered []claude.ExecutionMetadata
+	for _, exec := range executions {
+		if !since.IsZero() && exec.StartTime.Before(since) {
+			continue
+		}
+		if !until.IsZero() && exec.StartTime.After(until) {
+			continue
+		}
+		filtered = append(filtered, exec)
+	}
+	return filtered
+}
+
+// parseTaskLogsTimeRange parses the --since and --until values relative to
+// now. Empty values yield zero times.
+func parseTaskLogsTimeRange(sinceValue, untilValue string, now time.Time) (since, until time.Time, err error) {
+	if sinceValue != "" {
+		if since, err = parseTaskLogsTime(sinceValue, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if untilValue != "" {
+		if until, err = parseTaskLogsTime(untilValue, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--since (%s) is after --until (%s)",
+			since.Format(time.RFC3339), until.Format(time.RFC3339))
+	}
+	return since, until, nil
+}
+
+// parseTaskLogsTime parses an RFC3339 timestamp, a local YYYY-MM-DD date, or
+// a duration such as 24h or 7d that is subtracted from now.
+func parseTaskLogsTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if d, err := parseTaskLogsDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time: use RFC3339 (2024-01-15T09:00:00Z), a date (2024-01-15) or a duration (24h, 7d)", value)
+}
+
+// parseTaskLogsDuration parses a Go duration, also accepting a day suffix (e.g., "30d").
+func parseTaskLogsDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err == nil {
+		return duration, nil
+	}
+
+	// Try parsing as days (e.g., "30d")
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if d, err := time.ParseDuration(days + "h"); err == nil {
+			return d * 24, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid duration format: %s", value)
+}
+
+// filterTaskExecutionsByContent keeps executions whose prompt or tags contain text.
+// When deep is set, the JSONL log body is also scanned. The returned map holds
+// the matched snippet for each kept execution, keyed by execution ID.
+func filterTaskExecutionsByContent(executions []claude.ExecutionMetadata, text string, execMgr *claude.ExecutionManager, deep bool) ([]claude.ExecutionMetadata, map[string]string) {
 	var filtered []claude.ExecutionMetadata
+	snippets := make(map[string]string)
 	lowerText := strings.ToLower(text)
+	processor := claude.NewLogProcessor()
 
 	for _, exec := range executions {
 		// Check prompt
 		if strings.Contains(strings.ToLower(exec.Prompt), lowerText) {
 			filtered = append(filtered, exec)
+			snippets[exec.ExecutionID] = exec.Prompt
 			continue
 		}
 
 		// Check tags
+		tagMatched := false
 		for _, tag := range exec.Tags {
 			if strings.Contains(strings.ToLower(tag), lowerText) {
 				filtered = append(filtered, exec)
+				snippets[exec.ExecutionID] = "tag: " + tag
+				tagMatched = true
 				break
 			}
 		}
+		if tagMatched || !deep {
+			continue
+		}
+
+		// Check log body
+		logFile := claude.FindLogFileByExecutionID(execMgr.GetLogDir(), exec.StartTime, exec.ExecutionID)
+		snippet, found, err := processor.SearchLogContent(logFile, text)
+		if err != nil {
+			continue // Missing or unreadable logs simply don't match
+		}
+		if found {
+			filtered = append(filtered, exec)
+			snippets[exec.ExecutionID] = snippet
+		}
 	}
 
-	return filtered
+	return filtered, snippets
 }
 
-func selectTaskExecutionWithFinder(executions []claude.ExecutionMetadata) (*claude.ExecutionMetadata, error) {
+func selectTaskExecutionWithFinder(executions []claude.ExecutionMetadata, snippets map[string]string) (*claude.ExecutionMetadata, error) {
 	if len(executions) == 0 {
 		return nil, nil
 	}
@@ -407,41 +613,45 @@ func selectTaskExecutionWithFinder(executions []claude.ExecutionMetadata) (*clau
 			if i == -1 {
 				return ""
 			}
-			exec := executions[i]
-			return fmt.Sprintf("Execution: %s\nStatus: %s\nStarted: %s\nPrompt: %s",
-				exec.ExecutionID,
-				exec.Status,
-				exec.StartTime.Format("2006-01-02 15:04:05"),
-				exec.Prompt)
+			return taskExecutionFinderPreview(executions[i], snippets)
 		}),
 	}
 
 	idx, err := fuzzyfinder.Find(
 		executions,
 		func(i int) string {
-			exec := executions[i]
-			status := string(exec.Status)
-			relativeTime := formatTaskRelativeTime(exec.StartTime)
-
-			// Get branch info from working directory or use "no-branch"
-			branch := "no-branch"
-			if strings.Contains(exec.WorkingDirectory, "/.worktrees/") {
-				// Extract branch from worktree path
-				parts := strings.Split(exec.WorkingDirectory, "/.worktrees/")
-				if len(parts) > 1 {
-					branchParts := strings.Split(parts[1], "-")
-					if len(branchParts) > 0 {
-						branch = strings.Join(branchParts[:len(branchParts)-1], "-")
-					}
-				}
-			} else if exec.WorkingDirectory != "" {
-				// Assume we're on the default branch if not in a worktree
-				branch = "main"
+			return taskExecutionFinderLabel(executions[i])
+		},
+		opts...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &executions[idx], nil
+}
+
+// selectTaskExecutionsWithFinder lets the user pick one or more executions with Tab
+func selectTaskExecutionsWithFinder(executions []claude.ExecutionMetadata) ([]claude.ExecutionMetadata, error) {
+	if len(executions) == 0 {
+		return nil, nil
+	}
+
+	opts := []fuzzyfinder.Option{
+		fuzzyfinder.WithPromptString("Select Executions (Tab to select multiple)> "),
+		fuzzyfinder.WithPreviewWindow(func(i, w, h int) string {
+			if i == -1 {
+				return ""
 			}
+			return taskExecutionFinderPreview(executions[i], nil)
+		}),
+	}
 
-			// Format: [status] exec-id (~/path/to/repo on branch) - time ago
-			return fmt.Sprintf("[%s] %s (%s on %s) - %s",
-				status, exec.ExecutionID, exec.WorkingDirectory, branch, relativeTime)
+	indices, err := fuzzyfinder.FindMulti(
+		executions,
+		func(i int) string {
+			return taskExecutionFinderLabel(executions[i])
 		},
 		opts...,
 	)
@@ -450,7 +660,60 @@ func selectTaskExecutionWithFinder(executions []claude.ExecutionMetadata) (*clau
 		return nil, err
 	}
 
-	return &executions[idx], nil
+	selected := make([]claude.ExecutionMetadata, len(indices))
+	for i, idx := range indices {
+		selected[i] = executions[idx]
+	}
+
+	return selected, nil
+}
+
+// taskExecutionFinderPreview renders the finder preview of an execution
+func taskExecutionFinderPreview(exec claude.ExecutionMetadata, snippets map[string]string) string {
+	preview := fmt.Sprintf("Execution: %s\nStatus: %s\nStarted: %s\nPrompt: %s",
+		exec.ExecutionID,
+		taskExecutionStatusLabel(exec),
+		exec.StartTime.Format("2006-01-02 15:04:05"),
+		exec.Prompt)
+	if snippet, ok := snippets[exec.ExecutionID]; ok {
+		preview += fmt.Sprintf("\n\nMatch: %s", snippet)
+	}
+	return preview
+}
+
+// taskExecutionFinderLabel renders the finder line of an execution
+func taskExecutionFinderLabel(exec claude.ExecutionMetadata) string {
+	status := taskExecutionStatusLabel(exec)
+	relativeTime := formatTaskRelativeTime(exec.StartTime)
+
+	// Get branch info from working directory or use "no-branch"
+	branch := "no-branch"
+	if strings.Contains(exec.WorkingDirectory, "/.worktrees/") {
+		// Extract branch from worktree path
+		parts := strings.Split(exec.WorkingDirectory, "/.worktrees/")
+		if len(parts) > 1 {
+			branchParts := strings.Split(parts[1], "-")
+			if len(branchParts) > 0 {
+				branch = strings.Join(branchParts[:len(branchParts)-1], "-")
+			}
+		}
+	} else if exec.WorkingDirectory != "" {
+		// Assume we're on the default branch if not in a worktree
+		branch = "main"
+	}
+
+	// Format: [status] exec-id (~/path/to/repo on branch) - time ago
+	return fmt.Sprintf("[%s] %s (%s on %s) - %s",
+		status, exec.ExecutionID, exec.WorkingDirectory, branch, relativeTime)
+}
+
+// taskExecutionStatusLabel returns the status of an execution, with the exit
+// code for executions that exited with one
+func taskExecutionStatusLabel(exec claude.ExecutionMetadata) string {
+	if exec.ExitCode != 0 {
+		return fmt.Sprintf("%s exit %d", exec.Status, exec.ExitCode)
+	}
+	return string(exec.Status)
 }
 
 func showTaskExecution(metadata *claude.ExecutionMetadata, execMgr *claude.ExecutionManager) error {
@@ -470,15 +733,28 @@ func showTaskExecution(metadata *claude.ExecutionMetadata, execMgr *claude.Execu
 		return nil
 	}
 
-	// Load and format the log
+	format, err := claude.ParseLogFormat(taskLogsFormat)
+	if err != nil {
+		return err
+	}
+
+	// Use TUI if not plain mode and if we're in a terminal
+	useTUI := format == claude.LogFormatText && !taskLogsPlain && os.Getenv("TERM") != ""
+
+	// Load and format the log. The TUI keeps its decorated output, while plain
+	// text honors NO_COLOR and --width.
 	processor := claude.NewLogProcessor()
-	formatted, err := processor.ProcessExecution(metadata, execMgr)
+	if !useTUI {
+		opts := claude.DefaultLogProcessorOptions()
+		opts.Width = taskLogsWidth
+		processor = claude.NewLogProcessorWithOptions(opts)
+	}
+	formatted, err := processor.ProcessExecutionAs(metadata, execMgr, format)
 	if err != nil {
 		return fmt.Errorf("failed to process log: %w", err)
 	}
 
-	// Use TUI if not plain mode and if we're in a terminal
-	if !taskLogsPlain && os.Getenv("TERM") != "" {
+	if useTUI {
 		return tui.RunLogViewer(metadata, formatted)
 	}
 
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var taskLogsDiffCmd = &cobra.Command{
+	Use:   "diff EXEC_A EXEC_B",
+	Short: "Compare two executions",
+	Long: `Compare two executions side by side.
+
+Shows cost, duration, and model for each execution, the files changed by
+only one of them, and a unified diff of their prompts.`,
+	Example: `  # Compare two runs of the same prompt
+  gwq task logs diff exec-a1b2c3 exec-d4e5f6
+
+  # Machine-readable comparison
+  gwq task logs diff exec-a1b2c3 exec-d4e5f6 --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTaskLogsDiff,
+}
+
+var taskLogsDiffJSON bool
+
+func init() {
+	taskLogsCmd.AddCommand(taskLogsDiffCmd)
+
+	taskLogsDiffCmd.Flags().BoolVar(&taskLogsDiffJSON, "json", false, "Output in JSON format")
+}
+
+// taskLogsDiffSide describes one execution in a comparison
+type taskLogsDiffSide struct {
+	ExecutionID  string   `json:"execution_id"`
+	Status       string   `json:"status"`
+	Model        string   `json:"model"`
+	CostUSD      float64  `json:"cost_usd"`
+	DurationMS   int64    `json:"duration_ms"`
+	FilesChanged []string `json:"files_changed"`
+}
+
+// taskLogsDiffReport compares two executions; deltas are B minus A
+type taskLogsDiffReport struct {
+	A               taskLogsDiffSide `json:"a"`
+	B               taskLogsDiffSide `json:"b"`
+	CostDeltaUSD    float64          `json:"cost_delta_usd"`
+	DurationDeltaMS int64            `json:"duration_delta_ms"`
+	FilesOnlyInA    []string         `json:"files_only_in_a"`
+	FilesOnlyInB    []string         `json:"files_only_in_b"`
+	PromptDiff      string           `json:"prompt_diff"` // Empty when the prompts are identical
+}
+
+func runTaskLogsDiff(cmd *cobra.Command, args []string) error {
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+
+	a, b, err := loadTaskExecutionPair(execMgr, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	report := buildTaskLogsDiff(a, b)
+
+	if taskLogsDiffJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	printTaskLogsDiff(os.Stdout, report)
+	return nil
+}
+
+// loadTaskExecutionPair loads both executions, naming whichever ID is missing
+func loadTaskExecutionPair(execMgr *claude.ExecutionManager, idA, idB string) (*claude.ExecutionMetadata, *claude.ExecutionMetadata, error) {
+	a, err := execMgr.LoadMetadata(idA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("execution %s not found: %w", idA, err)
+	}
+	b, err := execMgr.LoadMetadata(idB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("execution %s not found: %w", idB, err)
+	}
+	return a, b, nil
+}
+
+// buildTaskLogsDiff compares the metadata of two executions
+func buildTaskLogsDiff(a, b *claude.ExecutionMetadata) taskLogsDiffReport {
+	sideA := newTaskLogsDiffSide(a)
+	sideB := newTaskLogsDiffSide(b)
+
+	report := taskLogsDiffReport{
+		A:               sideA,
+		B:               sideB,
+		CostDeltaUSD:    sideB.CostUSD - sideA.CostUSD,
+		DurationDeltaMS: sideB.DurationMS - sideA.DurationMS,
+		FilesOnlyInA:    stringSetDifference(sideA.FilesChanged, sideB.FilesChanged),
+		FilesOnlyInB:    stringSetDifference(sideB.FilesChanged, sideA.FilesChanged),
+	}
+	if a.Prompt != b.Prompt {
+		report.PromptDiff = unifiedLineDiff(a.Prompt, b.Prompt, a.ExecutionID, b.ExecutionID)
+	}
+
+	return report
+}
+
+func newTaskLogsDiffSide(metadata *claude.ExecutionMetadata) taskLogsDiffSide {
+	side := taskLogsDiffSide{
+		ExecutionID:  metadata.ExecutionID,
+		Status:       string(metadata.Status),
+		Model:        metadata.Model,
+		CostUSD:      metadata.CostUSD,
+		DurationMS:   metadata.DurationMS,
+		FilesChanged: []string{},
+	}
+	if side.Model == "" {
+		side.Model = defaultModelLabel
+	}
+	if metadata.Result != nil {
+		side.FilesChanged = append(side.FilesChanged, metadata.Result.FilesChanged...)
+		sort.Strings(side.FilesChanged)
+	}
+	return side
+}
+
+// stringSetDifference returns the sorted entries of a that are not in b
+func stringSetDifference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+
+	diff := []string{}
+	for _, s := range a {
+		if !inB[s] {
+			diff = append(diff, s)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// unifiedLineDiff renders a single-hunk unified diff of two texts
+func unifiedLineDiff(a, b, labelA, labelB string) string {
+	linesA := strings.Split(a, "\n")
+	linesB := strings.Split(b, "\n")
+
+	// Longest common subsequence table over lines
+	lcs := make([][]int, len(linesA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(linesB)+1)
+	}
+	for i := len(linesA) - 1; i >= 0; i-- {
+		for j := len(linesB) - 1; j >= 0; j-- {
+			if linesA[i] == linesB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", labelA, labelB)
+	fmt.Fprintf(&sb, "@@ -1,%d +1,%d @@\n", len(linesA), len(linesB))
+
+	i, j := 0, 0
+	for i < len(linesA) || j < len(linesB) {
+		switch {
+		case i < len(linesA) && j < len(linesB) && linesA[i] == linesB[j]:
+			fmt.Fprintf(&sb, " %s\n", linesA[i])
+			i++
+			j++
+		case i < len(linesA) && (j == len(linesB) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&sb, "-%s\n", linesA[i])
+			i++
+		default:
+			fmt.Fprintf(&sb, "+%s\n", linesB[j])
+			j++
+		}
+	}
+
+	return sb.String()
+}
+
+// printTaskLogsDiff writes a side-by-side comparison
+func printTaskLogsDiff(w io.Writer, report taskLogsDiffReport) {
+	a, b := report.A, report.B
+	durationA := time.Duration(a.DurationMS) * time.Millisecond
+	durationB := time.Duration(b.DurationMS) * time.Millisecond
+
+	_, _ = fmt.Fprintln(w, "Execution Diff")
+	_, _ = fmt.Fprintln(w, "==============")
+	_, _ = fmt.Fprintf(w, "%-10s %-24s %-24s\n", "", a.ExecutionID, b.ExecutionID)
+	_, _ = fmt.Fprintf(w, "%-10s %-24s %-24s\n", "Status:", a.Status, b.Status)
+	_, _ = fmt.Fprintf(w, "%-10s %-24s %-24s\n", "Model:", a.Model, b.Model)
+	_, _ = fmt.Fprintf(w, "%-10s %-24s %-24s (%+.4f)\n", "Cost:",
+		fmt.Sprintf("$%.4f", a.CostUSD), fmt.Sprintf("$%.4f", b.CostUSD), report.CostDeltaUSD)
+	_, _ = fmt.Fprintf(w, "%-10s %-24s %-24s (%s)\n", "Duration:",
+		formatTaskWorkerDuration(durationA), formatTaskWorkerDuration(durationB), formatSignedDuration(durationB-durationA))
+	_, _ = fmt.Fprintf(w, "%-10s %-24d %-24d\n", "Files:", len(a.FilesChanged), len(b.FilesChanged))
+
+	if len(report.FilesOnlyInA) > 0 {
+		_, _ = fmt.Fprintf(w, "\nFiles changed only in %s:\n", a.ExecutionID)
+		for _, f := range report.FilesOnlyInA {
+			_, _ = fmt.Fprintf(w, "  - %s\n", f)
+		}
+	}
+	if len(report.FilesOnlyInB) > 0 {
+		_, _ = fmt.Fprintf(w, "\nFiles changed only in %s:\n", b.ExecutionID)
+		for _, f := range report.FilesOnlyInB {
+			_, _ = fmt.Fprintf(w, "  + %s\n", f)
+		}
+	}
+
+	if report.PromptDiff == "" {
+		_, _ = fmt.Fprintln(w, "\nPrompts are identical.")
+	} else {
+		_, _ = fmt.Fprintf(w, "\nPrompt diff:\n%s", report.PromptDiff)
+	}
+}
+
+// formatSignedDuration formats a duration delta with an explicit sign
+func formatSignedDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + formatTaskWorkerDuration(-d)
+	}
+	return "+" + formatTaskWorkerDuration(d)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestTaskLogsDiff(t *testing.T) {
+	execMgr, err := claude.NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create execution manager: %v", err)
+	}
+
+	// Fixtures mirror the metadata written by the execution engine
+	fixtures := map[string]string{
+		"20250102-100000-task-aaa.json": `{
+  "execution_id": "task-aaa",
+  "start_time": "2025-01-02T10:00:00Z",
+  "status": "completed",
+  "prompt": "Add login\nUse JWT",
+  "model": "sonnet",
+  "cost_usd": 0.10,
+  "duration_ms": 60000,
+  "result": {"success": true, "files_changed": ["auth.go", "main.go"]}
+}`,
+		"20250102-110000-task-bbb.json": `{
+  "execution_id": "task-bbb",
+  "start_time": "2025-01-02T11:00:00Z",
+  "status": "failed",
+  "prompt": "Add login\nUse sessions",
+  "cost_usd": 0.35,
+  "duration_ms": 30000,
+  "result": {"success": false, "files_changed": ["main.go", "session.go"]}
+}`,
+	}
+	for name, content := range fixtures {
+		path := filepath.Join(execMgr.GetLogDir(), "metadata", name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+	}
+
+	a, b, err := loadTaskExecutionPair(execMgr, "task-aaa", "task-bbb")
+	if err != nil {
+		t.Fatalf("loadTaskExecutionPair() error = %v", err)
+	}
+	report := buildTaskLogsDiff(a, b)
+
+	if report.B.Model != defaultModelLabel {
+		t.Errorf("B.Model = %q, want %q", report.B.Model, defaultModelLabel)
+	}
+	if math.Abs(report.CostDeltaUSD-0.25) > 1e-9 {
+		t.Errorf("CostDeltaUSD = %v, want 0.25", report.CostDeltaUSD)
+	}
+	if report.DurationDeltaMS != -30000 {
+		t.Errorf("DurationDeltaMS = %d, want -30000", report.DurationDeltaMS)
+	}
+	if !reflect.DeepEqual(report.FilesOnlyInA, []string{"auth.go"}) {
+		t.Errorf("FilesOnlyInA = %v, want [auth.go]", report.FilesOnlyInA)
+	}
+	if !reflect.DeepEqual(report.FilesOnlyInB, []string{"session.go"}) {
+		t.Errorf("FilesOnlyInB = %v, want [session.go]", report.FilesOnlyInB)
+	}
+
+	wantDiff := "--- task-aaa\n+++ task-bbb\n@@ -1,2 +1,2 @@\n Add login\n-Use JWT\n+Use sessions\n"
+	if report.PromptDiff != wantDiff {
+		t.Errorf("PromptDiff = %q, want %q", report.PromptDiff, wantDiff)
+	}
+
+	var out bytes.Buffer
+	printTaskLogsDiff(&out, report)
+	for _, want := range []string{"$0.1000", "$0.3500", "(+0.2500)", "(-30s)", "  - auth.go", "  + session.go", "+Use sessions"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// Identical executions produce no prompt diff
+	if same := buildTaskLogsDiff(a, a); same.PromptDiff != "" || len(same.FilesOnlyInA) != 0 {
+		t.Errorf("self diff = %+v, want no differences", same)
+	}
+}
+
+func TestTaskLogsDiffMissingExecution(t *testing.T) {
+	execMgr, err := claude.NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create execution manager: %v", err)
+	}
+
+	_, _, err = loadTaskExecutionPair(execMgr, "task-missing-a", "task-missing-b")
+	if err == nil || !strings.Contains(err.Error(), "task-missing-a") {
+		t.Errorf("error = %v, want it to name task-missing-a", err)
+	}
+}
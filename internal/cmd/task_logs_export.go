@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var taskLogsExportCmd = &cobra.Command{
+	Use:   "export [EXEC_IDS...]",
+	Short: "Bundle execution logs into a tarball",
+	Long: `Bundle the raw JSONL logs and metadata of executions into a .tar.gz archive.
+
+The archive mirrors the log directory layout (executions/ and metadata/) and
+contains a manifest.json describing each included execution, which makes it
+easy to share repro steps with teammates. Missing files are skipped with a
+warning. When no execution IDs are given, executions are selected with the
+fuzzy finder (Tab selects multiple).`,
+	Example: `  # Export two executions
+  gwq task logs export exec-a1b2c3 exec-d4e5f6 --output repro.tar.gz
+
+  # Pick executions interactively
+  gwq task logs export`,
+	RunE: runTaskLogsExport,
+}
+
+var taskLogsExportOutput string
+
+func init() {
+	taskLogsCmd.AddCommand(taskLogsExportCmd)
+
+	taskLogsExportCmd.Flags().StringVarP(&taskLogsExportOutput, "output", "o", "", "Archive path (default: gwq-logs-YYYYMMDD-HHMMSS.tar.gz)")
+}
+
+// taskLogsExportManifest describes the contents of an exported archive
+type taskLogsExportManifest struct {
+	CreatedAt  time.Time                 `json:"created_at"`
+	Executions []taskLogsExportExecution `json:"executions"`
+}
+
+// taskLogsExportExecution describes one exported execution. File paths are
+// relative to the archive root and empty when the file was missing.
+type taskLogsExportExecution struct {
+	ExecutionID  string                 `json:"execution_id"`
+	Status       claude.ExecutionStatus `json:"status"`
+	StartTime    time.Time              `json:"start_time"`
+	Repository   string                 `json:"repository"`
+	Model        string                 `json:"model"`
+	CostUSD      float64                `json:"cost_usd"`
+	Prompt       string                 `json:"prompt"`
+	LogFile      string                 `json:"log_file,omitempty"`
+	MetadataFile string                 `json:"metadata_file,omitempty"`
+}
+
+func runTaskLogsExport(cmd *cobra.Command, args []string) error {
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+
+	executionIDs := args
+	if len(executionIDs) == 0 {
+		executions, err := loadTaskExecutionsFromIndex(execMgr)
+		if err != nil {
+			return fmt.Errorf("failed to load executions: %w", err)
+		}
+
+		selected, err := selectTaskExecutionsWithFinder(executions)
+		if err != nil {
+			return fmt.Errorf("failed to select executions: %w", err)
+		}
+		for _, exec := range selected {
+			executionIDs = append(executionIDs, exec.ExecutionID)
+		}
+	}
+	if len(executionIDs) == 0 {
+		fmt.Println("No executions selected.")
+		return nil
+	}
+
+	var executions []*claude.ExecutionMetadata
+	for _, id := range executionIDs {
+		metadata, err := execMgr.LoadMetadata(id)
+		if err != nil {
+			return fmt.Errorf("execution %s not found: %w", id, err)
+		}
+		executions = append(executions, metadata)
+	}
+
+	output := taskLogsExportOutput
+	if output == "" {
+		output = fmt.Sprintf("gwq-logs-%s.tar.gz", time.Now().Format("20060102-150405"))
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	manifest, err := exportTaskExecutions(file, execMgr.GetLogDir(), executions)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close archive: %w", closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		return err
+	}
+
+	fmt.Printf("Exported %d executions to %s\n", len(manifest.Executions), output)
+	return nil
+}
+
+// exportTaskExecutions writes a gzipped tar of the executions' logs, metadata
+// and a manifest to w. Missing files are skipped with a warning.
+func exportTaskExecutions(w io.Writer, logDir string, executions []*claude.ExecutionMetadata) (*taskLogsExportManifest, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest := &taskLogsExportManifest{CreatedAt: time.Now()}
+	for _, metadata := range executions {
+		entry := taskLogsExportExecution{
+			ExecutionID: metadata.ExecutionID,
+			Status:      metadata.Status,
+			StartTime:   metadata.StartTime,
+			Repository:  metadata.Repository,
+			Model:       metadata.Model,
+			CostUSD:     metadata.CostUSD,
+			Prompt:      metadata.Prompt,
+		}
+
+		logFile := claude.FindLogFileByExecutionID(logDir, metadata.StartTime, metadata.ExecutionID)
+		name := path.Join("executions", filepath.Base(logFile))
+		if ok, err := addFileToTar(tw, logFile, name); err != nil {
+			return nil, err
+		} else if ok {
+			entry.LogFile = name
+		} else {
+			logging.Warnf("log file not found for %s, skipping", metadata.ExecutionID)
+		}
+		for _, part := range claude.LogFileParts(logFile)[1:] {
+			if _, err := addFileToTar(tw, part, path.Join("executions", filepath.Base(part))); err != nil {
+				return nil, err
+			}
+		}
+
+		metadataFile := taskExecutionMetadataFile(logDir, metadata)
+		name = path.Join("metadata", filepath.Base(metadataFile))
+		if ok, err := addFileToTar(tw, metadataFile, name); err != nil {
+			return nil, err
+		} else if ok {
+			entry.MetadataFile = name
+		} else {
+			logging.Warnf("metadata file not found for %s, skipping", metadata.ExecutionID)
+		}
+
+		manifest.Executions = append(manifest.Executions, entry)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	header := &tar.Header{
+		Name:    "manifest.json",
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// addFileToTar copies the file at src into the archive as name. It reports
+// false without an error when src does not exist.
+func addFileToTar(tw *tar.Writer, src, name string) (bool, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return false, fmt.Errorf("failed to create header for %s: %w", src, err)
+	}
+	header.Name = name
+
+	if err := tw.WriteHeader(header); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	// Copy only the size in the header, as a running execution may still be
+	// appending to its log
+	if _, err := io.CopyN(tw, file, header.Size); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return true, nil
+}
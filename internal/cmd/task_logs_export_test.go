@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestExportTaskExecutions(t *testing.T) {
+	execMgr, err := claude.NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create execution manager: %v", err)
+	}
+
+	base := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	fixtures := []*claude.ExecutionMetadata{
+		{ExecutionID: "exec-1", StartTime: base, Status: claude.ExecutionStatusCompleted, Prompt: "Fix the tests", CostUSD: 0.10},
+		{ExecutionID: "exec-2", StartTime: base.Add(time.Minute), Status: claude.ExecutionStatusFailed, Prompt: "Add a feature", CostUSD: 0.20},
+	}
+	logDir := execMgr.GetLogDir()
+	for _, fixture := range fixtures {
+		data, err := json.Marshal(fixture)
+		if err != nil {
+			t.Fatalf("failed to marshal fixture: %v", err)
+		}
+		path := filepath.Join(logDir, "metadata", claude.GenerateMetadataFileName(fixture.StartTime, fixture.ExecutionID))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+	}
+
+	// Only exec-1 has a log; the missing log of exec-2 is skipped
+	logName := claude.GenerateLogFileName(base, "exec-1")
+	logBody := `{"type":"result","result":"done"}` + "\n"
+	if err := os.WriteFile(filepath.Join(logDir, "executions", logName), []byte(logBody), 0644); err != nil {
+		t.Fatalf("failed to write log fixture: %v", err)
+	}
+
+	var buf bytes.Buffer
+	manifest, err := exportTaskExecutions(&buf, logDir, fixtures)
+	if err != nil {
+		t.Fatalf("exportTaskExecutions() failed: %v", err)
+	}
+	if len(manifest.Executions) != 2 {
+		t.Fatalf("manifest has %d executions, want 2", len(manifest.Executions))
+	}
+
+	files := readTarGz(t, &buf)
+
+	wantFiles := []string{
+		"manifest.json",
+		"executions/" + logName,
+		"metadata/" + claude.GenerateMetadataFileName(base, "exec-1"),
+		"metadata/" + claude.GenerateMetadataFileName(base.Add(time.Minute), "exec-2"),
+	}
+	if len(files) != len(wantFiles) {
+		t.Errorf("archive has %d files, want %d", len(files), len(wantFiles))
+	}
+	for _, name := range wantFiles {
+		if _, ok := files[name]; !ok {
+			t.Errorf("archive is missing %s", name)
+		}
+	}
+	if got := files["executions/"+logName]; got != logBody {
+		t.Errorf("log content = %q, want %q", got, logBody)
+	}
+
+	var got taskLogsExportManifest
+	if err := json.Unmarshal([]byte(files["manifest.json"]), &got); err != nil {
+		t.Fatalf("failed to parse manifest: %v", err)
+	}
+	if len(got.Executions) != 2 {
+		t.Fatalf("manifest.json has %d executions, want 2", len(got.Executions))
+	}
+	first, second := got.Executions[0], got.Executions[1]
+	if first.ExecutionID != "exec-1" || first.LogFile != "executions/"+logName || first.Prompt != "Fix the tests" {
+		t.Errorf("unexpected manifest entry: %+v", first)
+	}
+	if second.ExecutionID != "exec-2" || second.LogFile != "" || second.MetadataFile == "" || second.Status != claude.ExecutionStatusFailed {
+		t.Errorf("unexpected manifest entry: %+v", second)
+	}
+}
+
+// readTarGz returns the regular files of a gzipped tar keyed by name
+func readTarGz(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatalf("failed to open gzip stream: %v", err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read archive: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", header.Name, err)
+		}
+		files[header.Name] = string(data)
+	}
+	return files
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var taskLogsOpenCmd = &cobra.Command{
+	Use:   "open [EXECUTION_ID]",
+	Short: "Open an execution's raw log in an editor",
+	Long: `Open the unprocessed JSONL log of an execution.
+
+The log is opened with $EDITOR, falling back to $PAGER and then less.
+When no execution ID is given, an execution is selected with the fuzzy finder.`,
+	Example: `  # Open the raw log of an execution
+  gwq task logs open exec-a1b2c3
+
+  # Open the execution's metadata file instead
+  gwq task logs open exec-a1b2c3 --metadata
+
+  # Use a specific viewer
+  PAGER="less -S" EDITOR= gwq task logs open exec-a1b2c3`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskLogsOpen,
+}
+
+var taskLogsOpenMetadata bool
+
+func init() {
+	taskLogsCmd.AddCommand(taskLogsOpenCmd)
+
+	taskLogsOpenCmd.Flags().BoolVar(&taskLogsOpenMetadata, "metadata", false, "Open the JSON metadata file instead of the log")
+}
+
+func runTaskLogsOpen(cmd *cobra.Command, args []string) error {
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+
+	var executionID string
+	if len(args) > 0 {
+		executionID = args[0]
+	} else {
+		executions, err := loadTaskExecutionsFromMetadata(execMgr)
+		if err != nil {
+			return fmt.Errorf("failed to load executions: %w", err)
+		}
+
+		selected, err := selectTaskExecutionWithFinder(executions, nil)
+		if err != nil {
+			return fmt.Errorf("failed to select execution: %w", err)
+		}
+		if selected == nil {
+			return nil
+		}
+		executionID = selected.ExecutionID
+	}
+
+	metadata, err := execMgr.LoadMetadata(executionID)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata for %s: %w", executionID, err)
+	}
+
+	path := claude.FindLogFileByExecutionID(execMgr.GetLogDir(), metadata.StartTime, metadata.ExecutionID)
+	if taskLogsOpenMetadata {
+		path = taskExecutionMetadataFile(execMgr.GetLogDir(), metadata)
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("Log file not found: %s\n", path)
+		fmt.Printf("⚠️  This execution may have been interrupted or not properly initialized.\n")
+		return nil
+	}
+
+	viewer := resolveTaskLogViewer(os.Getenv)
+	viewerCmd := exec.Command(viewer[0], append(viewer[1:], path)...)
+	viewerCmd.Stdin = os.Stdin
+	viewerCmd.Stdout = os.Stdout
+	viewerCmd.Stderr = os.Stderr
+
+	if err := viewerCmd.Run(); err != nil {
+		return fmt.Errorf("failed to open %s with %s: %w", path, viewer[0], err)
+	}
+	return nil
+}
+
+// resolveTaskLogViewer returns the command used to open a log file:
+// $EDITOR, then $PAGER, then less. Values may include arguments.
+func resolveTaskLogViewer(getenv func(string) string) []string {
+	for _, key := range []string{"EDITOR", "PAGER"} {
+		if fields := strings.Fields(getenv(key)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"less"}
+}
+
+// taskExecutionMetadataFile returns the metadata file of an execution,
+// preferring a legacy file named after the execution ID when one exists
+func taskExecutionMetadataFile(logDir string, metadata *claude.ExecutionMetadata) string {
+	metadataDir := filepath.Join(logDir, "metadata")
+	legacy := filepath.Join(metadataDir, metadata.ExecutionID+".json")
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+	return filepath.Join(metadataDir, claude.GenerateMetadataFileName(metadata.StartTime, metadata.ExecutionID))
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveTaskLogViewer(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{
+			name: "editor takes precedence",
+			env:  map[string]string{"EDITOR": "vim", "PAGER": "more"},
+			want: []string{"vim"},
+		},
+		{
+			name: "editor with arguments",
+			env:  map[string]string{"EDITOR": "code --wait"},
+			want: []string{"code", "--wait"},
+		},
+		{
+			name: "pager when editor is unset",
+			env:  map[string]string{"PAGER": "less -S"},
+			want: []string{"less", "-S"},
+		},
+		{
+			name: "blank editor falls back to pager",
+			env:  map[string]string{"EDITOR": "  ", "PAGER": "more"},
+			want: []string{"more"},
+		},
+		{
+			name: "less when nothing is set",
+			env:  map[string]string{},
+			want: []string{"less"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveTaskLogViewer(func(key string) string { return tt.env[key] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveTaskLogViewer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var taskLogsReplayCmd = &cobra.Command{
+	Use:   "replay [EXECUTION_ID]",
+	Short: "Replay an execution's operation flow step by step",
+	Long: `Print the operation flow of an execution one step at a time.
+
+Steps are printed with a --speed delay between them, or one per Enter key
+with --step. When no execution ID is given, an execution is selected with the
+fuzzy finder. Press Ctrl+C to stop the replay.`,
+	Example: `  # Replay an execution with a second between steps
+  gwq task logs replay exec-a1b2c3
+
+  # Replay faster
+  gwq task logs replay exec-a1b2c3 --speed 200ms
+
+  # Advance manually with Enter
+  gwq task logs replay exec-a1b2c3 --step`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskLogsReplay,
+}
+
+var (
+	taskLogsReplaySpeed time.Duration
+	taskLogsReplayStep  bool
+)
+
+func init() {
+	taskLogsCmd.AddCommand(taskLogsReplayCmd)
+
+	taskLogsReplayCmd.Flags().DurationVar(&taskLogsReplaySpeed, "speed", time.Second, "Delay between steps")
+	taskLogsReplayCmd.Flags().BoolVar(&taskLogsReplayStep, "step", false, "Wait for Enter before each step instead of --speed")
+}
+
+// errTaskLogsReplayStopped ends a --step replay when stdin is closed
+var errTaskLogsReplayStopped = errors.New("replay stopped")
+
+func runTaskLogsReplay(cmd *cobra.Command, args []string) error {
+	if taskLogsReplaySpeed < 0 {
+		return fmt.Errorf("--speed must not be negative")
+	}
+
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+
+	var executionID string
+	if len(args) > 0 {
+		executionID = args[0]
+	} else {
+		executions, err := loadTaskExecutionsFromMetadata(execMgr)
+		if err != nil {
+			return fmt.Errorf("failed to load executions: %w", err)
+		}
+
+		selected, err := selectTaskExecutionWithFinder(executions, nil)
+		if err != nil {
+			return fmt.Errorf("failed to select execution: %w", err)
+		}
+		if selected == nil {
+			return nil
+		}
+		executionID = selected.ExecutionID
+	}
+
+	metadata, err := execMgr.LoadMetadata(executionID)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata for %s: %w", executionID, err)
+	}
+
+	lp := claude.NewLogProcessor()
+	steps, err := lp.OperationFlow(claude.FindLogFileByExecutionID(execMgr.GetLogDir(), metadata.StartTime, metadata.ExecutionID))
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		fmt.Println("No operations to replay.")
+		return nil
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	next := taskLogsReplayDelay(taskLogsReplaySpeed)
+	if taskLogsReplayStep {
+		next = taskLogsReplayPrompt(cmd.InOrStdin(), cmd.ErrOrStderr())
+	}
+
+	err = lp.ReplayOperationFlow(ctx, cmd.OutOrStdout(), steps, next)
+	if errors.Is(err, context.Canceled) || errors.Is(err, errTaskLogsReplayStopped) {
+		return nil
+	}
+	return err
+}
+
+// taskLogsReplayDelay waits d between replayed steps
+func taskLogsReplayDelay(d time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+// taskLogsReplayPrompt waits for a line on in between replayed steps. The
+// end of input stops the replay.
+func taskLogsReplayPrompt(in io.Reader, prompt io.Writer) func(context.Context) error {
+	lines := make(chan error)
+	reader := bufio.NewReader(in)
+
+	return func(ctx context.Context) error {
+		_, _ = fmt.Fprint(prompt, "-- Press Enter for the next step --")
+		go func() {
+			_, err := reader.ReadString('\n')
+			select {
+			case lines <- err:
+			case <-ctx.Done():
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-lines:
+			_, _ = fmt.Fprint(prompt, "\r\033[K")
+			if errors.Is(err, io.EOF) {
+				return errTaskLogsReplayStopped
+			}
+			return err
+		}
+	}
+}
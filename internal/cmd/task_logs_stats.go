@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/internal/table"
+	"github.com/spf13/cobra"
+)
+
+var taskLogsStatsCmd = &cobra.Command{
+	Use:   "stats [EXECUTION_ID]",
+	Short: "Show per-tool usage statistics",
+	Long: `Show how often each tool was used, how often it failed, and how long it took.
+
+Statistics cover a single execution when an ID is given, or all executions
+otherwise. Time per tool is the time between the tool call and its result,
+and is only counted for log entries that carry timestamps.`,
+	Example: `  # Tool usage of one execution
+  gwq task logs stats exec-a1b2c3
+
+  # Tool usage across all executions as JSON
+  gwq task logs stats --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskLogsStats,
+}
+
+var taskLogsStatsJSON bool
+
+func init() {
+	taskLogsCmd.AddCommand(taskLogsStatsCmd)
+
+	taskLogsStatsCmd.Flags().BoolVar(&taskLogsStatsJSON, "json", false, "Output in JSON format")
+}
+
+func runTaskLogsStats(cmd *cobra.Command, args []string) error {
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+
+	var executions []claude.ExecutionMetadata
+	if len(args) > 0 {
+		metadata, err := execMgr.LoadMetadata(args[0])
+		if err != nil {
+			return fmt.Errorf("execution %s not found: %w", args[0], err)
+		}
+		executions = append(executions, *metadata)
+	} else {
+		executions, err = loadTaskExecutionsFromIndex(execMgr)
+		if err != nil {
+			return fmt.Errorf("failed to load executions: %w", err)
+		}
+	}
+
+	stats, err := collectTaskToolStats(execMgr.GetLogDir(), executions)
+	if err != nil {
+		return err
+	}
+
+	if taskLogsStatsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+
+	return printTaskToolStats(os.Stdout, stats)
+}
+
+// collectTaskToolStats aggregates tool usage from the logs of executions
+func collectTaskToolStats(logDir string, executions []claude.ExecutionMetadata) ([]claude.ToolStats, error) {
+	logFiles := make([]string, 0, len(executions))
+	for _, exec := range executions {
+		logFiles = append(logFiles, claude.FindLogFileByExecutionID(logDir, exec.StartTime, exec.ExecutionID))
+	}
+	return claude.NewLogProcessor().CollectToolStats(logFiles...)
+}
+
+func printTaskToolStats(w io.Writer, stats []claude.ToolStats) error {
+	if len(stats) == 0 {
+		_, err := fmt.Fprintln(w, "No tool usage found")
+		return err
+	}
+
+	t := table.New().SetOutput(w).Headers("TOOL", "USES", "OK", "FAILED", "FAILURE RATE", "TIME")
+	for _, s := range stats {
+		t.Row(
+			s.Name,
+			strconv.Itoa(s.Uses),
+			strconv.Itoa(s.Succeeded),
+			strconv.Itoa(s.Failed),
+			fmt.Sprintf("%.0f%%", s.FailureRatio*100),
+			formatTaskWorkerDuration(time.Duration(s.TotalDurationMS)*time.Millisecond),
+		)
+	}
+	return t.Println()
+}
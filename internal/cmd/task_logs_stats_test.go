@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+)
+
+func TestCollectTaskToolStats(t *testing.T) {
+	logDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(logDir, "executions"), 0755); err != nil {
+		t.Fatalf("failed to create log dir: %v", err)
+	}
+
+	base := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	logs := map[string]string{
+		"exec-1": `{"type":"assistant","timestamp":"2025-01-02T10:00:00Z","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}},{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"a.go"}}]}}
+{"type":"user","timestamp":"2025-01-02T10:00:04Z","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"FAIL","is_error":true},{"type":"tool_result","tool_use_id":"t2","content":[{"type":"text","text":"File updated"}]}]}}
+`,
+		"exec-2": `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go vet ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go build ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"ok","is_error":false}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"sleep 100"}}]}}
+`,
+	}
+
+	var executions []claude.ExecutionMetadata
+	for i, id := range []string{"exec-1", "exec-2", "exec-3"} {
+		exec := claude.ExecutionMetadata{ExecutionID: id, StartTime: base.Add(time.Duration(i) * time.Minute)}
+		executions = append(executions, exec)
+		if body, ok := logs[id]; ok {
+			path := filepath.Join(logDir, "executions", claude.GenerateLogFileName(exec.StartTime, id))
+			if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+				t.Fatalf("failed to write log fixture: %v", err)
+			}
+		}
+	}
+
+	stats, err := collectTaskToolStats(logDir, executions)
+	if err != nil {
+		t.Fatalf("collectTaskToolStats() failed: %v", err)
+	}
+
+	// The unfinished "sleep" call counts as a use but not towards the failure ratio
+	want := []claude.ToolStats{
+		{Name: "Bash", Uses: 4, Succeeded: 2, Failed: 1, FailureRatio: 1.0 / 3, TotalDurationMS: 4000},
+		{Name: "Edit", Uses: 1, Succeeded: 1, TotalDurationMS: 4000},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("stats = %+v, want %+v", stats, want)
+	}
+	for i := range want {
+		if stats[i] != want[i] {
+			t.Errorf("stats[%d] = %+v, want %+v", i, stats[i], want[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := printTaskToolStats(&buf, stats); err != nil {
+		t.Fatalf("printTaskToolStats() failed: %v", err)
+	}
+	for _, text := range []string{"Bash", "33%", "Edit", "4s"} {
+		if !strings.Contains(buf.String(), text) {
+			t.Errorf("output missing %q:\n%s", text, buf.String())
+		}
+	}
+}
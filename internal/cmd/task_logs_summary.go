@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+)
+
+// defaultModelLabel groups executions that ran with the Claude CLI default model
+const defaultModelLabel = "(default)"
+
+// taskLogsSummaryReport aggregates cost and duration across executions
+type taskLogsSummaryReport struct {
+	Executions      int                    `json:"executions"`
+	TotalCostUSD    float64                `json:"total_cost_usd"`
+	AverageCostUSD  float64                `json:"average_cost_usd"`
+	TotalDurationMS int64                  `json:"total_duration_ms"`
+	Models          []taskLogsModelSummary `json:"models"`
+}
+
+// taskLogsModelSummary aggregates executions for a single model
+type taskLogsModelSummary struct {
+	Model           string  `json:"model"`
+	Executions      int     `json:"executions"`
+	TotalCostUSD    float64 `json:"total_cost_usd"`
+	TotalDurationMS int64   `json:"total_duration_ms"`
+}
+
+// summarizeTaskExecutions computes totals and a per-model breakdown
+func summarizeTaskExecutions(executions []claude.ExecutionMetadata) taskLogsSummaryReport {
+	report := taskLogsSummaryReport{Models: []taskLogsModelSummary{}}
+	byModel := make(map[string]*taskLogsModelSummary)
+
+	for _, exec := range executions {
+		report.Executions++
+		report.TotalCostUSD += exec.CostUSD
+		report.TotalDurationMS += exec.DurationMS
+
+		model := exec.Model
+		if model == "" {
+			model = defaultModelLabel
+		}
+		summary, ok := byModel[model]
+		if !ok {
+			summary = &taskLogsModelSummary{Model: model}
+			byModel[model] = summary
+		}
+		summary.Executions++
+		summary.TotalCostUSD += exec.CostUSD
+		summary.TotalDurationMS += exec.DurationMS
+	}
+
+	if report.Executions > 0 {
+		report.AverageCostUSD = report.TotalCostUSD / float64(report.Executions)
+	}
+
+	for _, summary := range byModel {
+		report.Models = append(report.Models, *summary)
+	}
+	// Most expensive models first, then by name for a stable order
+	sort.Slice(report.Models, func(i, j int) bool {
+		if report.Models[i].TotalCostUSD != report.Models[j].TotalCostUSD {
+			return report.Models[i].TotalCostUSD > report.Models[j].TotalCostUSD
+		}
+		return report.Models[i].Model < report.Models[j].Model
+	})
+
+	return report
+}
+
+func outputTaskLogsSummary(executions []claude.ExecutionMetadata, asJSON bool) error {
+	report := summarizeTaskExecutions(executions)
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	fmt.Println("Execution Summary")
+	fmt.Println("=================")
+	fmt.Printf("Executions:     %d\n", report.Executions)
+	fmt.Printf("Total cost:     $%.4f\n", report.TotalCostUSD)
+	fmt.Printf("Average cost:   $%.4f per execution\n", report.AverageCostUSD)
+	fmt.Printf("Total duration: %s\n", formatTaskWorkerDuration(time.Duration(report.TotalDurationMS)*time.Millisecond))
+
+	if len(report.Models) > 0 {
+		fmt.Println("\nBy Model:")
+		for _, m := range report.Models {
+			fmt.Printf("  %-24s %4d runs  $%.4f  %s\n",
+				m.Model, m.Executions, m.TotalCostUSD,
+				formatTaskWorkerDuration(time.Duration(m.TotalDurationMS)*time.Millisecond))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestSummarizeTaskExecutions(t *testing.T) {
+	execMgr, err := claude.NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create execution manager: %v", err)
+	}
+
+	base := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	fixtures := []claude.ExecutionMetadata{
+		{ExecutionID: "exec-1", StartTime: base, Status: claude.ExecutionStatusCompleted, Model: "sonnet", CostUSD: 0.10, DurationMS: 1000},
+		{ExecutionID: "exec-2", StartTime: base.Add(time.Minute), Status: claude.ExecutionStatusCompleted, Model: "sonnet", CostUSD: 0.20, DurationMS: 2000},
+		{ExecutionID: "exec-3", StartTime: base.Add(2 * time.Minute), Status: claude.ExecutionStatusCompleted, Model: "opus", CostUSD: 0.90, DurationMS: 3000},
+		{ExecutionID: "exec-4", StartTime: base.Add(3 * time.Minute), Status: claude.ExecutionStatusFailed, CostUSD: 0, DurationMS: 500},
+	}
+	metadataDir := filepath.Join(execMgr.GetLogDir(), "metadata")
+	for _, fixture := range fixtures {
+		data, err := json.Marshal(fixture)
+		if err != nil {
+			t.Fatalf("failed to marshal fixture: %v", err)
+		}
+		path := filepath.Join(metadataDir, claude.GenerateMetadataFileName(fixture.StartTime, fixture.ExecutionID))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+
+		// Executions without a log file are reported as aborted
+		logFile := filepath.Join(execMgr.GetLogDir(), "executions", claude.GenerateLogFileName(fixture.StartTime, fixture.ExecutionID))
+		if err := os.WriteFile(logFile, nil, 0644); err != nil {
+			t.Fatalf("failed to write log fixture: %v", err)
+		}
+	}
+
+	executions, err := loadTaskExecutionsFromMetadata(execMgr)
+	if err != nil {
+		t.Fatalf("loadTaskExecutionsFromMetadata() error = %v", err)
+	}
+
+	report := summarizeTaskExecutions(executions)
+
+	if report.Executions != 4 {
+		t.Errorf("Executions = %d, want 4", report.Executions)
+	}
+	if !floatEqual(report.TotalCostUSD, 1.20) {
+		t.Errorf("TotalCostUSD = %f, want 1.20", report.TotalCostUSD)
+	}
+	if !floatEqual(report.AverageCostUSD, 0.30) {
+		t.Errorf("AverageCostUSD = %f, want 0.30", report.AverageCostUSD)
+	}
+	if report.TotalDurationMS != 6500 {
+		t.Errorf("TotalDurationMS = %d, want 6500", report.TotalDurationMS)
+	}
+
+	want := []taskLogsModelSummary{
+		{Model: "opus", Executions: 1, TotalCostUSD: 0.90, TotalDurationMS: 3000},
+		{Model: "sonnet", Executions: 2, TotalCostUSD: 0.30, TotalDurationMS: 3000},
+		{Model: defaultModelLabel, Executions: 1, TotalCostUSD: 0, TotalDurationMS: 500},
+	}
+	if len(report.Models) != len(want) {
+		t.Fatalf("Models = %+v, want %+v", report.Models, want)
+	}
+	for i, m := range report.Models {
+		if m.Model != want[i].Model || m.Executions != want[i].Executions ||
+			!floatEqual(m.TotalCostUSD, want[i].TotalCostUSD) || m.TotalDurationMS != want[i].TotalDurationMS {
+			t.Errorf("Models[%d] = %+v, want %+v", i, m, want[i])
+		}
+	}
+
+	// Filters compose with the summary
+	failed := summarizeTaskExecutions(filterTaskExecutionsByStatus(executions, "failed"))
+	if failed.Executions != 1 || failed.TotalCostUSD != 0 || failed.AverageCostUSD != 0 {
+		t.Errorf("failed-only summary = %+v, want a single zero-cost execution", failed)
+	}
+}
+
+func TestSummarizeTaskExecutionsEmpty(t *testing.T) {
+	report := summarizeTaskExecutions(nil)
+
+	if report.Executions != 0 || report.TotalCostUSD != 0 || report.AverageCostUSD != 0 {
+		t.Errorf("empty summary = %+v, want zero values", report)
+	}
+	if report.Models == nil {
+		t.Error("Models should be an empty slice, not nil")
+	}
+}
+
+func floatEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
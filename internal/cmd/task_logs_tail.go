@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var taskLogsTailCmd = &cobra.Command{
+	Use:   "tail [EXECUTION_ID]",
+	Short: "Follow a running execution's log",
+	Long: `Follow a Claude execution's log live until it stops running.
+
+When no execution ID is given, a running execution is selected with the
+fuzzy finder. If the log file has not been created yet, tail waits for it.`,
+	Example: `  # Follow a running execution (interactive selection)
+  gwq task logs tail
+
+  # Follow a specific execution
+  gwq task logs tail exec-a1b2c3
+
+  # Print the raw JSONL stream
+  gwq task logs tail exec-a1b2c3 --raw`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskLogsTail,
+}
+
+var taskLogsTailRaw bool
+
+func init() {
+	taskLogsCmd.AddCommand(taskLogsTailCmd)
+
+	taskLogsTailCmd.Flags().BoolVar(&taskLogsTailRaw, "raw", false, "Print unparsed JSONL lines")
+}
+
+func runTaskLogsTail(cmd *cobra.Command, args []string) error {
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+	reconcileTaskExecutions(execMgr)
+
+	var executionID string
+	if len(args) > 0 {
+		executionID = args[0]
+	} else {
+		executions, err := loadTaskExecutionsFromMetadata(execMgr)
+		if err != nil {
+			return fmt.Errorf("failed to load executions: %w", err)
+		}
+
+		running := filterTaskExecutionsByStatus(executions, string(claude.ExecutionStatusRunning))
+		if len(running) == 0 {
+			fmt.Println("No running executions.")
+			return nil
+		}
+
+		selected, err := selectTaskExecutionWithFinder(running, nil)
+		if err != nil {
+			return fmt.Errorf("failed to select execution: %w", err)
+		}
+		if selected == nil {
+			return nil
+		}
+		executionID = selected.ExecutionID
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	err = execMgr.WatchExecutionWithOptions(ctx, executionID, claude.WatchOptions{Raw: taskLogsTailRaw})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
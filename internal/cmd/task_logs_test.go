@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestFilterTaskExecutionsByContent(t *testing.T) {
+	execMgr, err := claude.NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create execution manager: %v", err)
+	}
+
+	startTime := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	executions := []claude.ExecutionMetadata{
+		{ExecutionID: "exec-prompt", StartTime: startTime, Prompt: "Add authentication middleware"},
+		{ExecutionID: "exec-tag", StartTime: startTime, Prompt: "Refactor handlers", Tags: []string{"authentication"}},
+		{ExecutionID: "exec-body", StartTime: startTime, Prompt: "Fix login bug"},
+		{ExecutionID: "exec-none", StartTime: startTime, Prompt: "Update README"},
+	}
+
+	body := `{"type":"assistant","message":{"content":[{"type":"text","text":"Looking at the code"}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"session token failed Authentication check"}]}}
+`
+	logFile := filepath.Join(execMgr.GetLogDir(), "executions", claude.GenerateLogFileName(startTime, "exec-body"))
+	if err := os.WriteFile(logFile, []byte(body), 0644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		deep bool
+		want []string
+	}{
+		{name: "prompt and tags only", deep: false, want: []string{"exec-prompt", "exec-tag"}},
+		{name: "deep includes log body", deep: true, want: []string{"exec-prompt", "exec-tag", "exec-body"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, snippets := filterTaskExecutionsByContent(executions, "authentication", execMgr, tt.deep)
+
+			var got []string
+			for _, exec := range filtered {
+				got = append(got, exec.ExecutionID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("filterTaskExecutionsByContent() = %v, want %v", got, tt.want)
+			}
+
+			for _, id := range tt.want {
+				if !strings.Contains(strings.ToLower(snippets[id]), "authentication") {
+					t.Errorf("snippet for %s = %q, want it to contain the match", id, snippets[id])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterTaskExecutionsByTimeRange(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	executions := []claude.ExecutionMetadata{
+		{ExecutionID: "exec-old", StartTime: now.AddDate(0, 0, -9), Status: claude.ExecutionStatusFailed},
+		{ExecutionID: "exec-week", StartTime: now.AddDate(0, 0, -5), Status: claude.ExecutionStatusCompleted},
+		{ExecutionID: "exec-day", StartTime: now.Add(-12 * time.Hour), Status: claude.ExecutionStatusFailed},
+	}
+
+	tests := []struct {
+		name   string
+		since  string
+		until  string
+		status string
+		want   []string
+	}{
+		{name: "since only", since: "7d", want: []string{"exec-week", "exec-day"}},
+		{name: "until only", until: "2025-01-06T00:00:00Z", want: []string{"exec-old", "exec-week"}},
+		{name: "combined range", since: "7d", until: "24h", want: []string{"exec-week"}},
+		{name: "date bound", since: "2025-01-10", want: []string{"exec-day"}},
+		{name: "with status", since: "10d", status: "failed", want: []string{"exec-old", "exec-day"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since, until, err := parseTaskLogsTimeRange(tt.since, tt.until, now)
+			if err != nil {
+				t.Fatalf("parseTaskLogsTimeRange() error = %v", err)
+			}
+
+			filtered := executions
+			if tt.status != "" {
+				filtered = filterTaskExecutionsByStatus(filtered, tt.status)
+			}
+			filtered = filterTaskExecutionsByTimeRange(filtered, since, until)
+
+			var got []string
+			for _, exec := range filtered {
+				got = append(got, exec.ExecutionID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filtered executions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTaskLogsTimeRangeErrors(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		since   string
+		until   string
+		wantErr string
+	}{
+		{name: "invalid since", since: "last week", wantErr: "invalid --since"},
+		{name: "invalid until", until: "7x", wantErr: "invalid --until"},
+		{name: "since after until", since: "24h", until: "7d", wantErr: "is after --until"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseTaskLogsTimeRange(tt.since, tt.until, now)
+			if err == nil {
+				t.Fatal("parseTaskLogsTimeRange() should fail")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaskLogsFailedOnly(t *testing.T) {
+	executions := []claude.ExecutionMetadata{
+		{ExecutionID: "exec-ok", Status: claude.ExecutionStatusCompleted},
+		{ExecutionID: "exec-fail", Status: claude.ExecutionStatusFailed, ExitCode: 2},
+		{ExecutionID: "exec-running", Status: claude.ExecutionStatusRunning},
+	}
+
+	tests := []struct {
+		name       string
+		status     string
+		failedOnly bool
+		want       []string
+		wantErr    bool
+	}{
+		{name: "no filter", want: []string{"exec-ok", "exec-fail", "exec-running"}},
+		{name: "failed only", failedOnly: true, want: []string{"exec-fail"}},
+		{name: "failed only with matching status", status: "failed", failedOnly: true, want: []string{"exec-fail"}},
+		{name: "failed only with other status", status: "completed", failedOnly: true, wantErr: true},
+		{name: "status alone", status: "running", want: []string{"exec-running"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := taskLogsStatusFilter(tt.status, tt.failedOnly)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("taskLogsStatusFilter() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("taskLogsStatusFilter() error = %v", err)
+			}
+
+			filtered := executions
+			if status != "" {
+				filtered = filterTaskExecutionsByStatus(filtered, status)
+			}
+			var got []string
+			for _, exec := range filtered {
+				got = append(got, exec.ExecutionID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filtered executions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskExecutionFinderLabelExitCode(t *testing.T) {
+	failed := claude.ExecutionMetadata{ExecutionID: "exec-fail", Status: claude.ExecutionStatusFailed, ExitCode: 2, StartTime: time.Now()}
+	if label := taskExecutionFinderLabel(failed); !strings.HasPrefix(label, "[failed exit 2] exec-fail") {
+		t.Errorf("taskExecutionFinderLabel() = %q, want it to start with %q", label, "[failed exit 2] exec-fail")
+	}
+	if preview := taskExecutionFinderPreview(failed, nil); !strings.Contains(preview, "Status: failed exit 2") {
+		t.Errorf("taskExecutionFinderPreview() = %q, want the exit code in the status", preview)
+	}
+
+	completed := claude.ExecutionMetadata{ExecutionID: "exec-ok", Status: claude.ExecutionStatusCompleted, StartTime: time.Now()}
+	if label := taskExecutionFinderLabel(completed); !strings.HasPrefix(label, "[completed] exec-ok") {
+		t.Errorf("taskExecutionFinderLabel() = %q, want no exit code for a successful execution", label)
+	}
+}
+
+func TestLoadTaskExecutionsSameStartTime(t *testing.T) {
+	execMgr, err := claude.NewExecutionManager(&models.ClaudeConfig{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create execution manager: %v", err)
+	}
+
+	batch := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	fixtures := []claude.ExecutionMetadata{
+		{ExecutionID: "exec-b", StartTime: batch},
+		{ExecutionID: "exec-old", StartTime: batch.Add(-time.Minute)},
+		{ExecutionID: "exec-c", StartTime: batch},
+		{ExecutionID: "exec-a", StartTime: batch},
+	}
+	metadataDir := filepath.Join(execMgr.GetLogDir(), "metadata")
+	for _, fixture := range fixtures {
+		data, err := json.Marshal(fixture)
+		if err != nil {
+			t.Fatalf("failed to marshal fixture: %v", err)
+		}
+		path := filepath.Join(metadataDir, claude.GenerateMetadataFileName(fixture.StartTime, fixture.ExecutionID))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+	}
+
+	executions, err := loadTaskExecutionsFromMetadata(execMgr)
+	if err != nil {
+		t.Fatalf("loadTaskExecutionsFromMetadata() error = %v", err)
+	}
+
+	var got []string
+	for _, exec := range executions {
+		got = append(got, exec.ExecutionID)
+	}
+	want := []string{"exec-a", "exec-b", "exec-c", "exec-old"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("execution order = %v, want %v", got, want)
+	}
+}
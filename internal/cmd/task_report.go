@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var taskReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a report for a batch of executions",
+	Long: `Generate a single Markdown or HTML report covering several executions,
+such as the tasks of a task file.
+
+The report starts with a summary table of every execution's status, duration,
+cost, changed files and prompt, followed by a section per execution with its
+prompt, final result and changed files. Executions are selected by start date
+or time range and by tag, and are listed oldest first. Without any filter,
+every execution is included.`,
+	Example: `  # Report on today's backend tasks
+  gwq task report --date 2024-01-15 --tag backend > report.md
+
+  # HTML report of the last 24 hours
+  gwq task report --since 24h --format html -o report.html`,
+	Args: cobra.NoArgs,
+	RunE: runTaskReport,
+}
+
+var (
+	taskReportFormat string
+	taskReportDate   string
+	taskReportSince  string
+	taskReportUntil  string
+	taskReportTag    string
+	taskReportOutput string
+)
+
+func init() {
+	taskCmd.AddCommand(taskReportCmd)
+
+	taskReportCmd.Flags().StringVar(&taskReportFormat, "format", "md", "Report format (md, html)")
+	taskReportCmd.Flags().StringVar(&taskReportDate, "date", "", "Include executions started on this date (YYYY-MM-DD)")
+	taskReportCmd.Flags().StringVar(&taskReportSince, "since", "", "Include executions started at or after this time (RFC3339, YYYY-MM-DD, or duration ago like 24h, 7d)")
+	taskReportCmd.Flags().StringVar(&taskReportUntil, "until", "", "Include executions started at or before this time (RFC3339, YYYY-MM-DD, or duration ago like 24h, 7d)")
+	taskReportCmd.Flags().StringVar(&taskReportTag, "tag", "", "Include only executions with this tag")
+	taskReportCmd.Flags().StringVarP(&taskReportOutput, "output", "o", "", "Write the report to this file instead of stdout")
+}
+
+func runTaskReport(cmd *cobra.Command, args []string) error {
+	if taskReportDate != "" {
+		if _, err := time.Parse("2006-01-02", taskReportDate); err != nil {
+			return fmt.Errorf("invalid --date: %s (expected YYYY-MM-DD)", taskReportDate)
+		}
+	}
+	since, until, err := parseTaskLogsTimeRange(taskReportSince, taskReportUntil, time.Now())
+	if err != nil {
+		return err
+	}
+
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+	reconcileTaskExecutions(execMgr)
+
+	// Metadata files carry the tags, cost and changed files the index lacks
+	executions, err := loadTaskExecutionsFromMetadata(execMgr)
+	if err != nil {
+		return fmt.Errorf("failed to load executions: %w", err)
+	}
+	executions = filterTaskReportExecutions(executions, taskReportDate, taskReportTag, since, until)
+	if len(executions) == 0 {
+		fmt.Fprintln(os.Stderr, "No executions match the filters.")
+		return nil
+	}
+
+	report, err := execMgr.GenerateReport(executions, taskReportFormat)
+	if err != nil {
+		return err
+	}
+
+	if taskReportOutput == "" {
+		fmt.Print(report)
+		return nil
+	}
+	if err := os.WriteFile(taskReportOutput, []byte(report), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	fmt.Printf("Wrote report of %d execution(s) to %s\n", len(executions), taskReportOutput)
+	return nil
+}
+
+// filterTaskReportExecutions keeps the executions started on date and within
+// [since, until] that carry tag, oldest first. Empty filters match everything.
+func filterTaskReportExecutions(executions []claude.ExecutionMetadata, date, tag string, since, until time.Time) []claude.ExecutionMetadata {
+	if date != "" {
+		executions = filterTaskExecutionsByDate(executions, date)
+	}
+	executions = filterTaskExecutionsByTimeRange(executions, since, until)
+
+	var filtered []claude.ExecutionMetadata
+	for _, exec := range executions {
+		if tag == "" || slices.Contains(exec.Tags, tag) {
+			filtered = append(filtered, exec)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].StartTime.Before(filtered[j].StartTime)
+	})
+	return filtered
+}
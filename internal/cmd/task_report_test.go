@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+)
+
+func TestFilterTaskReportExecutions(t *testing.T) {
+	day := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	executions := []claude.ExecutionMetadata{
+		{ExecutionID: "exec-late", StartTime: day.Add(15 * time.Hour), Tags: []string{"backend"}},
+		{ExecutionID: "exec-early", StartTime: day.Add(9 * time.Hour), Tags: []string{"backend", "api"}},
+		{ExecutionID: "exec-untagged", StartTime: day.Add(12 * time.Hour)},
+		{ExecutionID: "exec-other-day", StartTime: day.Add(-12 * time.Hour), Tags: []string{"backend"}},
+	}
+
+	tests := []struct {
+		name         string
+		date, tag    string
+		since, until time.Time
+		want         []string
+	}{
+		{name: "NoFilters", want: []string{"exec-other-day", "exec-early", "exec-untagged", "exec-late"}},
+		{name: "Date", date: "2025-01-02", want: []string{"exec-early", "exec-untagged", "exec-late"}},
+		{name: "Tag", tag: "backend", want: []string{"exec-other-day", "exec-early", "exec-late"}},
+		{name: "DateAndTag", date: "2025-01-02", tag: "backend", want: []string{"exec-early", "exec-late"}},
+		{name: "TimeRange", since: day.Add(10 * time.Hour), until: day.Add(16 * time.Hour), want: []string{"exec-untagged", "exec-late"}},
+		{name: "NoMatch", tag: "frontend", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, exec := range filterTaskReportExecutions(executions, tt.date, tt.tag, tt.since, tt.until) {
+				got = append(got, exec.ExecutionID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filterTaskReportExecutions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/internal/claude/presenters"
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var taskRerunCmd = &cobra.Command{
+	Use:   "rerun [EXECUTION_ID]",
+	Short: "Queue a new task that repeats a past execution",
+	Long: `Create a new task from the metadata of a past execution.
+
+The new task gets a fresh ID and reuses the execution's prompt, repository,
+model and tags. The execution's working directory becomes the task's worktree
+unless --worktree is given. Cost and duration of the old run are not copied.
+When no execution ID is given, an execution is selected with the fuzzy finder.`,
+	Example: `  # Repeat an execution
+  gwq task rerun exec-a1b2c3
+
+  # Adjust the prompt in $EDITOR before queueing
+  gwq task rerun exec-a1b2c3 --edit
+
+  # Run the same prompt in another worktree
+  gwq task rerun exec-a1b2c3 -w feature/retry`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskRerun,
+}
+
+var (
+	taskRerunEdit     bool
+	taskRerunWorktree string
+)
+
+func init() {
+	taskCmd.AddCommand(taskRerunCmd)
+
+	taskRerunCmd.Flags().BoolVar(&taskRerunEdit, "edit", false, "Edit the prompt in $EDITOR before queueing")
+	taskRerunCmd.Flags().StringVarP(&taskRerunWorktree, "worktree", "w", "", "Worktree for the new task (defaults to the execution's working directory)")
+}
+
+func runTaskRerun(cmd *cobra.Command, args []string) error {
+	execMgr, err := createTaskExecutionManager()
+	if err != nil {
+		return err
+	}
+
+	var executionID string
+	if len(args) > 0 {
+		executionID = args[0]
+	} else {
+		executions, err := loadTaskExecutionsFromMetadata(execMgr)
+		if err != nil {
+			return fmt.Errorf("failed to load executions: %w", err)
+		}
+
+		selected, err := selectTaskExecutionWithFinder(executions, nil)
+		if err != nil {
+			return fmt.Errorf("failed to select execution: %w", err)
+		}
+		if selected == nil {
+			return nil
+		}
+		executionID = selected.ExecutionID
+	}
+
+	metadata, err := execMgr.LoadMetadata(executionID)
+	if err != nil {
+		return fmt.Errorf("execution %s not found: %w", executionID, err)
+	}
+
+	req := claude.NewRerunTaskRequest(metadata)
+	if taskRerunWorktree != "" {
+		req.Worktree = taskRerunWorktree
+	}
+	if taskRerunEdit {
+		prompt, err := editTaskPrompt(req.Prompt)
+		if err != nil {
+			return err
+		}
+		req.Prompt = prompt
+	}
+
+	cfg := config.Get()
+	storage, err := claude.NewStorage(cfg.Claude.Queue.QueueDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	task, err := claude.NewTaskManager(storage, cfg).CreateTask(req)
+	if err != nil {
+		return err
+	}
+
+	presenters.NewTaskPresenter().OutputTaskCreationSummary(task)
+	return nil
+}
+
+// editTaskPrompt opens prompt in $EDITOR (vi when unset) and returns the
+// edited text. An empty result aborts the rerun.
+func editTaskPrompt(prompt string) (string, error) {
+	file, err := os.CreateTemp("", "gwq-prompt-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create prompt file: %w", err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if _, err := file.WriteString(prompt); err != nil {
+		_ = file.Close()
+		return "", fmt.Errorf("failed to write prompt file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write prompt file: %w", err)
+	}
+
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	editorCmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run %s: %w", editor[0], err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt file: %w", err)
+	}
+	edited := strings.TrimSpace(string(data))
+	if edited == "" {
+		return "", fmt.Errorf("prompt is empty, rerun aborted")
+	}
+	return edited, nil
+}
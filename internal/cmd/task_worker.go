@@ -2,15 +2,18 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/claude"
 	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/logging"
 	"github.com/d-kuro/gwq/internal/tmux"
 	"github.com/spf13/cobra"
 )
@@ -97,7 +100,10 @@ Displays information about:
 - Active task count and resource utilization
 - Queue statistics (pending, waiting, completed)
 - Recent task activity
-- Session management status`,
+- Session management status
+
+With --history, summarizes the metrics workers record while running instead:
+slot acquisitions, peak concurrency and how long tasks waited in the queue.`,
 	Example: `  # Show basic status
   gwq task worker status
 
@@ -105,10 +111,16 @@ Displays information about:
   gwq task worker status --verbose
 
   # Show status in JSON format
-  gwq task worker status --json`,
+  gwq task worker status --json
+
+  # Show peak concurrency and queue wait times across worker runs
+  gwq task worker status --history`,
 	RunE: runTaskWorkerStatus,
 }
 
+// defaultWorkerShutdownTimeout bounds how long a stopping worker waits for in-flight tasks
+const defaultWorkerShutdownTimeout = 5 * time.Minute
+
 // Worker flags
 var (
 	taskWorkerParallel int
@@ -117,6 +129,8 @@ var (
 	taskWorkerVerbose  bool
 	taskWorkerJSON     bool
 	taskWorkerWait     bool
+	taskWorkerShutdown time.Duration
+	taskWorkerHistory  bool
 )
 
 func init() {
@@ -127,6 +141,7 @@ func init() {
 	taskWorkerStartCmd.Flags().IntVar(&taskWorkerParallel, "parallel", 0, "Maximum parallel tasks (0 = use config default)")
 	taskWorkerStartCmd.Flags().BoolVar(&taskWorkerDaemon, "daemon", false, "Run in background as daemon")
 	taskWorkerStartCmd.Flags().BoolVar(&taskWorkerWait, "wait", false, "Keep running even when no tasks are available")
+	taskWorkerStartCmd.Flags().DurationVar(&taskWorkerShutdown, "shutdown-timeout", defaultWorkerShutdownTimeout, "How long to wait for active tasks on shutdown before aborting them")
 
 	// Stop command flags
 	taskWorkerStopCmd.Flags().DurationVar(&taskWorkerTimeout, "timeout", 5*time.Minute, "Graceful shutdown timeout")
@@ -134,6 +149,7 @@ func init() {
 	// Status command flags
 	taskWorkerStatusCmd.Flags().BoolVarP(&taskWorkerVerbose, "verbose", "v", false, "Show detailed status information")
 	taskWorkerStatusCmd.Flags().BoolVar(&taskWorkerJSON, "json", false, "Output status in JSON format")
+	taskWorkerStatusCmd.Flags().BoolVar(&taskWorkerHistory, "history", false, "Summarize concurrency and queue wait times recorded by past workers")
 }
 
 func runTaskWorkerStart(cmd *cobra.Command, args []string) error {
@@ -162,8 +178,36 @@ func runTaskWorkerStart(cmd *cobra.Command, args []string) error {
 		cfg.Claude.MaxParallel,
 		cfg.Claude.MaxDevelopmentTasks,
 	)
+	resourceMgr.SetMaxPerRepository(cfg.Claude.MaxParallelPerRepository)
 
 	dependencyGraph := claude.NewDependencyGraph()
+	dependencyGraph.SetPriorityAging(claude.PriorityAging{
+		Threshold: cfg.Claude.Queue.AgingThreshold,
+		Rate:      cfg.Claude.Queue.AgingRate,
+	})
+
+	// Repair executions left running by a previous worker that crashed
+	execMgr, err := claude.NewExecutionManager(&cfg.Claude)
+	if err != nil {
+		return fmt.Errorf("failed to create execution manager: %w", err)
+	}
+	if repaired, err := execMgr.ReconcileExecutions(); err != nil {
+		logging.Warnf("failed to reconcile executions: %v", err)
+	} else if repaired > 0 {
+		fmt.Printf("Repaired %d orphaned execution(s)\n", repaired)
+	}
+
+	// Metrics are best effort and never stop the worker from starting
+	metrics, err := claude.NewWorkerMetricsRecorder(workerMetricsPath(cfg.Claude.ConfigDir))
+	if err != nil {
+		logging.Warnf("worker metrics disabled: %v", err)
+	} else {
+		defer func() {
+			if err := metrics.Close(); err != nil {
+				logging.Warnf("%v", err)
+			}
+		}()
+	}
 
 	// Create worker
 	worker := NewTaskWorker(TaskWorkerConfig{
@@ -172,8 +216,13 @@ func runTaskWorkerStart(cmd *cobra.Command, args []string) error {
 		ResourceManager: resourceMgr,
 		DependencyGraph: dependencyGraph,
 		MaxParallel:     taskWorkerParallel,
-		PollInterval:    5 * time.Second,
+		PollInterval:    cfg.Claude.PollInterval,
+		MaxPollInterval: cfg.Claude.MaxPollInterval,
 		WaitForTasks:    taskWorkerWait,
+		LockFile:        workerLockPath(cfg.Claude.ConfigDir),
+		ShutdownTimeout: taskWorkerShutdown,
+		MaxLoadAverage:  cfg.Claude.MaxLoadAverage,
+		Metrics:         metrics,
 	})
 
 	// Handle shutdown gracefully
@@ -199,20 +248,56 @@ func runTaskWorkerStart(cmd *cobra.Command, args []string) error {
 }
 
 func runTaskWorkerStop(cmd *cobra.Command, args []string) error {
-	// TODO: Implement worker stop logic
-	// This would typically involve:
-	// 1. Finding the running worker process
-	// 2. Sending a graceful shutdown signal
-	// 3. Waiting for completion with timeout
-
-	fmt.Println("Worker stop not yet implemented.")
-	fmt.Println("Use Ctrl+C to stop a running worker.")
-	return nil
+	cfg := config.Get()
+	lockPath := workerLockPath(cfg.Claude.ConfigDir)
+
+	lock, err := readWorkerLock(lockPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("No running worker found.")
+			return nil
+		}
+		return err
+	}
+
+	if !isProcessRunning(lock.PID) {
+		if err := os.Remove(lockPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale worker lock: %w", err)
+		}
+		fmt.Printf("Removed stale worker lock (pid %d is no longer running).\n", lock.PID)
+		return nil
+	}
+
+	fmt.Printf("Stopping worker (pid %d)...\n", lock.PID)
+	if err := signalWorkerStop(lock.PID); err != nil {
+		return fmt.Errorf("failed to signal worker: %w", err)
+	}
+
+	// The worker removes its lock file once shutdown completes
+	deadline := time.Now().Add(taskWorkerTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(lockPath); os.IsNotExist(err) {
+			fmt.Println("Worker stopped.")
+			return nil
+		}
+		if !isProcessRunning(lock.PID) {
+			_ = os.Remove(lockPath)
+			fmt.Println("Worker stopped.")
+			return nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	return fmt.Errorf("worker (pid %d) did not stop within %s", lock.PID, taskWorkerTimeout)
 }
 
 func runTaskWorkerStatus(cmd *cobra.Command, args []string) error {
 	cfg := config.Get()
 
+	if taskWorkerHistory {
+		return runTaskWorkerHistory(workerMetricsPath(cfg.Claude.ConfigDir))
+	}
+
 	// Initialize storage to get task statistics
 	storage, err := claude.NewStorage(cfg.Claude.Queue.QueueDir)
 	if err != nil {
@@ -254,47 +339,101 @@ func runTaskWorkerStatus(cmd *cobra.Command, args []string) error {
 	return outputTaskWorkerStatusTable(statusCounts, claudeSessions, taskWorkerVerbose)
 }
 
+// TaskExecutor runs a single task to completion
+type TaskExecutor interface {
+	ExecuteTask(ctx context.Context, task *claude.Task) (*claude.UnifiedExecution, error)
+}
+
 // TaskWorker manages the execution of Claude tasks
 type TaskWorker struct {
 	config          TaskWorkerConfig
 	storage         *claude.Storage
-	executionEngine *claude.ExecutionEngine
+	executionEngine TaskExecutor
 	resourceMgr     *claude.ResourceManager
 	dependencyGraph *claude.DependencyGraph
 	running         bool
 	mu              sync.RWMutex
-	emptyPollCount  int // Track consecutive empty polls
+	emptyPollCount  int    // Track consecutive empty polls
+	deadlockReport  string // Deadlocked task IDs last reported, to log each deadlock once
+	loadPaused      bool   // Whether starting tasks is paused for high system load
+	tasksReady      bool   // Whether the last poll found tasks ready to run
+
+	// In-flight task tracking for graceful shutdown
+	activeWG    sync.WaitGroup
+	activeTasks map[string]claude.Task // Snapshot of each running task, keyed by ID
+	aborted     map[string]bool
+	execCtx     context.Context
+	execCancel  context.CancelFunc
 }
 
 type TaskWorkerConfig struct {
 	Storage         *claude.Storage
-	ExecutionEngine *claude.ExecutionEngine
+	ExecutionEngine TaskExecutor
 	ResourceManager *claude.ResourceManager
 	DependencyGraph *claude.DependencyGraph
 	MaxParallel     int
-	PollInterval    time.Duration
+	PollInterval    time.Duration // Delay between polls while tasks are ready (0 = defaultTaskWorkerPollInterval)
+	MaxPollInterval time.Duration // Delay polling backs off to while idle (at or below PollInterval = no backoff)
 	WaitForTasks    bool
+	LockFile        string        // Written while the worker runs so `worker stop` can find it
+	ShutdownTimeout time.Duration // How long shutdown waits for in-flight tasks
+	MaxLoadAverage  float64       // Don't start tasks above this 1-minute load average (0 = disabled)
+	// LoadAverage reports the system load; nil means claude.LoadAverage
+	LoadAverage func() (float64, error)
+	// After waits between polls; nil means time.After
+	After func(time.Duration) <-chan time.Time
+	// Metrics records slot acquisitions and releases, if set
+	Metrics *claude.WorkerMetricsRecorder
 }
 
+// defaultTaskWorkerPollInterval is the poll delay when none is configured
+const defaultTaskWorkerPollInterval = 5 * time.Second
+
 func NewTaskWorker(config TaskWorkerConfig) *TaskWorker {
-	return &TaskWorker{
+	w := &TaskWorker{
 		config:          config,
 		storage:         config.Storage,
 		executionEngine: config.ExecutionEngine,
 		resourceMgr:     config.ResourceManager,
 		dependencyGraph: config.DependencyGraph,
+		activeTasks:     make(map[string]claude.Task),
+		aborted:         make(map[string]bool),
 	}
+	if config.Metrics != nil && config.ResourceManager != nil {
+		config.ResourceManager.SetEventHandler(w.recordSlotEvent)
+	}
+	return w
 }
 
 func (w *TaskWorker) Start(ctx context.Context) error {
+	if w.config.LockFile != "" {
+		if err := acquireWorkerLock(w.config.LockFile); err != nil {
+			return err
+		}
+	}
+
+	// Executions outlive ctx so that a shutdown signal lets in-flight tasks
+	// finish; shutdown cancels them only once its timeout expires
+	execCtx, execCancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer execCancel()
+
 	w.mu.Lock()
 	w.running = true
+	w.execCtx = execCtx
+	w.execCancel = execCancel
 	w.mu.Unlock()
 
 	defer func() {
 		w.mu.Lock()
 		w.running = false
 		w.mu.Unlock()
+
+		// Covers early returns that bypass shutdown; releasing twice is a no-op
+		if w.config.LockFile != "" {
+			if err := releaseWorkerLock(w.config.LockFile); err != nil {
+				logging.Warnf("%v", err)
+			}
+		}
 	}()
 
 	// Load existing tasks into dependency graph
@@ -303,8 +442,11 @@ func (w *TaskWorker) Start(ctx context.Context) error {
 	}
 
 	// Start worker loop
-	ticker := time.NewTicker(w.config.PollInterval)
-	defer ticker.Stop()
+	after := w.config.After
+	if after == nil {
+		after = time.After
+	}
+	interval := w.minPollInterval()
 
 	fmt.Println("Worker started, polling for tasks...")
 
@@ -313,12 +455,13 @@ func (w *TaskWorker) Start(ctx context.Context) error {
 		case <-ctx.Done():
 			fmt.Println("Worker shutting down...")
 			return w.shutdown(ctx)
-		case <-ticker.C:
+		case <-after(interval):
 			hasMore, err := w.processTasks(ctx)
 			if err != nil {
 				fmt.Printf("Error processing tasks: %v\n", err)
 				continue
 			}
+			interval = w.nextPollInterval(interval)
 
 			// Exit if no tasks and not in wait mode
 			if !hasMore && !w.config.WaitForTasks {
@@ -343,7 +486,7 @@ func (w *TaskWorker) loadTasks() error {
 
 	for _, task := range tasks {
 		if err := w.dependencyGraph.AddTask(task); err != nil {
-			fmt.Printf("Warning: failed to add task %s to dependency graph: %v\n", task.ID, err)
+			logging.Warnf("failed to add task %s to dependency graph: %v", task.ID, err)
 		}
 	}
 
@@ -351,8 +494,14 @@ func (w *TaskWorker) loadTasks() error {
 }
 
 func (w *TaskWorker) processTasks(ctx context.Context) (bool, error) {
+	// Stop accepting new tasks once shutdown has been requested
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
 	// Get executable tasks
 	readyTasks := w.dependencyGraph.GetReadyTasks()
+	w.tasksReady = len(readyTasks) > 0
 
 	// Check if there are any tasks (ready or waiting)
 	tasks, err := w.storage.ListTasks()
@@ -369,27 +518,150 @@ func (w *TaskWorker) processTasks(ctx context.Context) (bool, error) {
 		}
 	}
 
-	for _, task := range readyTasks {
+	// Running tasks keep going, but no new ones start under high load
+	startable := readyTasks
+	if len(startable) > 0 && w.loadTooHigh() {
+		startable = nil
+	}
+
+	for _, task := range startable {
 		// Check if we can acquire a resource slot
 		if !w.resourceMgr.CanAcquire(claude.TaskTypeDevelopment) {
 			break // No more resources available
 		}
 
 		// Try to acquire slot
-		slot, err := w.resourceMgr.TryAcquireSlot(claude.TaskTypeDevelopment, task.ID)
+		slot, err := w.resourceMgr.TryAcquireSlot(claude.TaskTypeDevelopment, task.ID, task.RepositoryRoot)
 		if err != nil {
-			continue // Skip if can't acquire slot
+			continue // Skip if can't acquire slot (e.g. its repository is at capacity)
 		}
 
 		// Start task execution
-		go w.executeTask(ctx, task, slot)
+		w.startTask(task, slot)
 	}
 
 	// Return true if there are any pending/waiting tasks or running tasks
 	stats := w.resourceMgr.GetStats()
+	if hasPendingTasks && len(readyTasks) == 0 && stats.TotalActive == 0 && w.reportDeadlock() {
+		hasPendingTasks = false // Nothing left can run, so stop polling for them
+	}
 	return hasPendingTasks || stats.TotalActive > 0, nil
 }
 
+// minPollInterval returns the configured poll delay, or the default when unset
+func (w *TaskWorker) minPollInterval() time.Duration {
+	if w.config.PollInterval <= 0 {
+		return defaultTaskWorkerPollInterval
+	}
+	return w.config.PollInterval
+}
+
+// nextPollInterval returns the delay before the next poll: back to the
+// minimum when the last poll found ready tasks, otherwise doubled up to
+// MaxPollInterval
+func (w *TaskWorker) nextPollInterval(current time.Duration) time.Duration {
+	minInterval := w.minPollInterval()
+	if w.tasksReady || w.config.MaxPollInterval <= minInterval {
+		return minInterval
+	}
+	return min(current*2, w.config.MaxPollInterval)
+}
+
+// reportDeadlock logs the pending tasks that can never run because of failed
+// or skipped dependencies, and reports whether the queue is deadlocked
+func (w *TaskWorker) reportDeadlock() bool {
+	deadlocked := w.dependencyGraph.DetectDeadlock()
+	if len(deadlocked) == 0 {
+		w.deadlockReport = ""
+		return false
+	}
+
+	report := strings.Join(deadlocked, ", ")
+	if report != w.deadlockReport {
+		fmt.Printf("Deadlock: %d pending task(s) can never run because their dependencies failed or were skipped: %s\n", len(deadlocked), report)
+		fmt.Println("Retry or cancel the blocking tasks, or change the dependency policy of the waiting ones.")
+		w.deadlockReport = report
+	}
+	return true
+}
+
+// loadTooHigh reports whether the system load is above MaxLoadAverage, logging
+// when starting tasks pauses and resumes. It is always false when no limit is
+// set or the load cannot be read.
+func (w *TaskWorker) loadTooHigh() bool {
+	if w.config.MaxLoadAverage <= 0 {
+		return false
+	}
+
+	loadAverage := w.config.LoadAverage
+	if loadAverage == nil {
+		loadAverage = claude.LoadAverage
+	}
+	load, err := loadAverage()
+	if err != nil {
+		return false
+	}
+
+	high := load > w.config.MaxLoadAverage
+	if high && !w.loadPaused {
+		fmt.Printf("Load average %.2f is above %.2f, not starting new tasks\n", load, w.config.MaxLoadAverage)
+	} else if !high && w.loadPaused {
+		fmt.Printf("Load average %.2f is back under %.2f, resuming\n", load, w.config.MaxLoadAverage)
+	}
+	w.loadPaused = high
+	return high
+}
+
+// recordSlotEvent records a slot event in the worker metrics, along with how
+// long an acquiring task was queued
+func (w *TaskWorker) recordSlotEvent(event claude.SlotEvent) {
+	metric := claude.WorkerMetricEvent{
+		Time:   event.Time,
+		Event:  event.Kind,
+		TaskID: event.TaskID,
+		Active: event.Active,
+	}
+	if event.Kind == claude.SlotAcquired {
+		if task, ok := w.dependencyGraph.GetTask(event.TaskID); ok {
+			metric.WaitMS = max(0, event.Time.Sub(taskQueuedAt(task)).Milliseconds())
+		}
+	}
+	w.config.Metrics.Record(metric)
+}
+
+// taskQueuedAt returns when a task last entered the queue: its retry time if
+// it is being retried, otherwise its creation time
+func taskQueuedAt(task *claude.Task) time.Time {
+	if task.NextRetryAt != nil {
+		return *task.NextRetryAt
+	}
+	return task.CreatedAt
+}
+
+// startTask runs a task in the background and tracks it until it finishes.
+// The task is marked running in the dependency graph before this returns and
+// runs on a copy, which is stored back in the graph once it finishes.
+func (w *TaskWorker) startTask(task *claude.Task, slot *claude.Slot) {
+	task, ok := w.dependencyGraph.StartTask(task.ID)
+	if !ok {
+		slot.Release()
+		return
+	}
+
+	w.mu.Lock()
+	ctx := w.execCtx
+	w.mu.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	w.activeWG.Add(1)
+	go func() {
+		defer w.activeWG.Done()
+		w.executeTask(ctx, task, slot)
+	}()
+}
+
 func (w *TaskWorker) executeTask(ctx context.Context, task *claude.Task, slot *claude.Slot) {
 	defer slot.Release()
 
@@ -397,12 +669,28 @@ func (w *TaskWorker) executeTask(ctx context.Context, task *claude.Task, slot *c
 	task.Status = claude.StatusRunning
 	startTime := time.Now()
 	task.StartedAt = &startTime
+	task.NextRetryAt = nil
+
+	// Results are replaced per attempt, so carry the retry count and any
+	// dependency failures recorded when the dependency graph started the task
+	retryCount := 0
+	var dependencyFailures []string
+	if task.Result != nil {
+		retryCount = task.Result.RetryCount
+		dependencyFailures = task.Result.DependencyFailures
+	}
+	// Nothing else from the previous attempt applies to this one
+	task.Result = &claude.TaskResult{RetryCount: retryCount, DependencyFailures: dependencyFailures}
 
 	if err := w.storage.SaveTask(task); err != nil {
 		fmt.Printf("Error updating task status: %v\n", err)
 		return
 	}
 
+	w.mu.Lock()
+	w.activeTasks[task.ID] = *task
+	w.mu.Unlock()
+
 	// Use SimplifiedTask for consistent display name logic
 	simplified := claude.FromLegacyTask(task)
 	displayName := simplified.GetDisplayName()
@@ -411,6 +699,15 @@ func (w *TaskWorker) executeTask(ctx context.Context, task *claude.Task, slot *c
 	// Execute task through unified execution engine
 	execution, err := w.executionEngine.ExecuteTask(ctx, task)
 
+	// Shutdown already recorded this task as aborted; don't overwrite that
+	w.mu.Lock()
+	delete(w.activeTasks, task.ID)
+	aborted := w.aborted[task.ID]
+	w.mu.Unlock()
+	if aborted {
+		return
+	}
+
 	// Update task with execution results
 	if execution != nil {
 		task.SessionID = execution.TmuxSession
@@ -420,15 +717,19 @@ func (w *TaskWorker) executeTask(ctx context.Context, task *claude.Task, slot *c
 				Duration:     time.Duration(execution.DurationMS) * time.Millisecond,
 				FilesChanged: execution.Result.FilesChanged,
 				Error:        execution.Result.Error,
+				ErrorKind:    execution.Result.ErrorKind,
 			}
 		}
 	}
 
+	if task.Result == nil {
+		task.Result = &claude.TaskResult{}
+	}
+	task.Result.RetryCount = retryCount
+	task.Result.DependencyFailures = dependencyFailures
+
 	if err != nil {
 		task.Status = claude.StatusFailed
-		if task.Result == nil {
-			task.Result = &claude.TaskResult{}
-		}
 		task.Result.Error = err.Error()
 		fmt.Printf("Task failed: %s - %v\n", task.ID, err)
 	} else {
@@ -439,10 +740,17 @@ func (w *TaskWorker) executeTask(ctx context.Context, task *claude.Task, slot *c
 	completedTime := time.Now()
 	task.CompletedAt = &completedTime
 
-	// Update dependency graph and storage
-	if err := w.dependencyGraph.UpdateTask(task); err != nil {
-		fmt.Printf("Error updating dependency graph: %v\n", err)
+	// The retry is decided along with storing the result, so the graph never
+	// shows a failure that is about to be retried
+	retryable := task.Status == claude.StatusFailed && task.Result.ErrorKind.Retryable()
+	if task.Status == claude.StatusFailed && !retryable && task.MaxRetries > 0 {
+		fmt.Printf("Not retrying task %s: %s errors are not retryable\n", task.ID, task.Result.ErrorKind)
+	}
+	if w.dependencyGraph.FinishTask(task, retryable) {
+		fmt.Printf("Retrying task %s at %s (retry %d/%d)\n",
+			task.ID, task.NextRetryAt.Format("15:04:05"), task.Result.RetryCount, task.MaxRetries)
 	}
+
 	if err := w.storage.SaveTask(task); err != nil {
 		fmt.Printf("Error saving task result: %v\n", err)
 	}
@@ -452,12 +760,65 @@ func (w *TaskWorker) executeTask(ctx context.Context, task *claude.Task, slot *c
 func (w *TaskWorker) shutdown(ctx context.Context) error {
 	fmt.Println("Waiting for active tasks to complete...")
 
-	// TODO: Implement graceful shutdown
-	// 1. Stop accepting new tasks
-	// 2. Wait for active tasks to complete
-	// 3. Clean up resources
+	defer func() {
+		if w.config.LockFile != "" {
+			if err := releaseWorkerLock(w.config.LockFile); err != nil {
+				logging.Warnf("%v", err)
+			}
+		}
+	}()
 
-	return nil
+	done := make(chan struct{})
+	go func() {
+		w.activeWG.Wait()
+		close(done)
+	}()
+
+	timeout := w.config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultWorkerShutdownTimeout
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	// Record remaining tasks as aborted before cancelling their executions so
+	// their goroutines don't report them as failed
+	w.mu.Lock()
+	remaining := make([]claude.Task, 0, len(w.activeTasks))
+	for id, task := range w.activeTasks {
+		w.aborted[id] = true
+		remaining = append(remaining, task)
+	}
+	cancel := w.execCancel
+	w.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	for i := range remaining {
+		task := &remaining[i]
+		task.Status = claude.StatusAborted
+		now := time.Now()
+		task.CompletedAt = &now
+		task.Result = &claude.TaskResult{
+			Error: fmt.Sprintf("aborted: worker shut down after waiting %s", timeout),
+		}
+
+		if err := w.dependencyGraph.UpdateTask(task); err != nil {
+			fmt.Printf("Error updating dependency graph: %v\n", err)
+		}
+		if err := w.storage.SaveTask(task); err != nil {
+			fmt.Printf("Error saving aborted task %s: %v\n", task.ID, err)
+		}
+		fmt.Printf("Task aborted: %s\n", task.ID)
+	}
+
+	return fmt.Errorf("timed out after %s waiting for %d active task(s)", timeout, len(remaining))
 }
 
 func filterTaskClaudeSessions(sessions []*tmux.Session) []*tmux.Session {
@@ -470,9 +831,59 @@ func filterTaskClaudeSessions(sessions []*tmux.Session) []*tmux.Session {
 	return claudeSessions
 }
 
+// taskWorkerStatusOutput is the JSON shape of `gwq task worker status --json`
+type taskWorkerStatusOutput struct {
+	Running      bool                      `json:"running"`
+	StatusCounts map[claude.Status]int     `json:"status_counts"`
+	Sessions     []taskWorkerSessionOutput `json:"sessions"`
+}
+
+// taskWorkerSessionOutput describes an active Claude session in JSON output
+type taskWorkerSessionOutput struct {
+	Name            string    `json:"name"`
+	TaskID          string    `json:"task_id"`
+	TaskName        string    `json:"task_name"`
+	StartTime       time.Time `json:"start_time"`
+	DurationSeconds int64     `json:"duration_seconds"`
+}
+
 func outputTaskWorkerStatusJSON(statusCounts map[claude.Status]int, sessions []*tmux.Session) error {
-	// TODO: Implement JSON output
-	return fmt.Errorf("JSON output not yet implemented")
+	output := taskWorkerStatusOutput{
+		Running:      len(sessions) > 0,
+		StatusCounts: make(map[claude.Status]int),
+		Sessions:     []taskWorkerSessionOutput{},
+	}
+
+	// Always report every status so scripts can rely on the keys being present
+	for _, status := range []claude.Status{
+		claude.StatusPending,
+		claude.StatusWaiting,
+		claude.StatusRunning,
+		claude.StatusCompleted,
+		claude.StatusFailed,
+		claude.StatusSkipped,
+		claude.StatusCancelled,
+		claude.StatusAborted,
+	} {
+		output.StatusCounts[status] = 0
+	}
+	for status, count := range statusCounts {
+		output.StatusCounts[status] = count
+	}
+
+	for _, session := range sessions {
+		output.Sessions = append(output.Sessions, taskWorkerSessionOutput{
+			Name:            session.SessionName,
+			TaskID:          session.Metadata["task_id"],
+			TaskName:        session.Metadata["task_name"],
+			StartTime:       session.StartTime,
+			DurationSeconds: int64(time.Since(session.StartTime).Seconds()),
+		})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
 }
 
 func outputTaskWorkerStatusTable(statusCounts map[claude.Status]int, sessions []*tmux.Session, verbose bool) error {
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+)
+
+// workerMetricsFileName is the metrics file appended to by running workers under the Claude config directory
+const workerMetricsFileName = "worker-metrics.jsonl"
+
+// workerMetricsPath returns the worker metrics file path for a Claude config directory
+func workerMetricsPath(configDir string) string {
+	return filepath.Join(configDir, workerMetricsFileName)
+}
+
+func runTaskWorkerHistory(path string) error {
+	events, err := claude.LoadWorkerMetrics(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load worker metrics: %w", err)
+	}
+	summary := claude.SummarizeWorkerMetrics(events)
+
+	if taskWorkerJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	}
+
+	return printTaskWorkerHistory(os.Stdout, summary)
+}
+
+func printTaskWorkerHistory(w io.Writer, summary claude.WorkerMetricsSummary) error {
+	if summary.Acquisitions == 0 && summary.Releases == 0 {
+		_, err := fmt.Fprintln(w, "No worker history recorded")
+		return err
+	}
+
+	lines := []string{
+		"Claude Worker History",
+		"=====================",
+		fmt.Sprintf("Period:           %s - %s", summary.From.Local().Format("2006-01-02 15:04"), summary.To.Local().Format("2006-01-02 15:04")),
+		fmt.Sprintf("Tasks started:    %d", summary.Acquisitions),
+		fmt.Sprintf("Peak concurrency: %d", summary.PeakConcurrency),
+		fmt.Sprintf("Average wait:     %s", formatTaskWorkerDuration(time.Duration(summary.AverageWaitMS)*time.Millisecond)),
+		fmt.Sprintf("Longest wait:     %s", formatTaskWorkerDuration(time.Duration(summary.MaxWaitMS)*time.Millisecond)),
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
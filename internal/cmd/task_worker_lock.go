@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/logging"
+)
+
+// workerLockFileName is the lock file written by a running worker under the Claude config directory
+const workerLockFileName = "worker.lock"
+
+// workerLock is the content of the worker lock file
+type workerLock struct {
+	PID       int       `json:"pid"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// workerLockPath returns the worker lock file path for a Claude config directory
+func workerLockPath(configDir string) string {
+	return filepath.Join(configDir, workerLockFileName)
+}
+
+// readWorkerLock reads the worker lock file
+func readWorkerLock(path string) (*workerLock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock workerLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse worker lock %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// acquireWorkerLock writes a lock file for the current process. It fails if
+// another live worker holds the lock and replaces a stale one.
+func acquireWorkerLock(path string) error {
+	if existing, err := readWorkerLock(path); err == nil {
+		if existing.PID != os.Getpid() && isProcessRunning(existing.PID) {
+			return fmt.Errorf("worker already running (pid %d, started %s)",
+				existing.PID, existing.StartTime.Format("2006-01-02 15:04:05"))
+		}
+		logging.Warnf("removing stale worker lock (pid %d)", existing.PID)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale worker lock: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create worker lock directory: %w", err)
+	}
+
+	data, err := json.Marshal(workerLock{PID: os.Getpid(), StartTime: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker lock: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return errors.New("worker already running (lock acquired concurrently)")
+		}
+		return fmt.Errorf("failed to create worker lock: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			logging.Warnf("failed to close worker lock: %v", err)
+		}
+	}()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write worker lock: %w", err)
+	}
+	return nil
+}
+
+// releaseWorkerLock removes the lock file if it belongs to the current process
+func releaseWorkerLock(path string) error {
+	lock, err := readWorkerLock(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if lock.PID != os.Getpid() {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove worker lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWorkerLockLifecycle(t *testing.T) {
+	path := workerLockPath(t.TempDir())
+
+	if err := acquireWorkerLock(path); err != nil {
+		t.Fatalf("acquireWorkerLock() error = %v", err)
+	}
+
+	lock, err := readWorkerLock(path)
+	if err != nil {
+		t.Fatalf("readWorkerLock() error = %v", err)
+	}
+	if lock.PID != os.Getpid() {
+		t.Errorf("lock PID = %d, want %d", lock.PID, os.Getpid())
+	}
+	if !isProcessRunning(lock.PID) {
+		t.Error("isProcessRunning() = false for the current process")
+	}
+
+	if err := releaseWorkerLock(path); err != nil {
+		t.Fatalf("releaseWorkerLock() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("lock file should be removed after release, stat error = %v", err)
+	}
+
+	// Releasing an absent lock is a no-op
+	if err := releaseWorkerLock(path); err != nil {
+		t.Errorf("releaseWorkerLock() on missing file error = %v", err)
+	}
+}
+
+func TestAcquireWorkerLockDetectsRunningWorker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), workerLockFileName)
+
+	// The parent process (go test) is alive and is not us
+	writeTestWorkerLock(t, path, os.Getppid())
+
+	if err := acquireWorkerLock(path); err == nil {
+		t.Fatal("acquireWorkerLock() should fail while another worker is running")
+	}
+
+	lock, err := readWorkerLock(path)
+	if err != nil {
+		t.Fatalf("readWorkerLock() error = %v", err)
+	}
+	if lock.PID != os.Getppid() {
+		t.Errorf("existing lock was modified: PID = %d", lock.PID)
+	}
+}
+
+func TestAcquireWorkerLockReplacesStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), workerLockFileName)
+
+	// PIDs this large are above pid_max and cannot belong to a live process
+	stalePID := 1<<31 - 2
+	writeTestWorkerLock(t, path, stalePID)
+
+	if isProcessRunning(stalePID) {
+		t.Skip("stale PID unexpectedly reported as running")
+	}
+
+	if err := acquireWorkerLock(path); err != nil {
+		t.Fatalf("acquireWorkerLock() error = %v", err)
+	}
+
+	lock, err := readWorkerLock(path)
+	if err != nil {
+		t.Fatalf("readWorkerLock() error = %v", err)
+	}
+	if lock.PID != os.Getpid() {
+		t.Errorf("lock PID = %d, want %d", lock.PID, os.Getpid())
+	}
+}
+
+func writeTestWorkerLock(t *testing.T, path string, pid int) {
+	t.Helper()
+
+	data, err := json.Marshal(workerLock{PID: pid, StartTime: time.Now()})
+	if err != nil {
+		t.Fatalf("failed to marshal lock: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write lock: %v", err)
+	}
+}
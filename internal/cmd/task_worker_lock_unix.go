@@ -0,0 +1,22 @@
+//go:build unix
+
+package cmd
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isProcessRunning reports whether a process with the given PID exists
+func isProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// signalWorkerStop asks the worker process to shut down gracefully
+func signalWorkerStop(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
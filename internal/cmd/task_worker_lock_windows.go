@@ -0,0 +1,26 @@
+//go:build windows
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+// isProcessRunning reports whether a process with the given PID exists
+func isProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
+
+// signalWorkerStop is not supported on Windows, which has no SIGTERM
+func signalWorkerStop(pid int) error {
+	return errors.New("graceful worker stop is not supported on windows; use Ctrl+C in the worker terminal")
+}
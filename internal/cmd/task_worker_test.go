@@ -0,0 +1,529 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/claude"
+	"github.com/d-kuro/gwq/internal/tmux"
+)
+
+// captureTaskWorkerStatusJSON runs outputTaskWorkerStatusJSON and returns its stdout
+func captureTaskWorkerStatusJSON(t *testing.T, statusCounts map[claude.Status]int, sessions []*tmux.Session) []byte {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := outputTaskWorkerStatusJSON(statusCounts, sessions)
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("outputTaskWorkerStatusJSON() error = %v", err)
+	}
+	return out
+}
+
+func TestOutputTaskWorkerStatusJSON(t *testing.T) {
+	statusCounts := map[claude.Status]int{
+		claude.StatusPending:   3,
+		claude.StatusRunning:   1,
+		claude.StatusCompleted: 5,
+	}
+	sessions := []*tmux.Session{
+		{
+			SessionName: "gwq-claude-task-abc",
+			Context:     "claude",
+			StartTime:   time.Now().Add(-2 * time.Minute),
+			Metadata:    map[string]string{"task_id": "abc", "task_name": "Fix auth"},
+		},
+	}
+
+	var got taskWorkerStatusOutput
+	if err := json.Unmarshal(captureTaskWorkerStatusJSON(t, statusCounts, sessions), &got); err != nil {
+		t.Fatalf("failed to unmarshal output: %v", err)
+	}
+
+	if !got.Running {
+		t.Error("Running = false, want true")
+	}
+	for status, want := range statusCounts {
+		if got.StatusCounts[status] != want {
+			t.Errorf("StatusCounts[%s] = %d, want %d", status, got.StatusCounts[status], want)
+		}
+	}
+	if got.StatusCounts[claude.StatusFailed] != 0 {
+		t.Errorf("StatusCounts[failed] = %d, want 0", got.StatusCounts[claude.StatusFailed])
+	}
+	if len(got.Sessions) != 1 {
+		t.Fatalf("len(Sessions) = %d, want 1", len(got.Sessions))
+	}
+	if got.Sessions[0].TaskID != "abc" || got.Sessions[0].TaskName != "Fix auth" {
+		t.Errorf("Sessions[0] = %+v, want task abc / Fix auth", got.Sessions[0])
+	}
+	if got.Sessions[0].DurationSeconds < 120 {
+		t.Errorf("DurationSeconds = %d, want >= 120", got.Sessions[0].DurationSeconds)
+	}
+}
+
+func TestOutputTaskWorkerStatusJSONEmpty(t *testing.T) {
+	out := captureTaskWorkerStatusJSON(t, map[claude.Status]int{}, nil)
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(out, &raw); err != nil {
+		t.Fatalf("failed to unmarshal output: %v", err)
+	}
+	if string(raw["sessions"]) != "[]" {
+		t.Errorf("sessions = %s, want []", raw["sessions"])
+	}
+	if string(raw["running"]) != "false" {
+		t.Errorf("running = %s, want false", raw["running"])
+	}
+	if string(raw["status_counts"]) == "null" {
+		t.Error("status_counts should not be null")
+	}
+}
+
+// blockingExecutor is a TaskExecutor that blocks until released or cancelled
+type blockingExecutor struct {
+	started chan string
+	release chan struct{}
+}
+
+func (e *blockingExecutor) ExecuteTask(ctx context.Context, task *claude.Task) (*claude.UnifiedExecution, error) {
+	e.started <- task.ID
+	select {
+	case <-e.release:
+		return &claude.UnifiedExecution{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newShutdownTestWorker(t *testing.T, timeout time.Duration) (*TaskWorker, *blockingExecutor, *claude.Storage) {
+	t.Helper()
+
+	storage, err := claude.NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	executor := &blockingExecutor{started: make(chan string, 1), release: make(chan struct{})}
+	worker := NewTaskWorker(TaskWorkerConfig{
+		Storage:         storage,
+		ExecutionEngine: executor,
+		ResourceManager: claude.NewResourceManager(2, 2),
+		DependencyGraph: claude.NewDependencyGraph(),
+		ShutdownTimeout: timeout,
+	})
+	worker.execCtx, worker.execCancel = context.WithCancel(context.Background())
+	t.Cleanup(worker.execCancel)
+
+	return worker, executor, storage
+}
+
+func startShutdownTestTask(t *testing.T, worker *TaskWorker, executor *blockingExecutor, id string) {
+	t.Helper()
+
+	task := &claude.Task{ID: id, Name: id, Status: claude.StatusPending}
+	if err := worker.dependencyGraph.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	slot, err := worker.resourceMgr.TryAcquireSlot(claude.TaskTypeDevelopment, id, "")
+	if err != nil {
+		t.Fatalf("failed to acquire slot: %v", err)
+	}
+
+	worker.startTask(task, slot)
+	<-executor.started
+}
+
+func TestTaskWorkerShutdownWaitsForActiveTasks(t *testing.T) {
+	worker, executor, storage := newShutdownTestWorker(t, 5*time.Second)
+	startShutdownTestTask(t, worker, executor, "task-wait")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- worker.shutdown(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("shutdown returned before the active task finished: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(executor.release)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("shutdown() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not return after the task finished")
+	}
+
+	task, err := storage.LoadTask("task-wait")
+	if err != nil {
+		t.Fatalf("failed to load task: %v", err)
+	}
+	if task.Status != claude.StatusCompleted {
+		t.Errorf("task status = %s, want %s", task.Status, claude.StatusCompleted)
+	}
+}
+
+func TestTaskWorkerShutdownAbortsAfterTimeout(t *testing.T) {
+	worker, executor, storage := newShutdownTestWorker(t, 50*time.Millisecond)
+	startShutdownTestTask(t, worker, executor, "task-abort")
+
+	if err := worker.shutdown(context.Background()); err == nil {
+		t.Error("shutdown() should report a timeout error")
+	}
+
+	// Let the cancelled execution goroutine finish; it must not overwrite the status
+	worker.activeWG.Wait()
+
+	task, err := storage.LoadTask("task-abort")
+	if err != nil {
+		t.Fatalf("failed to load task: %v", err)
+	}
+	if task.Status != claude.StatusAborted {
+		t.Errorf("task status = %s, want %s", task.Status, claude.StatusAborted)
+	}
+	if task.CompletedAt == nil {
+		t.Error("aborted task should have CompletedAt set")
+	}
+}
+
+// failingExecutor is a TaskExecutor whose executions fail with a fixed error kind
+type failingExecutor struct {
+	kind claude.ErrorKind
+}
+
+func (e *failingExecutor) ExecuteTask(ctx context.Context, task *claude.Task) (*claude.UnifiedExecution, error) {
+	return &claude.UnifiedExecution{
+		Result: &claude.ExecutionResult{ExitCode: 1, Error: "boom", ErrorKind: e.kind},
+	}, errors.New("boom")
+}
+
+func TestTaskWorkerRetryDependsOnErrorKind(t *testing.T) {
+	tests := []struct {
+		kind       claude.ErrorKind
+		wantStatus claude.Status
+	}{
+		{kind: claude.ErrorKindCommand, wantStatus: claude.StatusPending},
+		{kind: claude.ErrorKindTimeout, wantStatus: claude.StatusPending},
+		{kind: claude.ErrorKindSetup, wantStatus: claude.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			storage, err := claude.NewStorage(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create storage: %v", err)
+			}
+			worker := NewTaskWorker(TaskWorkerConfig{
+				Storage:         storage,
+				ExecutionEngine: &failingExecutor{kind: tt.kind},
+				ResourceManager: claude.NewResourceManager(1, 1),
+				DependencyGraph: claude.NewDependencyGraph(),
+			})
+
+			task := &claude.Task{ID: "task-retry", Name: "retry", Status: claude.StatusPending, MaxRetries: 2, RetryBackoff: time.Second}
+			if err := worker.dependencyGraph.AddTask(task); err != nil {
+				t.Fatalf("failed to add task: %v", err)
+			}
+			slot, err := worker.resourceMgr.TryAcquireSlot(claude.TaskTypeDevelopment, task.ID, "")
+			if err != nil {
+				t.Fatalf("failed to acquire slot: %v", err)
+			}
+
+			worker.executeTask(context.Background(), task, slot)
+
+			saved, err := storage.LoadTask(task.ID)
+			if err != nil {
+				t.Fatalf("failed to load task: %v", err)
+			}
+			if saved.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", saved.Status, tt.wantStatus)
+			}
+			if saved.Result == nil || saved.Result.ErrorKind != tt.kind {
+				t.Errorf("result error kind = %+v, want %s", saved.Result, tt.kind)
+			}
+		})
+	}
+}
+
+// erroringExecutor is a TaskExecutor that fails without an execution record
+type erroringExecutor struct{}
+
+func (erroringExecutor) ExecuteTask(ctx context.Context, task *claude.Task) (*claude.UnifiedExecution, error) {
+	return nil, errors.New("boom")
+}
+
+func TestTaskWorkerResetsResultBetweenAttempts(t *testing.T) {
+	storage, err := claude.NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	worker := NewTaskWorker(TaskWorkerConfig{
+		Storage:         storage,
+		ExecutionEngine: erroringExecutor{},
+		ResourceManager: claude.NewResourceManager(1, 1),
+		DependencyGraph: claude.NewDependencyGraph(),
+	})
+
+	// The previous attempt failed with a setup error, which is not retryable
+	task := &claude.Task{
+		ID: "task-stale", Name: "stale", Status: claude.StatusPending, MaxRetries: 3, RetryBackoff: time.Second,
+		Result: &claude.TaskResult{ExitCode: 1, Error: "old", ErrorKind: claude.ErrorKindSetup, RetryCount: 1},
+	}
+	if err := worker.dependencyGraph.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	slot, err := worker.resourceMgr.TryAcquireSlot(claude.TaskTypeDevelopment, task.ID, "")
+	if err != nil {
+		t.Fatalf("failed to acquire slot: %v", err)
+	}
+
+	worker.executeTask(context.Background(), task, slot)
+
+	saved, err := storage.LoadTask(task.ID)
+	if err != nil {
+		t.Fatalf("failed to load task: %v", err)
+	}
+	if saved.Status != claude.StatusPending {
+		t.Errorf("status = %s, want the task retried as %s", saved.Status, claude.StatusPending)
+	}
+	if saved.Result == nil || saved.Result.ErrorKind != "" || saved.Result.ExitCode != 0 || saved.Result.Error != "boom" {
+		t.Errorf("result = %+v, want only this attempt's error", saved.Result)
+	}
+	if saved.Result != nil && saved.Result.RetryCount != 2 {
+		t.Errorf("retry count = %d, want 2", saved.Result.RetryCount)
+	}
+}
+
+func TestTaskWorkerStopsPollingOnDeadlock(t *testing.T) {
+	storage, err := claude.NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	worker := NewTaskWorker(TaskWorkerConfig{
+		Storage:         storage,
+		ExecutionEngine: &failingExecutor{kind: claude.ErrorKindCommand},
+		ResourceManager: claude.NewResourceManager(1, 1),
+		DependencyGraph: claude.NewDependencyGraph(),
+	})
+
+	tasks := []*claude.Task{
+		{ID: "dep", Name: "dep", Status: claude.StatusFailed},
+		{ID: "wait", Name: "wait", Status: claude.StatusPending, DependsOn: []string{"dep"}, DependencyPolicy: claude.DependencyPolicyWait},
+	}
+	for _, task := range tasks {
+		if err := storage.SaveTask(task); err != nil {
+			t.Fatalf("failed to save task: %v", err)
+		}
+	}
+	if err := worker.loadTasks(); err != nil {
+		t.Fatalf("loadTasks() error = %v", err)
+	}
+
+	hasMore, err := worker.processTasks(context.Background())
+	if err != nil {
+		t.Fatalf("processTasks() error = %v", err)
+	}
+	if hasMore {
+		t.Error("processTasks() should report no more work when the queue is deadlocked")
+	}
+	if worker.deadlockReport != "wait" {
+		t.Errorf("deadlockReport = %q, want %q", worker.deadlockReport, "wait")
+	}
+}
+
+func TestTaskWorkerPausesAboveMaxLoadAverage(t *testing.T) {
+	worker, executor, storage := newShutdownTestWorker(t, 5*time.Second)
+	load := 4.0
+	worker.config.MaxLoadAverage = 2.0
+	worker.config.LoadAverage = func() (float64, error) { return load, nil }
+
+	if err := storage.SaveTask(&claude.Task{ID: "task-load", Name: "load", Status: claude.StatusPending}); err != nil {
+		t.Fatalf("failed to save task: %v", err)
+	}
+	if err := worker.loadTasks(); err != nil {
+		t.Fatalf("loadTasks() error = %v", err)
+	}
+
+	hasMore, err := worker.processTasks(context.Background())
+	if err != nil {
+		t.Fatalf("processTasks() error = %v", err)
+	}
+	if !hasMore {
+		t.Error("processTasks() should keep polling while paused for load")
+	}
+	if active := worker.resourceMgr.GetStats().TotalActive; active != 0 {
+		t.Fatalf("%d slot(s) acquired above the load limit, want 0", active)
+	}
+
+	load = 1.0
+	if _, err := worker.processTasks(context.Background()); err != nil {
+		t.Fatalf("processTasks() error = %v", err)
+	}
+	select {
+	case id := <-executor.started:
+		if id != "task-load" {
+			t.Errorf("started %s, want task-load", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("task did not start once the load dropped")
+	}
+	close(executor.release)
+	worker.activeWG.Wait()
+}
+
+func TestTaskWorkerIgnoresUnsupportedLoadAverage(t *testing.T) {
+	worker := NewTaskWorker(TaskWorkerConfig{
+		MaxLoadAverage: 1.0,
+		LoadAverage:    func() (float64, error) { return 0, claude.ErrLoadAverageUnsupported },
+	})
+	if worker.loadTooHigh() {
+		t.Error("loadTooHigh() = true, want false when the load cannot be read")
+	}
+}
+
+func TestTaskWorkerPollBackoff(t *testing.T) {
+	worker, executor, storage := newShutdownTestWorker(t, 5*time.Second)
+	worker.config.WaitForTasks = true
+	worker.config.PollInterval = time.Second
+	worker.config.MaxPollInterval = 8 * time.Second
+
+	// Fake clock: each wait fires immediately and records its duration. A
+	// task becomes ready after the fourth wait and has started by the fifth.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var waits []time.Duration
+	worker.config.After = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		switch len(waits) {
+		case 4:
+			task := &claude.Task{ID: "task-ready", Name: "ready", Status: claude.StatusPending}
+			if err := storage.SaveTask(task); err != nil {
+				t.Errorf("failed to save task: %v", err)
+			}
+			if err := worker.dependencyGraph.AddTask(task); err != nil {
+				t.Errorf("failed to add task: %v", err)
+			}
+		case 5:
+			<-executor.started
+		case 6:
+			close(executor.release)
+			cancel()
+			return nil
+		}
+		fired := make(chan time.Time, 1)
+		fired <- time.Time{}
+		return fired
+	}
+
+	if err := worker.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, time.Second, 2 * time.Second}
+	if len(waits) != len(want) {
+		t.Fatalf("waits = %v, want %v", waits, want)
+	}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("waits = %v, want %v", waits, want)
+			break
+		}
+	}
+}
+
+func TestTaskWorkerNextPollInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		min     time.Duration
+		max     time.Duration
+		current time.Duration
+		ready   bool
+		want    time.Duration
+	}{
+		{name: "idle doubles", min: time.Second, max: time.Minute, current: 4 * time.Second, want: 8 * time.Second},
+		{name: "idle capped at max", min: time.Second, max: time.Minute, current: 45 * time.Second, want: time.Minute},
+		{name: "ready resets", min: time.Second, max: time.Minute, current: time.Minute, ready: true, want: time.Second},
+		{name: "no backoff without max", min: time.Second, current: time.Second, want: time.Second},
+		{name: "default min", max: time.Minute, current: time.Minute, ready: true, want: defaultTaskWorkerPollInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worker := NewTaskWorker(TaskWorkerConfig{PollInterval: tt.min, MaxPollInterval: tt.max})
+			worker.tasksReady = tt.ready
+			if got := worker.nextPollInterval(tt.current); got != tt.want {
+				t.Errorf("nextPollInterval(%s) = %s, want %s", tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskWorkerRecordsSlotWait(t *testing.T) {
+	path := filepath.Join(t.TempDir(), workerMetricsFileName)
+	metrics, err := claude.NewWorkerMetricsRecorder(path)
+	if err != nil {
+		t.Fatalf("NewWorkerMetricsRecorder() error = %v", err)
+	}
+	resourceMgr := claude.NewResourceManager(2, 2)
+	worker := NewTaskWorker(TaskWorkerConfig{
+		ResourceManager: resourceMgr,
+		DependencyGraph: claude.NewDependencyGraph(),
+		Metrics:         metrics,
+	})
+
+	retryAt := time.Now().Add(-time.Minute)
+	tasks := []*claude.Task{
+		{ID: "queued", Name: "queued", Status: claude.StatusPending, CreatedAt: time.Now().Add(-time.Hour)},
+		{ID: "retried", Name: "retried", Status: claude.StatusPending, CreatedAt: time.Now().Add(-time.Hour), NextRetryAt: &retryAt},
+	}
+	for _, task := range tasks {
+		if err := worker.dependencyGraph.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+		slot, err := resourceMgr.TryAcquireSlot(claude.TaskTypeDevelopment, task.ID, "")
+		if err != nil {
+			t.Fatalf("failed to acquire slot: %v", err)
+		}
+		slot.Release()
+	}
+	if err := metrics.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	events, err := claude.LoadWorkerMetrics(path)
+	if err != nil {
+		t.Fatalf("LoadWorkerMetrics() error = %v", err)
+	}
+	waits := make(map[string]time.Duration)
+	for _, e := range events {
+		if e.Event == claude.SlotAcquired {
+			waits[e.TaskID] = time.Duration(e.WaitMS) * time.Millisecond
+		}
+	}
+	// Retried tasks wait from their retry time, not from creation
+	if w := waits["queued"]; w < time.Hour || w > time.Hour+time.Minute {
+		t.Errorf("queued task wait = %s, want about 1h", w)
+	}
+	if w := waits["retried"]; w < time.Minute || w > 2*time.Minute {
+		t.Errorf("retried task wait = %s, want about 1m", w)
+	}
+}
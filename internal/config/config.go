@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/d-kuro/gwq/internal/url"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/d-kuro/gwq/pkg/utils"
 	"github.com/spf13/viper"
@@ -16,8 +17,9 @@ const (
 	configType = "toml"
 )
 
-// getConfigDir returns the configuration directory path.
-func getConfigDir() string {
+// Dir returns the configuration directory path, where gwq keeps its config
+// file and caches.
+func Dir() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		// Fallback to current directory if home is not available
@@ -28,7 +30,7 @@ func getConfigDir() string {
 
 // Init initializes the configuration system, creating default config if needed.
 func Init() error {
-	configDir := getConfigDir()
+	configDir := Dir()
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -39,6 +41,7 @@ func Init() error {
 
 	viper.SetDefault("worktree.basedir", "~/worktrees")
 	viper.SetDefault("worktree.auto_mkdir", true)
+	viper.SetDefault("worktree.post_create_hooks_fatal", false)
 	viper.SetDefault("finder.preview", true)
 	viper.SetDefault("ui.icons", true)
 	viper.SetDefault("ui.tilde_home", true)
@@ -46,11 +49,20 @@ func Init() error {
 	// Claude defaults
 	viper.SetDefault("claude.executable", "claude")
 	viper.SetDefault("claude.config_dir", "~/.config/gwq/claude")
+	viper.SetDefault("claude.output_format", "stream-json")
 	viper.SetDefault("claude.max_parallel", 3)
+	viper.SetDefault("claude.max_parallel_per_repository", 0)
+	viper.SetDefault("claude.max_load_average", 0)
+	viper.SetDefault("claude.max_cost_usd", 0)
+	viper.SetDefault("claude.poll_interval", "5s")
+	viper.SetDefault("claude.max_poll_interval", "1m")
 	viper.SetDefault("claude.max_development_tasks", 2)
 
 	// Claude queue defaults
 	viper.SetDefault("claude.queue.queue_dir", "~/.config/gwq/claude/queue")
+	viper.SetDefault("claude.queue.aging_threshold", "1h")
+	viper.SetDefault("claude.queue.aging_rate", 0)
+	viper.SetDefault("claude.queue.duplicate_similarity", 0.9)
 
 	// Claude worktree defaults
 	viper.SetDefault("claude.worktree.auto_create_worktree", true)
@@ -59,6 +71,9 @@ func Init() error {
 
 	// Claude execution defaults
 	viper.SetDefault("claude.execution.auto_cleanup", true)
+	viper.SetDefault("claude.execution.retention_days", 30)
+	viper.SetDefault("claude.execution.log_part_size_mb", 100)
+	viper.SetDefault("claude.execution.timeout", "2h")
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
@@ -89,6 +104,12 @@ func Load() (*models.Config, error) {
 	}
 	cfg.Worktree.BaseDir = expandedPath
 
+	if cfg.Worktree.PathTemplate != "" {
+		if err := url.ValidatePathTemplate(cfg.Worktree.PathTemplate); err != nil {
+			return nil, fmt.Errorf("invalid worktree.path_template: %w", err)
+		}
+	}
+
 	// Expand Claude configuration paths
 	expandedPath, err = utils.ExpandPath(cfg.Claude.ConfigDir)
 	if err != nil {
@@ -96,6 +117,12 @@ func Load() (*models.Config, error) {
 	}
 	cfg.Claude.ConfigDir = expandedPath
 
+	expandedPath, err = utils.ExpandPath(cfg.Claude.LogDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand claude log dir: %w", err)
+	}
+	cfg.Claude.LogDir = expandedPath
+
 	expandedPath, err = utils.ExpandPath(cfg.Claude.Queue.QueueDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to expand claude queue dir: %w", err)
@@ -111,6 +138,12 @@ func Set(key string, value any) error {
 	return viper.WriteConfig()
 }
 
+// Override sets a configuration value for the current run only, without
+// writing it to the config file.
+func Override(key string, value any) {
+	viper.Set(key, value)
+}
+
 // GetValue retrieves a configuration value by key.
 func GetValue(key string) any {
 	return viper.Get(key)
@@ -143,6 +176,11 @@ func Get() *models.Config {
 			defaultCfg.Claude.ConfigDir = expandedPath
 		}
 
+		expandedPath, err = utils.ExpandPath(defaultCfg.Claude.LogDir)
+		if err == nil {
+			defaultCfg.Claude.LogDir = expandedPath
+		}
+
 		expandedPath, err = utils.ExpandPath(defaultCfg.Claude.Queue.QueueDir)
 		if err == nil {
 			defaultCfg.Claude.Queue.QueueDir = expandedPath
@@ -17,16 +17,16 @@ func TestGetConfigDir(t *testing.T) {
 
 		_ = os.Unsetenv("XDG_CONFIG_HOME")
 
-		dir := getConfigDir()
+		dir := Dir()
 		if !filepath.IsAbs(dir) {
-			t.Errorf("getConfigDir() should return absolute path, got %s", dir)
+			t.Errorf("Dir() should return absolute path, got %s", dir)
 		}
 		if filepath.Base(dir) != "gwq" {
-			t.Errorf("getConfigDir() should end with 'gwq', got %s", dir)
+			t.Errorf("Dir() should end with 'gwq', got %s", dir)
 		}
 	})
 
-	// getConfigDir uses os.UserConfigDir which doesn't respect XDG_CONFIG_HOME on macOS
+	// Dir uses os.UserConfigDir which doesn't respect XDG_CONFIG_HOME on macOS
 	// So we just verify the basic behavior
 }
 
@@ -94,6 +94,28 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestLoadInvalidPathTemplate(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(func() {
+		viper.Reset()
+	})
+	viper.Set("worktree.basedir", "~/test-worktrees")
+	viper.Set("worktree.path_template", "{owner}/{project}/{branch}")
+
+	if _, err := Load(); err == nil {
+		t.Error("Load() should fail for a path template with an unknown placeholder")
+	}
+
+	viper.Set("worktree.path_template", "{owner}/{repo}/{branch}")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Worktree.PathTemplate != "{owner}/{repo}/{branch}" {
+		t.Errorf("WorktreeConfig.PathTemplate = %q, want %q", cfg.Worktree.PathTemplate, "{owner}/{repo}/{branch}")
+	}
+}
+
 func TestPathExpansion(t *testing.T) {
 	// Test home directory expansion
 	t.Run("HomeDirectoryExpansion", func(t *testing.T) {
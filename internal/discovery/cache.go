@@ -0,0 +1,127 @@
+package discovery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/config"
+)
+
+// cacheVersion is bumped whenever the on-disk cache format changes.
+const cacheVersion = 1
+
+// cachePath is the location of the discovery cache file, in the gwq config
+// directory.
+var cachePath = filepath.Join(config.Dir(), "discovery_cache.json")
+
+// discoveryCache is the on-disk representation of previously discovered worktrees.
+type discoveryCache struct {
+	Version int                     `json:"version"`
+	BaseDir string                  `json:"base_dir"`
+	Entries map[string]*cachedEntry `json:"entries"` // key is worktree path
+}
+
+// cachedEntry stores a worktree entry along with the modtimes it was derived from.
+type cachedEntry struct {
+	DirModTime  time.Time            `json:"dir_mod_time"`
+	HeadModTime time.Time            `json:"head_mod_time"`
+	Entry       *GlobalWorktreeEntry `json:"entry"`
+}
+
+// newDiscoveryCache returns an empty cache for baseDir.
+func newDiscoveryCache(baseDir string) *discoveryCache {
+	return &discoveryCache{
+		Version: cacheVersion,
+		BaseDir: baseDir,
+		Entries: make(map[string]*cachedEntry),
+	}
+}
+
+// loadDiscoveryCache reads the cache for baseDir. A missing, corrupted, or
+// mismatched cache yields an empty one so discovery falls back to a full scan.
+func loadDiscoveryCache(path, baseDir string) *discoveryCache {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newDiscoveryCache(baseDir)
+	}
+
+	var cache discoveryCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return newDiscoveryCache(baseDir)
+	}
+	if cache.Version != cacheVersion || cache.BaseDir != baseDir || cache.Entries == nil {
+		return newDiscoveryCache(baseDir)
+	}
+
+	return &cache
+}
+
+// save writes the cache atomically. Errors are returned but callers treat
+// the cache as best effort.
+func (c *discoveryCache) save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".discovery_cache-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// lookup returns the cached entry for path if its modtimes still match.
+func (c *discoveryCache) lookup(path string, dirModTime, headModTime time.Time) *GlobalWorktreeEntry {
+	cached, ok := c.Entries[path]
+	if !ok || cached == nil || cached.Entry == nil {
+		return nil
+	}
+	if !cached.DirModTime.Equal(dirModTime) || !cached.HeadModTime.Equal(headModTime) {
+		return nil
+	}
+	return cached.Entry
+}
+
+// headModTime returns the latest modtime of the worktree's HEAD and HEAD
+// reflog, which change on checkout and commit respectively.
+func headModTime(worktreePath, gitdir string) time.Time {
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(worktreePath, gitdir)
+	}
+
+	var latest time.Time
+	for _, name := range []string{"HEAD", filepath.Join("logs", "HEAD")} {
+		info, err := os.Stat(filepath.Join(gitdir, name))
+		if err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// parseGitdir extracts the gitdir path from the contents of a worktree .git file.
+func parseGitdir(content string) (string, bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "gitdir: ") {
+		return "", false
+	}
+	return strings.TrimPrefix(content, "gitdir: "), true
+}
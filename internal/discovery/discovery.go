@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/d-kuro/gwq/internal/git"
 	"github.com/d-kuro/gwq/internal/url"
@@ -21,10 +24,37 @@ type GlobalWorktreeEntry struct {
 	Path           string
 	CommitHash     string
 	IsMain         bool
+	Locked         bool   // Whether the worktree is locked (git worktree lock)
+	LockReason     string // Reason given when the worktree was locked, if any
+}
+
+// DiscoverOptions controls global worktree discovery.
+type DiscoverOptions struct {
+	ForceRefresh bool // Ignore cached entries and rescan every worktree
+	Concurrency  int  // Number of worktrees inspected in parallel (0 = runtime.NumCPU())
 }
 
 // DiscoverGlobalWorktrees finds all worktrees in the configured base directory.
-func DiscoverGlobalWorktrees(baseDir string) ([]*GlobalWorktreeEntry, error) {
+// Results are cached per worktree and reused while the worktree directory and
+// its HEAD are unchanged; forceRefresh bypasses the cache. Directories matched
+// by a .gwqignore file in the base directory are skipped.
+func DiscoverGlobalWorktrees(baseDir string, forceRefresh bool) ([]*GlobalWorktreeEntry, error) {
+	return DiscoverGlobalWorktreesWithOptions(baseDir, DiscoverOptions{ForceRefresh: forceRefresh})
+}
+
+// candidate is a worktree directory found while walking the base directory.
+type candidate struct {
+	path        string
+	dirModTime  time.Time
+	headModTime time.Time
+	locked      bool
+	lockReason  string
+	entry       *GlobalWorktreeEntry // Set when served from cache or after extraction
+}
+
+// DiscoverGlobalWorktreesWithOptions finds all worktrees in the base directory,
+// inspecting uncached worktrees concurrently. Results are ordered by path.
+func DiscoverGlobalWorktreesWithOptions(baseDir string, opts DiscoverOptions) ([]*GlobalWorktreeEntry, error) {
 	if baseDir == "" {
 		return nil, fmt.Errorf("base directory not configured")
 	}
@@ -41,9 +71,53 @@ func DiscoverGlobalWorktrees(baseDir string) ([]*GlobalWorktreeEntry, error) {
 		return []*GlobalWorktreeEntry{}, nil
 	}
 
+	cache := newDiscoveryCache(baseDir)
+	if !opts.ForceRefresh {
+		cache = loadDiscoveryCache(cachePath, baseDir)
+	}
+
+	ignore, err := loadIgnoreFile(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := findCandidates(baseDir, cache, ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	extractCandidates(candidates, opts.Concurrency)
+
+	updated := newDiscoveryCache(baseDir)
 	var entries []*GlobalWorktreeEntry
+	for _, c := range candidates {
+		if c.entry == nil {
+			continue // Extraction failed, skip this worktree
+		}
+		// Locking doesn't touch the directory or HEAD, so the lock state is
+		// never taken from the cache
+		c.entry.Locked, c.entry.LockReason = c.locked, c.lockReason
+		updated.Entries[c.path] = &cachedEntry{
+			DirModTime:  c.dirModTime,
+			HeadModTime: c.headModTime,
+			Entry:       c.entry,
+		}
+		entries = append(entries, c.entry)
+	}
+
+	// The cache only speeds up later runs, so a failed write is not an error
+	_ = updated.save(cachePath)
+
+	return entries, nil
+}
 
-	err = filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+// findCandidates walks baseDir in lexical order and returns every worktree
+// directory, filling in entries that are still valid in the cache. Directories
+// matched by the ignore file are not descended into.
+func findCandidates(baseDir string, cache *discoveryCache, ignore *ignoreMatcher) ([]*candidate, error) {
+	var candidates []*candidate
+
+	err := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors and continue walking
 		}
@@ -53,6 +127,10 @@ func DiscoverGlobalWorktrees(baseDir string) ([]*GlobalWorktreeEntry, error) {
 			return nil
 		}
 
+		if rel, err := filepath.Rel(baseDir, path); err == nil && ignore.match(filepath.ToSlash(rel)) {
+			return filepath.SkipDir
+		}
+
 		// Check if this directory contains a .git file (worktree marker)
 		gitFile := filepath.Join(path, ".git")
 		if _, err := os.Stat(gitFile); err != nil {
@@ -65,19 +143,19 @@ func DiscoverGlobalWorktrees(baseDir string) ([]*GlobalWorktreeEntry, error) {
 			return nil
 		}
 
-		gitContentStr := strings.TrimSpace(string(gitContent))
-		if !strings.HasPrefix(gitContentStr, "gitdir: ") {
+		gitdir, ok := parseGitdir(string(gitContent))
+		if !ok {
 			return nil // Not a worktree, it's a main repository
 		}
 
-		// This is a worktree, extract information
-		entry, err := extractWorktreeInfo(path)
-		if err != nil {
-			// Log error but continue discovery
-			return nil
+		c := &candidate{
+			path:        path,
+			dirModTime:  info.ModTime(),
+			headModTime: headModTime(path, gitdir),
 		}
-
-		entries = append(entries, entry)
+		c.locked, c.lockReason = worktreeLock(path, gitdir)
+		c.entry = cache.lookup(path, c.dirModTime, c.headModTime)
+		candidates = append(candidates, c)
 		return nil
 	})
 
@@ -85,7 +163,54 @@ func DiscoverGlobalWorktrees(baseDir string) ([]*GlobalWorktreeEntry, error) {
 		return nil, fmt.Errorf("failed to walk directory: %w", err)
 	}
 
-	return entries, nil
+	return candidates, nil
+}
+
+// worktreeLock reports whether the worktree with the given gitdir is locked,
+// and the lock reason, from the "locked" file git keeps in the gitdir.
+func worktreeLock(worktreePath, gitdir string) (bool, string) {
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(worktreePath, gitdir)
+	}
+
+	reason, err := os.ReadFile(filepath.Join(gitdir, "locked"))
+	if err != nil {
+		return false, ""
+	}
+	return true, strings.TrimSpace(string(reason))
+}
+
+// extractCandidates extracts worktree information for candidates without a
+// cached entry using a bounded worker pool. Candidates that fail are left
+// without an entry so a single broken worktree does not abort discovery.
+func extractCandidates(candidates []*candidate, concurrency int) {
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	jobs := make(chan *candidate)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				entry, err := extractWorktreeInfo(c.path)
+				if err != nil {
+					continue
+				}
+				c.entry = entry
+			}
+		}()
+	}
+
+	for _, c := range candidates {
+		if c.entry == nil {
+			jobs <- c
+		}
+	}
+	close(jobs)
+	wg.Wait()
 }
 
 // extractWorktreeInfo extracts worktree information from a worktree directory.
@@ -177,6 +302,8 @@ func ConvertToWorktreeModels(entries []*GlobalWorktreeEntry, showRepoName bool)
 			Path:       entry.Path,
 			CommitHash: entry.CommitHash,
 			IsMain:     entry.IsMain,
+			Locked:     entry.Locked,
+			LockReason: entry.LockReason,
 		}
 		worktrees = append(worktrees, wt)
 	}
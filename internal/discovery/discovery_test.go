@@ -0,0 +1,314 @@
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/config"
+)
+
+// runGit runs a git command in dir, failing the test on error
+func runGit(tb testing.TB, dir string, args ...string) {
+	tb.Helper()
+	args = append([]string{"-c", "user.name=gwq", "-c", "user.email=gwq@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		tb.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// setupBaseDir creates a repository with n worktrees under a temp base directory
+// and points the discovery cache at a temp file
+func setupBaseDir(tb testing.TB, n int) (baseDir, repoDir string) {
+	tb.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		tb.Skip("git not available")
+	}
+
+	baseDir = tb.TempDir()
+	repoDir = filepath.Join(tb.TempDir(), "repo")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		tb.Fatal(err)
+	}
+	runGit(tb, repoDir, "init", "-q", "-b", "main")
+	runGit(tb, repoDir, "remote", "add", "origin", "https://github.com/example/repo.git")
+	runGit(tb, repoDir, "commit", "-q", "--allow-empty", "-m", "init")
+
+	for i := 0; i < n; i++ {
+		addWorktree(tb, repoDir, baseDir, fmt.Sprintf("feature-%d", i))
+	}
+
+	oldCachePath := cachePath
+	cachePath = filepath.Join(tb.TempDir(), "discovery_cache.json")
+	tb.Cleanup(func() { cachePath = oldCachePath })
+
+	return baseDir, repoDir
+}
+
+func addWorktree(tb testing.TB, repoDir, baseDir, branch string) {
+	tb.Helper()
+	runGit(tb, repoDir, "worktree", "add", "-q", "-b", branch, filepath.Join(baseDir, branch))
+}
+
+func branches(entries []*GlobalWorktreeEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Branch)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestCachePathInConfigDir(t *testing.T) {
+	if dir := filepath.Dir(cachePath); dir != config.Dir() {
+		t.Errorf("cache directory = %s, want the config directory %s", dir, config.Dir())
+	}
+}
+
+func TestDiscoverGlobalWorktreesCache(t *testing.T) {
+	baseDir, repoDir := setupBaseDir(t, 2)
+
+	entries, err := DiscoverGlobalWorktrees(baseDir, false)
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktrees() error = %v", err)
+	}
+	if got := branches(entries); fmt.Sprint(got) != "[feature-0 feature-1]" {
+		t.Fatalf("branches = %v", got)
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("cache file not written: %v", err)
+	}
+
+	// Tamper with a cached entry: an unchanged worktree is served from cache
+	cache := loadDiscoveryCache(cachePath, baseDir)
+	cache.Entries[filepath.Join(baseDir, "feature-0")].Entry.Branch = "from-cache"
+	if err := cache.save(cachePath); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = DiscoverGlobalWorktrees(baseDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := branches(entries); fmt.Sprint(got) != "[feature-1 from-cache]" {
+		t.Errorf("cached branches = %v, want cached entry reused", got)
+	}
+
+	// ForceRefresh ignores the cache
+	entries, err = DiscoverGlobalWorktrees(baseDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := branches(entries); fmt.Sprint(got) != "[feature-0 feature-1]" {
+		t.Errorf("refreshed branches = %v", got)
+	}
+
+	// A new worktree is picked up
+	addWorktree(t, repoDir, baseDir, "feature-2")
+	entries, err = DiscoverGlobalWorktrees(baseDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := branches(entries); fmt.Sprint(got) != "[feature-0 feature-1 feature-2]" {
+		t.Errorf("branches after add = %v", got)
+	}
+
+	// Switching branches changes HEAD and invalidates the entry
+	runGit(t, filepath.Join(baseDir, "feature-1"), "checkout", "-q", "-b", "renamed")
+	entries, err = DiscoverGlobalWorktrees(baseDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := branches(entries); fmt.Sprint(got) != "[feature-0 feature-2 renamed]" {
+		t.Errorf("branches after checkout = %v", got)
+	}
+
+	// Removed worktrees are dropped from the cache
+	runGit(t, repoDir, "worktree", "remove", filepath.Join(baseDir, "feature-2"))
+	if _, err := DiscoverGlobalWorktrees(baseDir, false); err != nil {
+		t.Fatal(err)
+	}
+	cache = loadDiscoveryCache(cachePath, baseDir)
+	if _, ok := cache.Entries[filepath.Join(baseDir, "feature-2")]; ok {
+		t.Error("removed worktree should not remain in cache")
+	}
+}
+
+func TestDiscoverGlobalWorktreesLocked(t *testing.T) {
+	baseDir, repoDir := setupBaseDir(t, 2)
+	lockedPath := filepath.Join(baseDir, "feature-1")
+	runGit(t, repoDir, "worktree", "lock", "--reason", "on a removable disk", lockedPath)
+
+	lockState := func() map[string]string {
+		t.Helper()
+		entries, err := DiscoverGlobalWorktrees(baseDir, false)
+		if err != nil {
+			t.Fatalf("DiscoverGlobalWorktrees() error = %v", err)
+		}
+		state := make(map[string]string)
+		for _, wt := range ConvertToWorktreeModels(entries, false) {
+			if wt.Locked {
+				state[wt.Branch] = wt.LockReason
+			}
+		}
+		return state
+	}
+
+	want := map[string]string{"feature-1": "on a removable disk"}
+	if got := lockState(); !reflect.DeepEqual(got, want) {
+		t.Errorf("locked worktrees = %v, want %v", got, want)
+	}
+	// Served from the cache, the lock state is still read fresh
+	runGit(t, repoDir, "worktree", "unlock", lockedPath)
+	if got := lockState(); len(got) != 0 {
+		t.Errorf("locked worktrees after unlock = %v, want none", got)
+	}
+}
+
+func TestDiscoverGlobalWorktreesCorruptedCache(t *testing.T) {
+	baseDir, _ := setupBaseDir(t, 1)
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid json", content: "{not json"},
+		{name: "wrong version", content: `{"version":99,"base_dir":"` + baseDir + `","entries":{}}`},
+		{name: "null entry", content: `{"version":1,"base_dir":"` + baseDir + `","entries":{"x":null}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(cachePath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := DiscoverGlobalWorktrees(baseDir, false)
+			if err != nil {
+				t.Fatalf("DiscoverGlobalWorktrees() error = %v", err)
+			}
+			if got := branches(entries); fmt.Sprint(got) != "[feature-0]" {
+				t.Errorf("branches = %v", got)
+			}
+
+			// The cache is rewritten in a valid format
+			data, err := os.ReadFile(cachePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cache discoveryCache
+			if err := json.Unmarshal(data, &cache); err != nil || cache.Version != cacheVersion {
+				t.Errorf("cache not rewritten: %v", err)
+			}
+		})
+	}
+}
+
+func TestDiscoverGlobalWorktreesIgnoreFile(t *testing.T) {
+	baseDir, repoDir := setupBaseDir(t, 0)
+	for _, branch := range []string{"app/main-work", "archive/old-fix", "app/tmp-spike", "vendor/lib/patch", "vendor/keep"} {
+		addWorktree(t, repoDir, baseDir, branch)
+	}
+
+	// Without an ignore file everything is discovered
+	entries, err := DiscoverGlobalWorktrees(baseDir, false)
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktrees() error = %v", err)
+	}
+	if got := branches(entries); fmt.Sprint(got) != "[app/main-work app/tmp-spike archive/old-fix vendor/keep vendor/lib/patch]" {
+		t.Fatalf("branches without %s = %v", IgnoreFileName, got)
+	}
+
+	ignore := "# unrelated checkouts\n/archive/\ntmp-*\nvendor/**\n!vendor/keep\n"
+	if err := os.WriteFile(filepath.Join(baseDir, IgnoreFileName), []byte(ignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cached entries of ignored worktrees are not served either
+	entries, err = DiscoverGlobalWorktrees(baseDir, false)
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktrees() error = %v", err)
+	}
+	if got := branches(entries); fmt.Sprint(got) != "[app/main-work vendor/keep]" {
+		t.Errorf("branches with %s = %v, want ignored worktrees excluded", IgnoreFileName, got)
+	}
+}
+
+func BenchmarkDiscoverGlobalWorktrees(b *testing.B) {
+	baseDir, _ := setupBaseDir(b, 10)
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := DiscoverGlobalWorktrees(baseDir, true); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		if _, err := DiscoverGlobalWorktrees(baseDir, false); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := DiscoverGlobalWorktrees(baseDir, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestDiscoverGlobalWorktreesConcurrency(t *testing.T) {
+	baseDir, _ := setupBaseDir(t, 6)
+
+	// A worktree whose gitdir is missing fails extraction and must be skipped
+	broken := filepath.Join(baseDir, "broken")
+	if err := os.MkdirAll(broken, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(broken, ".git"), []byte("gitdir: /nonexistent/worktrees/broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sequential, err := DiscoverGlobalWorktreesWithOptions(baseDir, DiscoverOptions{ForceRefresh: true, Concurrency: 1})
+	if err != nil {
+		t.Fatalf("sequential discovery error = %v", err)
+	}
+	if len(sequential) != 6 {
+		t.Fatalf("sequential discovery found %d worktrees, want 6", len(sequential))
+	}
+
+	for _, concurrency := range []int{0, 2, 4, 16} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			got, err := DiscoverGlobalWorktreesWithOptions(baseDir, DiscoverOptions{ForceRefresh: true, Concurrency: concurrency})
+			if err != nil {
+				t.Fatalf("discovery error = %v", err)
+			}
+			if !reflect.DeepEqual(got, sequential) {
+				t.Errorf("results differ from sequential discovery:\ngot  %v\nwant %v", branches(got), branches(sequential))
+			}
+		})
+	}
+}
+
+func BenchmarkDiscoverGlobalWorktreesConcurrency(b *testing.B) {
+	baseDir, _ := setupBaseDir(b, 20)
+
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", concurrency), func(b *testing.B) {
+			opts := DiscoverOptions{ForceRefresh: true, Concurrency: concurrency}
+			for i := 0; i < b.N; i++ {
+				if _, err := DiscoverGlobalWorktreesWithOptions(baseDir, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
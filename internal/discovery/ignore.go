@@ -0,0 +1,141 @@
+package discovery
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the file in the base directory listing paths that
+// discovery does not descend into.
+const IgnoreFileName = ".gwqignore"
+
+// ignoreRule is one pattern of an ignore file.
+type ignoreRule struct {
+	segments []string // Pattern split on "/"; "**" matches any number of segments
+	anchored bool     // Matched against the whole relative path rather than any trailing part
+	negate   bool     // Re-includes paths excluded by an earlier rule
+}
+
+// ignoreMatcher decides which directories under the base directory are
+// skipped. A nil matcher skips nothing.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// loadIgnoreFile parses the ignore file in baseDir. It returns nil when the
+// file does not exist.
+func loadIgnoreFile(baseDir string) (*ignoreMatcher, error) {
+	file, err := os.Open(filepath.Join(baseDir, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	return parseIgnorePatterns(lines), nil
+}
+
+// parseIgnorePatterns builds a matcher from gitignore-style lines. Blank
+// lines and lines starting with "#" are skipped, "!" negates a pattern, a
+// trailing "/" is allowed since only directories are matched, and a pattern
+// containing a "/" other than a trailing one is relative to the base
+// directory. Otherwise it matches a directory name at any depth.
+func parseIgnorePatterns(lines []string) *ignoreMatcher {
+	m := &ignoreMatcher{}
+	for _, line := range lines {
+		pattern := strings.TrimSpace(line)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(pattern, "!") {
+			rule.negate = true
+			pattern = pattern[1:]
+		}
+		pattern = strings.TrimSuffix(pattern, "/")
+		if strings.Contains(pattern, "/") {
+			rule.anchored = true
+			pattern = strings.TrimPrefix(pattern, "/")
+		}
+		if pattern == "" {
+			continue
+		}
+		rule.segments = strings.Split(pattern, "/")
+		m.rules = append(m.rules, rule)
+	}
+	return m
+}
+
+// match reports whether the directory at rel, a slash-separated path relative
+// to the base directory, is ignored. The last matching rule wins.
+func (m *ignoreMatcher) match(rel string) bool {
+	if m == nil || rel == "." || rel == "" {
+		return false
+	}
+
+	segments := strings.Split(rel, "/")
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.matches(segments) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule matches the path segments.
+func (r ignoreRule) matches(segments []string) bool {
+	if r.anchored {
+		return matchSegments(r.segments, segments)
+	}
+	// Unanchored patterns may match at any depth
+	for start := range segments {
+		if matchSegments(r.segments, segments[start:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches a whole path against pattern segments, where "**"
+// stands for zero or more path segments and other segments use path.Match. A
+// trailing "**" needs at least one segment, so "dir/**" matches what is
+// inside dir but not dir itself.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return len(segments) > 0
+		}
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
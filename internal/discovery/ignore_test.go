@@ -0,0 +1,49 @@
+package discovery
+
+import "testing"
+
+func TestIgnoreMatcher(t *testing.T) {
+	m := parseIgnorePatterns([]string{
+		"# comment",
+		"",
+		"node_modules",
+		"/archive/",
+		"tmp-*",
+		"clients/*/legacy",
+		"vendor/**",
+		"!vendor/keep",
+		"**/build",
+	})
+
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{rel: ".", want: false},
+		{rel: "node_modules", want: true},
+		{rel: "github.com/org/repo/node_modules", want: true},
+		{rel: "archive", want: true},
+		{rel: "github.com/archive", want: false},
+		{rel: "tmp-spike", want: true},
+		{rel: "github.com/org/tmp-1", want: true},
+		{rel: "clients/acme/legacy", want: true},
+		{rel: "clients/acme/beta/legacy", want: false},
+		{rel: "vendor/lib", want: true},
+		{rel: "vendor/keep", want: false},
+		{rel: "vendor", want: false},
+		{rel: "a/b/build", want: true},
+		{rel: "github.com/org/repo", want: false},
+		{rel: "comment", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := m.match(tt.rel); got != tt.want {
+			t.Errorf("match(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+
+	var none *ignoreMatcher
+	if none.match("anything") {
+		t.Error("nil matcher should not ignore anything")
+	}
+}
@@ -4,6 +4,7 @@ package finder
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/git"
@@ -13,17 +14,45 @@ import (
 	"github.com/ktr0731/go-fuzzyfinder"
 )
 
+// Time formats for FinderConfig.TimeFormat.
+const (
+	// TimeFormatRelative shows times as precise elapsed durations, e.g. "2h 15m ago".
+	TimeFormatRelative = "relative"
+	// TimeFormatAbsolute shows times as local timestamps with seconds.
+	TimeFormatAbsolute = "absolute"
+	// TimeFormatISO shows times in RFC 3339 format.
+	TimeFormatISO = "iso"
+)
+
 // Finder provides fuzzy finder functionality.
 type Finder struct {
-	git          *git.Git
+	git          previewGit
 	config       *models.FinderConfig
 	useTildeHome bool
+	activity     ActivityFunc
+
+	// baseBranch is resolved on the first preview that needs it
+	baseOnce   sync.Once
+	baseBranch string
+}
+
+// maxChangelogCommits caps the commit subjects listed under "N commits ahead
+// of <base>" in previews.
+const maxChangelogCommits = 5
+
+// previewGit is the subset of git operations used to build previews.
+type previewGit interface {
+	GetRecentCommits(path string, limit int) ([]models.CommitInfo, error)
+	GetAheadBehind(path string) (ahead, behind int, err error)
+	GetCommitsSince(base, branch string, limit int) ([]models.CommitInfo, error)
+	CountCommitsSince(base, branch string) (int, error)
+	GetDefaultBranch() (string, error)
 }
 
 // New creates a new Finder instance.
 func New(g *git.Git, config *models.FinderConfig) *Finder {
 	return &Finder{
-		git:    g,
+		git:    newPreviewGit(g),
 		config: config,
 	}
 }
@@ -31,17 +60,26 @@ func New(g *git.Git, config *models.FinderConfig) *Finder {
 // NewWithUI creates a new Finder instance with UI configuration.
 func NewWithUI(g *git.Git, config *models.FinderConfig, uiConfig *models.UIConfig) *Finder {
 	return &Finder{
-		git:          g,
+		git:          newPreviewGit(g),
 		config:       config,
 		useTildeHome: uiConfig.TildeHome,
 	}
 }
 
+// newPreviewGit keeps a nil *git.Git from becoming a non-nil interface.
+func newPreviewGit(g *git.Git) previewGit {
+	if g == nil {
+		return nil
+	}
+	return g
+}
+
 // SelectWorktree displays a fuzzy finder for worktree selection.
 func (f *Finder) SelectWorktree(worktrees []models.Worktree) (*models.Worktree, error) {
 	if len(worktrees) == 0 {
 		return nil, fmt.Errorf("no worktrees available for selection")
 	}
+	worktrees = f.sortWorktrees(worktrees)
 
 	opts := []fuzzyfinder.Option{
 		fuzzyfinder.WithPromptString("Select worktree> "),
@@ -126,6 +164,7 @@ func (f *Finder) SelectMultipleWorktrees(worktrees []models.Worktree) ([]models.
 	if len(worktrees) == 0 {
 		return nil, fmt.Errorf("no worktrees available for multiple selection")
 	}
+	worktrees = f.sortWorktrees(worktrees)
 
 	opts := []fuzzyfinder.Option{
 		fuzzyfinder.WithPromptString("Select worktrees (Tab to select multiple)> "),
@@ -214,8 +253,8 @@ func (f *Finder) generateSessionPreview(session *tmux.Session, maxLines int) str
 		fmt.Sprintf("Context: %s", session.Context),
 		fmt.Sprintf("Identifier: %s", session.Identifier),
 		fmt.Sprintf("Command: %s", session.Command),
-		fmt.Sprintf("Duration: %s", formatDuration(time.Since(session.StartTime))),
-		fmt.Sprintf("Started: %s", session.StartTime.Format("2006-01-02 15:04:05")),
+		fmt.Sprintf("Duration: %s", formatDuration(time.Since(session.StartTime), f.timeFormat() == TimeFormatRelative)),
+		fmt.Sprintf("Started: %s", f.formatTime(session.StartTime, "2006-01-02 15:04:05")),
 	}
 
 	if session.WorkingDir != "" {
@@ -237,7 +276,13 @@ func (f *Finder) generateSessionPreview(session *tmux.Session, maxLines int) str
 	return strings.Join(preview, "\n")
 }
 
-func formatDuration(d time.Duration) string {
+// formatDuration formats a duration for display. Composite output keeps the two
+// most significant units (e.g. "2h 15m"); otherwise a coarse bucket is used.
+func formatDuration(d time.Duration, composite bool) string {
+	if composite {
+		return formatCompositeDuration(d)
+	}
+
 	switch {
 	case d < time.Minute:
 		return "just now"
@@ -262,6 +307,59 @@ func formatDuration(d time.Duration) string {
 	}
 }
 
+// formatCompositeDuration formats d using its two most significant units.
+func formatCompositeDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	mins := int(d/time.Minute) % 60
+	secs := int(d/time.Second) % 60
+
+	switch {
+	case days > 0:
+		return joinUnits(days, "d", hours, "h")
+	case hours > 0:
+		return joinUnits(hours, "h", mins, "m")
+	case mins > 0:
+		return joinUnits(mins, "m", secs, "s")
+	default:
+		return fmt.Sprintf("%ds", secs)
+	}
+}
+
+// joinUnits formats a pair of units, omitting the minor one when it is zero.
+func joinUnits(major int, majorUnit string, minor int, minorUnit string) string {
+	if minor == 0 {
+		return fmt.Sprintf("%d%s", major, majorUnit)
+	}
+	return fmt.Sprintf("%d%s %d%s", major, majorUnit, minor, minorUnit)
+}
+
+// timeFormat returns the configured time format, or "" for the default.
+func (f *Finder) timeFormat() string {
+	if f.config == nil {
+		return ""
+	}
+	return f.config.TimeFormat
+}
+
+// formatTime formats a timestamp for previews according to the configured
+// time format, falling back to layout by default.
+func (f *Finder) formatTime(t time.Time, layout string) string {
+	switch f.timeFormat() {
+	case TimeFormatRelative:
+		return formatCompositeDuration(time.Since(t)) + " ago"
+	case TimeFormatAbsolute:
+		return t.Local().Format("2006-01-02 15:04:05")
+	case TimeFormatISO:
+		return t.Format(time.RFC3339)
+	default:
+		return t.Format(layout)
+	}
+}
+
 // generateWorktreePreview generates preview content for a worktree.
 func (f *Finder) generateWorktreePreview(wt models.Worktree, maxLines int) string {
 	path := wt.Path
@@ -272,7 +370,7 @@ func (f *Finder) generateWorktreePreview(wt models.Worktree, maxLines int) strin
 		fmt.Sprintf("Branch: %s", wt.Branch),
 		fmt.Sprintf("Path: %s", path),
 		fmt.Sprintf("Commit: %s", truncateHash(wt.CommitHash)),
-		fmt.Sprintf("Created: %s", wt.CreatedAt.Format("2006-01-02 15:04")),
+		fmt.Sprintf("Created: %s", f.formatTime(wt.CreatedAt, "2006-01-02 15:04")),
 	}
 
 	if wt.IsMain {
@@ -281,6 +379,16 @@ func (f *Finder) generateWorktreePreview(wt models.Worktree, maxLines int) strin
 		preview = append(preview, "Type: Additional worktree")
 	}
 
+	if f.git != nil {
+		if ahead, behind, err := f.git.GetAheadBehind(wt.Path); err == nil {
+			preview = append(preview, fmt.Sprintf("Ahead/Behind: ↑%d ↓%d", ahead, behind))
+		}
+	}
+
+	if !wt.IsMain {
+		preview = append(preview, f.changelogPreview(wt.Branch)...)
+	}
+
 	remainingLines := maxLines - len(preview) - 2
 	if remainingLines > 0 && f.git != nil {
 		preview = append(preview, "", "Recent commits:")
@@ -312,13 +420,57 @@ func (f *Finder) generateBranchPreview(branch models.Branch, maxLines int) strin
 		fmt.Sprintf("Type: %s", branchType),
 		fmt.Sprintf("Last commit: %s", truncateMessage(branch.LastCommit.Message, 60)),
 		fmt.Sprintf("Author: %s", branch.LastCommit.Author),
-		fmt.Sprintf("Date: %s", branch.LastCommit.Date.Format("2006-01-02 15:04")),
+		fmt.Sprintf("Date: %s", f.formatTime(branch.LastCommit.Date, "2006-01-02 15:04")),
 		fmt.Sprintf("Hash: %s", truncateHash(branch.LastCommit.Hash)),
 	}
+	preview = append(preview, f.changelogPreview(branch.Name)...)
 
 	return strings.Join(preview[:utils.Min(len(preview), maxLines)], "\n")
 }
 
+// changelogPreview lists the commits on branch that are not on the default
+// branch, or returns nil when there is nothing to compare.
+func (f *Finder) changelogPreview(branch string) []string {
+	if f.git == nil || branch == "" || branch == "HEAD" {
+		return nil
+	}
+
+	f.baseOnce.Do(func() {
+		if base, err := f.git.GetDefaultBranch(); err == nil {
+			f.baseBranch = base
+		}
+	})
+	base := f.baseBranch
+	if base == "" || branch == base || strings.HasSuffix(base, "/"+branch) {
+		return nil
+	}
+
+	commits, err := f.git.GetCommitsSince(base, branch, maxChangelogCommits)
+	if err != nil {
+		return nil
+	}
+	// Only a full page of commits can have more behind it
+	total := len(commits)
+	if total == maxChangelogCommits {
+		if count, err := f.git.CountCommitsSince(base, branch); err == nil {
+			total = count
+		}
+	}
+
+	noun := "commits"
+	if total == 1 {
+		noun = "commit"
+	}
+	lines := []string{"", fmt.Sprintf("%d %s ahead of %s", total, noun, base)}
+	for _, commit := range commits {
+		lines = append(lines, fmt.Sprintf("  %s %s", truncateHash(commit.Hash), truncateMessage(commit.Message, 50)))
+	}
+	if total > len(commits) {
+		lines = append(lines, fmt.Sprintf("  ... %d more", total-len(commits)))
+	}
+	return lines
+}
+
 // truncateHash truncates a commit hash to 8 characters.
 func truncateHash(hash string) string {
 	if len(hash) > 8 {
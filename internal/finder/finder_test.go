@@ -0,0 +1,210 @@
+package finder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// stubGit returns canned preview data.
+type stubGit struct {
+	commits     []models.CommitInfo
+	ahead       int
+	behind      int
+	upstreamErr error
+	base        string
+	since       []models.CommitInfo
+}
+
+func (s *stubGit) GetRecentCommits(path string, limit int) ([]models.CommitInfo, error) {
+	return s.commits, nil
+}
+
+func (s *stubGit) GetAheadBehind(path string) (int, int, error) {
+	return s.ahead, s.behind, s.upstreamErr
+}
+
+func (s *stubGit) GetCommitsSince(base, branch string, limit int) ([]models.CommitInfo, error) {
+	if limit > 0 && len(s.since) > limit {
+		return s.since[:limit], nil
+	}
+	return s.since, nil
+}
+
+func (s *stubGit) CountCommitsSince(base, branch string) (int, error) {
+	return len(s.since), nil
+}
+
+func (s *stubGit) GetDefaultBranch() (string, error) {
+	if s.base == "" {
+		return "", errors.New("no default branch")
+	}
+	return s.base, nil
+}
+
+func TestGenerateWorktreePreview(t *testing.T) {
+	wt := models.Worktree{
+		Path:       "/repo/feature",
+		Branch:     "feature/x",
+		CommitHash: "0123456789abcdef",
+		CreatedAt:  time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		git      previewGit
+		want     []string
+		excluded []string
+	}{
+		{
+			name:     "nil git",
+			want:     []string{"Branch: feature/x", "Path: /repo/feature", "Commit: 01234567", "Type: Additional worktree"},
+			excluded: []string{"Ahead/Behind", "Recent commits:"},
+		},
+		{
+			name: "with upstream",
+			git: &stubGit{
+				ahead:   3,
+				behind:  1,
+				commits: []models.CommitInfo{{Hash: "fedcba9876543210", Message: "Add feature"}},
+			},
+			want: []string{"Type: Additional worktree", "Ahead/Behind: ↑3 ↓1", "Recent commits:", "  fedcba98 Add feature"},
+		},
+		{
+			name: "ahead of base",
+			git: &stubGit{
+				base: "origin/main",
+				since: []models.CommitInfo{
+					{Hash: "aaaaaaaa11111111", Message: "Add parser"},
+					{Hash: "bbbbbbbb22222222", Message: "Add tests"},
+				},
+			},
+			want: []string{"2 commits ahead of origin/main", "  aaaaaaaa Add parser", "  bbbbbbbb Add tests"},
+		},
+		{
+			name:     "base unknown",
+			git:      &stubGit{since: []models.CommitInfo{{Hash: "aaaaaaaa11111111", Message: "Add parser"}}},
+			excluded: []string{"ahead of"},
+		},
+		{
+			name:     "without upstream",
+			git:      &stubGit{upstreamErr: errors.New("no upstream configured")},
+			want:     []string{"Type: Additional worktree", "Recent commits:"},
+			excluded: []string{"Ahead/Behind"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finder{git: tt.git}
+			preview := f.generateWorktreePreview(wt, 20)
+
+			for _, want := range tt.want {
+				if !strings.Contains(preview, want+"\n") && !strings.HasSuffix(preview, want) {
+					t.Errorf("preview missing line %q:\n%s", want, preview)
+				}
+			}
+			for _, excluded := range tt.excluded {
+				if strings.Contains(preview, excluded) {
+					t.Errorf("preview should not contain %q:\n%s", excluded, preview)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBranchPreviewChangelog(t *testing.T) {
+	var since []models.CommitInfo
+	for i := 0; i < maxChangelogCommits+2; i++ {
+		since = append(since, models.CommitInfo{Hash: fmt.Sprintf("%016d", i), Message: fmt.Sprintf("Change %d", i)})
+	}
+	f := &Finder{git: &stubGit{base: "main", since: since}}
+
+	preview := f.generateBranchPreview(models.Branch{Name: "feature/x"}, 40)
+	for _, want := range []string{"7 commits ahead of main", "Change 4", "  ... 2 more"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("preview missing %q:\n%s", want, preview)
+		}
+	}
+	if strings.Contains(preview, "Change 5") {
+		t.Errorf("preview should list at most %d commits:\n%s", maxChangelogCommits, preview)
+	}
+
+	// Neither the base itself nor its local counterpart is compared
+	for _, name := range []string{"main", "origin/main"} {
+		f := &Finder{git: &stubGit{base: name, since: since}}
+		if preview := f.generateBranchPreview(models.Branch{Name: "main"}, 40); strings.Contains(preview, "ahead of") {
+			t.Errorf("preview of main against %s should not list commits:\n%s", name, preview)
+		}
+	}
+}
+
+func TestNewWithNilGit(t *testing.T) {
+	f := New(nil, &models.FinderConfig{})
+	if f.git != nil {
+		t.Error("New(nil) should leave git unset so previews skip git lookups")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		name          string
+		d             time.Duration
+		wantCoarse    string
+		wantComposite string
+	}{
+		{name: "seconds", d: 45 * time.Second, wantCoarse: "just now", wantComposite: "45s"},
+		{name: "one minute", d: time.Minute, wantCoarse: "1 min", wantComposite: "1m"},
+		{name: "minutes and seconds", d: 5*time.Minute + 30*time.Second, wantCoarse: "5 mins", wantComposite: "5m 30s"},
+		{name: "hours and minutes", d: 2*time.Hour + 15*time.Minute + 40*time.Second, wantCoarse: "2 hours", wantComposite: "2h 15m"},
+		{name: "whole hours", d: 3 * time.Hour, wantCoarse: "3 hours", wantComposite: "3h"},
+		{name: "days and hours", d: 49*time.Hour + 10*time.Minute, wantCoarse: "2 days", wantComposite: "2d 1h"},
+		{name: "negative", d: -time.Minute, wantCoarse: "just now", wantComposite: "0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDuration(tt.d, false); got != tt.wantCoarse {
+				t.Errorf("formatDuration(%v, false) = %q, want %q", tt.d, got, tt.wantCoarse)
+			}
+			if got := formatDuration(tt.d, true); got != tt.wantComposite {
+				t.Errorf("formatDuration(%v, true) = %q, want %q", tt.d, got, tt.wantComposite)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		config *models.FinderConfig
+		t      time.Time
+		want   string
+	}{
+		{name: "nil config", t: ts, want: "2025-01-02 03:04"},
+		{name: "default", config: &models.FinderConfig{}, t: ts, want: "2025-01-02 03:04"},
+		{name: "absolute", config: &models.FinderConfig{TimeFormat: TimeFormatAbsolute}, t: ts, want: ts.Local().Format("2006-01-02 15:04:05")},
+		{name: "iso", config: &models.FinderConfig{TimeFormat: TimeFormatISO}, t: ts, want: "2025-01-02T03:04:05Z"},
+		{
+			name:   "relative",
+			config: &models.FinderConfig{TimeFormat: TimeFormatRelative},
+			t:      time.Now().Add(-(2*time.Hour + 15*time.Minute + 30*time.Second)),
+			want:   "2h 15m ago",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finder{config: tt.config}
+			if got := f.formatTime(tt.t, "2006-01-02 15:04"); got != tt.want {
+				t.Errorf("formatTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
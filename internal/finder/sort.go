@@ -0,0 +1,77 @@
+package finder
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// Worktree sort orders for FinderConfig.SortBy.
+const (
+	SortByName     = "name"
+	SortByActivity = "activity"
+	SortByBranch   = "branch"
+)
+
+// ActivityFunc returns the last activity time of the worktree at path.
+type ActivityFunc func(path string) (time.Time, error)
+
+// SetActivityFunc sets the function used to look up worktree activity when
+// sorting by activity.
+func (f *Finder) SetActivityFunc(fn ActivityFunc) {
+	f.activity = fn
+}
+
+// sortWorktrees returns a copy of worktrees ordered according to the configured SortBy.
+// The input order is kept when no (or an unknown) sort order is configured.
+func (f *Finder) sortWorktrees(worktrees []models.Worktree) []models.Worktree {
+	sorted := make([]models.Worktree, len(worktrees))
+	copy(sorted, worktrees)
+
+	switch f.config.SortBy {
+	case SortByName:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return compareByName(sorted[i], sorted[j])
+		})
+	case SortByBranch:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Branch < sorted[j].Branch
+		})
+	case SortByActivity:
+		if f.activity == nil {
+			return sorted
+		}
+		activity := make(map[string]time.Time, len(sorted))
+		for _, wt := range sorted {
+			if t, err := f.activity(wt.Path); err == nil {
+				activity[wt.Path] = t
+			}
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return compareByActivity(sorted[i], sorted[j], activity)
+		})
+	}
+
+	return sorted
+}
+
+// compareByName orders worktrees by directory name, then by full path.
+func compareByName(a, b models.Worktree) bool {
+	nameA, nameB := filepath.Base(a.Path), filepath.Base(b.Path)
+	if nameA != nameB {
+		return nameA < nameB
+	}
+	return a.Path < b.Path
+}
+
+// compareByActivity orders worktrees by most recent activity first. Worktrees
+// without a known activity time sort last, in branch order.
+func compareByActivity(a, b models.Worktree, activity map[string]time.Time) bool {
+	timeA, timeB := activity[a.Path], activity[b.Path]
+	if !timeA.Equal(timeB) {
+		return timeA.After(timeB)
+	}
+	return a.Branch < b.Branch
+}
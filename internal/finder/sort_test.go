@@ -0,0 +1,83 @@
+package finder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestSortWorktrees(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	worktrees := []models.Worktree{
+		{Path: "/wt/main", Branch: "main", IsMain: true},
+		{Path: "/wt/feature-b", Branch: "feature/b"},
+		{Path: "/wt/feature-a", Branch: "feature/a"},
+		{Path: "/wt/bugfix", Branch: "bugfix/x"},
+	}
+	activity := map[string]time.Time{
+		"/wt/main":      now.Add(-48 * time.Hour),
+		"/wt/feature-b": now.Add(-time.Hour),
+		"/wt/feature-a": now,
+		// bugfix has no known activity
+	}
+	activityFunc := func(path string) (time.Time, error) {
+		if t, ok := activity[path]; ok {
+			return t, nil
+		}
+		return time.Time{}, errors.New("no activity")
+	}
+
+	tests := []struct {
+		name   string
+		sortBy string
+		want   []string
+	}{
+		{"unset keeps input order", "", []string{"main", "feature/b", "feature/a", "bugfix/x"}},
+		{"unknown keeps input order", "size", []string{"main", "feature/b", "feature/a", "bugfix/x"}},
+		{"name", SortByName, []string{"bugfix/x", "feature/a", "feature/b", "main"}},
+		{"branch", SortByBranch, []string{"bugfix/x", "feature/a", "feature/b", "main"}},
+		{"activity newest first, unknown last", SortByActivity, []string{"feature/a", "feature/b", "main", "bugfix/x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(nil, &models.FinderConfig{SortBy: tt.sortBy})
+			f.SetActivityFunc(activityFunc)
+
+			sorted := f.sortWorktrees(worktrees)
+
+			for i, wt := range sorted {
+				if wt.Branch != tt.want[i] {
+					t.Fatalf("sortWorktrees() order = %v, want %v", branches(sorted), tt.want)
+				}
+			}
+			if worktrees[0].Branch != "main" || worktrees[2].Branch != "feature/a" {
+				t.Error("sortWorktrees() should not modify the input slice")
+			}
+		})
+	}
+}
+
+func TestCompareByActivityTieBreak(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := models.Worktree{Path: "/wt/a", Branch: "alpha"}
+	b := models.Worktree{Path: "/wt/b", Branch: "beta"}
+	activity := map[string]time.Time{"/wt/a": ts, "/wt/b": ts}
+
+	if !compareByActivity(a, b, activity) {
+		t.Error("equal activity should fall back to branch order")
+	}
+	if compareByActivity(b, a, activity) {
+		t.Error("compareByActivity should be asymmetric for distinct branches")
+	}
+}
+
+func branches(worktrees []models.Worktree) []string {
+	names := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		names[i] = wt.Branch
+	}
+	return names
+}
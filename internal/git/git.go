@@ -4,16 +4,26 @@ package git
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
+// ErrBranchNotMerged is returned by DeleteBranch when a non-forced delete is
+// refused because the branch has unmerged commits.
+var ErrBranchNotMerged = errors.New("branch has unmerged commits")
+
+// ErrBranchExists is returned by RenameBranch when the new name is already
+// taken by another branch.
+var ErrBranchExists = errors.New("branch already exists")
+
 // Git provides Git command operations.
 type Git struct {
 	workDir string
@@ -49,8 +59,8 @@ func (g *Git) ListWorktrees() ([]models.Worktree, error) {
 		if strings.HasPrefix(lines[i], "worktree ") {
 			path := strings.TrimPrefix(lines[i], "worktree ")
 
-			var branch, commitHash string
-			isMain := false
+			var branch, commitHash, lockReason string
+			isMain, locked := false, false
 
 			for j := i + 1; j < len(lines) && !strings.HasPrefix(lines[j], "worktree "); j++ {
 				if strings.HasPrefix(lines[j], "branch ") {
@@ -59,6 +69,9 @@ func (g *Git) ListWorktrees() ([]models.Worktree, error) {
 					branch = strings.TrimPrefix(branch, "refs/heads/")
 				} else if strings.HasPrefix(lines[j], "HEAD ") {
 					commitHash = strings.TrimPrefix(lines[j], "HEAD ")
+				} else if lines[j] == "locked" || strings.HasPrefix(lines[j], "locked ") {
+					locked = true
+					lockReason = strings.TrimPrefix(strings.TrimPrefix(lines[j], "locked"), " ")
 				} else if strings.HasPrefix(lines[j], "bare") {
 					continue
 				}
@@ -80,6 +93,8 @@ func (g *Git) ListWorktrees() ([]models.Worktree, error) {
 				Branch:     branch,
 				CommitHash: commitHash,
 				IsMain:     isMain,
+				Locked:     locked,
+				LockReason: lockReason,
 				CreatedAt:  createdAt,
 			})
 		}
@@ -100,6 +115,23 @@ func (g *Git) ListWorktrees() ([]models.Worktree, error) {
 	return worktrees, nil
 }
 
+// GetWorktreeLock reports whether the worktree at path is locked and the
+// reason given when it was locked, if any.
+func (g *Git) GetWorktreeLock(path string) (bool, string, error) {
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		return false, "", err
+	}
+
+	for _, wt := range worktrees {
+		if wt.Path == path {
+			return wt.Locked, wt.LockReason, nil
+		}
+	}
+
+	return false, "", fmt.Errorf("worktree not found: %s", path)
+}
+
 // AddWorktree creates a new worktree.
 func (g *Git) AddWorktree(path, branch string, createBranch bool) error {
 	args := []string{"worktree", "add"}
@@ -132,6 +164,15 @@ func (g *Git) AddWorktreeFromBase(path, branch, baseBranch string) error {
 	return nil
 }
 
+// AddWorktreeTracking creates a worktree on a new local branch that starts at
+// remoteRef and tracks it as its upstream.
+func (g *Git) AddWorktreeTracking(path, branch, remoteRef string) error {
+	if _, err := g.run("worktree", "add", "--track", "-b", branch, path, remoteRef); err != nil {
+		return fmt.Errorf("failed to add worktree tracking %s: %w", remoteRef, err)
+	}
+	return nil
+}
+
 // RemoveWorktree removes a worktree.
 func (g *Git) RemoveWorktree(path string, force bool) error {
 	args := []string{"worktree", "remove"}
@@ -157,24 +198,126 @@ func (g *Git) DeleteBranch(branch string, force bool) error {
 	}
 	args = append(args, branch)
 
-	if _, err := g.run(args...); err != nil {
+	if _, err := g.runStderr(args...); err != nil {
+		if !force && strings.Contains(err.Error(), "not fully merged") {
+			return fmt.Errorf("failed to delete branch %s: %w", branch, ErrBranchNotMerged)
+		}
 		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
 	}
 
 	return nil
 }
 
-// PruneWorktrees removes worktree information for deleted directories.
-func (g *Git) PruneWorktrees() error {
-	if _, err := g.run("worktree", "prune"); err != nil {
-		return fmt.Errorf("failed to prune worktrees: %w", err)
+// RenameBranch renames a local branch, including one checked out in a
+// worktree. It never overwrites an existing branch.
+func (g *Git) RenameBranch(oldName, newName string) error {
+	if _, err := g.runStderr("branch", "-m", oldName, newName); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return fmt.Errorf("failed to rename branch %s to %s: %w", oldName, newName, ErrBranchExists)
+		}
+		return fmt.Errorf("failed to rename branch %s to %s: %w", oldName, newName, err)
+	}
+
+	return nil
+}
+
+// MoveWorktree moves a worktree to newPath and updates git's record of it.
+func (g *Git) MoveWorktree(oldPath, newPath string) error {
+	if _, err := g.run("worktree", "move", oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to move worktree: %w", err)
 	}
+
 	return nil
 }
 
+// PruneWorktrees removes worktree information for deleted directories and
+// returns the paths of the worktrees that were pruned.
+func (g *Git) PruneWorktrees() ([]string, error) {
+	return g.pruneWorktrees("--verbose")
+}
+
+// PruneWorktreesDryRun returns the paths of the worktrees that PruneWorktrees
+// would prune, without removing anything.
+func (g *Git) PruneWorktreesDryRun() ([]string, error) {
+	return g.pruneWorktrees("--dry-run")
+}
+
+// pruneWorktrees runs git worktree prune with the given flag and resolves
+// the administrative directories it reports to worktree paths.
+func (g *Git) pruneWorktrees(flag string) ([]string, error) {
+	// Read the recorded paths first since a real prune deletes them
+	adminPaths, err := g.worktreeAdminPaths()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+
+	report, err := g.runStderr("worktree", "prune", flag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+
+	var pruned []string
+	for _, adminDir := range parsePruneOutput(report) {
+		if path, ok := adminPaths[adminDir]; ok {
+			pruned = append(pruned, path)
+		} else {
+			pruned = append(pruned, adminDir)
+		}
+	}
+	return pruned, nil
+}
+
+// parsePruneOutput returns the administrative directories ("worktrees/<name>")
+// from the "Removing <dir>: <reason>" lines printed by git worktree prune.
+func parsePruneOutput(output string) []string {
+	var dirs []string
+	for _, line := range strings.Split(output, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "Removing ")
+		if !ok {
+			continue
+		}
+		if dir, _, found := strings.Cut(rest, ":"); found {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// worktreeAdminPaths maps each administrative directory of the repository
+// ("worktrees/<name>") to the worktree path recorded in its gitdir file.
+func (g *Git) worktreeAdminPaths() (map[string]string, error) {
+	output, err := g.run("rev-parse", "--git-common-dir")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git common directory: %w", err)
+	}
+	commonDir := strings.TrimSpace(output)
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(g.workDir, commonDir)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(commonDir, "worktrees"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read worktree administrative directory: %w", err)
+	}
+
+	paths := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(commonDir, "worktrees", entry.Name(), "gitdir"))
+		if err != nil {
+			continue // Missing gitdir files are reported by their directory name
+		}
+		// gitdir points at the .git file inside the worktree
+		paths["worktrees/"+entry.Name()] = filepath.Dir(strings.TrimSpace(string(data)))
+	}
+	return paths, nil
+}
+
 // ListBranches returns a list of all branches.
 func (g *Git) ListBranches(includeRemote bool) ([]models.Branch, error) {
-	args := []string{"branch", "-v", "--format=%(refname:short)|%(HEAD)|%(committerdate:iso)|%(objectname)|%(subject)|%(authorname)"}
+	args := []string{"branch", "-v", "--format=%(refname)|%(HEAD)|%(committerdate:iso)|%(objectname)|%(subject)|%(authorname)"}
 	if includeRemote {
 		args = append(args, "-a")
 	}
@@ -204,9 +347,16 @@ func (g *Git) ListBranches(includeRemote bool) ([]models.Branch, error) {
 		message := parts[4]
 		author := parts[5]
 
-		isRemote := strings.HasPrefix(name, "remotes/")
+		// Full ref names tell local and remote-tracking branches apart; the
+		// short form of refs/remotes/origin/x is just origin/x.
+		isRemote := strings.HasPrefix(name, "refs/remotes/")
 		if isRemote {
-			name = strings.TrimPrefix(name, "remotes/")
+			name = strings.TrimPrefix(name, "refs/remotes/")
+			if strings.HasSuffix(name, "/HEAD") {
+				continue
+			}
+		} else {
+			name = strings.TrimPrefix(name, "refs/heads/")
 		}
 
 		date, _ := time.Parse("2006-01-02 15:04:05 -0700", dateStr)
@@ -279,6 +429,258 @@ func (g *Git) GetRecentCommits(path string, limit int) ([]models.CommitInfo, err
 	return commits, nil
 }
 
+// GetCommitsSince returns the commits on branch that are not on base, newest
+// first, like git log base..branch. Commits base gained after the two
+// diverged are not included. A limit of 0 or less returns every commit.
+func (g *Git) GetCommitsSince(base, branch string, limit int) ([]models.CommitInfo, error) {
+	// Fields are separated by US (0x1f) so subjects may contain any text
+	args := []string{"log", "--pretty=format:%H%x1f%s%x1f%an%x1f%ai"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-%d", limit))
+	}
+	args = append(args, base+".."+branch, "--")
+
+	output, err := g.run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits of %s since %s: %w", branch, base, err)
+	}
+
+	var commits []models.CommitInfo
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 4 {
+			continue
+		}
+
+		date, _ := time.Parse("2006-01-02 15:04:05 -0700", parts[3])
+
+		commits = append(commits, models.CommitInfo{
+			Hash:    parts[0],
+			Message: parts[1],
+			Author:  parts[2],
+			Date:    date,
+		})
+	}
+
+	return commits, nil
+}
+
+// CountCommitsSince returns how many commits are on branch but not on base,
+// like git rev-list --count base..branch.
+func (g *Git) CountCommitsSince(base, branch string) (int, error) {
+	output, err := g.run("rev-list", "--count", base+".."+branch, "--")
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits of %s since %s: %w", branch, base, err)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected rev-list output: %q", output)
+	}
+	return count, nil
+}
+
+// GetDefaultBranch returns the branch work is usually based on: the branch
+// origin/HEAD points to (such as origin/main) when known, otherwise the
+// branch checked out in the main worktree.
+func (g *Git) GetDefaultBranch() (string, error) {
+	if output, err := g.run("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if ref := strings.TrimSpace(output); ref != "" {
+			return ref, nil
+		}
+	}
+
+	// git lists the main worktree first
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		return "", err
+	}
+	if len(worktrees) == 0 || worktrees[0].Branch == "" || worktrees[0].Branch == "HEAD" {
+		return "", fmt.Errorf("failed to determine the default branch")
+	}
+	return worktrees[0].Branch, nil
+}
+
+// GetAheadBehind returns how many commits the branch checked out at path is
+// ahead of and behind its upstream. It fails if the branch has no upstream.
+func (g *Git) GetAheadBehind(path string) (ahead, behind int, err error) {
+	oldWorkDir := g.workDir
+	g.workDir = path
+	defer func() { g.workDir = oldWorkDir }()
+
+	output, err := g.run("rev-list", "--left-right", "--count", "@{upstream}...HEAD")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count commits against upstream: %w", err)
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", output)
+	}
+	if behind, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse behind count: %w", err)
+	}
+	if ahead, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse ahead count: %w", err)
+	}
+
+	return ahead, behind, nil
+}
+
+// IsClean reports whether the working directory has no staged, unstaged or
+// untracked changes. Ignored files don't count, and untracked files count even
+// when status.showUntrackedFiles is off.
+func (g *Git) IsClean() (bool, error) {
+	return g.IsCleanWithContext(context.Background())
+}
+
+// IsCleanWithContext is like IsClean but stops git when ctx is cancelled or times out.
+func (g *Git) IsCleanWithContext(ctx context.Context) (bool, error) {
+	status, err := g.runWithContext(ctx, "status", "--porcelain", "--untracked-files=normal")
+	if err != nil {
+		return false, fmt.Errorf("failed to check for changes: %w", err)
+	}
+	return strings.TrimSpace(status) == "", nil
+}
+
+// IsWorktreeClean runs IsClean in the worktree at path.
+func (g *Git) IsWorktreeClean(path string) (bool, error) {
+	oldWorkDir := g.workDir
+	g.workDir = path
+	defer func() { g.workDir = oldWorkDir }()
+
+	return g.IsClean()
+}
+
+// Stash saves tracked and untracked changes in the working directory to a new
+// stash entry and returns the stash commit hash, which stays valid when other
+// entries are added. It returns an empty hash when there is nothing to stash.
+func (g *Git) Stash(message string) (string, error) {
+	clean, err := g.IsClean()
+	if err != nil {
+		return "", err
+	}
+	if clean {
+		return "", nil
+	}
+
+	if _, err := g.run("stash", "push", "--include-untracked", "-m", message); err != nil {
+		return "", fmt.Errorf("failed to stash changes: %w", err)
+	}
+
+	ref, err := g.run("rev-parse", "refs/stash")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve stash: %w", err)
+	}
+	return strings.TrimSpace(ref), nil
+}
+
+// StashPop applies the most recent stash entry and removes it from the stash.
+func (g *Git) StashPop() error {
+	return g.StashPopRef("")
+}
+
+// StashPopRef applies the stash entry with the given commit hash, as returned
+// by Stash, and removes it from the stash. An empty ref pops the latest entry.
+func (g *Git) StashPopRef(ref string) error {
+	args := []string{"stash", "pop"}
+	if ref != "" {
+		entry, err := g.stashEntry(ref)
+		if err != nil {
+			return err
+		}
+		args = append(args, entry)
+	}
+
+	if _, err := g.run(args...); err != nil {
+		return fmt.Errorf("failed to pop stash: %w", err)
+	}
+	return nil
+}
+
+// stashEntry returns the stash@{n} reference of the entry with commit hash ref.
+func (g *Git) stashEntry(ref string) (string, error) {
+	output, err := g.run("stash", "list", "--format=%H")
+	if err != nil {
+		return "", fmt.Errorf("failed to list stash entries: %w", err)
+	}
+
+	for i, hash := range strings.Fields(output) {
+		if strings.HasPrefix(hash, ref) {
+			return fmt.Sprintf("stash@{%d}", i), nil
+		}
+	}
+	return "", fmt.Errorf("stash entry %s not found", ref)
+}
+
+// StashWorktree runs Stash in the worktree at path.
+func (g *Git) StashWorktree(path, message string) (string, error) {
+	oldWorkDir := g.workDir
+	g.workDir = path
+	defer func() { g.workDir = oldWorkDir }()
+
+	return g.Stash(message)
+}
+
+// StashPopWorktree runs StashPopRef in the worktree at path. The stash is
+// shared by all worktrees of a repository, so path may differ from the
+// worktree the changes were stashed in.
+func (g *Git) StashPopWorktree(path, ref string) error {
+	oldWorkDir := g.workDir
+	g.workDir = path
+	defer func() { g.workDir = oldWorkDir }()
+
+	return g.StashPopRef(ref)
+}
+
+// Fetch downloads objects and refs from remote so remote-tracking branches,
+// and therefore ahead/behind counts, reflect the remote. An empty remote
+// fetches from the default remote of the current branch.
+func (g *Git) Fetch(remote string) error {
+	return g.FetchWithContext(context.Background(), remote)
+}
+
+// FetchWithContext is like Fetch but stops the fetch when ctx is cancelled or times out.
+func (g *Git) FetchWithContext(ctx context.Context, remote string) error {
+	args := []string{"fetch", "--quiet"}
+	if remote != "" {
+		args = append(args, remote)
+	}
+
+	if _, err := g.runWithContext(ctx, args...); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", remote, err)
+	}
+	return nil
+}
+
+// FetchRef fetches a single ref from remote into FETCH_HEAD. Each config
+// entry is a "key=value" git setting applied to this fetch only; settings are
+// passed through the environment so secrets stay out of the process list and
+// error messages.
+func (g *Git) FetchRef(remote, ref string, config ...string) error {
+	cmd := exec.Command("git", "fetch", "--quiet", remote, ref)
+	if g.workDir != "" {
+		cmd.Dir = g.workDir
+	}
+	if len(config) > 0 {
+		cmd.Env = append(os.Environ(), fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(config)))
+		for i, entry := range config {
+			key, value, _ := strings.Cut(entry, "=")
+			cmd.Env = append(cmd.Env,
+				fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, key),
+				fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, value),
+			)
+		}
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %s", ref, remote, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
 // getCurrentBranch returns the current branch name for a specific worktree.
 func (g *Git) getCurrentBranch(worktreePath string) string {
 	oldWorkDir := g.workDir
@@ -355,6 +757,25 @@ func (g *Git) run(args ...string) (string, error) {
 	return stdout.String(), nil
 }
 
+// runStderr executes a git command and returns its stderr, where git reports
+// progress for commands such as worktree prune.
+func (g *Git) runStderr(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	if g.workDir != "" {
+		cmd.Dir = g.workDir
+	}
+	cmd.Env = append(os.Environ(), "LC_ALL=C") // Messages are parsed, so keep them untranslated
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), stderr.String())
+	}
+
+	return stderr.String(), nil
+}
+
 // runWithContext executes a git command with context support.
 func (g *Git) runWithContext(ctx context.Context, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", args...)
@@ -1,114 +1,21 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/d-kuro/gwq/internal/git/gittest"
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
-// TestRepository creates a test git repository
-type TestRepository struct {
-	Path string
-}
-
-// NewTestRepository creates a new test repository
-func NewTestRepository(t *testing.T) *TestRepository {
-	t.Helper()
-
-	tmpDir := t.TempDir()
-	repo := &TestRepository{Path: tmpDir}
-
-	// Set environment variables for git if needed in CI
-	t.Setenv("GIT_AUTHOR_NAME", "Test User")
-	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
-	t.Setenv("GIT_COMMITTER_NAME", "Test User")
-	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
-
-	// Initialize repository with main as default branch
-	if err := repo.run("init", "-b", "main"); err != nil {
-		t.Fatalf("Failed to init repository: %v", err)
-	}
-
-	// Configure git user for commits
-	if err := repo.run("config", "user.name", "Test User"); err != nil {
-		t.Fatalf("Failed to set user.name: %v", err)
-	}
-	if err := repo.run("config", "user.email", "test@example.com"); err != nil {
-		t.Fatalf("Failed to set user.email: %v", err)
-	}
-
-	// Create initial commit
-	testFile := filepath.Join(tmpDir, "README.md")
-	if err := os.WriteFile(testFile, []byte("# Test Repository\n"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-	if err := repo.run("add", "."); err != nil {
-		t.Fatalf("Failed to add files: %v", err)
-	}
-	if err := repo.run("commit", "-m", "Initial commit"); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
-
-	return repo
-}
-
-// run executes a git command in the test repository
-func (r *TestRepository) run(args ...string) error {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = r.Path
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("git %s failed: %v\nOutput: %s", strings.Join(args, " "), err, output)
-	}
-	return nil
-}
-
-// CreateBranch creates a new branch in the test repository
-func (r *TestRepository) CreateBranch(t *testing.T, name string) {
-	t.Helper()
-	if err := r.run("checkout", "-b", name); err != nil {
-		t.Fatalf("Failed to create branch %s: %v", name, err)
-	}
-}
-
-// CreateWorktree creates a worktree in the test repository
-func (r *TestRepository) CreateWorktree(t *testing.T, path, branch string) {
-	t.Helper()
-	// First check if branch exists in current worktree, if so switch away
-	currentBranch, _ := r.getCurrentBranch()
-	if currentBranch == branch {
-		// Try to switch to main branch first
-		if err := r.run("checkout", "main"); err != nil {
-			// If main doesn't exist or we're already on it, create a temporary branch
-			if err := r.run("checkout", "-b", "temp-branch-"+branch); err != nil {
-				t.Fatalf("Failed to switch away from branch: %v", err)
-			}
-		}
-	}
-
-	if err := r.run("worktree", "add", path, branch); err != nil {
-		t.Fatalf("Failed to create worktree: %v", err)
-	}
-}
-
-func (r *TestRepository) getCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	cmd.Dir = r.Path
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(output)), nil
-}
-
 func TestNew(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	if g.workDir != repo.Path {
@@ -117,7 +24,7 @@ func TestNew(t *testing.T) {
 }
 
 func TestNewFromCwd(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 
 	// Change to test repository directory
 	origDir, err := os.Getwd()
@@ -145,7 +52,7 @@ func TestNewFromCwd(t *testing.T) {
 }
 
 func TestListWorktrees(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	// Create test branches and worktrees
@@ -154,7 +61,7 @@ func TestListWorktrees(t *testing.T) {
 	repo.CreateWorktree(t, worktree1Path, "feature/test1")
 
 	// Switch back to main branch
-	if err := repo.run("checkout", "main"); err != nil {
+	if err := repo.Run("checkout", "main"); err != nil {
 		t.Fatalf("Failed to checkout main: %v", err)
 	}
 
@@ -199,14 +106,70 @@ func TestListWorktrees(t *testing.T) {
 	}
 }
 
+func TestListWorktreesLocked(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	g := New(repo.Path)
+
+	repo.CreateBranch(t, "feature/locked")
+	lockedPath := filepath.Join(t.TempDir(), "locked")
+	repo.CreateWorktree(t, lockedPath, "feature/locked")
+	if err := repo.Run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	repo.CreateBranch(t, "feature/bare-lock")
+	bareLockPath := filepath.Join(t.TempDir(), "bare-lock")
+	repo.CreateWorktree(t, bareLockPath, "feature/bare-lock")
+
+	if err := repo.Run("worktree", "lock", "--reason", "on a USB drive", lockedPath); err != nil {
+		t.Fatalf("Failed to lock worktree: %v", err)
+	}
+	if err := repo.Run("worktree", "lock", bareLockPath); err != nil {
+		t.Fatalf("Failed to lock worktree: %v", err)
+	}
+
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+
+	byBranch := make(map[string]models.Worktree)
+	for _, wt := range worktrees {
+		byBranch[wt.Branch] = wt
+	}
+
+	if wt := byBranch["main"]; wt.Locked {
+		t.Error("main worktree should not be locked")
+	}
+	locked := byBranch["feature/locked"]
+	if !locked.Locked || locked.LockReason != "on a USB drive" {
+		t.Errorf("feature/locked: Locked = %v, LockReason = %q; want true, %q", locked.Locked, locked.LockReason, "on a USB drive")
+	}
+	bareLock := byBranch["feature/bare-lock"]
+	if !bareLock.Locked || bareLock.LockReason != "" {
+		t.Errorf("feature/bare-lock: Locked = %v, LockReason = %q; want true, empty", bareLock.Locked, bareLock.LockReason)
+	}
+
+	isLocked, reason, err := g.GetWorktreeLock(locked.Path)
+	if err != nil {
+		t.Fatalf("GetWorktreeLock() error = %v", err)
+	}
+	if !isLocked || reason != "on a USB drive" {
+		t.Errorf("GetWorktreeLock() = %v, %q; want true, %q", isLocked, reason, "on a USB drive")
+	}
+
+	if _, _, err := g.GetWorktreeLock(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("GetWorktreeLock() should fail for an unknown worktree")
+	}
+}
+
 func TestAddWorktree(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	t.Run("ExistingBranch", func(t *testing.T) {
 		// Create a branch first
 		repo.CreateBranch(t, "existing-branch")
-		if err := repo.run("checkout", "main"); err != nil {
+		if err := repo.Run("checkout", "main"); err != nil {
 			t.Fatalf("Failed to checkout main: %v", err)
 		}
 
@@ -263,7 +226,7 @@ func TestAddWorktree(t *testing.T) {
 }
 
 func TestRemoveWorktree(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	// Create a worktree to remove
@@ -286,8 +249,74 @@ func TestRemoveWorktree(t *testing.T) {
 	}
 }
 
+func TestDeleteBranchNotMerged(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	g := New(repo.Path)
+
+	repo.CreateBranch(t, "unmerged")
+	if err := os.WriteFile(filepath.Join(repo.Path, "unmerged.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := repo.Run("add", "unmerged.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Run("commit", "-m", "Unmerged work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Run("checkout", "-"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := g.DeleteBranch("unmerged", false)
+	if !errors.Is(err, ErrBranchNotMerged) {
+		t.Fatalf("DeleteBranch() error = %v, want ErrBranchNotMerged", err)
+	}
+
+	if err := g.DeleteBranch("unmerged", true); err != nil {
+		t.Fatalf("DeleteBranch(force) error = %v", err)
+	}
+}
+
+func TestRenameBranchAndMoveWorktree(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	g := New(repo.Path)
+
+	repo.CreateBranch(t, "old-name")
+	repo.CreateBranch(t, "taken")
+	oldPath := filepath.Join(t.TempDir(), "old-name")
+	repo.CreateWorktree(t, oldPath, "old-name")
+
+	if err := g.RenameBranch("old-name", "taken"); !errors.Is(err, ErrBranchExists) {
+		t.Fatalf("RenameBranch() to an existing branch error = %v, want ErrBranchExists", err)
+	}
+
+	if err := g.RenameBranch("old-name", "new-name"); err != nil {
+		t.Fatalf("RenameBranch() error = %v", err)
+	}
+	newPath := filepath.Join(t.TempDir(), "new-name")
+	if err := g.MoveWorktree(oldPath, newPath); err != nil {
+		t.Fatalf("MoveWorktree() error = %v", err)
+	}
+
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	for _, wt := range worktrees {
+		if sameWorktreePath(t, wt.Path, oldPath) {
+			t.Errorf("old worktree path still listed: %+v", wt)
+		}
+		if sameWorktreePath(t, wt.Path, newPath) && wt.Branch != "new-name" {
+			t.Errorf("moved worktree branch = %q, want new-name", wt.Branch)
+		}
+	}
+	if !worktreeListed(t, g, newPath) {
+		t.Errorf("moved worktree %s not listed", newPath)
+	}
+}
+
 func TestPruneWorktrees(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	// Create a worktree
@@ -300,23 +329,86 @@ func TestPruneWorktrees(t *testing.T) {
 		t.Fatalf("Failed to remove worktree directory: %v", err)
 	}
 
+	// A dry run reports the worktree without pruning it
+	wouldPrune, err := g.PruneWorktreesDryRun()
+	if err != nil {
+		t.Fatalf("PruneWorktreesDryRun() error = %v", err)
+	}
+	if len(wouldPrune) != 1 || !sameWorktreePath(t, wouldPrune[0], worktreePath) {
+		t.Errorf("PruneWorktreesDryRun() = %v, want [%s]", wouldPrune, worktreePath)
+	}
+	if !worktreeListed(t, g, worktreePath) {
+		t.Fatal("Dry run pruned the worktree")
+	}
+
 	// Prune worktrees
-	err := g.PruneWorktrees()
+	pruned, err := g.PruneWorktrees()
 	if err != nil {
 		t.Fatalf("PruneWorktrees() error = %v", err)
 	}
+	if len(pruned) != 1 || !sameWorktreePath(t, pruned[0], worktreePath) {
+		t.Errorf("PruneWorktrees() = %v, want [%s]", pruned, worktreePath)
+	}
 
 	// Verify worktree is pruned
-	worktrees, _ := g.ListWorktrees()
+	if worktreeListed(t, g, worktreePath) {
+		t.Error("Deleted worktree still exists after prune")
+	}
+
+	// Nothing is left to prune
+	if again, err := g.PruneWorktreesDryRun(); err != nil || len(again) != 0 {
+		t.Errorf("PruneWorktreesDryRun() after prune = %v, %v; want none", again, err)
+	}
+}
+
+// worktreeListed reports whether git still lists a worktree at path
+func worktreeListed(t *testing.T, g *Git, path string) bool {
+	t.Helper()
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
 	for _, wt := range worktrees {
-		if wt.Path == worktreePath {
-			t.Error("Deleted worktree still exists after prune")
+		if sameWorktreePath(t, wt.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameWorktreePath compares paths after resolving symlinks in their parent
+// directories, since the temp dir may be reached through a symlink
+func sameWorktreePath(t *testing.T, a, b string) bool {
+	t.Helper()
+	resolve := func(p string) string {
+		dir, err := filepath.EvalSymlinks(filepath.Dir(p))
+		if err != nil {
+			return filepath.Clean(p)
+		}
+		return filepath.Join(dir, filepath.Base(p))
+	}
+	return resolve(a) == resolve(b)
+}
+
+func TestParsePruneOutput(t *testing.T) {
+	output := "Removing worktrees/wt1: gitdir file points to non-existent location\n" +
+		"Removing worktrees/wt 2: gitdir file does not exist\n" +
+		"unrelated line\n"
+
+	got := parsePruneOutput(output)
+	want := []string{"worktrees/wt1", "worktrees/wt 2"}
+	if len(got) != len(want) {
+		t.Fatalf("parsePruneOutput() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parsePruneOutput()[%d] = %q, want %q", i, got[i], want[i])
 		}
 	}
 }
 
 func TestListBranches(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	// Create test branches
@@ -329,10 +421,10 @@ func TestListBranches(t *testing.T) {
 		if err := os.WriteFile(testFile, []byte(branch), 0644); err != nil {
 			t.Fatalf("Failed to create test file: %v", err)
 		}
-		if err := repo.run("add", "."); err != nil {
+		if err := repo.Run("add", "."); err != nil {
 			t.Fatalf("Failed to add files: %v", err)
 		}
-		if err := repo.run("commit", "-m", fmt.Sprintf("Commit for %s", branch)); err != nil {
+		if err := repo.Run("commit", "-m", fmt.Sprintf("Commit for %s", branch)); err != nil {
 			t.Fatalf("Failed to commit: %v", err)
 		}
 	}
@@ -381,7 +473,7 @@ func TestListBranches(t *testing.T) {
 }
 
 func TestGetRepositoryName(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	name, err := g.GetRepositoryName()
@@ -397,7 +489,7 @@ func TestGetRepositoryName(t *testing.T) {
 }
 
 func TestGetRecentCommits(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	// Create multiple commits
@@ -412,10 +504,10 @@ func TestGetRecentCommits(t *testing.T) {
 		if err := os.WriteFile(testFile, []byte(fmt.Sprintf("Content %d", i)), 0644); err != nil {
 			t.Fatalf("Failed to create test file: %v", err)
 		}
-		if err := repo.run("add", "."); err != nil {
+		if err := repo.Run("add", "."); err != nil {
 			t.Fatalf("Failed to add files: %v", err)
 		}
-		if err := repo.run("commit", "-m", expectedMessages[i]); err != nil {
+		if err := repo.Run("commit", "-m", expectedMessages[i]); err != nil {
 			t.Fatalf("Failed to commit: %v", err)
 		}
 
@@ -450,8 +542,368 @@ func TestGetRecentCommits(t *testing.T) {
 	}
 }
 
+func TestGetAheadBehind(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	g := New(repo.Path)
+
+	commit := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo.Path, name), []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		if err := repo.Run("add", "."); err != nil {
+			t.Fatalf("Failed to add files: %v", err)
+		}
+		if err := repo.Run("commit", "-m", name); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	if _, _, err := g.GetAheadBehind(repo.Path); err == nil {
+		t.Error("GetAheadBehind() should fail for a branch without upstream")
+	}
+
+	// feature tracks main, gains two commits while main gains one
+	if err := repo.Run("checkout", "-b", "feature", "--track", "main"); err != nil {
+		t.Fatalf("Failed to create tracking branch: %v", err)
+	}
+	commit("feature1.txt")
+	commit("feature2.txt")
+	if err := repo.Run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	commit("main1.txt")
+	if err := repo.Run("checkout", "feature"); err != nil {
+		t.Fatalf("Failed to checkout feature: %v", err)
+	}
+
+	ahead, behind, err := g.GetAheadBehind(repo.Path)
+	if err != nil {
+		t.Fatalf("GetAheadBehind() error = %v", err)
+	}
+	if ahead != 2 || behind != 1 {
+		t.Errorf("GetAheadBehind() = (%d, %d), want (2, 1)", ahead, behind)
+	}
+}
+
+func TestGetCommitsSince(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	g := New(repo.Path)
+
+	commit := func(name, message string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo.Path, name), []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		if err := repo.Run("add", "."); err != nil {
+			t.Fatalf("Failed to add files: %v", err)
+		}
+		if err := repo.Run("commit", "-m", message); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	// feature gains two commits, then main moves on so the two have diverged
+	repo.CreateBranch(t, "feature")
+	commit("parser.go", "Add parser | with a pipe")
+	commit("parser_test.go", "Add parser tests")
+	if err := repo.Run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	commit("main.go", "Unrelated change on main")
+
+	commits, err := g.GetCommitsSince("main", "feature", 0)
+	if err != nil {
+		t.Fatalf("GetCommitsSince() error = %v", err)
+	}
+	want := []string{"Add parser tests", "Add parser | with a pipe"}
+	if len(commits) != len(want) {
+		t.Fatalf("GetCommitsSince() returned %d commits, want %d: %+v", len(commits), len(want), commits)
+	}
+	for i, commit := range commits {
+		if commit.Message != want[i] {
+			t.Errorf("Commit[%d].Message = %q, want %q", i, commit.Message, want[i])
+		}
+		if commit.Author != "Test User" || commit.Hash == "" || commit.Date.IsZero() {
+			t.Errorf("Commit[%d] is incomplete: %+v", i, commit)
+		}
+	}
+
+	limited, err := g.GetCommitsSince("main", "feature", 1)
+	if err != nil {
+		t.Fatalf("GetCommitsSince() with limit error = %v", err)
+	}
+	if len(limited) != 1 || limited[0].Message != want[0] {
+		t.Errorf("GetCommitsSince() with limit 1 = %+v, want the newest commit", limited)
+	}
+
+	none, err := g.GetCommitsSince("feature", "feature", 0)
+	if err != nil || len(none) != 0 {
+		t.Errorf("GetCommitsSince() of a branch against itself = %+v, %v; want no commits", none, err)
+	}
+
+	if count, err := g.CountCommitsSince("main", "feature"); err != nil || count != len(want) {
+		t.Errorf("CountCommitsSince() = %d, %v; want %d", count, err, len(want))
+	}
+
+	if _, err := g.GetCommitsSince("main", "missing", 0); err == nil {
+		t.Error("GetCommitsSince() should fail for an unknown branch")
+	}
+}
+
+func TestGetDefaultBranch(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	g := New(repo.Path)
+
+	// Without origin the main worktree's branch is used
+	repo.CreateBranch(t, "feature")
+	if err := repo.Run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	repo.CreateWorktree(t, filepath.Join(t.TempDir(), "feature"), "feature")
+	if got, err := g.GetDefaultBranch(); err != nil || got != "main" {
+		t.Errorf("GetDefaultBranch() = %q, %v; want main", got, err)
+	}
+
+	// origin/HEAD takes precedence
+	if err := repo.Run("update-ref", "refs/remotes/origin/trunk", "HEAD"); err != nil {
+		t.Fatalf("Failed to create remote ref: %v", err)
+	}
+	if err := repo.Run("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk"); err != nil {
+		t.Fatalf("Failed to set origin/HEAD: %v", err)
+	}
+	if got, err := g.GetDefaultBranch(); err != nil || got != "origin/trunk" {
+		t.Errorf("GetDefaultBranch() = %q, %v; want origin/trunk", got, err)
+	}
+}
+
+func TestIsClean(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, dir string)
+		want  bool
+	}{
+		{
+			name:  "Clean",
+			setup: func(t *testing.T, dir string) {},
+			want:  true,
+		},
+		{
+			name: "Modified",
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed\n"), 0644); err != nil {
+					t.Fatalf("Failed to modify file: %v", err)
+				}
+			},
+			want: false,
+		},
+		{
+			name: "UntrackedOnly",
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644); err != nil {
+					t.Fatalf("Failed to create file: %v", err)
+				}
+			},
+			want: false,
+		},
+		{
+			name: "IgnoredOnly",
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, ".git", "info", "exclude"), []byte("*.log\n"), 0644); err != nil {
+					t.Fatalf("Failed to write exclude: %v", err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "debug.log"), []byte("log\n"), 0644); err != nil {
+					t.Fatalf("Failed to create file: %v", err)
+				}
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.NewTestRepository(t)
+			tt.setup(t, repo.Path)
+			g := New(repo.Path)
+
+			got, err := g.IsClean()
+			if err != nil {
+				t.Fatalf("IsClean() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsClean() = %v, want %v", got, tt.want)
+			}
+
+			// The worktree variant checks path, not the Git's own directory
+			got, err = New(t.TempDir()).IsWorktreeClean(repo.Path)
+			if err != nil {
+				t.Fatalf("IsWorktreeClean() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsWorktreeClean() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCleanWithContext(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	g := New(repo.Path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.IsCleanWithContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("IsCleanWithContext() with cancelled context error = %v, want context.Canceled", err)
+	}
+
+	if _, err := New(t.TempDir()).IsClean(); err == nil {
+		t.Error("IsClean() outside a repository should fail")
+	}
+}
+
+func TestStash(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	g := New(repo.Path)
+
+	ref, err := g.Stash("nothing")
+	if err != nil {
+		t.Fatalf("Stash() on a clean repository error = %v", err)
+	}
+	if ref != "" {
+		t.Errorf("Stash() on a clean repository = %q, want empty", ref)
+	}
+
+	readme := filepath.Join(repo.Path, "README.md")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(readme, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	write("first\n")
+	first, err := g.Stash("first")
+	if err != nil || first == "" {
+		t.Fatalf("Stash() = %q, %v; want a stash ref", first, err)
+	}
+	write("second\n")
+	if _, err := g.Stash("second"); err != nil {
+		t.Fatalf("Stash() error = %v", err)
+	}
+
+	// Popping by ref finds the older entry even though it is no longer stash@{0}
+	if err := g.StashPopRef(first); err != nil {
+		t.Fatalf("StashPopRef() error = %v", err)
+	}
+	if got, _ := os.ReadFile(readme); string(got) != "first\n" {
+		t.Errorf("README.md after StashPopRef() = %q, want %q", got, "first\n")
+	}
+
+	if err := repo.Run("checkout", "--", "README.md"); err != nil {
+		t.Fatalf("Failed to reset file: %v", err)
+	}
+	if err := g.StashPop(); err != nil {
+		t.Fatalf("StashPop() error = %v", err)
+	}
+	if got, _ := os.ReadFile(readme); string(got) != "second\n" {
+		t.Errorf("README.md after StashPop() = %q, want %q", got, "second\n")
+	}
+
+	if err := g.StashPopRef(first); err == nil {
+		t.Error("StashPopRef() should fail for a stash that was already popped")
+	}
+}
+
+func TestFetch(t *testing.T) {
+	remote := gittest.NewTestRepository(t)
+
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	if err := remote.Run("clone", "-q", remote.Path, clonePath); err != nil {
+		t.Fatalf("Failed to clone: %v", err)
+	}
+	g := New(clonePath)
+
+	// A new commit on the remote is invisible until fetched
+	if err := os.WriteFile(filepath.Join(remote.Path, "remote.txt"), []byte("remote"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := remote.Run("add", "."); err != nil {
+		t.Fatalf("Failed to add files: %v", err)
+	}
+	if err := remote.Run("commit", "-m", "remote commit"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	ahead, behind, err := g.GetAheadBehind(clonePath)
+	if err != nil {
+		t.Fatalf("GetAheadBehind() error = %v", err)
+	}
+	if ahead != 0 || behind != 0 {
+		t.Errorf("GetAheadBehind() before fetch = (%d, %d), want (0, 0)", ahead, behind)
+	}
+
+	if err := g.Fetch("origin"); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	ahead, behind, err = g.GetAheadBehind(clonePath)
+	if err != nil {
+		t.Fatalf("GetAheadBehind() error = %v", err)
+	}
+	if ahead != 0 || behind != 1 {
+		t.Errorf("GetAheadBehind() after fetch = (%d, %d), want (0, 1)", ahead, behind)
+	}
+
+	if err := g.Fetch("missing-remote"); err == nil {
+		t.Error("Fetch() should fail for an unknown remote")
+	}
+}
+
+func TestFetchRef(t *testing.T) {
+	remote := gittest.NewTestRepository(t)
+
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	if err := remote.Run("clone", "-q", remote.Path, clonePath); err != nil {
+		t.Fatalf("Failed to clone: %v", err)
+	}
+	g := New(clonePath)
+
+	// Publish a commit under a pull request ref, which clones don't fetch
+	if err := os.WriteFile(filepath.Join(remote.Path, "pr.txt"), []byte("pr"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := remote.Run("add", "."); err != nil {
+		t.Fatalf("Failed to add files: %v", err)
+	}
+	if err := remote.Run("commit", "-m", "pr commit"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if err := remote.Run("update-ref", "refs/pull/7/head", "HEAD"); err != nil {
+		t.Fatalf("Failed to create pull ref: %v", err)
+	}
+	want, err := New(remote.Path).RunCommand("rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+
+	if err := g.FetchRef("origin", "refs/pull/7/head", "http.extraheader=X-Test: 1"); err != nil {
+		t.Fatalf("FetchRef() error = %v", err)
+	}
+	got, err := g.RunCommand("rev-parse", "FETCH_HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve FETCH_HEAD: %v", err)
+	}
+	if got != want {
+		t.Errorf("FETCH_HEAD = %q, want %q", got, want)
+	}
+
+	if err := g.FetchRef("origin", "refs/pull/8/head"); err == nil {
+		t.Error("FetchRef() should fail for a missing ref")
+	}
+}
+
 func TestGetCurrentBranch(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	// Test on main branch
@@ -470,7 +922,7 @@ func TestGetCurrentBranch(t *testing.T) {
 }
 
 func TestGetRootDir(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 
 	// Create a subdirectory
 	subDir := filepath.Join(repo.Path, "subdir")
@@ -495,7 +947,7 @@ func TestGetRootDir(t *testing.T) {
 }
 
 func TestRunCommand(t *testing.T) {
-	repo := NewTestRepository(t)
+	repo := gittest.NewTestRepository(t)
 	g := New(repo.Path)
 
 	// Test successful command
@@ -527,3 +979,67 @@ func containsWorktreeWithPath(worktrees []models.Worktree, path string) bool {
 	}
 	return false
 }
+
+func TestListBranchesRemote(t *testing.T) {
+	remote := gittest.NewTestRepository(t)
+	remote.CreateBranch(t, "feature/login")
+	if err := remote.Run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	if err := remote.Run("clone", "-q", remote.Path, clonePath); err != nil {
+		t.Fatalf("Failed to clone: %v", err)
+	}
+	g := New(clonePath)
+
+	branches, err := g.ListBranches(true)
+	if err != nil {
+		t.Fatalf("ListBranches(true) error = %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, b := range branches {
+		got[b.Name] = b.IsRemote
+	}
+	if isRemote, ok := got["origin/feature/login"]; !ok || !isRemote {
+		t.Errorf("origin/feature/login missing or not remote in %+v", branches)
+	}
+	if _, ok := got["origin/HEAD"]; ok {
+		t.Errorf("origin/HEAD should not be listed: %+v", branches)
+	}
+	if _, ok := got["feature/login"]; ok {
+		t.Errorf("feature/login has no local branch but was listed: %+v", branches)
+	}
+}
+
+func TestAddWorktreeTracking(t *testing.T) {
+	remote := gittest.NewTestRepository(t)
+	remote.CreateBranch(t, "feature/login")
+	if err := remote.Run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	if err := remote.Run("clone", "-q", remote.Path, clonePath); err != nil {
+		t.Fatalf("Failed to clone: %v", err)
+	}
+	g := New(clonePath)
+
+	path := filepath.Join(t.TempDir(), "login")
+	if err := g.AddWorktreeTracking(path, "feature/login", "origin/feature/login"); err != nil {
+		t.Fatalf("AddWorktreeTracking() error = %v", err)
+	}
+
+	upstream, err := g.run("rev-parse", "--abbrev-ref", "feature/login@{upstream}")
+	if err != nil {
+		t.Fatalf("failed to read upstream: %v", err)
+	}
+	if strings.TrimSpace(upstream) != "origin/feature/login" {
+		t.Errorf("upstream = %q, want origin/feature/login", upstream)
+	}
+
+	if err := g.AddWorktreeTracking(filepath.Join(t.TempDir(), "again"), "feature/login", "origin/feature/login"); err == nil {
+		t.Error("AddWorktreeTracking() should fail when the local branch exists")
+	}
+}
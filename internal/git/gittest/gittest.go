@@ -0,0 +1,116 @@
+// Package gittest provides git repositories for tests in packages that work
+// with real repositories.
+package gittest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRepository creates a test git repository
+type TestRepository struct {
+	Path string
+}
+
+// NewTestRepository creates a new test repository with a README committed on
+// main
+func NewTestRepository(t *testing.T) *TestRepository {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	repo := &TestRepository{Path: tmpDir}
+
+	// Set environment variables for git if needed in CI
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	// Initialize repository with main as default branch
+	if err := repo.Run("init", "-b", "main"); err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+
+	// Configure git user for commits
+	if err := repo.Run("config", "user.name", "Test User"); err != nil {
+		t.Fatalf("Failed to set user.name: %v", err)
+	}
+	if err := repo.Run("config", "user.email", "test@example.com"); err != nil {
+		t.Fatalf("Failed to set user.email: %v", err)
+	}
+
+	// Create initial commit
+	testFile := filepath.Join(tmpDir, "README.md")
+	if err := os.WriteFile(testFile, []byte("# Test Repository\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := repo.Run("add", "."); err != nil {
+		t.Fatalf("Failed to add files: %v", err)
+	}
+	if err := repo.Run("commit", "-m", "Initial commit"); err != nil {
+		t.Fatalf("Failed to create initial commit: %v", err)
+	}
+
+	return repo
+}
+
+// Run executes a git command in the test repository
+func (r *TestRepository) Run(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s failed: %v\nOutput: %s", strings.Join(args, " "), err, output)
+	}
+	return nil
+}
+
+// Git executes a git command in the test repository, failing the test on error
+func (r *TestRepository) Git(t *testing.T, args ...string) {
+	t.Helper()
+	if err := r.Run(args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CreateBranch creates a new branch in the test repository
+func (r *TestRepository) CreateBranch(t *testing.T, name string) {
+	t.Helper()
+	if err := r.Run("checkout", "-b", name); err != nil {
+		t.Fatalf("Failed to create branch %s: %v", name, err)
+	}
+}
+
+// CreateWorktree creates a worktree in the test repository
+func (r *TestRepository) CreateWorktree(t *testing.T, path, branch string) {
+	t.Helper()
+	// First check if branch exists in current worktree, if so switch away
+	currentBranch, _ := r.getCurrentBranch()
+	if currentBranch == branch {
+		// Try to switch to main branch first
+		if err := r.Run("checkout", "main"); err != nil {
+			// If main doesn't exist or we're already on it, create a temporary branch
+			if err := r.Run("checkout", "-b", "temp-branch-"+branch); err != nil {
+				t.Fatalf("Failed to switch away from branch: %v", err)
+			}
+		}
+	}
+
+	if err := r.Run("worktree", "add", path, branch); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+}
+
+func (r *TestRepository) getCurrentBranch() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = r.Path
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
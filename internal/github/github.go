@@ -0,0 +1,62 @@
+// Package github provides the GitHub-specific pieces used to check out pull
+// requests. All fetching is done with git; the API is never called.
+package github
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/url"
+)
+
+// TokenEnv is the environment variable holding a GitHub token. It is only
+// needed to fetch pull requests of private repositories over HTTPS.
+const TokenEnv = "GITHUB_TOKEN"
+
+// ErrNotGitHub is returned by ParseRemote for remotes hosted elsewhere.
+var ErrNotGitHub = errors.New("remote is not hosted on GitHub")
+
+// githubHosts are the hosts that serve github.com repositories.
+var githubHosts = []string{"github.com", "www.github.com", "ssh.github.com"}
+
+// ParseRemote parses a git remote URL (HTTPS, SSH or scp-like) and returns
+// its repository information if it points at GitHub.
+func ParseRemote(remoteURL string) (*url.RepositoryInfo, error) {
+	info, err := url.ParseRepositoryURL(strings.TrimSpace(remoteURL))
+	if err != nil {
+		return nil, err
+	}
+
+	host := strings.ToLower(info.Host)
+	for _, githubHost := range githubHosts {
+		if host == githubHost {
+			return info, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotGitHub, remoteURL)
+}
+
+// PullRequestRef returns the ref GitHub publishes the head of a pull request under.
+func PullRequestRef(number int) string {
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
+// PullRequestBranch returns the local branch name used for a pull request.
+func PullRequestBranch(number int) string {
+	return fmt.Sprintf("pr/%d", number)
+}
+
+// AuthConfig returns "key=value" git config entries that authenticate HTTPS
+// fetches from GitHub with token, for git.FetchRef to pass through the
+// GIT_CONFIG_* environment variables. It returns nil when there is no token
+// or the remote does not use HTTPS, where git's own credentials apply.
+func AuthConfig(remoteURL, token string) []string {
+	if token == "" || !strings.HasPrefix(strings.ToLower(remoteURL), "https://") {
+		return nil
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	return []string{"http.https://github.com/.extraheader=AUTHORIZATION: basic " + credentials}
+}
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPullRequestRef(t *testing.T) {
+	if got := PullRequestRef(123); got != "refs/pull/123/head" {
+		t.Errorf("PullRequestRef(123) = %q, want refs/pull/123/head", got)
+	}
+	if got := PullRequestBranch(123); got != "pr/123" {
+		t.Errorf("PullRequestBranch(123) = %q, want pr/123", got)
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		name      string
+		remote    string
+		wantOwner string
+		wantRepo  string
+		wantErr   error
+	}{
+		{name: "HTTPS", remote: "https://github.com/d-kuro/gwq.git", wantOwner: "d-kuro", wantRepo: "gwq"},
+		{name: "HTTPSWithoutSuffix", remote: "https://github.com/d-kuro/gwq", wantOwner: "d-kuro", wantRepo: "gwq"},
+		{name: "HTTPSWithUser", remote: "https://user@github.com/d-kuro/gwq.git", wantOwner: "d-kuro", wantRepo: "gwq"},
+		{name: "SCPLike", remote: "git@github.com:d-kuro/gwq.git", wantOwner: "d-kuro", wantRepo: "gwq"},
+		{name: "SSH", remote: "ssh://git@github.com/d-kuro/gwq.git", wantOwner: "d-kuro", wantRepo: "gwq"},
+		{name: "UpperCaseHost", remote: "https://GitHub.com/d-kuro/gwq.git", wantOwner: "d-kuro", wantRepo: "gwq"},
+		{name: "TrailingNewline", remote: "git@github.com:d-kuro/gwq.git\n", wantOwner: "d-kuro", wantRepo: "gwq"},
+		{name: "GitLab", remote: "git@gitlab.com:group/project.git", wantErr: ErrNotGitHub},
+		{name: "Lookalike", remote: "https://github.com.example.org/d-kuro/gwq.git", wantErr: ErrNotGitHub},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseRemote(tt.remote)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseRemote(%q) error = %v, want %v", tt.remote, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRemote(%q) error = %v", tt.remote, err)
+			}
+			if info.Owner != tt.wantOwner || info.Repository != tt.wantRepo {
+				t.Errorf("ParseRemote(%q) = %s/%s, want %s/%s", tt.remote, info.Owner, info.Repository, tt.wantOwner, tt.wantRepo)
+			}
+		})
+	}
+}
+
+func TestAuthConfig(t *testing.T) {
+	if got := AuthConfig("https://github.com/d-kuro/gwq.git", ""); got != nil {
+		t.Errorf("AuthConfig() without token = %v, want nil", got)
+	}
+	if got := AuthConfig("git@github.com:d-kuro/gwq.git", "secret"); got != nil {
+		t.Errorf("AuthConfig() for SSH = %v, want nil", got)
+	}
+
+	got := AuthConfig("https://github.com/d-kuro/gwq.git", "secret")
+	if len(got) != 1 || !strings.HasPrefix(got[0], "http.https://github.com/.extraheader=AUTHORIZATION: basic ") {
+		t.Errorf("AuthConfig() = %v, want a basic auth extraheader", got)
+	}
+	if strings.Contains(got[0], "secret") {
+		t.Error("AuthConfig() should not contain the raw token")
+	}
+}
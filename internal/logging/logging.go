@@ -0,0 +1,82 @@
+// Package logging writes gwq's diagnostic messages to stderr, filtered by a
+// process-wide level, so that stdout only carries command output.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level controls which diagnostic messages are written.
+type Level int
+
+const (
+	// LevelQuiet suppresses warnings and debug messages.
+	LevelQuiet Level = iota
+	// LevelNormal writes warnings. This is the default.
+	LevelNormal
+	// LevelVerbose writes warnings and debug messages.
+	LevelVerbose
+)
+
+var (
+	mu     sync.Mutex
+	level  = LevelNormal
+	output io.Writer // nil writes to the current os.Stderr
+)
+
+// SetLevel sets the level of messages written from now on.
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+}
+
+// GetLevel returns the current level.
+func GetLevel() Level {
+	mu.Lock()
+	defer mu.Unlock()
+	return level
+}
+
+// SetOutput sets where messages are written and returns the previous
+// destination, so tests can restore it. A nil writer means os.Stderr.
+func SetOutput(w io.Writer) io.Writer {
+	mu.Lock()
+	defer mu.Unlock()
+	previous := output
+	output = w
+	return previous
+}
+
+// Warnf writes a "Warning: " message unless the level is LevelQuiet.
+func Warnf(format string, args ...any) {
+	logf(LevelNormal, "Warning: ", format, args...)
+}
+
+// Debugf writes a message only at LevelVerbose.
+func Debugf(format string, args ...any) {
+	logf(LevelVerbose, "", format, args...)
+}
+
+// logf writes a message, ending it with a newline, when the level is at least min.
+func logf(min Level, prefix, format string, args ...any) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if level < min {
+		return
+	}
+	msg := prefix + fmt.Sprintf(format, args...)
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	w := output
+	if w == nil {
+		w = os.Stderr
+	}
+	_, _ = io.WriteString(w, msg)
+}
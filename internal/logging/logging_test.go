@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{level: LevelQuiet, want: ""},
+		{level: LevelNormal, want: "Warning: disk full\n"},
+		{level: LevelVerbose, want: "Warning: disk full\ndetails 42\n"},
+	}
+
+	defer SetLevel(GetLevel())
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		previous := SetOutput(&buf)
+		SetLevel(tt.level)
+
+		Warnf("disk %s", "full")
+		Debugf("details %d\n", 42) // A trailing newline isn't doubled
+
+		SetOutput(previous)
+		if buf.String() != tt.want {
+			t.Errorf("level %d wrote %q, want %q", tt.level, buf.String(), tt.want)
+		}
+	}
+}
@@ -181,3 +181,12 @@ func (sm *SessionManager) AttachSessionDirect(session *Session) error {
 func (sm *SessionManager) HasSession(sessionName string) bool {
 	return sm.tmuxCmd.HasSession(sessionName)
 }
+
+// PipePane streams new output of a session's active pane into a shell command
+func (sm *SessionManager) PipePane(sessionName, shellCommand string) error {
+	if !sm.tmuxCmd.HasSession(sessionName) {
+		return fmt.Errorf("tmux session %s no longer exists", sessionName)
+	}
+
+	return sm.tmuxCmd.PipePane(sessionName, shellCommand)
+}
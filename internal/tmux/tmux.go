@@ -21,6 +21,7 @@ type TmuxInterface interface {
 	KillSession(sessionName string) error
 	AttachSession(sessionName string) error
 	HasSession(sessionName string) bool
+	PipePane(sessionName, shellCommand string) error
 }
 
 // SessionManagerInterface defines the contract for session management
@@ -169,6 +170,12 @@ func (t *TmuxCommand) HasSession(sessionName string) bool {
 	return err == nil
 }
 
+// PipePane streams new output of the session's active pane into shellCommand
+func (t *TmuxCommand) PipePane(sessionName, shellCommand string) error {
+	args := []string{"pipe-pane", "-o", "-t", sessionName, shellCommand}
+	return t.runCommand(args...)
+}
+
 func (t *TmuxCommand) runCommand(args ...string) error {
 	cmd := exec.Command(t.command, args...)
 	var stderr bytes.Buffer
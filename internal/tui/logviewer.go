@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbletea"
@@ -61,6 +63,10 @@ var (
 				Padding(0, 2).
 				MarginBottom(1)
 
+	// contentLineStyle renders a single content line; the margin below the
+	// section is a line of its own
+	contentLineStyle = sectionContentStyle.UnsetMarginBottom()
+
 	// Footer styles - unobtrusive
 	helpStyle = lipgloss.NewStyle().
 			Foreground(mutedColor).
@@ -75,25 +81,60 @@ var (
 			BorderForeground(mutedColor).
 			Padding(1, 0).
 			MarginTop(1)
+
+	// Search styles
+	searchMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#000000")).
+				Background(warningColor)
+
+	searchPromptStyle = lipgloss.NewStyle().
+				Foreground(primaryColor).
+				Bold(true)
 )
 
+// ansiEscape matches SGR escape sequences emitted by lipgloss
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
 // LogSection represents a structured section of the log
 type LogSection struct {
 	Title   string
 	Content string
 }
 
+// sectionLayout is a section's place in the scrollable view. Only the title
+// is rendered up front; content lines are styled as they scroll into view, so
+// huge logs are never rendered as a whole.
+type sectionLayout struct {
+	start int      // Index of the section's first line in the view
+	title []string // Rendered title lines, including its margins
+	lines []string // Unstyled content lines
+}
+
+// lineCount returns the number of view lines of the section: the title, the
+// content and the blank line after it
+func (s sectionLayout) lineCount() int {
+	return len(s.title) + len(s.lines) + 1
+}
+
 // LogViewerModel represents the TUI model for log viewing
 type LogViewerModel struct {
-	metadata     *claude.ExecutionMetadata
-	rawContent   string
-	sections     []LogSection
-	scrollY      int
-	maxScrollY   int
-	width        int
-	height       int
-	contentArea  int
-	renderedView string
+	metadata    *claude.ExecutionMetadata
+	rawContent  string
+	sections    []LogSection
+	layout      []sectionLayout
+	totalLines  int
+	scrollY     int
+	maxScrollY  int
+	width       int
+	height      int
+	contentArea int
+
+	// Search state
+	searching   bool   // Whether the search prompt is active
+	searchInput string // Query being typed
+	searchQuery string // Last submitted query
+	matchLines  []int  // Rendered line indices containing the query
+	matchIndex  int    // Index into matchLines of the current match
 }
 
 // NewLogViewerModel creates a new log viewer model
@@ -104,6 +145,7 @@ func NewLogViewerModel(metadata *claude.ExecutionMetadata, logContent string) Lo
 		scrollY:    0,
 	}
 	model.sections = parseLogContent(logContent)
+	model.buildLayout()
 	return model
 }
 
@@ -119,14 +161,27 @@ func (m LogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.contentArea = m.height - 8 // Account for header and footer
-		m.renderSections()
 		m.updateMaxScroll()
 
 	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearchInput(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
 			return m, tea.Quit
 
+		case "/":
+			m.searching = true
+			m.searchInput = ""
+
+		case "n":
+			m.jumpToMatch(m.matchIndex + 1)
+
+		case "N":
+			m.jumpToMatch(m.matchIndex - 1)
+
 		case "up", "k":
 			if m.scrollY > 0 {
 				m.scrollY--
@@ -160,17 +215,106 @@ func (m LogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateSearchInput handles key presses while the search prompt is open
+func (m LogViewerModel) updateSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.searching = false
+		m.searchInput = ""
+
+	case "enter":
+		m.searching = false
+		m.applySearch(m.searchInput)
+
+	case "backspace":
+		if runes := []rune(m.searchInput); len(runes) > 0 {
+			m.searchInput = string(runes[:len(runes)-1])
+		}
+
+	default:
+		m.searchInput += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// applySearch sets the query, which highlights matches as they are rendered,
+// and jumps to the first match at or below the current position
+func (m *LogViewerModel) applySearch(query string) {
+	m.searchQuery = query
+	m.matchLines = m.findMatches(query)
+	m.matchIndex = 0
+	for i, line := range m.matchLines {
+		if line >= m.scrollY {
+			m.matchIndex = i
+			break
+		}
+	}
+	m.jumpToMatch(m.matchIndex)
+}
+
+// jumpToMatch moves to the match at index i (wrapping) and centers it
+func (m *LogViewerModel) jumpToMatch(i int) {
+	if len(m.matchLines) == 0 {
+		return
+	}
+
+	m.matchIndex = (i%len(m.matchLines) + len(m.matchLines)) % len(m.matchLines)
+	m.scrollY = min(max(0, m.matchLines[m.matchIndex]-m.contentArea/2), m.maxScrollY)
+}
+
+// findMatches returns the view lines containing query
+func (m *LogViewerModel) findMatches(query string) []int {
+	var matches []int
+	for _, s := range m.layout {
+		for _, i := range findMatchLines(s.title, query) {
+			matches = append(matches, s.start+i)
+		}
+		for _, i := range findMatchLines(s.lines, query) {
+			matches = append(matches, s.start+len(s.title)+i)
+		}
+	}
+	return matches
+}
+
+// findMatchLines returns the indices of lines containing query, ignoring case
+// and terminal styling
+func findMatchLines(lines []string, query string) []int {
+	if query == "" {
+		return nil
+	}
+
+	lowerQuery := strings.ToLower(query)
+	var matches []int
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(ansiEscape.ReplaceAllString(line, "")), lowerQuery) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// highlightMatches wraps case-insensitive occurrences of query in the match style
+func highlightMatches(text, query string) string {
+	if query == "" {
+		return text
+	}
+
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	return re.ReplaceAllStringFunc(text, func(match string) string {
+		return searchMatchStyle.Render(match)
+	})
+}
+
 // View renders the TUI
 func (m LogViewerModel) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Loading..."
 	}
 
-	// Ensure we have rendered content
-	if m.renderedView == "" {
-		m.renderSections()
-	}
-
 	var sections []string
 
 	// Header with execution info
@@ -221,35 +365,60 @@ func (m LogViewerModel) renderHeader() string {
 }
 
 func (m LogViewerModel) renderContent() string {
-	if m.renderedView == "" {
+	if len(m.layout) == 0 {
 		return "No content to display"
 	}
 
-	lines := strings.Split(m.renderedView, "\n")
-
 	// Calculate visible lines
 	start := m.scrollY
-	end := start + m.contentArea
-	if end > len(lines) {
-		end = len(lines)
-	}
+	end := min(start+m.contentArea, m.totalLines)
 
 	var visibleLines []string
-	if start < len(lines) {
-		visibleLines = lines[start:end]
+	for i := start; i < end; i++ {
+		visibleLines = append(visibleLines, m.renderLine(i))
 	}
 
 	return strings.Join(visibleLines, "\n")
 }
 
+// renderLine renders line i of the view
+func (m LogViewerModel) renderLine(i int) string {
+	n := sort.Search(len(m.layout), func(n int) bool { return m.layout[n].start > i }) - 1
+	if n < 0 {
+		return ""
+	}
+	s := m.layout[n]
+
+	offset := i - s.start
+	if offset < len(s.title) {
+		return s.title[offset]
+	}
+	offset -= len(s.title)
+	if offset < len(s.lines) {
+		return contentLineStyle.Render(highlightMatches(s.lines[offset], m.searchQuery))
+	}
+	return "" // Margin below the section
+}
+
 func (m LogViewerModel) renderFooter() string {
-	totalLines := len(strings.Split(m.renderedView, "\n"))
+	totalLines := max(1, m.totalLines)
 	currentEnd := min(m.scrollY+m.contentArea, totalLines)
 
 	scrollInfo := scrollInfoStyle.Render(fmt.Sprintf("Line %d-%d of %d",
 		m.scrollY+1, currentEnd, totalLines))
 
-	help := helpStyle.Render("↑/k: up • ↓/j: down • PgUp/PgDn: page • Home/End: start/end • q/Esc: quit")
+	if m.searchQuery != "" {
+		if len(m.matchLines) == 0 {
+			scrollInfo += helpStyle.Render(fmt.Sprintf("  No matches for %q", m.searchQuery))
+		} else {
+			scrollInfo += scrollInfoStyle.Render(fmt.Sprintf("  Match %d/%d", m.matchIndex+1, len(m.matchLines)))
+		}
+	}
+
+	help := helpStyle.Render("↑/k: up • ↓/j: down • PgUp/PgDn: page • Home/End: start/end • /: search • n/N: next/prev • q/Esc: quit")
+	if m.searching {
+		help = searchPromptStyle.Render("/"+m.searchInput) + helpStyle.Render("  Enter: search • Esc: cancel")
+	}
 
 	footerContent := lipgloss.JoinHorizontal(lipgloss.Left,
 		scrollInfo,
@@ -265,6 +434,9 @@ func (m LogViewerModel) getStyledStatus() string {
 	}
 
 	status := string(m.metadata.Status)
+	if m.metadata.ExitCode != 0 {
+		status += fmt.Sprintf(" (exit %d)", m.metadata.ExitCode)
+	}
 	icon := m.getStatusIcon()
 
 	switch m.metadata.Status {
@@ -308,34 +480,33 @@ func (m LogViewerModel) getDurationString() string {
 }
 
 func (m *LogViewerModel) updateMaxScroll() {
-	totalLines := len(strings.Split(m.renderedView, "\n"))
-	m.maxScrollY = max(0, totalLines-m.contentArea)
+	m.maxScrollY = max(0, max(1, m.totalLines)-m.contentArea)
 }
 
-func (m *LogViewerModel) renderSections() {
-	if m.width == 0 {
-		return
-	}
-
-	var renderedSections []string
+// buildLayout computes where each non-empty section starts in the view
+func (m *LogViewerModel) buildLayout() {
+	m.layout = nil
+	m.totalLines = 0
 
 	for _, section := range m.sections {
 		if section.Content == "" {
 			continue
 		}
 
-		// Simple title with consistent styling
-		title := sectionTitleStyle.Render(section.Title)
-
-		// Clean content without heavy styling
-		content := sectionContentStyle.Render(section.Content)
+		// Expand tabs as lipgloss does, so searches match what is shown
+		content := section.Content
+		if strings.Contains(content, "\t") {
+			content = strings.ReplaceAll(content, "\t", "    ")
+		}
 
-		// Combine with natural spacing
-		sectionText := lipgloss.JoinVertical(lipgloss.Left, title, content)
-		renderedSections = append(renderedSections, sectionText)
+		s := sectionLayout{
+			start: m.totalLines,
+			title: strings.Split(sectionTitleStyle.Render(section.Title), "\n"),
+			lines: strings.Split(content, "\n"),
+		}
+		m.layout = append(m.layout, s)
+		m.totalLines += s.lineCount()
 	}
-
-	m.renderedView = strings.Join(renderedSections, "\n")
 }
 
 // parseLogContent parses the log content into structured sections
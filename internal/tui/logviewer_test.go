@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFindMatchLines(t *testing.T) {
+	lines := []string{
+		"💬 Prompt",
+		"Fix the Authentication bug",
+		"",
+		"\x1b[1mUsing Bash\x1b[0m",
+		"go test ./auth/... # authentication",
+		"\x1b[33mauth\x1b[0mentication split by styling",
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{"case insensitive", "authentication", []int{1, 4, 5}},
+		{"ignores styling", "using bash", []int{3}},
+		{"no matches", "deploy", nil},
+		{"empty query", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findMatchLines(lines, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findMatchLines(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJumpToMatchCentersAndWraps(t *testing.T) {
+	m := LogViewerModel{
+		contentArea: 10,
+		maxScrollY:  100,
+		matchLines:  []int{3, 40, 90},
+	}
+
+	m.jumpToMatch(1)
+	if m.scrollY != 35 {
+		t.Errorf("scrollY = %d, want 35 (match centered)", m.scrollY)
+	}
+
+	m.jumpToMatch(0)
+	if m.scrollY != 0 {
+		t.Errorf("scrollY = %d, want 0 (clamped at top)", m.scrollY)
+	}
+
+	m.jumpToMatch(-1)
+	if m.matchIndex != 2 || m.scrollY != 85 {
+		t.Errorf("matchIndex = %d, scrollY = %d, want wrap to last match at 85", m.matchIndex, m.scrollY)
+	}
+
+	m.jumpToMatch(3)
+	if m.matchIndex != 0 {
+		t.Errorf("matchIndex = %d, want wrap to first match", m.matchIndex)
+	}
+}
+
+// syntheticLog returns a formatted log whose operation flow has steps steps
+func syntheticLog(steps int) string {
+	var b strings.Builder
+	b.WriteString("💬 Prompt:\nFix the failing tests\n\n⚡ Operation Flow:\n")
+	for i := 1; i <= steps; i++ {
+		fmt.Fprintf(&b, "%d. 🔧 Using Bash\n   ➤ Command: go test ./pkg/%d/...\n   ✅ ok  \tpkg/%d\t0.%03ds\n", i, i, i, i%1000)
+	}
+	b.WriteString("\n🤖 Claude's Response:\nAll tests pass now.\n")
+	return b.String()
+}
+
+// renderFullView renders every section into one string, as the viewer did
+// before rendering lazily
+func renderFullView(sections []LogSection) string {
+	var rendered []string
+	for _, section := range sections {
+		if section.Content == "" {
+			continue
+		}
+		title := sectionTitleStyle.Render(section.Title)
+		content := sectionContentStyle.Render(section.Content)
+		rendered = append(rendered, lipgloss.JoinVertical(lipgloss.Left, title, content))
+	}
+	return strings.Join(rendered, "\n")
+}
+
+// plainLine strips styling and the padding lipgloss adds to even out line widths
+func plainLine(line string) string {
+	return strings.TrimRight(ansiEscape.ReplaceAllString(line, ""), " ")
+}
+
+func TestLogViewerLayoutMatchesFullRender(t *testing.T) {
+	m := NewLogViewerModel(nil, syntheticLog(20))
+
+	full := strings.Split(renderFullView(m.sections), "\n")
+	if m.totalLines != len(full) {
+		t.Fatalf("totalLines = %d, want %d as in a full render", m.totalLines, len(full))
+	}
+	for i, want := range full {
+		if got := plainLine(m.renderLine(i)); got != plainLine(want) {
+			t.Fatalf("line %d = %q, want %q", i, got, plainLine(want))
+		}
+	}
+}
+
+func TestLogViewerScrollToEnd(t *testing.T) {
+	var model tea.Model = NewLogViewerModel(nil, syntheticLog(5000))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnd})
+
+	m := model.(LogViewerModel)
+	if m.scrollY != m.maxScrollY || m.maxScrollY != m.totalLines-m.contentArea {
+		t.Fatalf("scrollY = %d, maxScrollY = %d, want both %d", m.scrollY, m.maxScrollY, m.totalLines-m.contentArea)
+	}
+
+	content := m.renderContent()
+	for _, want := range []string{"5000. 🔧 Using Bash", "pkg/5000", "All tests pass now."} {
+		if !strings.Contains(content, want) {
+			t.Errorf("end of view is missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "4990. 🔧") {
+		t.Errorf("end of view shows lines from before the last page:\n%s", content)
+	}
+	if lines := strings.Count(content, "\n") + 1; lines != m.contentArea {
+		t.Errorf("visible lines = %d, want %d", lines, m.contentArea)
+	}
+	if !strings.Contains(m.renderFooter(), fmt.Sprintf("of %d", m.totalLines)) {
+		t.Errorf("footer = %q, want the total line count %d", m.renderFooter(), m.totalLines)
+	}
+}
+
+func TestLogViewerSearchMatchesRenderedLines(t *testing.T) {
+	m := NewLogViewerModel(nil, syntheticLog(50))
+	m.contentArea = 10
+	m.updateMaxScroll()
+	m.applySearch("ok      pkg/42 ") // Tabs are shown, and searched, as spaces
+
+	full := strings.Split(renderFullView(m.sections), "\n")
+	want := findMatchLines(full, "ok      pkg/42 ")
+	if !reflect.DeepEqual(m.matchLines, want) || len(want) != 1 {
+		t.Fatalf("matchLines = %v, want %v", m.matchLines, want)
+	}
+	if !strings.Contains(m.renderContent(), "pkg/42") {
+		t.Errorf("view after search doesn't show the match:\n%s", m.renderContent())
+	}
+}
+
+// BenchmarkLogViewer opens a large log and jumps to its end. "full" renders
+// the whole view up front as the viewer used to; "lazy" is the current viewer.
+func BenchmarkLogViewer(b *testing.B) {
+	log := syntheticLog(50000)
+
+	b.Run("full", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m := NewLogViewerModel(nil, log)
+			lines := strings.Split(renderFullView(m.sections), "\n")
+			_ = strings.Join(lines[len(lines)-32:], "\n")
+		}
+	})
+
+	b.Run("lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var model tea.Model = NewLogViewerModel(nil, log)
+			model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnd})
+			_ = model.View()
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/table"
+)
+
+// Table holds tabular output before it is rendered.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+	// Data is the value the rows were built from, used by structured renderers.
+	Data any
+	// Empty is shown by the table renderer when there are no rows.
+	Empty string
+	// RecordHeaders and Records hold the unformatted values behind Headers
+	// and Rows, without markers or padding. Delimited renderers write them
+	// in place of Headers and Rows when set.
+	RecordHeaders []string
+	Records       [][]string
+}
+
+// Renderer writes a Table to w in a specific output format.
+type Renderer interface {
+	Render(w io.Writer, t Table) error
+}
+
+// TableRenderer renders human-readable tables. It is the default renderer.
+type TableRenderer struct{}
+
+// Render implements Renderer.
+func (TableRenderer) Render(w io.Writer, t Table) error {
+	if len(t.Rows) == 0 && t.Empty != "" {
+		_, err := fmt.Fprintln(w, t.Empty)
+		return err
+	}
+	return table.New().SetOutput(w).Headers(t.Headers...).Rows(t.Rows).Println()
+}
+
+// JSONRenderer renders the table's Data as indented JSON, falling back to
+// one object per row keyed by header when Data is nil.
+type JSONRenderer struct{}
+
+// Render implements Renderer.
+func (JSONRenderer) Render(w io.Writer, t Table) error {
+	data := t.Data
+	if data == nil {
+		records := make([]map[string]string, 0, len(t.Rows))
+		for _, row := range t.Rows {
+			record := make(map[string]string, len(t.Headers))
+			for i, header := range t.Headers {
+				if i < len(row) {
+					record[header] = row[i]
+				}
+			}
+			records = append(records, record)
+		}
+		data = records
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// CSVRenderer renders the table's records as CSV, or as another delimited
+// format when Comma is set. Tables without records fall back to the headers
+// and rows, with cells trimmed, as the padding used to align markers in
+// tables is meaningless in CSV.
+type CSVRenderer struct {
+	// Comma is the field delimiter. Zero means ','.
+	Comma rune
+}
+
+// Render implements Renderer.
+func (r CSVRenderer) Render(w io.Writer, t Table) error {
+	writer := csv.NewWriter(w)
+	if r.Comma != 0 {
+		writer.Comma = r.Comma
+	}
+
+	headers, records := t.RecordHeaders, t.Records
+	if headers == nil {
+		headers = t.Headers
+		records = make([][]string, len(t.Rows))
+		for i, row := range t.Rows {
+			records[i] = make([]string, len(row))
+			for j, cell := range row {
+				records[i][j] = strings.TrimSpace(cell)
+			}
+		}
+	}
+
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
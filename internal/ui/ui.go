@@ -4,10 +4,11 @@ package ui
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"time"
 
-	"github.com/d-kuro/gwq/internal/table"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/d-kuro/gwq/pkg/utils"
 )
@@ -16,6 +17,17 @@ import (
 type Printer struct {
 	useIcons     bool
 	useTildeHome bool
+	output       io.Writer
+	renderer     Renderer
+}
+
+// PrinterOptions configures where and how a Printer writes its output.
+type PrinterOptions struct {
+	// Output receives all non-error output. Nil means os.Stdout at the time
+	// of writing.
+	Output io.Writer
+	// Renderer formats worktree and branch listings. Nil means TableRenderer.
+	Renderer Renderer
 }
 
 // UseIcons returns whether icon display is enabled.
@@ -30,87 +42,126 @@ func (p *Printer) UseTildeHome() bool {
 
 // New creates a new Printer instance.
 func New(config *models.UIConfig) *Printer {
+	return NewWithOptions(config, PrinterOptions{})
+}
+
+// NewWithOptions creates a new Printer that writes to a custom output or
+// renders listings in another format.
+func NewWithOptions(config *models.UIConfig, opts PrinterOptions) *Printer {
+	renderer := opts.Renderer
+	if renderer == nil {
+		renderer = TableRenderer{}
+	}
 	return &Printer{
 		useIcons:     config.Icons,
 		useTildeHome: config.TildeHome,
+		output:       opts.Output,
+		renderer:     renderer,
+	}
+}
+
+// out returns the writer for non-error output.
+func (p *Printer) out() io.Writer {
+	if p.output != nil {
+		return p.output
+	}
+	return os.Stdout
+}
+
+// render writes t with the configured renderer.
+func (p *Printer) render(t Table) {
+	renderer := p.renderer
+	if renderer == nil {
+		renderer = TableRenderer{}
+	}
+	if err := renderer.Render(p.out(), t); err != nil {
+		fmt.Fprintf(p.out(), "Error printing table: %v\n", err)
 	}
 }
 
 // PrintWorktrees displays worktrees in a formatted table.
 func (p *Printer) PrintWorktrees(worktrees []models.Worktree, verbose bool) {
-	if len(worktrees) == 0 {
-		fmt.Println("No worktrees found")
-		return
+	t := Table{Data: worktrees, Empty: "No worktrees found"}
+	if verbose {
+		t.Headers = []string{"BRANCH", "PATH", "COMMIT", "CREATED", "TYPE"}
+		t.RecordHeaders = []string{"BRANCH", "PATH", "COMMIT", "CREATED", "TYPE", "CURRENT", "LOCKED"}
+	} else {
+		t.Headers = []string{"BRANCH", "PATH"}
+		t.RecordHeaders = []string{"BRANCH", "PATH", "CURRENT", "LOCKED"}
 	}
 
-	var t *table.Builder
-	if verbose {
-		t = table.New().Headers("BRANCH", "PATH", "COMMIT", "CREATED", "TYPE")
-		for _, wt := range worktrees {
-			wtType := models.WorktreeTypeWorktree
-			if wt.IsMain {
-				wtType = models.WorktreeTypeMain
-			}
+	for _, wt := range worktrees {
+		branchWithMarker := p.formatWorktreeBranch(wt)
 
-			// Apply marker with consistent spacing
-			var branchWithMarker string
-			if wt.IsMain && p.useIcons {
-				branchWithMarker = "● " + wt.Branch
-			} else {
-				branchWithMarker = "  " + wt.Branch // Two spaces to match "● " width
-			}
+		path := wt.Path
+		if p.useTildeHome {
+			path = utils.TildePath(path)
+		}
 
-			path := wt.Path
-			if p.useTildeHome {
-				path = utils.TildePath(path)
-			}
-			t.Row(
-				branchWithMarker,
-				path,
-				p.truncateHash(wt.CommitHash),
-				p.formatTime(wt.CreatedAt),
-				wtType,
-			)
+		current := strconv.FormatBool(wt.IsMain)
+		locked := strconv.FormatBool(wt.Locked)
+		if !verbose {
+			t.Rows = append(t.Rows, []string{branchWithMarker, path})
+			t.Records = append(t.Records, []string{wt.Branch, wt.Path, current, locked})
+			continue
 		}
-	} else {
-		t = table.New().Headers("BRANCH", "PATH")
-		for _, wt := range worktrees {
-			// Apply marker with consistent spacing
-			var branchWithMarker string
-			if wt.IsMain && p.useIcons {
-				branchWithMarker = "● " + wt.Branch
-			} else {
-				branchWithMarker = "  " + wt.Branch // Two spaces to match "● " width
-			}
 
-			path := wt.Path
-			if p.useTildeHome {
-				path = utils.TildePath(path)
-			}
-			t.Row(branchWithMarker, path)
+		wtType := models.WorktreeTypeWorktree
+		if wt.IsMain {
+			wtType = models.WorktreeTypeMain
 		}
+		created := ""
+		if !wt.CreatedAt.IsZero() {
+			created = wt.CreatedAt.Format(time.RFC3339)
+		}
+		t.Records = append(t.Records, []string{wt.Branch, wt.Path, wt.CommitHash, created, wtType, current, locked})
+		t.Rows = append(t.Rows, []string{
+			branchWithMarker,
+			path,
+			p.truncateHash(wt.CommitHash),
+			p.formatTime(wt.CreatedAt),
+			wtType,
+		})
 	}
 
-	if err := t.Println(); err != nil {
-		fmt.Printf("Error printing table: %v\n", err)
+	p.render(t)
+}
+
+// formatWorktreeBranch returns the branch cell of a worktree row, marking
+// the main worktree and flagging locked worktrees.
+func (p *Printer) formatWorktreeBranch(wt models.Worktree) string {
+	// Apply marker with consistent spacing
+	var branch string
+	if wt.IsMain && p.useIcons {
+		branch = "● " + wt.Branch
+	} else {
+		branch = "  " + wt.Branch // Two spaces to match "● " width
+	}
+
+	if wt.Locked {
+		if p.useIcons {
+			branch += " 🔒"
+		} else {
+			branch += " (locked)"
+		}
 	}
+	return branch
 }
 
 // PrintWorktreesJSON displays worktrees in JSON format.
 func (p *Printer) PrintWorktreesJSON(worktrees []models.Worktree) error {
-	encoder := json.NewEncoder(os.Stdout)
+	encoder := json.NewEncoder(p.out())
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(worktrees)
 }
 
 // PrintBranches displays branches in a formatted table.
 func (p *Printer) PrintBranches(branches []models.Branch) {
-	if len(branches) == 0 {
-		fmt.Println("No branches found")
-		return
+	t := Table{
+		Headers: []string{"BRANCH", "LAST COMMIT", "AUTHOR", "DATE"},
+		Data:    branches,
+		Empty:   "No branches found",
 	}
-
-	t := table.New().Headers("BRANCH", "LAST COMMIT", "AUTHOR", "DATE")
 	for _, branch := range branches {
 		marker := ""
 		if p.useIcons {
@@ -123,17 +174,15 @@ func (p *Printer) PrintBranches(branches []models.Branch) {
 			}
 		}
 
-		t.Row(
-			marker+branch.Name,
+		t.Rows = append(t.Rows, []string{
+			marker + branch.Name,
 			p.truncateMessage(branch.LastCommit.Message, 50),
 			branch.LastCommit.Author,
 			p.formatTime(branch.LastCommit.Date),
-		)
+		})
 	}
 
-	if err := t.Println(); err != nil {
-		fmt.Printf("Error printing table: %v\n", err)
-	}
+	p.render(t)
 }
 
 // PrintConfig displays configuration in a formatted manner.
@@ -148,12 +197,12 @@ func (p *Printer) PrintError(err error) {
 
 // PrintSuccess displays a success message.
 func (p *Printer) PrintSuccess(message string) {
-	fmt.Println(message)
+	fmt.Fprintln(p.out(), message)
 }
 
 // PrintInfo displays an informational message.
 func (p *Printer) PrintInfo(message string) {
-	fmt.Println(message)
+	fmt.Fprintln(p.out(), message)
 }
 
 // PrintWorktreePath prints only the worktree path (for cd command).
@@ -161,7 +210,7 @@ func (p *Printer) PrintWorktreePath(path string) {
 	if p.useTildeHome {
 		path = utils.TildePath(path)
 	}
-	fmt.Println(path)
+	fmt.Fprintln(p.out(), path)
 }
 
 // truncateHash truncates a commit hash to 8 characters.
@@ -213,6 +262,6 @@ func (p *Printer) printConfigRecursive(prefix string, data any) {
 			p.printConfigRecursive(newPrefix, value)
 		}
 	default:
-		fmt.Printf("%s = %v\n", prefix, v)
+		fmt.Fprintf(p.out(), "%s = %v\n", prefix, v)
 	}
 }
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -139,6 +140,38 @@ func TestPrintWorktreesVerbose(t *testing.T) {
 	}
 }
 
+func TestPrintWorktreesLocked(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Path: "/path/to/main", Branch: "main", IsMain: true},
+		{Path: "/path/to/usb", Branch: "feature/usb", Locked: true, LockReason: "on a USB drive"},
+	}
+
+	tests := []struct {
+		name   string
+		icons  bool
+		marker string
+	}{
+		{name: "icons", icons: true, marker: "feature/usb 🔒"},
+		{name: "no icons", icons: false, marker: "feature/usb (locked)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := NewWithOptions(&models.UIConfig{Icons: tt.icons}, PrinterOptions{Output: &buf})
+			p.PrintWorktrees(worktrees, false)
+			output := buf.String()
+
+			if !strings.Contains(output, tt.marker) {
+				t.Errorf("Output should mark the locked worktree with %q, got:\n%s", tt.marker, output)
+			}
+			if strings.Count(output, "locked") > 1 || strings.Count(output, "🔒") > 1 {
+				t.Errorf("Only the locked worktree should be marked, got:\n%s", output)
+			}
+		})
+	}
+}
+
 func TestPrintWorktreesEmpty(t *testing.T) {
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -199,6 +232,68 @@ func TestPrintWorktreesJSON(t *testing.T) {
 	}
 }
 
+func TestPrintWorktreesCSV(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	worktrees := []models.Worktree{
+		{Path: "/path/to/main", Branch: "main", CommitHash: "abc123def456", IsMain: true, CreatedAt: created},
+		{Path: "/path/to/feature, one", Branch: "feature/csv", CommitHash: "def456abc789", CreatedAt: created, Locked: true},
+	}
+
+	var buf bytes.Buffer
+	p := NewWithOptions(&models.UIConfig{Icons: true}, PrinterOptions{Output: &buf, Renderer: CSVRenderer{}})
+	p.PrintWorktrees(worktrees, true)
+
+	// Cells hold the raw values, with the markers as separate columns
+	want := "BRANCH,PATH,COMMIT,CREATED,TYPE,CURRENT,LOCKED\n" +
+		"main,/path/to/main,abc123def456,2024-01-15T10:00:00Z,main,true,false\n" +
+		"feature/csv,\"/path/to/feature, one\",def456abc789,2024-01-15T10:00:00Z,worktree,false,true\n"
+	if got := buf.String(); got != want {
+		t.Errorf("CSV output = %q, want %q", got, want)
+	}
+}
+
+func TestPrintWorktreesEmptyRenderers(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer Renderer
+		want     string
+	}{
+		{name: "table", renderer: TableRenderer{}, want: "No worktrees found\n"},
+		{name: "csv", renderer: CSVRenderer{}, want: "BRANCH,PATH,CURRENT,LOCKED\n"},
+		{name: "json", renderer: JSONRenderer{}, want: "[]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := NewWithOptions(&models.UIConfig{}, PrinterOptions{Output: &buf, Renderer: tt.renderer})
+			p.PrintWorktrees([]models.Worktree{}, false)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONRendererRows(t *testing.T) {
+	var buf bytes.Buffer
+	err := JSONRenderer{}.Render(&buf, Table{
+		Headers: []string{"NAME", "VALUE"},
+		Rows:    [][]string{{"a", "1"}, {"b", "2"}},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var got []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshal JSON output: %v", err)
+	}
+	if len(got) != 2 || got[0]["NAME"] != "a" || got[1]["VALUE"] != "2" {
+		t.Errorf("JSON rows = %v", got)
+	}
+}
+
 func TestPrintBranches(t *testing.T) {
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -324,18 +419,10 @@ func TestPrintError(t *testing.T) {
 }
 
 func TestPrintSuccess(t *testing.T) {
-	// Capture stdout
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	p := New(&models.UIConfig{})
+	var buf bytes.Buffer
+	p := NewWithOptions(&models.UIConfig{}, PrinterOptions{Output: &buf})
 	p.PrintSuccess("Operation completed successfully")
-
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	output := string(out)
-	os.Stdout = oldStdout
+	output := buf.String()
 
 	expected := "Operation completed successfully\n"
 	if output != expected {
@@ -344,18 +431,10 @@ func TestPrintSuccess(t *testing.T) {
 }
 
 func TestPrintWorktreePath(t *testing.T) {
-	// Capture stdout
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	p := New(&models.UIConfig{})
+	var buf bytes.Buffer
+	p := NewWithOptions(&models.UIConfig{}, PrinterOptions{Output: &buf})
 	p.PrintWorktreePath("/path/to/worktree")
-
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	output := string(out)
-	os.Stdout = oldStdout
+	output := buf.String()
 
 	expected := "/path/to/worktree\n"
 	if output != expected {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/d-kuro/gwq/pkg/utils"
@@ -62,6 +63,74 @@ func GenerateWorktreePath(baseDir string, repoInfo *RepositoryInfo, branch strin
 	return filepath.Join(baseDir, repoInfo.FullPath, safeBranch)
 }
 
+// pathTemplatePlaceholders lists the placeholders supported in worktree path templates.
+var pathTemplatePlaceholders = []string{"host", "owner", "repo", "branch"}
+
+// ValidatePathTemplate checks that a worktree path template only uses known
+// placeholders and includes {branch}, so each branch gets its own directory.
+func ValidatePathTemplate(template string) error {
+	rest := template
+	hasBranch := false
+	for {
+		open := strings.IndexAny(rest, "{}")
+		if open == -1 {
+			break
+		}
+		if rest[open] == '}' {
+			return fmt.Errorf("unmatched '}' in path template %q", template)
+		}
+		end := strings.IndexAny(rest[open+1:], "{}")
+		if end == -1 || rest[open+1+end] != '}' {
+			return fmt.Errorf("unclosed placeholder in path template %q", template)
+		}
+		name := rest[open+1 : open+1+end]
+		if !slices.Contains(pathTemplatePlaceholders, name) {
+			return fmt.Errorf("unknown placeholder {%s} in path template %q (supported: {%s})", name, template, strings.Join(pathTemplatePlaceholders, "}, {"))
+		}
+		if name == "branch" {
+			hasBranch = true
+		}
+		rest = rest[open+end+2:]
+	}
+
+	if !hasBranch {
+		return fmt.Errorf("path template %q must contain {branch}", template)
+	}
+	return nil
+}
+
+// RenderPathTemplate creates a worktree path from a template such as
+// "{host}/{owner}/{repo}/{branch}". A template starting with "~" is expanded
+// like the base directory, and relative results are placed under baseDir.
+// Slashes in the branch name are sanitized so each branch maps to one directory.
+func RenderPathTemplate(baseDir, template string, repoInfo *RepositoryInfo, branch string) (string, error) {
+	if err := ValidatePathTemplate(template); err != nil {
+		return "", err
+	}
+
+	// Expanded before the placeholders are filled in, so branch names are
+	// used as they are
+	if strings.HasPrefix(template, "~") {
+		expanded, err := utils.ExpandPath(template)
+		if err != nil {
+			return "", err
+		}
+		template = expanded
+	}
+
+	replacer := strings.NewReplacer(
+		"{host}", repoInfo.Host,
+		"{owner}", repoInfo.Owner,
+		"{repo}", repoInfo.Repository,
+		"{branch}", sanitizeBranchName(branch),
+	)
+	path := filepath.FromSlash(replacer.Replace(template))
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+	return filepath.Join(baseDir, path), nil
+}
+
 // normalizeURL converts various git URL formats to a standard HTTP(S) format for parsing.
 func normalizeURL(repoURL string) string {
 	// Convert SSH format to HTTPS format for easier parsing
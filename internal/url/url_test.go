@@ -1,6 +1,7 @@
 package url
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -46,3 +47,93 @@ func TestNormalizeURL(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderPathTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	repoInfo, err := ParseRepositoryURL("git@github.com:user1/myapp.git")
+	if err != nil {
+		t.Fatalf("ParseRepositoryURL() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		branch   string
+		expected string
+	}{
+		{
+			name:     "default layout",
+			template: "{host}/{owner}/{repo}/{branch}",
+			branch:   "main",
+			expected: filepath.Join("/base", "github.com", "user1", "myapp", "main"),
+		},
+		{
+			name:     "group by owner",
+			template: "{owner}/{repo}-{branch}",
+			branch:   "feature/auth",
+			expected: filepath.Join("/base", "user1", "myapp-feature-auth"),
+		},
+		{
+			name:     "nested branch slashes are sanitized",
+			template: "{repo}/{branch}",
+			branch:   "user/feature/x",
+			expected: filepath.Join("/base", "myapp", "user-feature-x"),
+		},
+		{
+			name:     "absolute template ignores base dir",
+			template: "/srv/worktrees/{repo}/{branch}",
+			branch:   "fix",
+			expected: filepath.Join("/srv", "worktrees", "myapp", "fix"),
+		},
+		{
+			name:     "leading tilde expands to the home directory",
+			template: "~/worktrees/{repo}/{branch}",
+			branch:   "fix",
+			expected: filepath.Join(home, "worktrees", "myapp", "fix"),
+		},
+		{
+			name:     "branch names are not expanded",
+			template: "{repo}/{branch}",
+			branch:   "$HOME",
+			expected: filepath.Join("/base", "myapp", "$HOME"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderPathTemplate("/base", tt.template, repoInfo, tt.branch)
+			if err != nil {
+				t.Fatalf("RenderPathTemplate() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("RenderPathTemplate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidatePathTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  bool
+	}{
+		{template: "{host}/{owner}/{repo}/{branch}"},
+		{template: "{branch}"},
+		{template: "{repo}/{name}", wantErr: true},
+		{template: "{owner}/{repo}", wantErr: true},
+		{template: "{repo}/{branch", wantErr: true},
+		{template: "{repo}/branch}", wantErr: true},
+		{template: "{{branch}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			err := ValidatePathTemplate(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePathTemplate(%q) error = %v, wantErr %v", tt.template, err, tt.wantErr)
+			}
+		})
+	}
+}
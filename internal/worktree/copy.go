@@ -0,0 +1,120 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// CopyFiles copies files matching the glob patterns from the src directory
+// into dst, preserving relative paths. Patterns are matched relative to src
+// using filepath.Match syntax and must stay within src; a matching directory
+// is copied recursively.
+// Symlinks are recreated rather than followed and existing files in dst are
+// left untouched. It returns the relative paths that were copied.
+func CopyFiles(src, dst string, patterns []string) ([]string, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access copy source: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("copy source %s is not a directory", src)
+	}
+
+	seen := make(map[string]bool)
+	var copied []string
+
+	for _, pattern := range patterns {
+		if !filepath.IsLocal(pattern) {
+			return copied, fmt.Errorf("invalid copy pattern %q: must be a relative path within the repository", pattern)
+		}
+		matches, err := filepath.Glob(filepath.Join(src, pattern))
+		if err != nil {
+			return copied, fmt.Errorf("invalid copy pattern %q: %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			err := filepath.Walk(match, func(p string, fi os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				// Never copy git metadata into another worktree
+				if fi.IsDir() && fi.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				if fi.IsDir() {
+					return nil
+				}
+
+				rel, err := filepath.Rel(src, p)
+				if err != nil {
+					return err
+				}
+				if !filepath.IsLocal(rel) {
+					return fmt.Errorf("copy path %s is outside %s", p, src)
+				}
+				if seen[rel] {
+					return nil
+				}
+				seen[rel] = true
+
+				target := filepath.Join(dst, rel)
+				if _, err := os.Lstat(target); err == nil {
+					return nil // Keep files that already exist in the new worktree
+				}
+
+				if err := copyEntry(p, target, fi); err != nil {
+					return fmt.Errorf("failed to copy %s: %w", rel, err)
+				}
+				copied = append(copied, rel)
+				return nil
+			})
+			if err != nil {
+				return copied, err
+			}
+		}
+	}
+
+	sort.Strings(copied)
+	return copied, nil
+}
+
+// copyEntry copies a single regular file or symlink to target.
+func copyEntry(src, target string, info os.FileInfo) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, target)
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil // Skip sockets, devices and other special files
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,196 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// writeTestFile creates a file (and parent directories) under root
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", rel, err)
+	}
+}
+
+func TestCopyFiles(t *testing.T) {
+	src := t.TempDir()
+	writeTestFile(t, src, ".env", "SECRET=1")
+	writeTestFile(t, src, ".env.local", "LOCAL=1")
+	writeTestFile(t, src, "config/local.yml", "debug: true")
+	writeTestFile(t, src, "config/app.yml", "name: app")
+	writeTestFile(t, src, "README.md", "readme")
+	if err := os.Symlink("/opt/shared/node_modules", filepath.Join(src, "node_modules")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		existing map[string]string
+		want     []string
+	}{
+		{
+			name:     "glob",
+			patterns: []string{".env*"},
+			want:     []string{".env", ".env.local"},
+		},
+		{
+			name:     "nested path and directory",
+			patterns: []string{"config/local.yml", "config"},
+			want:     []string{"config/app.yml", "config/local.yml"},
+		},
+		{
+			name:     "symlink copied as link",
+			patterns: []string{"node_modules"},
+			want:     []string{"node_modules"},
+		},
+		{
+			name:     "skip existing",
+			patterns: []string{".env*"},
+			existing: map[string]string{".env": "KEEP=1"},
+			want:     []string{".env.local"},
+		},
+		{
+			name:     "no matches",
+			patterns: []string{"*.secret"},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := t.TempDir()
+			for rel, content := range tt.existing {
+				writeTestFile(t, dst, rel, content)
+			}
+
+			got, err := CopyFiles(src, dst, tt.patterns)
+			if err != nil {
+				t.Fatalf("CopyFiles() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CopyFiles() = %v, want %v", got, tt.want)
+			}
+
+			for rel, content := range tt.existing {
+				data, _ := os.ReadFile(filepath.Join(dst, rel))
+				if string(data) != content {
+					t.Errorf("existing file %s was overwritten: %q", rel, data)
+				}
+			}
+		})
+	}
+
+	// Symlinks are recreated, not followed
+	dst := t.TempDir()
+	if _, err := CopyFiles(src, dst, []string{"node_modules"}); err != nil {
+		t.Fatalf("CopyFiles() error = %v", err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "node_modules")); err != nil || link != "/opt/shared/node_modules" {
+		t.Errorf("node_modules link = %q, %v; want /opt/shared/node_modules", link, err)
+	}
+}
+
+func TestCopyFilesInvalidPattern(t *testing.T) {
+	if _, err := CopyFiles(t.TempDir(), t.TempDir(), []string{"[unterminated"}); err == nil {
+		t.Error("CopyFiles() should reject an invalid glob")
+	}
+}
+
+func TestCopyFilesRejectsEscapingPatterns(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "repo")
+	writeTestFile(t, src, ".env", "SECRET=1")
+	writeTestFile(t, root, "outside.txt", "private")
+
+	for _, pattern := range []string{"../*", "config/../../*", filepath.Join(root, "*")} {
+		dst := t.TempDir()
+		copied, err := CopyFiles(src, dst, []string{pattern})
+		if err == nil {
+			t.Errorf("CopyFiles(%q) should reject a pattern outside the source", pattern)
+		}
+		if len(copied) != 0 {
+			t.Errorf("CopyFiles(%q) copied %v", pattern, copied)
+		}
+		if _, err := os.Stat(filepath.Join(dst, "outside.txt")); err == nil {
+			t.Errorf("CopyFiles(%q) copied a file from outside the source", pattern)
+		}
+	}
+}
+
+func TestManagerAddWithCopyFrom(t *testing.T) {
+	src := t.TempDir()
+	writeTestFile(t, src, ".env", "SECRET=1")
+	writeTestFile(t, src, "notes.txt", "notes")
+
+	config := &models.Config{
+		Worktree: models.WorktreeConfig{
+			BaseDir:      t.TempDir(),
+			AutoMkdir:    true,
+			CopyPatterns: []string{".env"},
+		},
+	}
+
+	t.Run("config patterns", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "wt")
+		m := New(&mockGit{}, config)
+
+		copied, err := m.AddWithOptions("feature/copy", dst, true, AddOptions{CopyFrom: src})
+		if err != nil {
+			t.Fatalf("AddWithOptions() error = %v", err)
+		}
+		if !reflect.DeepEqual(copied, []string{".env"}) {
+			t.Errorf("copied = %v, want [.env]", copied)
+		}
+		if _, err := os.Stat(filepath.Join(dst, ".env")); err != nil {
+			t.Errorf(".env should exist in new worktree: %v", err)
+		}
+	})
+
+	t.Run("CLI override", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "wt")
+		m := New(&mockGit{}, config)
+
+		copied, err := m.AddWithOptions("feature/copy", dst, true, AddOptions{CopyFrom: src, CopyPatterns: []string{"*.txt"}})
+		if err != nil {
+			t.Fatalf("AddWithOptions() error = %v", err)
+		}
+		if !reflect.DeepEqual(copied, []string{"notes.txt"}) {
+			t.Errorf("copied = %v, want [notes.txt]", copied)
+		}
+	})
+
+	t.Run("no copy without source", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "wt")
+		m := New(&mockGit{}, config)
+
+		copied, err := m.AddWithOptions("feature/copy", dst, true, AddOptions{})
+		if err != nil || copied != nil {
+			t.Errorf("AddWithOptions() = %v, %v; want nil, nil", copied, err)
+		}
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		mockG := &mockGit{}
+		m := New(mockG, config)
+
+		_, err := m.AddWithOptions("feature/copy", filepath.Join(t.TempDir(), "wt"), true,
+			AddOptions{CopyFrom: filepath.Join(src, "missing")})
+		if !errors.Is(err, ErrCopyFailed) {
+			t.Errorf("AddWithOptions() error = %v, want ErrCopyFailed", err)
+		}
+		if len(mockG.worktrees) != 1 {
+			t.Error("worktree should still be created when copying fails")
+		}
+	})
+}
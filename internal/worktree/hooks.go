@@ -0,0 +1,63 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/d-kuro/gwq/internal/logging"
+)
+
+// HookRunner executes a post-create hook command.
+type HookRunner interface {
+	Run(dir string, env []string, command string) error
+}
+
+// shellHookRunner runs hooks through the system shell, streaming their output.
+type shellHookRunner struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// Run executes command in dir with env appended to the current environment.
+func (r *shellHookRunner) Run(dir string, env []string, command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = r.stdout
+	cmd.Stderr = r.stderr
+	return cmd.Run()
+}
+
+// SetHookRunner replaces the runner used for post-create hooks.
+func (m *Manager) SetHookRunner(r HookRunner) {
+	m.hooks = r
+}
+
+// runPostCreateHooks executes the configured hooks in order inside the new
+// worktree. Failures are returned when post_create_hooks_fatal is set and
+// reported as warnings otherwise.
+func (m *Manager) runPostCreateHooks(path, branch string) error {
+	env := []string{
+		"GWQ_WORKTREE_PATH=" + path,
+		"GWQ_BRANCH=" + branch,
+	}
+
+	for _, hook := range m.config.Worktree.PostCreateHooks {
+		if err := m.hooks.Run(path, env, hook); err != nil {
+			if m.config.Worktree.PostCreateHooksFatal {
+				return fmt.Errorf("post-create hook %q failed: %w", hook, err)
+			}
+			logging.Warnf("post-create hook %q failed: %v", hook, err)
+		}
+	}
+
+	return nil
+}
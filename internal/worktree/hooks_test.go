@@ -0,0 +1,132 @@
+package worktree
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+type hookCall struct {
+	dir     string
+	env     []string
+	command string
+}
+
+// fakeHookRunner records hook invocations and fails the configured commands
+type fakeHookRunner struct {
+	calls []hookCall
+	fail  map[string]error
+}
+
+func (f *fakeHookRunner) Run(dir string, env []string, command string) error {
+	f.calls = append(f.calls, hookCall{dir: dir, env: env, command: command})
+	return f.fail[command]
+}
+
+func TestManagerPostCreateHooks(t *testing.T) {
+	errHook := errors.New("exit status 1")
+
+	tests := []struct {
+		name         string
+		hooks        []string
+		fatal        bool
+		fail         map[string]error
+		fromBase     bool
+		wantCommands []string
+		wantErr      bool
+	}{
+		{
+			name:         "hooks run in order",
+			hooks:        []string{"direnv allow", "npm install"},
+			wantCommands: []string{"direnv allow", "npm install"},
+		},
+		{
+			name:         "hooks run for AddFromBase",
+			hooks:        []string{"npm install"},
+			fromBase:     true,
+			wantCommands: []string{"npm install"},
+		},
+		{
+			name:         "non-fatal failure continues",
+			hooks:        []string{"false", "npm install"},
+			fail:         map[string]error{"false": errHook},
+			wantCommands: []string{"false", "npm install"},
+		},
+		{
+			name:         "fatal failure stops",
+			hooks:        []string{"false", "npm install"},
+			fatal:        true,
+			fail:         map[string]error{"false": errHook},
+			wantCommands: []string{"false"},
+			wantErr:      true,
+		},
+		{
+			name:         "no hooks",
+			wantCommands: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{
+				Worktree: models.WorktreeConfig{
+					BaseDir:              t.TempDir(),
+					AutoMkdir:            true,
+					PostCreateHooks:      tt.hooks,
+					PostCreateHooksFatal: tt.fatal,
+				},
+			}
+			runner := &fakeHookRunner{fail: tt.fail}
+			m := New(&mockGit{}, config)
+			m.SetHookRunner(runner)
+
+			path := filepath.Join(t.TempDir(), "wt")
+			var err error
+			if tt.fromBase {
+				err = m.AddFromBase("feature/hooks", "main", path)
+			} else {
+				err = m.Add("feature/hooks", path, true)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var commands []string
+			for _, call := range runner.calls {
+				commands = append(commands, call.command)
+				if call.dir != path {
+					t.Errorf("hook %q ran in %s, want %s", call.command, call.dir, path)
+				}
+				wantEnv := []string{"GWQ_WORKTREE_PATH=" + path, "GWQ_BRANCH=feature/hooks"}
+				if !reflect.DeepEqual(call.env, wantEnv) {
+					t.Errorf("hook %q env = %v, want %v", call.command, call.env, wantEnv)
+				}
+			}
+			if !reflect.DeepEqual(commands, tt.wantCommands) {
+				t.Errorf("commands = %v, want %v", commands, tt.wantCommands)
+			}
+		})
+	}
+}
+
+func TestManagerAddGitFailureSkipsHooks(t *testing.T) {
+	config := &models.Config{
+		Worktree: models.WorktreeConfig{
+			BaseDir:         t.TempDir(),
+			PostCreateHooks: []string{"npm install"},
+		},
+	}
+	runner := &fakeHookRunner{}
+	m := New(&mockGit{addError: errors.New("branch exists")}, config)
+	m.SetHookRunner(runner)
+
+	if err := m.Add("feature/hooks", filepath.Join(t.TempDir(), "wt"), true); err == nil {
+		t.Fatal("Add() should fail")
+	}
+	if len(runner.calls) != 0 {
+		t.Errorf("hooks should not run when worktree creation fails, got %v", runner.calls)
+	}
+}
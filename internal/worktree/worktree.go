@@ -2,11 +2,13 @@
 package worktree
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/d-kuro/gwq/internal/git"
 	"github.com/d-kuro/gwq/internal/url"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/d-kuro/gwq/pkg/utils"
@@ -17,18 +19,27 @@ type GitInterface interface {
 	ListWorktrees() ([]models.Worktree, error)
 	AddWorktree(path, branch string, createBranch bool) error
 	AddWorktreeFromBase(path, branch, baseBranch string) error
+	AddWorktreeTracking(path, branch, remoteRef string) error
+	ListBranches(includeRemote bool) ([]models.Branch, error)
 	RemoveWorktree(path string, force bool) error
 	DeleteBranch(branch string, force bool) error
-	PruneWorktrees() error
+	RenameBranch(oldName, newName string) error
+	MoveWorktree(oldPath, newPath string) error
+	PruneWorktrees() ([]string, error)
+	PruneWorktreesDryRun() ([]string, error)
 	GetRepositoryName() (string, error)
 	GetRecentCommits(path string, limit int) ([]models.CommitInfo, error)
 	GetRepositoryURL() (string, error)
+	StashWorktree(path, message string) (string, error)
+	IsWorktreeClean(path string) (bool, error)
+	StashPopWorktree(path, ref string) error
 }
 
 // Manager handles worktree operations.
 type Manager struct {
 	git    GitInterface
 	config *models.Config
+	hooks  HookRunner
 }
 
 // New creates a new worktree Manager.
@@ -36,48 +47,136 @@ func New(g GitInterface, config *models.Config) *Manager {
 	return &Manager{
 		git:    g,
 		config: config,
+		hooks:  &shellHookRunner{stdout: os.Stdout, stderr: os.Stderr},
 	}
 }
 
+// ErrCopyFailed is returned by AddWithOptions when the worktree was created
+// but seeding files into it failed.
+var ErrCopyFailed = errors.New("worktree created but copying files failed")
+
+// ErrDirtyWorktree is returned when removing a worktree with uncommitted
+// changes without force.
+var ErrDirtyWorktree = errors.New("worktree has uncommitted changes")
+
+// ErrProtectedBranch is returned by RemoveWithBranch when asked to delete the
+// branch checked out in the main worktree.
+var ErrProtectedBranch = errors.New("branch is checked out in the main worktree")
+
+// ErrMainWorktree is returned by Move when asked to move the main worktree.
+var ErrMainWorktree = errors.New("the main worktree cannot be moved")
+
+// AddOptions holds optional settings for AddWithOptions.
+type AddOptions struct {
+	CopyFrom     string   // Worktree to seed untracked files from (empty disables copying)
+	CopyPatterns []string // Globs to copy; overrides WorktreeConfig.CopyPatterns when set
+}
+
 // Add creates a new worktree.
 func (m *Manager) Add(branch string, customPath string, createBranch bool) error {
-	path := customPath
-	if path == "" {
-		generatedPath, err := m.generateWorktreePath(branch)
+	_, err := m.AddWithOptions(branch, customPath, createBranch, AddOptions{})
+	return err
+}
+
+// AddWithOptions creates a new worktree and, if opts.CopyFrom is set, copies
+// files matching the copy patterns from that worktree into the new one.
+// Post-create hooks run afterwards so they can rely on the copied files.
+// It returns the paths (relative to the worktree root) that were copied.
+func (m *Manager) AddWithOptions(branch string, customPath string, createBranch bool, opts AddOptions) ([]string, error) {
+	var remoteRef string
+	if !createBranch {
+		local, ref, err := m.resolveRemoteBranch(branch)
 		if err != nil {
-			return fmt.Errorf("failed to generate worktree path: %w", err)
+			return nil, err
 		}
-		path = generatedPath
+		branch, remoteRef = local, ref
 	}
 
-	// Expand path (handles ~, env vars, and relative paths)
-	expandedPath, err := utils.ExpandPath(path)
+	path, err := m.prepareWorktreePath(branch, customPath)
 	if err != nil {
-		return fmt.Errorf("failed to expand path: %w", err)
+		return nil, err
 	}
-	path = expandedPath
 
-	if m.config.Worktree.AutoMkdir {
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
+	if remoteRef != "" {
+		err = m.git.AddWorktreeTracking(path, branch, remoteRef)
+	} else {
+		err = m.git.AddWorktree(path, branch, createBranch)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var copied []string
+	var copyErr error
+	if opts.CopyFrom != "" {
+		patterns := opts.CopyPatterns
+		if len(patterns) == 0 {
+			patterns = m.config.Worktree.CopyPatterns
+		}
+
+		copied, err = CopyFiles(opts.CopyFrom, path, patterns)
+		if err != nil {
+			copyErr = fmt.Errorf("%w from %s: %w", ErrCopyFailed, opts.CopyFrom, err)
 		}
 	}
 
-	if err := m.git.AddWorktree(path, branch, createBranch); err != nil {
-		return err
+	if err := m.runPostCreateHooks(path, branch); err != nil {
+		return copied, err
 	}
 
-	return nil
+	return copied, copyErr
 }
 
-// AddFromBase creates a new worktree with a branch from a specific base branch.
-func (m *Manager) AddFromBase(branch string, baseBranch string, customPath string) error {
+// resolveRemoteBranch checks whether branch names a remote-tracking branch
+// with no local counterpart, either in full (origin/feature) or by the name it
+// has on exactly one remote (feature). It returns the local branch name to
+// create and the remote ref it should track, or branch unchanged and an empty
+// ref when branch should be checked out as is.
+func (m *Manager) resolveRemoteBranch(branch string) (string, string, error) {
+	branches, err := m.git.ListBranches(true)
+	if err != nil {
+		return "", "", err
+	}
+
+	locals := make(map[string]bool)
+	var remotes []string
+	for _, b := range branches {
+		if b.IsRemote {
+			remotes = append(remotes, b.Name)
+		} else {
+			locals[b.Name] = true
+		}
+	}
+	if locals[branch] {
+		return branch, "", nil
+	}
+
+	var matches []string
+	for _, ref := range remotes {
+		if ref == branch {
+			if _, local, ok := strings.Cut(ref, "/"); ok && !locals[local] {
+				return local, ref, nil
+			}
+			return branch, "", nil
+		}
+		if _, local, ok := strings.Cut(ref, "/"); ok && local == branch {
+			matches = append(matches, ref)
+		}
+	}
+	if len(matches) == 1 {
+		return branch, matches[0], nil
+	}
+	return branch, "", nil
+}
+
+// prepareWorktreePath resolves the final worktree path and creates its parent
+// directory when auto_mkdir is enabled.
+func (m *Manager) prepareWorktreePath(branch string, customPath string) (string, error) {
 	path := customPath
 	if path == "" {
 		generatedPath, err := m.generateWorktreePath(branch)
 		if err != nil {
-			return fmt.Errorf("failed to generate worktree path: %w", err)
+			return "", fmt.Errorf("failed to generate worktree path: %w", err)
 		}
 		path = generatedPath
 	}
@@ -85,33 +184,149 @@ func (m *Manager) AddFromBase(branch string, baseBranch string, customPath strin
 	// Expand path (handles ~, env vars, and relative paths)
 	expandedPath, err := utils.ExpandPath(path)
 	if err != nil {
-		return fmt.Errorf("failed to expand path: %w", err)
+		return "", fmt.Errorf("failed to expand path: %w", err)
 	}
 	path = expandedPath
 
 	if m.config.Worktree.AutoMkdir {
 		dir := filepath.Dir(path)
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
+			return "", fmt.Errorf("failed to create directory: %w", err)
 		}
 	}
 
+	return path, nil
+}
+
+// AddFromBase creates a new worktree with a branch from a specific base branch.
+func (m *Manager) AddFromBase(branch string, baseBranch string, customPath string) error {
+	path, err := m.prepareWorktreePath(branch, customPath)
+	if err != nil {
+		return err
+	}
+
 	if err := m.git.AddWorktreeFromBase(path, branch, baseBranch); err != nil {
 		return err
 	}
 
-	return nil
+	return m.runPostCreateHooks(path, branch)
+}
+
+// RemoveOptions holds optional settings for RemoveWithOptions.
+type RemoveOptions struct {
+	Force     bool // Remove even if the worktree is dirty, discarding changes
+	AutoStash bool // Stash uncommitted changes first so removal doesn't need Force
 }
 
 // Remove deletes a worktree.
 func (m *Manager) Remove(path string, force bool) error {
+	_, err := m.RemoveWithOptions(path, RemoveOptions{Force: force})
+	return err
+}
+
+// RemoveWithOptions deletes a worktree. With opts.AutoStash, uncommitted
+// changes are stashed first and the stash commit hash is returned so they can
+// be brought back with RestoreStash; it is empty when nothing was stashed.
+func (m *Manager) RemoveWithOptions(path string, opts RemoveOptions) (string, error) {
+	var stashRef string
+	if opts.AutoStash {
+		ref, err := m.StashChanges(path)
+		if err != nil {
+			return "", err
+		}
+		stashRef = ref
+	}
+
+	if err := m.removeWorktree(path, opts.Force); err != nil {
+		if stashRef != "" {
+			return stashRef, fmt.Errorf("changes stashed as %s but removal failed: %w", stashRef, err)
+		}
+		return "", err
+	}
+
+	return stashRef, nil
+}
+
+// removeWorktree removes the worktree at path. Without force, a worktree with
+// changes is refused with ErrDirtyWorktree before git is asked to remove it.
+// When the check itself fails, for example because the directory is already
+// gone, git decides.
+func (m *Manager) removeWorktree(path string, force bool) error {
+	if !force {
+		if clean, err := m.git.IsWorktreeClean(path); err == nil && !clean {
+			return fmt.Errorf("cannot remove %s: %w", path, ErrDirtyWorktree)
+		}
+	}
 	return m.git.RemoveWorktree(path, force)
 }
 
-// RemoveWithBranch deletes a worktree and optionally its branch.
+// StashChanges stashes the uncommitted changes of the worktree at path and
+// returns the stash commit hash, or an empty string if it was clean.
+func (m *Manager) StashChanges(path string) (string, error) {
+	ref, err := m.git.StashWorktree(path, fmt.Sprintf("gwq: auto-stash before removing %s", path))
+	if err != nil {
+		return "", fmt.Errorf("failed to stash changes in %s: %w", path, err)
+	}
+	return ref, nil
+}
+
+// RestoreStash applies a stash created by RemoveWithOptions to the worktree
+// at path and drops it from the stash.
+func (m *Manager) RestoreStash(path, stashRef string) error {
+	if err := m.git.StashPopWorktree(path, stashRef); err != nil {
+		return fmt.Errorf("failed to restore stash %s: %w", stashRef, err)
+	}
+	return nil
+}
+
+// RemoveFailure records a worktree that RemoveMany could not remove.
+type RemoveFailure struct {
+	Path string
+	Err  error
+}
+
+// RemoveManyResult summarizes the outcome of RemoveMany.
+type RemoveManyResult struct {
+	Removed []string
+	Failed  []RemoveFailure
+}
+
+// Err combines the failures into a single error, or returns nil if every
+// worktree was removed.
+func (r *RemoveManyResult) Err() error {
+	errs := make([]error, 0, len(r.Failed))
+	for _, failure := range r.Failed {
+		errs = append(errs, fmt.Errorf("%s: %w", failure.Path, failure.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// RemoveMany deletes each worktree in paths. A failure does not stop the
+// remaining removals; all outcomes are collected in the result.
+func (m *Manager) RemoveMany(paths []string, force bool) *RemoveManyResult {
+	result := &RemoveManyResult{}
+	for _, path := range paths {
+		if err := m.Remove(path, force); err != nil {
+			result.Failed = append(result.Failed, RemoveFailure{Path: path, Err: err})
+			continue
+		}
+		result.Removed = append(result.Removed, path)
+	}
+	return result
+}
+
+// RemoveWithBranch deletes a worktree and optionally its branch. The branch
+// of the main worktree is never deleted; asking for it fails before anything
+// is removed.
 func (m *Manager) RemoveWithBranch(path string, branch string, forceWorktree bool, deleteBranch bool, forceBranch bool) error {
+	if deleteBranch && branch != "" {
+		if err := m.checkBranchDeletable(branch); err != nil {
+			return err
+		}
+	}
+
 	// First remove the worktree
-	if err := m.git.RemoveWorktree(path, forceWorktree); err != nil {
+	if err := m.removeWorktree(path, forceWorktree); err != nil {
 		return err
 	}
 
@@ -126,16 +341,136 @@ func (m *Manager) RemoveWithBranch(path string, branch string, forceWorktree boo
 	return nil
 }
 
+// checkBranchDeletable refuses to delete the branch of the main worktree.
+func (m *Manager) checkBranchDeletable(branch string) error {
+	worktrees, err := m.git.ListWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		if wt.IsMain && wt.Branch == branch {
+			return fmt.Errorf("refusing to delete branch %s: %w", branch, ErrProtectedBranch)
+		}
+	}
+	return nil
+}
+
+// RenameBranch renames a branch. When the branch is checked out in a worktree
+// whose path was generated from the old name, the worktree is moved to the
+// path generated from the new name and its new path is returned; otherwise
+// the returned path is empty. Nothing is changed if newName already exists
+// or the new path is taken.
+func (m *Manager) RenameBranch(oldName, newName string) (string, error) {
+	if oldName == newName {
+		return "", fmt.Errorf("branch is already named %s", newName)
+	}
+
+	worktrees, err := m.git.ListWorktrees()
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var oldPath, newPath string
+	for _, wt := range worktrees {
+		if wt.Branch == newName {
+			return "", fmt.Errorf("cannot rename %s to %s: %w", oldName, newName, git.ErrBranchExists)
+		}
+		if wt.Branch == oldName && !wt.IsMain {
+			oldPath = wt.Path
+		}
+	}
+
+	if oldPath != "" {
+		generated, err := m.generateWorktreePath(oldName)
+		if err == nil && filepath.Clean(generated) == filepath.Clean(oldPath) {
+			if newPath, err = m.generateWorktreePath(newName); err != nil {
+				return "", fmt.Errorf("failed to generate worktree path: %w", err)
+			}
+			if _, err := os.Stat(newPath); err == nil {
+				return "", fmt.Errorf("cannot move worktree: %s already exists", newPath)
+			}
+		}
+	}
+
+	if err := m.git.RenameBranch(oldName, newName); err != nil {
+		return "", err
+	}
+	if newPath == "" {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return "", fmt.Errorf("branch renamed but failed to create %s: %w", filepath.Dir(newPath), err)
+	}
+	if err := m.git.MoveWorktree(oldPath, newPath); err != nil {
+		return "", fmt.Errorf("branch renamed but worktree was not moved: %w", err)
+	}
+
+	return newPath, nil
+}
+
+// Move moves the worktree at oldPath to newPath, keeping its branch and any
+// local changes, and returns the expanded new path. The main worktree can't be
+// moved and newPath must not exist yet. The parent directory of newPath is
+// created when auto_mkdir is enabled.
+func (m *Manager) Move(oldPath, newPath string) (string, error) {
+	worktrees, err := m.git.ListWorktrees()
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var source *models.Worktree
+	for i := range worktrees {
+		if filepath.Clean(worktrees[i].Path) == filepath.Clean(oldPath) {
+			source = &worktrees[i]
+			break
+		}
+	}
+	if source == nil {
+		return "", fmt.Errorf("no worktree found at %s", oldPath)
+	}
+	if source.IsMain {
+		return "", fmt.Errorf("cannot move %s: %w", oldPath, ErrMainWorktree)
+	}
+
+	newPath, err = utils.ExpandPath(newPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand path: %w", err)
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		return "", fmt.Errorf("cannot move worktree: %s already exists", newPath)
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to check path: %w", err)
+	}
+
+	if m.config.Worktree.AutoMkdir {
+		if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	if err := m.git.MoveWorktree(source.Path, newPath); err != nil {
+		return "", err
+	}
+
+	return newPath, nil
+}
+
 // List returns all worktrees.
 func (m *Manager) List() ([]models.Worktree, error) {
 	return m.git.ListWorktrees()
 }
 
-// Prune removes worktree information for deleted directories.
-func (m *Manager) Prune() error {
+// Prune removes worktree information for deleted directories and returns
+// the paths of the pruned worktrees.
+func (m *Manager) Prune() ([]string, error) {
 	return m.git.PruneWorktrees()
 }
 
+// PruneDryRun returns the paths of the worktrees Prune would remove.
+func (m *Manager) PruneDryRun() ([]string, error) {
+	return m.git.PruneWorktreesDryRun()
+}
+
 // GetWorktreePath returns the path for a worktree by pattern matching.
 func (m *Manager) GetWorktreePath(pattern string) (string, error) {
 	worktrees, err := m.List()
@@ -195,7 +530,8 @@ func (m *Manager) ValidateWorktreePath(path string) error {
 	return nil
 }
 
-// generateWorktreePath generates a path for a new worktree using URL-based hierarchy.
+// generateWorktreePath generates a path for a new worktree using URL-based hierarchy,
+// or the configured path template when set.
 func (m *Manager) generateWorktreePath(branch string) (string, error) {
 	// Get repository URL
 	repoURL, err := m.git.GetRepositoryURL()
@@ -209,6 +545,10 @@ func (m *Manager) generateWorktreePath(branch string) (string, error) {
 		return "", fmt.Errorf("failed to parse repository URL: %w", err)
 	}
 
+	if m.config.Worktree.PathTemplate != "" {
+		return url.RenderPathTemplate(m.config.Worktree.BaseDir, m.config.Worktree.PathTemplate, repoInfo, branch)
+	}
+
 	// Generate path using URL hierarchy
 	path := url.GenerateWorktreePath(m.config.Worktree.BaseDir, repoInfo, branch)
 
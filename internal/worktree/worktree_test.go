@@ -1,11 +1,14 @@
 package worktree
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/git/gittest"
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
@@ -15,10 +18,19 @@ type mockGit struct {
 	repoName          string
 	addError          error
 	removeError       error
+	removeErrors      map[string]error // Per-path errors for RemoveWorktree
 	listError         error
 	pruneError        error
+	pruned            []string
 	deleteBranchError error
+	deletedBranches   []string // Branches passed to DeleteBranch, with "!" appended when forced
 	recentCommits     []models.CommitInfo
+	renameHook        func(oldName, newName string) error // Called by RenameBranch before the rename is applied
+	renamedBranches   []string                            // "old->new" for each RenameBranch call
+	moveError         error
+	branches          []models.Branch
+	tracked           map[string]string // Branch to the remote ref passed to AddWorktreeTracking
+	dirty             map[string]bool   // Worktree paths IsWorktreeClean reports as dirty
 }
 
 func (m *mockGit) ListWorktrees() ([]models.Worktree, error) {
@@ -39,10 +51,32 @@ func (m *mockGit) AddWorktree(path, branch string, createBranch bool) error {
 	return nil
 }
 
+func (m *mockGit) AddWorktreeTracking(path, branch, remoteRef string) error {
+	if err := m.AddWorktree(path, branch, true); err != nil {
+		return err
+	}
+	if m.tracked == nil {
+		m.tracked = make(map[string]string)
+	}
+	m.tracked[branch] = remoteRef
+	return nil
+}
+
+func (m *mockGit) IsWorktreeClean(path string) (bool, error) {
+	return !m.dirty[path], nil
+}
+
+func (m *mockGit) ListBranches(includeRemote bool) ([]models.Branch, error) {
+	return m.branches, nil
+}
+
 func (m *mockGit) RemoveWorktree(path string, force bool) error {
 	if m.removeError != nil {
 		return m.removeError
 	}
+	if err := m.removeErrors[path]; err != nil {
+		return err
+	}
 	var updated []models.Worktree
 	for _, wt := range m.worktrees {
 		if wt.Path != path {
@@ -53,8 +87,12 @@ func (m *mockGit) RemoveWorktree(path string, force bool) error {
 	return nil
 }
 
-func (m *mockGit) PruneWorktrees() error {
-	return m.pruneError
+func (m *mockGit) PruneWorktrees() ([]string, error) {
+	return m.pruned, m.pruneError
+}
+
+func (m *mockGit) PruneWorktreesDryRun() ([]string, error) {
+	return m.pruned, m.pruneError
 }
 
 func (m *mockGit) GetRepositoryName() (string, error) {
@@ -76,6 +114,37 @@ func (m *mockGit) DeleteBranch(branch string, force bool) error {
 	if m.deleteBranchError != nil {
 		return m.deleteBranchError
 	}
+	if force {
+		branch += "!"
+	}
+	m.deletedBranches = append(m.deletedBranches, branch)
+	return nil
+}
+
+func (m *mockGit) RenameBranch(oldName, newName string) error {
+	if m.renameHook != nil {
+		if err := m.renameHook(oldName, newName); err != nil {
+			return err
+		}
+	}
+	for i := range m.worktrees {
+		if m.worktrees[i].Branch == oldName {
+			m.worktrees[i].Branch = newName
+		}
+	}
+	m.renamedBranches = append(m.renamedBranches, oldName+"->"+newName)
+	return nil
+}
+
+func (m *mockGit) MoveWorktree(oldPath, newPath string) error {
+	if m.moveError != nil {
+		return m.moveError
+	}
+	for i := range m.worktrees {
+		if m.worktrees[i].Path == oldPath {
+			m.worktrees[i].Path = newPath
+		}
+	}
 	return nil
 }
 
@@ -90,6 +159,14 @@ func (m *mockGit) AddWorktreeFromBase(path, branch, baseBranch string) error {
 	return nil
 }
 
+func (m *mockGit) StashWorktree(path, message string) (string, error) {
+	return "", nil
+}
+
+func (m *mockGit) StashPopWorktree(path, ref string) error {
+	return nil
+}
+
 func TestManagerAdd(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -188,6 +265,183 @@ func TestManagerRemove(t *testing.T) {
 	}
 }
 
+func TestManagerRemoveDirty(t *testing.T) {
+	mockG := &mockGit{
+		worktrees: []models.Worktree{{Path: "/path/to/dirty", Branch: "feature"}},
+		dirty:     map[string]bool{"/path/to/dirty": true},
+	}
+	m := New(mockG, &models.Config{})
+
+	if err := m.Remove("/path/to/dirty", false); !errors.Is(err, ErrDirtyWorktree) {
+		t.Fatalf("Remove() error = %v, want ErrDirtyWorktree", err)
+	}
+	if err := m.RemoveWithBranch("/path/to/dirty", "feature", false, true, false); !errors.Is(err, ErrDirtyWorktree) {
+		t.Fatalf("RemoveWithBranch() error = %v, want ErrDirtyWorktree", err)
+	}
+	if len(mockG.worktrees) != 1 || len(mockG.deletedBranches) != 0 {
+		t.Fatalf("dirty worktree was touched: worktrees %+v, deleted branches %v", mockG.worktrees, mockG.deletedBranches)
+	}
+
+	if err := m.Remove("/path/to/dirty", true); err != nil {
+		t.Fatalf("Remove() with force error = %v", err)
+	}
+	if len(mockG.worktrees) != 0 {
+		t.Errorf("worktree not removed with force: %+v", mockG.worktrees)
+	}
+}
+
+func TestManagerRemoveWithBranch(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		branch       string
+		deleteBranch bool
+		forceBranch  bool
+		wantDeleted  []string
+		wantRemoved  bool
+		wantErr      error
+	}{
+		{name: "KeepBranch", path: "/path/to/feature", branch: "feature", wantRemoved: true},
+		{name: "DeleteBranch", path: "/path/to/feature", branch: "feature", deleteBranch: true, wantDeleted: []string{"feature"}, wantRemoved: true},
+		{name: "ForceDeleteBranch", path: "/path/to/feature", branch: "feature", deleteBranch: true, forceBranch: true, wantDeleted: []string{"feature!"}, wantRemoved: true},
+		{name: "MainBranch", path: "/path/to/other", branch: "main", deleteBranch: true, forceBranch: true, wantErr: ErrProtectedBranch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockG := &mockGit{
+				worktrees: []models.Worktree{
+					{Path: "/path/to/repo", Branch: "main", IsMain: true},
+					{Path: "/path/to/feature", Branch: "feature"},
+					{Path: "/path/to/other", Branch: "main"},
+				},
+			}
+			m := New(mockG, &models.Config{})
+
+			err := m.RemoveWithBranch(tt.path, tt.branch, false, tt.deleteBranch, tt.forceBranch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RemoveWithBranch() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if strings.Join(mockG.deletedBranches, ",") != strings.Join(tt.wantDeleted, ",") {
+				t.Errorf("deleted branches = %v, want %v", mockG.deletedBranches, tt.wantDeleted)
+			}
+			removed := true
+			for _, wt := range mockG.worktrees {
+				if wt.Path == tt.path {
+					removed = false
+				}
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("worktree removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestManagerRemoveAutoStash(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	repo.Git(t, "worktree", "add", "-q", "-b", "feature", wtPath)
+
+	// Leave a tracked modification and an untracked file behind
+	if err := os.WriteFile(filepath.Join(wtPath, "README.md"), []byte("changed\n"), 0644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wtPath, "notes.txt"), []byte("notes\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	m := New(git.New(repo.Path), &models.Config{})
+
+	if err := m.Remove(wtPath, false); err == nil {
+		t.Fatal("Remove() without force should fail for a dirty worktree")
+	}
+
+	stashRef, err := m.RemoveWithOptions(wtPath, RemoveOptions{AutoStash: true})
+	if err != nil {
+		t.Fatalf("RemoveWithOptions() error = %v", err)
+	}
+	if stashRef == "" {
+		t.Fatal("RemoveWithOptions() should return the stash ref for a dirty worktree")
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Errorf("worktree directory should be removed, stat err = %v", err)
+	}
+
+	// Bring the changes back in a fresh worktree of the same branch
+	restorePath := filepath.Join(t.TempDir(), "restore")
+	repo.Git(t, "worktree", "add", "-q", restorePath, "feature")
+	if err := m.RestoreStash(restorePath, stashRef); err != nil {
+		t.Fatalf("RestoreStash() error = %v", err)
+	}
+
+	for file, want := range map[string]string{"README.md": "changed\n", "notes.txt": "notes\n"} {
+		got, err := os.ReadFile(filepath.Join(restorePath, file))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q (err %v), want %q", file, got, err, want)
+		}
+	}
+}
+
+func TestManagerRemoveAutoStashClean(t *testing.T) {
+	mockG := &mockGit{worktrees: []models.Worktree{{Path: "/path/to/worktree1", Branch: "feature1"}}}
+	m := New(mockG, &models.Config{})
+
+	stashRef, err := m.RemoveWithOptions("/path/to/worktree1", RemoveOptions{AutoStash: true})
+	if err != nil {
+		t.Fatalf("RemoveWithOptions() error = %v", err)
+	}
+	if stashRef != "" {
+		t.Errorf("stash ref = %q, want empty for a clean worktree", stashRef)
+	}
+	if len(mockG.worktrees) != 0 {
+		t.Errorf("Expected worktree to be removed, got %v", mockG.worktrees)
+	}
+}
+
+func TestManagerRemoveMany(t *testing.T) {
+	lockedErr := errors.New("worktree is locked")
+	mockG := &mockGit{
+		worktrees: []models.Worktree{
+			{Path: "/path/to/worktree1", Branch: "feature1"},
+			{Path: "/path/to/worktree2", Branch: "feature2"},
+			{Path: "/path/to/worktree3", Branch: "feature3"},
+			{Path: "/path/to/keep", Branch: "keep"},
+		},
+		removeErrors: map[string]error{"/path/to/worktree2": lockedErr},
+	}
+
+	m := New(mockG, &models.Config{})
+	result := m.RemoveMany([]string{"/path/to/worktree1", "/path/to/worktree2", "/path/to/worktree3"}, false)
+
+	wantRemoved := []string{"/path/to/worktree1", "/path/to/worktree3"}
+	if strings.Join(result.Removed, ",") != strings.Join(wantRemoved, ",") {
+		t.Errorf("Removed = %v, want %v", result.Removed, wantRemoved)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Path != "/path/to/worktree2" || !errors.Is(result.Failed[0].Err, lockedErr) {
+		t.Errorf("Failed = %+v, want worktree2 with %v", result.Failed, lockedErr)
+	}
+
+	err := result.Err()
+	if !errors.Is(err, lockedErr) || !strings.Contains(err.Error(), "/path/to/worktree2") {
+		t.Errorf("Err() = %v, want error mentioning worktree2 and wrapping %v", err, lockedErr)
+	}
+
+	var remaining []string
+	for _, wt := range mockG.worktrees {
+		remaining = append(remaining, wt.Path)
+	}
+	if want := "/path/to/worktree2,/path/to/keep"; strings.Join(remaining, ",") != want {
+		t.Errorf("remaining worktrees = %v, want %s", remaining, want)
+	}
+
+	if err := m.RemoveMany(nil, false).Err(); err != nil {
+		t.Errorf("RemoveMany(nil).Err() = %v, want nil", err)
+	}
+}
+
 func TestManagerList(t *testing.T) {
 	expectedWorktrees := []models.Worktree{
 		{Path: "/path/1", Branch: "main", IsMain: true},
@@ -211,13 +465,24 @@ func TestManagerList(t *testing.T) {
 }
 
 func TestManagerPrune(t *testing.T) {
-	mockG := &mockGit{}
+	mockG := &mockGit{pruned: []string{"/path/to/deleted"}}
 	m := New(mockG, &models.Config{})
 
-	err := m.Prune()
+	wouldPrune, err := m.PruneDryRun()
+	if err != nil {
+		t.Fatalf("PruneDryRun() error = %v", err)
+	}
+	if len(wouldPrune) != 1 || wouldPrune[0] != "/path/to/deleted" {
+		t.Errorf("PruneDryRun() = %v, want [/path/to/deleted]", wouldPrune)
+	}
+
+	pruned, err := m.Prune()
 	if err != nil {
 		t.Fatalf("Prune() error = %v", err)
 	}
+	if len(pruned) != 1 || pruned[0] != "/path/to/deleted" {
+		t.Errorf("Prune() = %v, want [/path/to/deleted]", pruned)
+	}
 }
 
 func TestManagerGetWorktreePath(t *testing.T) {
@@ -413,10 +678,11 @@ func TestManagerValidateWorktreePath(t *testing.T) {
 
 func TestGenerateWorktreePath(t *testing.T) {
 	tests := []struct {
-		name       string
-		branch     string
-		repoName   string
-		wantSuffix string
+		name         string
+		branch       string
+		repoName     string
+		pathTemplate string
+		wantSuffix   string
 	}{
 		{
 			name:       "BasicTemplate",
@@ -436,6 +702,13 @@ func TestGenerateWorktreePath(t *testing.T) {
 			repoName:   "myrepo",
 			wantSuffix: "github.com/test-user/test-repo/feature-test-new",
 		},
+		{
+			name:         "PathTemplate",
+			branch:       "feature/test",
+			repoName:     "myrepo",
+			pathTemplate: "{owner}/{repo}/{branch}",
+			wantSuffix:   "test-user/test-repo/feature-test",
+		},
 	}
 
 	for _, tt := range tests {
@@ -444,7 +717,8 @@ func TestGenerateWorktreePath(t *testing.T) {
 
 			config := &models.Config{
 				Worktree: models.WorktreeConfig{
-					BaseDir: "/base",
+					BaseDir:      "/base",
+					PathTemplate: tt.pathTemplate,
 				},
 			}
 
@@ -462,3 +736,333 @@ func TestGenerateWorktreePath(t *testing.T) {
 		})
 	}
 }
+
+func TestManagerRenameBranch(t *testing.T) {
+	baseDir := t.TempDir()
+	config := &models.Config{Worktree: models.WorktreeConfig{BaseDir: baseDir}}
+	generated := filepath.Join(baseDir, "github.com/test-user/test-repo/feature-login")
+	renamed := filepath.Join(baseDir, "github.com/test-user/test-repo/feature-auth")
+
+	tests := []struct {
+		name         string
+		worktrees    []models.Worktree
+		renameHook   func(oldName, newName string) error
+		moveError    error
+		oldName      string
+		newName      string
+		wantPath     string
+		wantRenamed  bool
+		wantErr      error
+		errContains  string
+		wantWorktree models.Worktree // Expected worktree entry afterwards, if any
+	}{
+		{
+			name:         "generated path is moved",
+			worktrees:    []models.Worktree{{Path: "/repo", Branch: "main", IsMain: true}, {Path: generated, Branch: "feature/login"}},
+			oldName:      "feature/login",
+			newName:      "feature/auth",
+			wantPath:     renamed,
+			wantRenamed:  true,
+			wantWorktree: models.Worktree{Path: renamed, Branch: "feature/auth"},
+		},
+		{
+			name:         "custom path stays",
+			worktrees:    []models.Worktree{{Path: "/elsewhere/login", Branch: "feature/login"}},
+			oldName:      "feature/login",
+			newName:      "feature/auth",
+			wantRenamed:  true,
+			wantWorktree: models.Worktree{Path: "/elsewhere/login", Branch: "feature/auth"},
+		},
+		{
+			name:        "branch without worktree",
+			oldName:     "feature/login",
+			newName:     "feature/auth",
+			wantRenamed: true,
+		},
+		{
+			name:         "main worktree is never moved",
+			worktrees:    []models.Worktree{{Path: generated, Branch: "feature/login", IsMain: true}},
+			oldName:      "feature/login",
+			newName:      "feature/auth",
+			wantRenamed:  true,
+			wantWorktree: models.Worktree{Path: generated, Branch: "feature/auth", IsMain: true},
+		},
+		{
+			name:      "target checked out in a worktree",
+			worktrees: []models.Worktree{{Path: generated, Branch: "feature/login"}, {Path: renamed, Branch: "feature/auth"}},
+			oldName:   "feature/login",
+			newName:   "feature/auth",
+			wantErr:   git.ErrBranchExists,
+		},
+		{
+			name:       "target branch exists",
+			renameHook: func(oldName, newName string) error { return git.ErrBranchExists },
+			oldName:    "feature/login",
+			newName:    "feature/auth",
+			wantErr:    git.ErrBranchExists,
+		},
+		{
+			name:        "same name",
+			oldName:     "feature/login",
+			newName:     "feature/login",
+			errContains: "already named",
+		},
+		{
+			name:        "move fails after rename",
+			worktrees:   []models.Worktree{{Path: generated, Branch: "feature/login"}},
+			moveError:   errors.New("worktree is locked"),
+			oldName:     "feature/login",
+			newName:     "feature/auth",
+			wantRenamed: true,
+			errContains: "branch renamed but worktree was not moved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockG := &mockGit{
+				worktrees:  append([]models.Worktree(nil), tt.worktrees...),
+				renameHook: tt.renameHook,
+				moveError:  tt.moveError,
+			}
+			m := New(mockG, config)
+
+			path, err := m.RenameBranch(tt.oldName, tt.newName)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RenameBranch() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.errContains != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("RenameBranch() error = %v, want error containing %q", err, tt.errContains)
+				}
+			case err != nil:
+				t.Fatalf("RenameBranch() error = %v", err)
+			}
+
+			if path != tt.wantPath {
+				t.Errorf("RenameBranch() path = %q, want %q", path, tt.wantPath)
+			}
+			if renamed := len(mockG.renamedBranches) > 0; renamed != tt.wantRenamed {
+				t.Errorf("branch renamed = %v, want %v (%v)", renamed, tt.wantRenamed, mockG.renamedBranches)
+			}
+			if tt.wantWorktree.Path != "" {
+				found := false
+				for _, wt := range mockG.worktrees {
+					if wt == tt.wantWorktree {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("worktrees = %+v, want an entry %+v", mockG.worktrees, tt.wantWorktree)
+				}
+			}
+		})
+	}
+}
+
+func TestManagerRenameBranchRealRepo(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	repo.Git(t, "remote", "add", "origin", "https://github.com/test-user/test-repo.git")
+	repo.Git(t, "branch", "taken")
+
+	baseDir := t.TempDir()
+	g := git.New(repo.Path)
+	m := New(g, &models.Config{Worktree: models.WorktreeConfig{BaseDir: baseDir, AutoMkdir: true}})
+	if err := m.Add("feature/login", "", true); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	oldPath := filepath.Join(baseDir, "github.com/test-user/test-repo/feature-login")
+
+	if _, err := m.RenameBranch("feature/login", "taken"); !errors.Is(err, git.ErrBranchExists) {
+		t.Fatalf("RenameBranch() to an existing branch error = %v, want ErrBranchExists", err)
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		t.Fatalf("worktree should stay after a refused rename: %v", err)
+	}
+
+	newPath, err := m.RenameBranch("feature/login", "feature/auth")
+	if err != nil {
+		t.Fatalf("RenameBranch() error = %v", err)
+	}
+	if want := filepath.Join(baseDir, "github.com/test-user/test-repo/feature-auth"); newPath != want {
+		t.Errorf("RenameBranch() path = %q, want %q", newPath, want)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Errorf("old worktree directory should be gone, stat err = %v", err)
+	}
+
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	found := false
+	for _, wt := range worktrees {
+		if wt.Branch == "feature/login" {
+			t.Errorf("old branch still listed: %+v", wt)
+		}
+		if wt.Branch == "feature/auth" {
+			found = true
+			if resolved, _ := filepath.EvalSymlinks(newPath); wt.Path != newPath && wt.Path != resolved {
+				t.Errorf("worktree path = %q, want %q", wt.Path, newPath)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("renamed branch not found in worktrees: %+v", worktrees)
+	}
+
+	// The moved worktree is still usable by git
+	if out, err := g.Run("-C", newPath, "rev-parse", "--abbrev-ref", "HEAD"); err != nil || strings.TrimSpace(out) != "feature/auth" {
+		t.Errorf("HEAD in moved worktree = %q (err %v), want feature/auth", out, err)
+	}
+}
+
+func TestManagerMoveRealRepo(t *testing.T) {
+	repo := gittest.NewTestRepository(t)
+	repo.Git(t, "remote", "add", "origin", "https://github.com/test-user/test-repo.git")
+
+	baseDir := t.TempDir()
+	g := git.New(repo.Path)
+	m := New(g, &models.Config{Worktree: models.WorktreeConfig{BaseDir: baseDir, AutoMkdir: true}})
+	if err := m.Add("feature/login", "", true); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	oldPath := filepath.Join(baseDir, "github.com/test-user/test-repo/feature-login")
+	if err := os.WriteFile(filepath.Join(oldPath, "wip.txt"), []byte("uncommitted\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			if _, err := m.Move(wt.Path, filepath.Join(t.TempDir(), "main")); !errors.Is(err, ErrMainWorktree) {
+				t.Errorf("Move() of the main worktree error = %v, want ErrMainWorktree", err)
+			}
+		}
+		if wt.Branch == "feature/login" {
+			oldPath = wt.Path
+		}
+	}
+
+	if _, err := m.Move(oldPath, t.TempDir()); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Move() onto an existing directory error = %v, want already exists", err)
+	}
+
+	target := filepath.Join(t.TempDir(), "nested", "login")
+	newPath, err := m.Move(oldPath, target)
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if newPath != target {
+		t.Errorf("Move() path = %q, want %q", newPath, target)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Errorf("old worktree directory should be gone, stat err = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(newPath, "wip.txt")); err != nil || string(data) != "uncommitted\n" {
+		t.Errorf("local changes were not kept: %q (err %v)", data, err)
+	}
+
+	worktrees, err = g.ListWorktrees()
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	found := false
+	for _, wt := range worktrees {
+		if wt.Branch != "feature/login" {
+			continue
+		}
+		found = true
+		if resolved, _ := filepath.EvalSymlinks(newPath); wt.Path != newPath && wt.Path != resolved {
+			t.Errorf("worktree path = %q, want %q", wt.Path, newPath)
+		}
+	}
+	if !found {
+		t.Errorf("moved worktree not listed: %+v", worktrees)
+	}
+}
+
+func TestManagerAddRemoteBranch(t *testing.T) {
+	branches := []models.Branch{
+		{Name: "main"},
+		{Name: "taken"},
+		{Name: "origin/main", IsRemote: true},
+		{Name: "origin/feature/login", IsRemote: true},
+		{Name: "origin/taken", IsRemote: true},
+		{Name: "origin/shared", IsRemote: true},
+		{Name: "upstream/shared", IsRemote: true},
+	}
+
+	tests := []struct {
+		name       string
+		branch     string
+		wantBranch string
+		wantRef    string // Empty when no tracking branch should be created
+	}{
+		{name: "FullRemoteName", branch: "origin/feature/login", wantBranch: "feature/login", wantRef: "origin/feature/login"},
+		{name: "UniqueShortName", branch: "feature/login", wantBranch: "feature/login", wantRef: "origin/feature/login"},
+		{name: "LocalBranchExists", branch: "main", wantBranch: "main"},
+		{name: "RemoteOfExistingLocal", branch: "origin/taken", wantBranch: "origin/taken"},
+		{name: "AmbiguousShortName", branch: "shared", wantBranch: "shared"},
+		{name: "Unknown", branch: "missing", wantBranch: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockG := &mockGit{branches: branches}
+			m := New(mockG, &models.Config{Worktree: models.WorktreeConfig{BaseDir: t.TempDir(), AutoMkdir: true}})
+
+			if err := m.Add(tt.branch, "", false); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if len(mockG.worktrees) != 1 || mockG.worktrees[0].Branch != tt.wantBranch {
+				t.Fatalf("worktrees = %+v, want one on %s", mockG.worktrees, tt.wantBranch)
+			}
+			if got := mockG.tracked[tt.wantBranch]; got != tt.wantRef {
+				t.Errorf("tracked ref = %q, want %q", got, tt.wantRef)
+			}
+		})
+	}
+}
+
+func TestManagerAddRemoteBranchRealRepo(t *testing.T) {
+	remote := gittest.NewTestRepository(t)
+	remote.Git(t, "branch", "feature/login")
+
+	repo := filepath.Join(t.TempDir(), "clone")
+	remote.Git(t, "clone", "-q", remote.Path, repo)
+
+	// The clone's origin is a local path, which can't generate a worktree path
+	g := git.New(repo)
+	m := New(g, &models.Config{Worktree: models.WorktreeConfig{BaseDir: t.TempDir(), AutoMkdir: true}})
+	if err := m.Add("origin/feature/login", filepath.Join(t.TempDir(), "login"), false); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	out, err := g.Run("branch", "-vv", "--list", "feature/login")
+	if err != nil {
+		t.Fatalf("git branch -vv error = %v", err)
+	}
+	if !strings.Contains(out, "[origin/feature/login]") {
+		t.Errorf("git branch -vv = %q, want upstream origin/feature/login", out)
+	}
+
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	found := false
+	for _, wt := range worktrees {
+		if wt.Branch == "feature/login" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no worktree on feature/login: %+v", worktrees)
+	}
+}
@@ -1,15 +1,22 @@
 // Package models defines the core data structures used throughout the gwq application.
 package models
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+)
 
 // Worktree represents a Git worktree with its associated metadata.
 type Worktree struct {
-	Path       string    `json:"path"`        // Absolute path to the worktree directory
-	Branch     string    `json:"branch"`      // Branch name associated with this worktree
-	CommitHash string    `json:"commit_hash"` // Current HEAD commit hash
-	IsMain     bool      `json:"is_main"`     // Whether this is the main worktree
-	CreatedAt  time.Time `json:"created_at"`  // Creation timestamp
+	Path       string    `json:"path"`                  // Absolute path to the worktree directory
+	Branch     string    `json:"branch"`                // Branch name associated with this worktree
+	CommitHash string    `json:"commit_hash"`           // Current HEAD commit hash
+	IsMain     bool      `json:"is_main"`               // Whether this is the main worktree
+	Locked     bool      `json:"locked,omitempty"`      // Whether the worktree is locked (git worktree lock)
+	LockReason string    `json:"lock_reason,omitempty"` // Reason given when the worktree was locked
+	CreatedAt  time.Time `json:"created_at"`            // Creation timestamp
 }
 
 // Branch represents a Git branch with its metadata.
@@ -36,15 +43,89 @@ type Config struct {
 	Claude   ClaudeConfig   `mapstructure:"claude"`   // Claude Code task queue configuration
 }
 
+// Validate checks the configuration for invalid values and returns a single
+// error listing every problem found, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+	}
+
+	switch {
+	case c.Worktree.BaseDir == "":
+		invalid("worktree.basedir", "must not be empty")
+	case !filepath.IsAbs(c.Worktree.BaseDir):
+		invalid("worktree.basedir", "must be an absolute path, got %q", c.Worktree.BaseDir)
+	}
+
+	switch c.Finder.SortBy {
+	case "", "name", "activity", "branch":
+	default:
+		invalid("finder.sort_by", "must be one of name, activity, branch, got %q", c.Finder.SortBy)
+	}
+
+	switch c.Finder.TimeFormat {
+	case "", "relative", "absolute", "iso":
+	default:
+		invalid("finder.time_format", "must be one of relative, absolute, iso, got %q", c.Finder.TimeFormat)
+	}
+
+	if c.Claude.MaxParallel <= 0 {
+		invalid("claude.max_parallel", "must be greater than 0, got %d", c.Claude.MaxParallel)
+	}
+	if c.Claude.MaxDevelopmentTasks <= 0 {
+		invalid("claude.max_development_tasks", "must be greater than 0, got %d", c.Claude.MaxDevelopmentTasks)
+	}
+	if c.Claude.MaxParallelPerRepository < 0 {
+		invalid("claude.max_parallel_per_repository", "must not be negative, got %d", c.Claude.MaxParallelPerRepository)
+	}
+	if c.Claude.MaxCostUSD < 0 {
+		invalid("claude.max_cost_usd", "must not be negative, got %g", c.Claude.MaxCostUSD)
+	}
+	if c.Claude.PollInterval < 0 {
+		invalid("claude.poll_interval", "must not be negative, got %s", c.Claude.PollInterval)
+	}
+	if c.Claude.MaxPollInterval < 0 {
+		invalid("claude.max_poll_interval", "must not be negative, got %s", c.Claude.MaxPollInterval)
+	}
+	if c.Claude.Queue.AgingThreshold < 0 {
+		invalid("claude.queue.aging_threshold", "must not be negative, got %s", c.Claude.Queue.AgingThreshold)
+	}
+	if c.Claude.Queue.AgingRate < 0 {
+		invalid("claude.queue.aging_rate", "must not be negative, got %g", c.Claude.Queue.AgingRate)
+	}
+	if c.Claude.Queue.DuplicateSimilarity < 0 || c.Claude.Queue.DuplicateSimilarity > 1 {
+		invalid("claude.queue.duplicate_similarity", "must be between 0 and 1, got %g", c.Claude.Queue.DuplicateSimilarity)
+	}
+	if c.Claude.Execution.RetentionDays < 0 {
+		invalid("claude.execution.retention_days", "must not be negative, got %d", c.Claude.Execution.RetentionDays)
+	}
+	if c.Claude.Execution.LogPartSizeMB < 0 {
+		invalid("claude.execution.log_part_size_mb", "must not be negative, got %d", c.Claude.Execution.LogPartSizeMB)
+	}
+	if c.Claude.Execution.Timeout < 0 {
+		invalid("claude.execution.timeout", "must not be negative, got %s", c.Claude.Execution.Timeout)
+	}
+
+	return errors.Join(errs...)
+}
+
 // WorktreeConfig contains worktree-specific configuration options.
 type WorktreeConfig struct {
-	BaseDir   string `mapstructure:"basedir"`    // Base directory for creating worktrees
-	AutoMkdir bool   `mapstructure:"auto_mkdir"` // Automatically create directories
+	BaseDir      string   `mapstructure:"basedir"`       // Base directory for creating worktrees
+	AutoMkdir    bool     `mapstructure:"auto_mkdir"`    // Automatically create directories
+	CopyPatterns []string `mapstructure:"copy_patterns"` // Globs copied from --copy-from source (e.g. ".env*")
+	PathTemplate string   `mapstructure:"path_template"` // Layout of generated paths, e.g. "{owner}/{repo}/{branch}" (empty = {host}/{owner}/{repo}/{branch})
+
+	PostCreateHooks      []string `mapstructure:"post_create_hooks"`       // Commands run in a new worktree after creation
+	PostCreateHooksFatal bool     `mapstructure:"post_create_hooks_fatal"` // Fail the command when a hook exits non-zero
 }
 
 // FinderConfig contains fuzzy finder configuration options.
 type FinderConfig struct {
-	Preview bool `mapstructure:"preview"` // Enable preview window
+	Preview    bool   `mapstructure:"preview"`     // Enable preview window
+	SortBy     string `mapstructure:"sort_by"`     // Worktree order: name, activity, branch (empty keeps git order)
+	TimeFormat string `mapstructure:"time_format"` // Preview times: relative, absolute, iso (empty keeps the default)
 }
 
 // UIConfig contains UI-related configuration options.
@@ -55,14 +136,18 @@ type UIConfig struct {
 
 // WorktreeStatus represents the current status of a worktree.
 type WorktreeStatus struct {
-	Path          string        `json:"path"`             // Absolute path to the worktree
-	Branch        string        `json:"branch"`           // Branch name
-	Repository    string        `json:"repository"`       // Repository identifier
-	Status        WorktreeState `json:"status"`           // Current status (clean, modified, etc.)
-	GitStatus     GitStatus     `json:"git_status"`       // Detailed git status
-	LastActivity  time.Time     `json:"last_activity"`    // Last modification time
-	ActiveProcess []ProcessInfo `json:"active_processes"` // Running processes
-	IsCurrent     bool          `json:"is_current"`       // Whether this is the current worktree
+	Path          string        `json:"path"`                     // Absolute path to the worktree
+	Branch        string        `json:"branch"`                   // Branch name
+	CommitHash    string        `json:"commit_hash"`              // Current HEAD commit hash
+	Repository    string        `json:"repository"`               // Repository identifier
+	Status        WorktreeState `json:"status"`                   // Current status (clean, modified, etc.)
+	GitStatus     GitStatus     `json:"git_status"`               // Detailed git status
+	ConflictFiles []string      `json:"conflict_files,omitempty"` // Paths with merge conflicts, relative to the worktree
+	LastActivity  time.Time     `json:"last_activity"`            // Last modification time
+	ActiveProcess []ProcessInfo `json:"active_processes"`         // Running processes
+	IsCurrent     bool          `json:"is_current"`               // Whether this is the current worktree
+	Stale         bool          `json:"stale"`                    // No activity within the stale threshold, whatever the status
+	Size          *int64        `json:"size,omitempty"`           // Disk usage in bytes, only collected on request
 }
 
 // WorktreeState represents the overall state of a worktree.
@@ -93,6 +178,8 @@ type GitStatus struct {
 	Ahead     int `json:"ahead"`     // Number of commits ahead of remote
 	Behind    int `json:"behind"`    // Number of commits behind remote
 	Conflicts int `json:"conflicts"` // Number of files with conflicts
+
+	SubmoduleModified int `json:"submodule_modified"` // Number of submodules not at the recorded commit or not initialized
 }
 
 // ProcessInfo represents information about a running process.
@@ -105,13 +192,29 @@ type ProcessInfo struct {
 // ClaudeConfig contains Claude Code task queue configuration.
 type ClaudeConfig struct {
 	// Claude Code executable and core options
-	Executable string `mapstructure:"executable"` // Claude Code executable path
-	ConfigDir  string `mapstructure:"config_dir"` // Configuration and state directory
+	Executable    string   `mapstructure:"executable"`     // Claude Code executable path
+	ConfigDir     string   `mapstructure:"config_dir"`     // Configuration and state directory
+	LogDir        string   `mapstructure:"log_dir"`        // Execution log directory (empty = <config_dir>/logs)
+	AllowedModels []string `mapstructure:"allowed_models"` // Models accepted for --model (empty = any)
+	OutputFormat  string   `mapstructure:"output_format"`  // Value passed to --output-format (text, json or stream-json)
+
+	SessionNameTemplate string `mapstructure:"session_name_template"` // tmux session name: {repo}, {branch}, {task_name}, {id} (empty = "{id}")
+
+	RedactPatterns []string `mapstructure:"redact_patterns"` // Extra regexes masked in captured logs, on top of the built-in secret patterns
 
 	// Global parallelism control
 	MaxParallel         int `mapstructure:"max_parallel"`          // Max parallel Claude instances
 	MaxDevelopmentTasks int `mapstructure:"max_development_tasks"` // Max concurrent development tasks
 
+	MaxParallelPerRepository int `mapstructure:"max_parallel_per_repository"` // Max concurrent tasks in one repository (0 = unlimited)
+
+	MaxLoadAverage float64 `mapstructure:"max_load_average"` // Don't start tasks while the 1-minute load average is above this (0 = disabled)
+	MaxCostUSD     float64 `mapstructure:"max_cost_usd"`     // Abort executions costing more than this in USD (0 = no limit)
+
+	// Worker polling
+	PollInterval    time.Duration `mapstructure:"poll_interval"`     // Delay between queue polls, and the delay polling resets to when tasks are ready (0 = 5s)
+	MaxPollInterval time.Duration `mapstructure:"max_poll_interval"` // Longest delay polling backs off to while no tasks are ready (at or below poll_interval = no backoff)
+
 	// Queue configuration
 	Queue ClaudeQueueConfig `mapstructure:"queue"` // Queue management configuration
 
@@ -124,7 +227,10 @@ type ClaudeConfig struct {
 
 // ClaudeQueueConfig contains task queue management configuration.
 type ClaudeQueueConfig struct {
-	QueueDir string `mapstructure:"queue_dir"` // Queue storage directory
+	QueueDir            string        `mapstructure:"queue_dir"`            // Queue storage directory
+	AgingThreshold      time.Duration `mapstructure:"aging_threshold"`      // Time a task waits before its priority starts to increase
+	AgingRate           float64       `mapstructure:"aging_rate"`           // Priority points gained per hour beyond the threshold (0 = disabled)
+	DuplicateSimilarity float64       `mapstructure:"duplicate_similarity"` // Prompt similarity (0-1) at which a task for the same worktree is a duplicate (0 = disabled)
 }
 
 // ClaudeWorktreeConfig contains worktree integration configuration.
@@ -136,7 +242,11 @@ type ClaudeWorktreeConfig struct {
 
 // ClaudeExecutionConfig contains execution configuration.
 type ClaudeExecutionConfig struct {
-	AutoCleanup bool `mapstructure:"auto_cleanup"` // Auto cleanup old logs
+	AutoCleanup   bool `mapstructure:"auto_cleanup"`     // Auto cleanup old logs
+	RetentionDays int  `mapstructure:"retention_days"`   // Days to keep logs before auto cleanup (0 = default)
+	LogPartSizeMB int  `mapstructure:"log_part_size_mb"` // Size at which a capture continues in a new log part file (0 = no rotation)
+
+	Timeout time.Duration `mapstructure:"timeout"` // Longest an execution may run before its session is killed (0 = no limit)
 }
 
 // ClaudeExecutionFormattingConfig contains log formatting configuration.
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -124,3 +125,139 @@ func TestConfigDefaults(t *testing.T) {
 		t.Error("Default Icons should be true")
 	}
 }
+
+func TestConfigValidate(t *testing.T) {
+	baseDir := t.TempDir()
+	validConfig := func() Config {
+		return Config{
+			Worktree: WorktreeConfig{BaseDir: baseDir},
+			Finder:   FinderConfig{SortBy: "activity", TimeFormat: "iso"},
+			Claude: ClaudeConfig{
+				MaxParallel:         3,
+				MaxDevelopmentTasks: 2,
+				Queue: ClaudeQueueConfig{
+					AgingThreshold:      time.Hour,
+					AgingRate:           1,
+					DuplicateSimilarity: 0.9,
+				},
+				Execution: ClaudeExecutionConfig{RetentionDays: 30},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(cfg *Config)
+		wantErrs []string
+	}{
+		{
+			name:   "valid config",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "boundary values",
+			modify: func(cfg *Config) {
+				cfg.Finder.SortBy = ""
+				cfg.Finder.TimeFormat = ""
+				cfg.Claude.MaxParallel = 1
+				cfg.Claude.MaxDevelopmentTasks = 1
+				cfg.Claude.MaxParallelPerRepository = 0
+				cfg.Claude.MaxCostUSD = 0
+				cfg.Claude.PollInterval = 0
+				cfg.Claude.MaxPollInterval = 0
+				cfg.Claude.Queue.AgingThreshold = 0
+				cfg.Claude.Queue.AgingRate = 0
+				cfg.Claude.Queue.DuplicateSimilarity = 0
+				cfg.Claude.Execution.RetentionDays = 0
+				cfg.Claude.Execution.LogPartSizeMB = 0
+				cfg.Claude.Execution.Timeout = 0
+			},
+		},
+		{
+			name: "zero max parallel",
+			modify: func(cfg *Config) {
+				cfg.Claude.MaxParallel = 0
+			},
+			wantErrs: []string{"claude.max_parallel: must be greater than 0, got 0"},
+		},
+		{
+			name: "negative retention days",
+			modify: func(cfg *Config) {
+				cfg.Claude.Execution.RetentionDays = -1
+			},
+			wantErrs: []string{"claude.execution.retention_days: must not be negative, got -1"},
+		},
+		{
+			name: "relative base dir",
+			modify: func(cfg *Config) {
+				cfg.Worktree.BaseDir = "worktrees"
+			},
+			wantErrs: []string{`worktree.basedir: must be an absolute path, got "worktrees"`},
+		},
+		{
+			name: "multiple violations",
+			modify: func(cfg *Config) {
+				cfg.Worktree.BaseDir = ""
+				cfg.Finder.SortBy = "size"
+				cfg.Finder.TimeFormat = "unix"
+				cfg.Claude.MaxParallel = -1
+				cfg.Claude.MaxDevelopmentTasks = 0
+				cfg.Claude.MaxParallelPerRepository = -2
+				cfg.Claude.MaxCostUSD = -1.5
+				cfg.Claude.PollInterval = -time.Second
+				cfg.Claude.MaxPollInterval = -time.Minute
+				cfg.Claude.Queue.AgingThreshold = -time.Minute
+				cfg.Claude.Queue.AgingRate = -0.5
+				cfg.Claude.Queue.DuplicateSimilarity = 1.5
+				cfg.Claude.Execution.RetentionDays = -7
+				cfg.Claude.Execution.LogPartSizeMB = -1
+				cfg.Claude.Execution.Timeout = -time.Hour
+			},
+			wantErrs: []string{
+				"worktree.basedir: must not be empty",
+				`finder.sort_by: must be one of name, activity, branch, got "size"`,
+				`finder.time_format: must be one of relative, absolute, iso, got "unix"`,
+				"claude.max_parallel: must be greater than 0, got -1",
+				"claude.max_development_tasks: must be greater than 0, got 0",
+				"claude.max_parallel_per_repository: must not be negative, got -2",
+				"claude.max_cost_usd: must not be negative, got -1.5",
+				"claude.poll_interval: must not be negative, got -1s",
+				"claude.max_poll_interval: must not be negative, got -1m0s",
+				"claude.queue.aging_threshold: must not be negative, got -1m0s",
+				"claude.queue.aging_rate: must not be negative, got -0.5",
+				"claude.queue.duplicate_similarity: must be between 0 and 1, got 1.5",
+				"claude.execution.retention_days: must not be negative, got -7",
+				"claude.execution.log_part_size_mb: must not be negative, got -1",
+				"claude.execution.timeout: must not be negative, got -1h0m0s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want error")
+			}
+
+			got := strings.Split(err.Error(), "\n")
+			if len(got) != len(tt.wantErrs) {
+				t.Fatalf("Validate() reported %d problems, want %d:\n%v", len(got), len(tt.wantErrs), err)
+			}
+			for i, want := range tt.wantErrs {
+				if got[i] != want {
+					t.Errorf("problem %d = %q, want %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
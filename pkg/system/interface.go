@@ -17,6 +17,9 @@ type SystemInterface interface {
 
 	// NotifySignal sets up signal notification for the given signals
 	NotifySignal(c chan<- os.Signal, signals ...os.Signal)
+
+	// LookPath searches for an executable in the directories named by PATH
+	LookPath(file string) (string, error)
 }
 
 // StandardSystem implements SystemInterface using standard Go library functions
@@ -27,13 +30,6 @@ func NewStandardSystem() SystemInterface {
 	return &StandardSystem{}
 }
 
-// CreateNamedPipe creates a named pipe using the mkfifo command
-func (s *StandardSystem) CreateNamedPipe(path string, mode uint32) error {
-	// Use mkfifo command as a portable solution
-	cmd := exec.Command("mkfifo", path)
-	return cmd.Run()
-}
-
 // RemoveFile removes a file or directory
 func (s *StandardSystem) RemoveFile(path string) error {
 	return os.Remove(path)
@@ -43,3 +39,8 @@ func (s *StandardSystem) RemoveFile(path string) error {
 func (s *StandardSystem) NotifySignal(c chan<- os.Signal, signals ...os.Signal) {
 	signal.Notify(c, signals...)
 }
+
+// LookPath searches for an executable in PATH
+func (s *StandardSystem) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
@@ -7,30 +7,28 @@ import (
 	"testing"
 )
 
-func TestStandardSystem_CreateNamedPipe(t *testing.T) {
+func TestStandardSystem_CreateNamedPipe_Cleanup(t *testing.T) {
 	sys := NewStandardSystem()
 	tmpDir := t.TempDir()
 
-	pipePath := filepath.Join(tmpDir, "test.pipe")
+	pipePath := filepath.Join(tmpDir, "cleanup.pipe")
 
-	err := sys.CreateNamedPipe(pipePath, 0600)
-	if err != nil {
+	if err := sys.CreateNamedPipe(pipePath, 0600); err != nil {
 		t.Fatalf("CreateNamedPipe() error = %v", err)
 	}
 
-	// Verify pipe was created
-	info, err := os.Stat(pipePath)
-	if err != nil {
-		t.Fatalf("Pipe was not created: %v", err)
+	// Creating the same path twice must fail rather than silently reuse it
+	if err := sys.CreateNamedPipe(pipePath, 0600); err == nil {
+		t.Errorf("CreateNamedPipe() should fail when the path already exists")
 	}
 
-	// Check if it's a named pipe (FIFO)
-	if info.Mode()&os.ModeNamedPipe == 0 {
-		t.Errorf("Created file is not a named pipe")
+	if err := sys.RemoveFile(pipePath); err != nil {
+		t.Fatalf("RemoveFile() error = %v", err)
 	}
 
-	// Clean up
-	_ = os.Remove(pipePath)
+	if _, err := os.Stat(pipePath); !os.IsNotExist(err) {
+		t.Errorf("Pipe still exists after RemoveFile()")
+	}
 }
 
 func TestStandardSystem_RemoveFile(t *testing.T) {
@@ -143,6 +141,10 @@ func (m *MockSystem) NotifySignal(c chan<- os.Signal, signals ...os.Signal) {
 	m.signals = append(m.signals, signals...)
 }
 
+func (m *MockSystem) LookPath(file string) (string, error) {
+	return file, nil
+}
+
 func (m *MockSystem) GetCreatedPipes() []string {
 	return m.createdPipes
 }
@@ -0,0 +1,16 @@
+//go:build unix
+
+package system
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// CreateNamedPipe creates a named pipe (FIFO) using the mkfifo system call
+func (s *StandardSystem) CreateNamedPipe(path string, mode uint32) error {
+	if err := syscall.Mkfifo(path, mode); err != nil {
+		return fmt.Errorf("failed to create named pipe (FIFO) %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStandardSystem_CreateNamedPipe(t *testing.T) {
+	sys := NewStandardSystem()
+	tmpDir := t.TempDir()
+
+	pipePath := filepath.Join(tmpDir, "test.pipe")
+
+	err := sys.CreateNamedPipe(pipePath, 0600)
+	if err != nil {
+		t.Fatalf("CreateNamedPipe() error = %v", err)
+	}
+
+	// Verify pipe was created
+	info, err := os.Stat(pipePath)
+	if err != nil {
+		t.Fatalf("Pipe was not created: %v", err)
+	}
+
+	// Check if it's a named pipe (FIFO)
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		t.Errorf("Created file is not a named pipe")
+	}
+
+	// Clean up
+	_ = os.Remove(pipePath)
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package system
+
+import (
+	"fmt"
+	"os"
+)
+
+// CreateNamedPipe creates a regular file in place of a named pipe.
+// Windows has no filesystem FIFOs, so writers append to the file and
+// readers see the output once it has been flushed.
+func (s *StandardSystem) CreateNamedPipe(path string, mode uint32) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, os.FileMode(mode))
+	if err != nil {
+		return fmt.Errorf("named pipes (FIFO) are not supported on windows and the file fallback failed for %s: %w", path, err)
+	}
+	return f.Close()
+}
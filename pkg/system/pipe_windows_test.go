@@ -0,0 +1,33 @@
+//go:build windows
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStandardSystem_CreateNamedPipe(t *testing.T) {
+	sys := NewStandardSystem()
+	tmpDir := t.TempDir()
+
+	pipePath := filepath.Join(tmpDir, "test.pipe")
+
+	err := sys.CreateNamedPipe(pipePath, 0600)
+	if err != nil {
+		t.Fatalf("CreateNamedPipe() error = %v", err)
+	}
+
+	// Verify the fallback file was created
+	info, err := os.Stat(pipePath)
+	if err != nil {
+		t.Fatalf("Pipe was not created: %v", err)
+	}
+
+	if !info.Mode().IsRegular() {
+		t.Errorf("Expected a regular file fallback on windows, got mode %v", info.Mode())
+	}
+
+	_ = os.Remove(pipePath)
+}
@@ -189,3 +189,10 @@ func SanitizeForFilesystem(input string) string {
 
 	return result
 }
+
+// ShellQuote quotes s as a single POSIX shell word. The result is wrapped in
+// single quotes and each embedded single quote is closed, escaped and reopened,
+// so newlines, $ and backticks reach the command unchanged.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 )
@@ -136,3 +137,34 @@ func TestSanitizeForFilesystem(t *testing.T) {
 		})
 	}
 }
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "plain", input: "fix the tests"},
+		{name: "double quotes", input: `say "hello"`},
+		{name: "single quotes", input: "it's Bob's branch"},
+		{name: "only single quote", input: "'"},
+		{name: "dollar", input: "cost is $HOME and ${PATH} and $(whoami)"},
+		{name: "backticks", input: "run `rm -rf /` never"},
+		{name: "backslashes", input: `C:\path\to\file \n \\`},
+		{name: "newlines", input: "line one\nline two\n\nline four\n"},
+		{name: "mixed", input: "it's \"$x\"\n`y` \\ 'z'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The shell must hand the quoted word to printf unchanged
+			out, err := exec.Command("sh", "-c", "printf '%s' "+ShellQuote(tt.input)).Output()
+			if err != nil {
+				t.Fatalf("sh -c failed for %q: %v", ShellQuote(tt.input), err)
+			}
+			if string(out) != tt.input {
+				t.Errorf("ShellQuote(%q) round trip = %q", tt.input, out)
+			}
+		})
+	}
+}